@@ -0,0 +1,501 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/redis/go-redis/v9"
+
+	"MKK-Luna/internal/config"
+	authinfra "MKK-Luna/internal/infra/auth"
+	"MKK-Luna/internal/infra/cache"
+	ideminfra "MKK-Luna/internal/infra/idempotency"
+	redisinfra "MKK-Luna/internal/infra/redis"
+	"MKK-Luna/internal/repository"
+	"MKK-Luna/internal/service"
+)
+
+type command struct {
+	usage string
+	run   func(ctx context.Context, e *env, args []string) (*result, error)
+}
+
+var commands = map[string]command{
+	"user-create":         {"-email E -username U (-password P | -password-stdin)", userCreate},
+	"user-reset-password": {"-user ID|EMAIL|USERNAME (-password P | -password-stdin); also revokes sessions and clears lockout", userResetPassword},
+	"sessions-revoke":     {"-user ID|EMAIL|USERNAME", sessionsRevoke},
+	"member-add":          {"-team ID -user ID|EMAIL|USERNAME [-role member|admin|owner]", memberAdd},
+	"member-remove":       {"-team ID -user ID|EMAIL|USERNAME", memberRemove},
+	"integrity-assignees": {"[-fix]  count tasks assigned to users outside their team; -fix unassigns them", integrityAssignees},
+	"lockout-list":        {"[-limit N]", lockoutList},
+	"lockout-unlock":      {"-login EMAIL|USERNAME", lockoutUnlock},
+	"cache-flush":         {"[-team ID] [-stats]  bump task list cache versions (one team or all), -stats also drops stats entries", cacheFlush},
+	"idem-list":           {"-user ID [-limit N]  stored Idempotency-Key responses", idemList},
+	"idem-delete":         {"-key KEY", idemDelete},
+}
+
+func main() {
+	format := flag.String("format", "table", "output format: table|json")
+	operator := flag.String("operator", os.Getenv("USER"), "operator name recorded in the audit log")
+	timeout := flag.Duration("timeout", 2*time.Minute, "overall timeout")
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+	cmd, ok := commands[flag.Arg(0)]
+	if !ok {
+		fatalf("unknown command: %s", flag.Arg(0))
+	}
+	if *format != "table" && *format != "json" {
+		fatalf("unknown format: %s", *format)
+	}
+
+	cfg, err := config.New()
+	if err != nil {
+		fatalf("load config: %v", err)
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, *timeout)
+	defer cancel()
+
+	e := &env{cfg: cfg, operator: *operator, logger: slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn}))}
+	defer e.close()
+
+	res, err := cmd.run(ctx, e, flag.Args()[1:])
+	if err != nil {
+		fatalf("%s: %v", flag.Arg(0), describe(err))
+	}
+	if err := res.write(os.Stdout, *format); err != nil {
+		fatalf("write output: %v", err)
+	}
+}
+
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "usage: admin [-format table|json] [-operator NAME] <command> [flags]\n\ncommands:\n")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(out, "  %-20s %s\n", name, commands[name].usage)
+	}
+	fmt.Fprintf(out, "\nglobal flags:\n")
+	flag.PrintDefaults()
+}
+
+// env opens MySQL and Redis on first use, so Redis-only commands work while MySQL is down
+// and the other way round.
+type env struct {
+	cfg      *config.Config
+	operator string
+	logger   *slog.Logger
+
+	db    *sqlx.DB
+	redis *redis.Client
+	ops   *service.OpsService
+}
+
+func (e *env) mysql() (*sqlx.DB, error) {
+	if e.db == nil {
+		db, err := repository.NewMySQL(e.cfg.MySQL)
+		if err != nil {
+			return nil, err
+		}
+		e.db = db
+	}
+	return e.db, nil
+}
+
+func (e *env) rdb(ctx context.Context) (*redis.Client, error) {
+	if e.redis == nil {
+		client := redisinfra.New(e.cfg.Redis)
+		if err := client.Redis.Ping(ctx).Err(); err != nil {
+			_ = client.Redis.Close()
+			return nil, fmt.Errorf("redis: %w", err)
+		}
+		e.redis = client.Redis
+	}
+	return e.redis, nil
+}
+
+func (e *env) service() (*service.OpsService, error) {
+	if e.ops != nil {
+		return e.ops, nil
+	}
+	db, err := e.mysql()
+	if err != nil {
+		return nil, err
+	}
+	teams := repository.NewTeamRepository(db)
+	members := repository.NewTeamMemberRepository(db)
+	audit := service.NewAuditService(repository.NewAuditRepository(db), teams, members, e.cfg.Admin.UserIDs, e.logger)
+	integrityRepo := repository.NewIntegrityRepository(db)
+	integrity := service.NewIntegrityService(db, integrityRepo,
+		service.DefaultIntegrityChecks(integrityRepo, repository.NewAnalyticsRepository(db), repository.NewTaskHistoryRepository(db)),
+		e.cfg.Integrity.SampleSize, e.cfg.Admin.UserIDs, e.logger,
+	)
+	e.ops = service.NewOpsService(
+		db,
+		repository.NewUserRepository(db),
+		repository.NewSessionRepository(db),
+		teams,
+		members,
+		integrity,
+		e.cfg.Auth.BcryptCost,
+		service.WithOpsAudit(audit, e.operator),
+	)
+	return e.ops, nil
+}
+
+// Caches are built enabled regardless of config so flushes always reach Redis.
+func (e *env) taskCache(ctx context.Context) (*cache.TaskCache, error) {
+	client, err := e.rdb(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return cache.NewTaskCache(client, e.cfg.Cache.TaskCacheTTL, true, e.logger, nil), nil
+}
+
+func (e *env) lockout(ctx context.Context) (*authinfra.Lockout, error) {
+	client, err := e.rdb(ctx)
+	if err != nil {
+		return nil, err
+	}
+	l := e.cfg.Auth.Lockout
+	return authinfra.NewLockout(client, l.MaxAttempts, l.LockTTL, l.KeyMaxLen, e.logger, nil), nil
+}
+
+// invalidateTeams bumps cached task lists after a change made behind the API's back.
+// Redis being down is only a warning: the change itself is already committed.
+func (e *env) invalidateTeams(ctx context.Context, teamIDs ...int64) {
+	c, err := e.taskCache(ctx)
+	if err == nil {
+		for _, id := range teamIDs {
+			if err = c.InvalidateTeam(ctx, id); err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		e.logger.Warn("task cache not invalidated; run cache-flush once redis is back", "err", err)
+	}
+}
+
+func (e *env) close() {
+	if e.db != nil {
+		_ = e.db.Close()
+	}
+	if e.redis != nil {
+		_ = e.redis.Close()
+	}
+}
+
+func userCreate(ctx context.Context, e *env, args []string) (*result, error) {
+	fs := flag.NewFlagSet("user-create", flag.ExitOnError)
+	email := fs.String("email", "", "email")
+	username := fs.String("username", "", "username")
+	password := passwordFlags(fs)
+	_ = fs.Parse(args)
+	pw, err := password()
+	if err != nil {
+		return nil, err
+	}
+	ops, err := e.service()
+	if err != nil {
+		return nil, err
+	}
+	user, err := ops.CreateUser(ctx, *email, *username, pw)
+	if err != nil {
+		return nil, err
+	}
+	res := newResult("id", "email", "username")
+	res.add(user.ID, user.Email, user.Username)
+	return res, nil
+}
+
+func userResetPassword(ctx context.Context, e *env, args []string) (*result, error) {
+	fs := flag.NewFlagSet("user-reset-password", flag.ExitOnError)
+	ref := fs.String("user", "", "user id, email or username")
+	password := passwordFlags(fs)
+	_ = fs.Parse(args)
+	pw, err := password()
+	if err != nil {
+		return nil, err
+	}
+	ops, err := e.service()
+	if err != nil {
+		return nil, err
+	}
+	user, revoked, err := ops.ResetPassword(ctx, *ref, pw)
+	if err != nil {
+		return nil, err
+	}
+	unlocked := false
+	if l, lerr := e.lockout(ctx); lerr == nil {
+		for _, login := range []string{user.Email, user.Username} {
+			if ok, uerr := l.Unlock(ctx, strings.ToLower(login)); uerr == nil && ok {
+				unlocked = true
+			}
+		}
+	} else {
+		e.logger.Warn("lockout not cleared", "err", lerr)
+	}
+	res := newResult("id", "email", "sessions_revoked", "unlocked")
+	res.add(user.ID, user.Email, revoked, unlocked)
+	return res, nil
+}
+
+func sessionsRevoke(ctx context.Context, e *env, args []string) (*result, error) {
+	fs := flag.NewFlagSet("sessions-revoke", flag.ExitOnError)
+	ref := fs.String("user", "", "user id, email or username")
+	_ = fs.Parse(args)
+	ops, err := e.service()
+	if err != nil {
+		return nil, err
+	}
+	user, revoked, err := ops.RevokeSessions(ctx, *ref)
+	if err != nil {
+		return nil, err
+	}
+	res := newResult("id", "email", "sessions_revoked")
+	res.add(user.ID, user.Email, revoked)
+	return res, nil
+}
+
+func memberAdd(ctx context.Context, e *env, args []string) (*result, error) {
+	fs := flag.NewFlagSet("member-add", flag.ExitOnError)
+	teamID := fs.Int64("team", 0, "team id")
+	ref := fs.String("user", "", "user id, email or username")
+	role := fs.String("role", service.RoleMember, "member|admin|owner")
+	_ = fs.Parse(args)
+	ops, err := e.service()
+	if err != nil {
+		return nil, err
+	}
+	user, err := ops.AddMember(ctx, *teamID, *ref, *role)
+	if err != nil {
+		return nil, err
+	}
+	e.invalidateTeams(ctx, *teamID)
+	res := newResult("team_id", "user_id", "email", "role")
+	res.add(*teamID, user.ID, user.Email, *role)
+	return res, nil
+}
+
+func memberRemove(ctx context.Context, e *env, args []string) (*result, error) {
+	fs := flag.NewFlagSet("member-remove", flag.ExitOnError)
+	teamID := fs.Int64("team", 0, "team id")
+	ref := fs.String("user", "", "user id, email or username")
+	_ = fs.Parse(args)
+	ops, err := e.service()
+	if err != nil {
+		return nil, err
+	}
+	user, err := ops.RemoveMember(ctx, *teamID, *ref)
+	if err != nil {
+		return nil, err
+	}
+	e.invalidateTeams(ctx, *teamID)
+	res := newResult("team_id", "user_id", "email", "removed")
+	res.add(*teamID, user.ID, user.Email, true)
+	return res, nil
+}
+
+func integrityAssignees(ctx context.Context, e *env, args []string) (*result, error) {
+	fs := flag.NewFlagSet("integrity-assignees", flag.ExitOnError)
+	fix := fs.Bool("fix", false, "unassign the tasks")
+	_ = fs.Parse(args)
+	ops, err := e.service()
+	if err != nil {
+		return nil, err
+	}
+	checked, err := ops.CheckAssignees(ctx, *fix)
+	if err != nil {
+		return nil, err
+	}
+	if len(checked.TeamIDs) > 0 {
+		e.invalidateTeams(ctx, checked.TeamIDs...)
+	}
+	run := checked.Run
+	if run.Error.Valid {
+		return nil, fmt.Errorf("integrity check: %s", run.Error.String)
+	}
+	res := newResult("check", "found", "repaired", "repair", "sample")
+	res.add(run.CheckName, run.Found, run.Repaired, run.Repair, string(run.Sample))
+	return res, nil
+}
+
+func lockoutList(ctx context.Context, e *env, args []string) (*result, error) {
+	fs := flag.NewFlagSet("lockout-list", flag.ExitOnError)
+	limit := fs.Int("limit", 100, "max entries")
+	_ = fs.Parse(args)
+	l, err := e.lockout(ctx)
+	if err != nil {
+		return nil, err
+	}
+	locked, err := l.Locked(ctx, *limit)
+	if err != nil {
+		return nil, err
+	}
+	res := newResult("login", "ttl")
+	for _, item := range locked {
+		res.add(item.Login, item.TTL)
+	}
+	return res, nil
+}
+
+func lockoutUnlock(ctx context.Context, e *env, args []string) (*result, error) {
+	fs := flag.NewFlagSet("lockout-unlock", flag.ExitOnError)
+	login := fs.String("login", "", "email or username as typed at login")
+	_ = fs.Parse(args)
+	l, err := e.lockout(ctx)
+	if err != nil {
+		return nil, err
+	}
+	normalized, err := l.Normalize(*login)
+	if err != nil {
+		return nil, err
+	}
+	unlocked, err := l.Unlock(ctx, normalized)
+	if err != nil {
+		return nil, err
+	}
+	res := newResult("login", "unlocked")
+	res.add(normalized, unlocked)
+	return res, nil
+}
+
+func cacheFlush(ctx context.Context, e *env, args []string) (*result, error) {
+	fs := flag.NewFlagSet("cache-flush", flag.ExitOnError)
+	teamID := fs.Int64("team", 0, "only this team (default: all teams)")
+	stats := fs.Bool("stats", false, "also drop cached stats")
+	_ = fs.Parse(args)
+	tasks, err := e.taskCache(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := newResult("cache", "scope", "count")
+	if *teamID > 0 {
+		if err := tasks.InvalidateTeam(ctx, *teamID); err != nil {
+			return nil, err
+		}
+		res.add("tasks", fmt.Sprintf("team %d", *teamID), 1)
+	} else {
+		n, err := tasks.InvalidateAll(ctx)
+		if err != nil {
+			return nil, err
+		}
+		res.add("tasks", "all teams", n)
+	}
+	if *stats {
+		client, err := e.rdb(ctx)
+		if err != nil {
+			return nil, err
+		}
+		n, err := cache.NewStatsCache(client, e.cfg.Cache.StatsTTL, true, e.logger, nil).Flush(ctx)
+		if err != nil {
+			return nil, err
+		}
+		res.add("stats", "all keys", n)
+	}
+	return res, nil
+}
+
+func idemList(ctx context.Context, e *env, args []string) (*result, error) {
+	fs := flag.NewFlagSet("idem-list", flag.ExitOnError)
+	userID := fs.Int64("user", 0, "user id")
+	limit := fs.Int("limit", 100, "max entries")
+	_ = fs.Parse(args)
+	if *userID <= 0 {
+		return nil, errors.New("-user is required")
+	}
+	client, err := e.rdb(ctx)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := ideminfra.NewStore(client).ListByUser(ctx, *userID, *limit)
+	if err != nil {
+		return nil, err
+	}
+	res := newResult("key", "status", "created_at", "ttl", "request_hash")
+	for _, item := range entries {
+		res.add(item.Key, item.Response.Status, time.Unix(item.Response.CreatedAt, 0), item.TTL, item.Response.RequestHash)
+	}
+	return res, nil
+}
+
+func idemDelete(ctx context.Context, e *env, args []string) (*result, error) {
+	fs := flag.NewFlagSet("idem-delete", flag.ExitOnError)
+	key := fs.String("key", "", "full key as shown by idem-list")
+	_ = fs.Parse(args)
+	if !strings.HasPrefix(*key, "idem:resp:") {
+		return nil, errors.New("-key must be an idem:resp: key")
+	}
+	client, err := e.rdb(ctx)
+	if err != nil {
+		return nil, err
+	}
+	deleted, err := ideminfra.NewStore(client).Delete(ctx, *key)
+	if err != nil {
+		return nil, err
+	}
+	res := newResult("key", "deleted")
+	res.add(*key, deleted)
+	return res, nil
+}
+
+// passwordFlags keeps passwords out of shell history: -password-stdin reads the first
+// line of stdin instead.
+func passwordFlags(fs *flag.FlagSet) func() (string, error) {
+	value := fs.String("password", "", "new password")
+	fromStdin := fs.Bool("password-stdin", false, "read the password from stdin")
+	return func() (string, error) {
+		if !*fromStdin {
+			if *value == "" {
+				return "", errors.New("-password or -password-stdin is required")
+			}
+			return *value, nil
+		}
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return "", err
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+}
+
+func describe(err error) string {
+	switch {
+	case errors.Is(err, service.ErrNotFound):
+		return "not found"
+	case errors.Is(err, service.ErrConflict):
+		return "conflict (already exists, or would remove the last owner)"
+	case errors.Is(err, service.ErrBadRequest):
+		return "invalid arguments"
+	default:
+		return err.Error()
+	}
+}
+
+func fatalf(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(1)
+}
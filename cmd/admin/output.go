@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// result is what every command prints: a table for people, or the same rows as JSON
+// objects keyed by column for scripts.
+type result struct {
+	columns []string
+	rows    [][]any
+}
+
+func newResult(columns ...string) *result {
+	return &result{columns: columns}
+}
+
+func (r *result) add(values ...any) {
+	r.rows = append(r.rows, values)
+}
+
+func (r *result) write(w io.Writer, format string) error {
+	switch format {
+	case "json":
+		items := make([]map[string]any, 0, len(r.rows))
+		for _, row := range r.rows {
+			item := make(map[string]any, len(r.columns))
+			for i, col := range r.columns {
+				item[col] = jsonValue(row[i])
+			}
+			items = append(items, item)
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(items)
+	case "table":
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.ToUpper(strings.Join(r.columns, "\t")))
+		for _, row := range r.rows {
+			cells := make([]string, len(row))
+			for i, v := range row {
+				cells[i] = tableValue(v)
+			}
+			fmt.Fprintln(tw, strings.Join(cells, "\t"))
+		}
+		return tw.Flush()
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+}
+
+func jsonValue(v any) any {
+	switch x := v.(type) {
+	case time.Duration:
+		return int64(x.Seconds())
+	case time.Time:
+		return x.UTC().Format(time.RFC3339)
+	default:
+		return v
+	}
+}
+
+func tableValue(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return "-"
+	case time.Duration:
+		return x.Round(time.Second).String()
+	case time.Time:
+		return x.UTC().Format(time.RFC3339)
+	case string:
+		if x == "" {
+			return "-"
+		}
+		return x
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,306 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"MKK-Luna/internal/api/middleware"
+	"MKK-Luna/internal/repository"
+	"MKK-Luna/internal/service"
+	"MKK-Luna/pkg/api/response"
+)
+
+const attachmentMemoryLimit = 8 << 20
+
+type AttachmentHandler struct {
+	attachments *service.AttachmentService
+}
+
+func NewAttachmentHandler(attachments *service.AttachmentService) *AttachmentHandler {
+	return &AttachmentHandler{attachments: attachments}
+}
+
+type attachmentResponse struct {
+	ID          int64  `json:"id"`
+	TaskID      int64  `json:"task_id"`
+	CommentID   *int64 `json:"comment_id,omitempty"`
+	UploadedBy  *int64 `json:"uploaded_by,omitempty"`
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	SizeBytes   int64  `json:"size_bytes"`
+	DownloadURL string `json:"download_url"`
+	ExpiresAt   string `json:"download_url_expires_at"`
+	CreatedAt   string `json:"created_at"`
+}
+
+// Upload godoc
+// @Summary Upload attachment
+// @Description Multipart upload with a "file" part and an optional "comment_id" field. Size and content type are limited by server configuration.
+// @Tags attachments
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param file formData file true "File"
+// @Param comment_id formData int false "Comment ID on the same task"
+// @Success 201 {object} attachmentResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 413 {object} response.ErrorResponse
+// @Router /api/v1/tasks/{id}/attachments [post]
+func (h *AttachmentHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	taskID, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || taskID <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.attachments.MaxSize()+attachmentMemoryLimit)
+	if err := r.ParseMultipartForm(attachmentMemoryLimit); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			response.Error(w, http.StatusRequestEntityTooLarge, "file too large")
+			return
+		}
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	defer func() { _ = r.MultipartForm.RemoveAll() }()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	defer file.Close()
+	if header.Size > h.attachments.MaxSize() {
+		response.Error(w, http.StatusRequestEntityTooLarge, "file too large")
+		return
+	}
+
+	var commentID *int64
+	if v := r.FormValue("comment_id"); v != "" {
+		id, err := parseInt64(v)
+		if err != nil || id <= 0 {
+			response.Error(w, http.StatusBadRequest, "invalid request")
+			return
+		}
+		commentID = &id
+	}
+
+	// The declared part type is client-controlled; sniff the content instead.
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		response.Error(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	a, err := h.attachments.Upload(ctx, userID, service.AttachmentUpload{
+		TaskID:      taskID,
+		CommentID:   commentID,
+		Filename:    header.Filename,
+		ContentType: http.DetectContentType(head[:n]),
+		Size:        header.Size,
+		Body:        file,
+	})
+	if mapServiceError(w, err) {
+		return
+	}
+	response.JSON(w, http.StatusCreated, h.toAttachmentResponse(*a))
+}
+
+// List godoc
+// @Summary List task attachments
+// @Description Each item carries a short-lived signed download URL.
+// @Tags attachments
+// @Produce json
+// @Param id path int true "Task ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/tasks/{id}/attachments [get]
+func (h *AttachmentHandler) List(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	taskID, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || taskID <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	items, err := h.attachments.List(ctx, userID, taskID)
+	if mapServiceError(w, err) {
+		return
+	}
+	resp := make([]attachmentResponse, 0, len(items))
+	for _, a := range items {
+		resp = append(resp, h.toAttachmentResponse(a))
+	}
+	response.JSON(w, http.StatusOK, map[string]any{"attachments": resp})
+}
+
+// Get godoc
+// @Summary Get attachment metadata
+// @Tags attachments
+// @Produce json
+// @Param id path int true "Attachment ID"
+// @Success 200 {object} attachmentResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/attachments/{id} [get]
+func (h *AttachmentHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	id, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || id <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	a, err := h.attachments.Get(ctx, userID, id)
+	if mapServiceError(w, err) {
+		return
+	}
+	response.JSON(w, http.StatusOK, h.toAttachmentResponse(*a))
+}
+
+// Delete godoc
+// @Summary Delete attachment
+// @Description Allowed for the uploader and team owners/admins.
+// @Tags attachments
+// @Param id path int true "Attachment ID"
+// @Success 204
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/attachments/{id} [delete]
+func (h *AttachmentHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	id, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || id <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	if mapServiceError(w, h.attachments.Delete(ctx, userID, id)) {
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Download godoc
+// @Summary Download attachment
+// @Description Public route authorised by the signed URL returned from the attachment endpoints.
+// @Tags attachments
+// @Produce octet-stream
+// @Param id path int true "Attachment ID"
+// @Param expires query int true "Unix expiry"
+// @Param sig query string true "Signature"
+// @Success 200 {file} file
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/attachments/{id}/download [get]
+func (h *AttachmentHandler) Download(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 60*time.Second)
+	defer cancel()
+
+	id, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || id <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	expires, err := parseInt64(r.URL.Query().Get("expires"))
+	sig := r.URL.Query().Get("sig")
+	if err != nil || sig == "" {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	a, body, err := h.attachments.OpenSigned(ctx, id, expires, sig)
+	if errors.Is(err, service.ErrInvalidToken) {
+		response.Error(w, http.StatusUnauthorized, "invalid or expired link")
+		return
+	}
+	if mapServiceError(w, err) {
+		return
+	}
+	defer body.Close()
+
+	w.Header().Set("Content-Type", a.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(a.SizeBytes, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": a.Filename}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.Copy(w, body)
+}
+
+func (h *AttachmentHandler) toAttachmentResponse(a repository.TaskAttachment) attachmentResponse {
+	expires, sig := h.attachments.SignDownload(a.ID)
+	q := url.Values{}
+	q.Set("expires", strconv.FormatInt(expires, 10))
+	q.Set("sig", sig)
+	resp := attachmentResponse{
+		ID:          a.ID,
+		TaskID:      a.TaskID,
+		Filename:    a.Filename,
+		ContentType: a.ContentType,
+		SizeBytes:   a.SizeBytes,
+		DownloadURL: "/api/v1/attachments/" + strconv.FormatInt(a.ID, 10) + "/download?" + q.Encode(),
+		ExpiresAt:   time.Unix(expires, 0).UTC().Format(time.RFC3339Nano),
+		CreatedAt:   a.CreatedAt.UTC().Format(time.RFC3339Nano),
+	}
+	if a.CommentID.Valid {
+		resp.CommentID = &a.CommentID.Int64
+	}
+	if a.UploadedBy.Valid {
+		resp.UploadedBy = &a.UploadedBy.Int64
+	}
+	return resp
+}
@@ -0,0 +1,394 @@
+package api
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"MKK-Luna/internal/api/middleware"
+	"MKK-Luna/internal/repository"
+	"MKK-Luna/internal/service"
+	"MKK-Luna/pkg/api/response"
+)
+
+type AuditHandler struct {
+	audit *service.AuditService
+}
+
+func NewAuditHandler(audit *service.AuditService) *AuditHandler {
+	return &AuditHandler{audit: audit}
+}
+
+type auditEventResponse struct {
+	ID         int64           `json:"id"`
+	ActorID    *int64          `json:"actor_id,omitempty"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"target_type"`
+	TargetID   *int64          `json:"target_id,omitempty"`
+	TeamID     *int64          `json:"team_id,omitempty"`
+	IP         *string         `json:"ip,omitempty"`
+	RequestID  *string         `json:"request_id,omitempty"`
+	Payload    json.RawMessage `json:"payload,omitempty" swaggertype:"object"`
+	CreatedAt  string          `json:"created_at"`
+}
+
+type listAuditEventsResponse struct {
+	Items  []auditEventResponse `json:"items"`
+	Total  int64                `json:"total"`
+	Limit  int                  `json:"limit"`
+	Offset int                  `json:"offset"`
+}
+
+const (
+	auditCSVFlushEvery = 100
+	auditExportTimeout = 2 * time.Minute
+)
+
+var auditCSVHeader = []string{"id", "created_at", "actor_id", "action", "target_type", "target_id", "team_id", "ip", "request_id", "payload"}
+
+// TeamAudit godoc
+// @Summary Team audit log
+// @Description Owners only. Newest first.
+// @Tags teams
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Team ID"
+// @Param action query string false "Action, e.g. team.member_invited"
+// @Param actor_id query int false "Actor user ID"
+// @Param target_type query string false "Target type"
+// @Param target_id query int false "Target ID"
+// @Param from query string false "RFC3339 UTC from (inclusive)"
+// @Param to query string false "RFC3339 UTC to (exclusive)"
+// @Param limit query int false "Limit (1..100)"
+// @Param offset query int false "Offset (>=0)"
+// @Success 200 {object} listAuditEventsResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/teams/{id}/audit [get]
+func (h *AuditHandler) TeamAudit(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	teamID, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || teamID <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	filter, err := parseAuditFilter(r.URL.Query(), false)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	limit, offset, err := parseAuditPage(r.URL.Query())
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	items, total, err := h.audit.ListTeamEvents(ctx, userID, teamID, filter, limit, offset)
+	if err != nil {
+		if mapServiceError(w, err) {
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	response.JSON(w, http.StatusOK, toListAuditEventsResponse(items, total, limit, offset))
+}
+
+// List godoc
+// @Summary Global audit log
+// @Description system_admin only. Newest first.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param team_id query int false "Team ID"
+// @Param action query string false "Action"
+// @Param actor_id query int false "Actor user ID"
+// @Param target_type query string false "Target type"
+// @Param target_id query int false "Target ID"
+// @Param from query string false "RFC3339 UTC from (inclusive)"
+// @Param to query string false "RFC3339 UTC to (exclusive)"
+// @Param limit query int false "Limit (1..100)"
+// @Param offset query int false "Offset (>=0)"
+// @Success 200 {object} listAuditEventsResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Router /api/v1/admin/audit [get]
+func (h *AuditHandler) List(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	filter, err := parseAuditFilter(r.URL.Query(), true)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	limit, offset, err := parseAuditPage(r.URL.Query())
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	items, total, err := h.audit.ListEvents(ctx, userID, filter, limit, offset)
+	if err != nil {
+		if mapServiceError(w, err) {
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	response.JSON(w, http.StatusOK, toListAuditEventsResponse(items, total, limit, offset))
+}
+
+// Export godoc
+// @Summary Export global audit log
+// @Description system_admin only. Streams all matching events newest first as CSV or NDJSON.
+// @Tags admin
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Security BearerAuth
+// @Param format query string false "csv (default) or ndjson"
+// @Param team_id query int false "Team ID"
+// @Param action query string false "Action"
+// @Param actor_id query int false "Actor user ID"
+// @Param target_type query string false "Target type"
+// @Param target_id query int false "Target ID"
+// @Param from query string false "RFC3339 UTC from (inclusive)"
+// @Param to query string false "RFC3339 UTC to (exclusive)"
+// @Success 200 {string} string
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Router /api/v1/admin/audit/export [get]
+func (h *AuditHandler) Export(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), auditExportTimeout)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "ndjson" {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	filter, err := parseAuditFilter(r.URL.Query(), true)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	// The server write timeout is sized for regular requests; an export may take longer.
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(auditExportTimeout))
+
+	// Headers go out with the first event so that permission and query errors
+	// before it can still be reported as regular JSON errors.
+	started := false
+	rows := 0
+	var cw *csv.Writer
+	enc := json.NewEncoder(w)
+	start := func() error {
+		started = true
+		if format == "csv" {
+			w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+			w.Header().Set("Content-Disposition", `attachment; filename="audit.csv"`)
+			w.WriteHeader(http.StatusOK)
+			cw = csv.NewWriter(w)
+			return cw.Write(auditCSVHeader)
+		}
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.Header().Set("Content-Disposition", `attachment; filename="audit.ndjson"`)
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+
+	_, err = h.audit.ExportEvents(ctx, userID, filter, func(ev repository.AuditEvent) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		if cw != nil {
+			if err := cw.Write(auditCSVRecord(ev)); err != nil {
+				return err
+			}
+			if rows++; rows%auditCSVFlushEvery == 0 {
+				cw.Flush()
+			}
+			return cw.Error()
+		}
+		return enc.Encode(toAuditEventResponse(ev))
+	})
+	if !started {
+		if err != nil {
+			if mapServiceError(w, err) {
+				return
+			}
+			response.Error(w, http.StatusInternalServerError, "internal error")
+			return
+		}
+		// Empty exports still get a CSV header line.
+		_ = start()
+	}
+	if cw != nil {
+		cw.Flush()
+	}
+}
+
+func parseAuditFilter(q url.Values, allowTeam bool) (repository.AuditFilter, error) {
+	var f repository.AuditFilter
+	idParam := func(name string) (*int64, error) {
+		v := q.Get(name)
+		if v == "" {
+			return nil, nil
+		}
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || id <= 0 {
+			return nil, errors.New("invalid " + name)
+		}
+		return &id, nil
+	}
+	timeParam := func(name string) (*time.Time, error) {
+		v := q.Get(name)
+		if v == "" {
+			return nil, nil
+		}
+		tm, err := parseRFC3339UTC(v)
+		if err != nil {
+			return nil, err
+		}
+		return &tm, nil
+	}
+
+	var err error
+	if allowTeam {
+		if f.TeamID, err = idParam("team_id"); err != nil {
+			return f, err
+		}
+	}
+	if f.ActorID, err = idParam("actor_id"); err != nil {
+		return f, err
+	}
+	if f.TargetID, err = idParam("target_id"); err != nil {
+		return f, err
+	}
+	if f.From, err = timeParam("from"); err != nil {
+		return f, err
+	}
+	if f.To, err = timeParam("to"); err != nil {
+		return f, err
+	}
+	if v := q.Get("action"); v != "" {
+		if len(v) > 64 {
+			return f, errors.New("invalid action")
+		}
+		f.Action = &v
+	}
+	if v := q.Get("target_type"); v != "" {
+		if len(v) > 32 {
+			return f, errors.New("invalid target_type")
+		}
+		f.TargetType = &v
+	}
+	return f, nil
+}
+
+func parseAuditPage(q url.Values) (int, int, error) {
+	limit, err := parseStrictPositiveInt(q.Get("limit"), 50, 100)
+	if err != nil {
+		return 0, 0, err
+	}
+	offset, err := parseStrictNonNegativeInt(q.Get("offset"), 0)
+	if err != nil {
+		return 0, 0, err
+	}
+	return limit, offset, nil
+}
+
+func toListAuditEventsResponse(items []repository.AuditEvent, total int64, limit, offset int) listAuditEventsResponse {
+	resp := listAuditEventsResponse{
+		Items:  make([]auditEventResponse, 0, len(items)),
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}
+	for _, item := range items {
+		resp.Items = append(resp.Items, toAuditEventResponse(item))
+	}
+	return resp
+}
+
+func toAuditEventResponse(ev repository.AuditEvent) auditEventResponse {
+	resp := auditEventResponse{
+		ID:         ev.ID,
+		Action:     ev.Action,
+		TargetType: ev.TargetType,
+		CreatedAt:  ev.CreatedAt.UTC().Format(time.RFC3339Nano),
+	}
+	if ev.ActorID.Valid {
+		resp.ActorID = &ev.ActorID.Int64
+	}
+	if ev.TargetID.Valid {
+		resp.TargetID = &ev.TargetID.Int64
+	}
+	if ev.TeamID.Valid {
+		resp.TeamID = &ev.TeamID.Int64
+	}
+	if ev.IP.Valid {
+		resp.IP = &ev.IP.String
+	}
+	if ev.RequestID.Valid {
+		resp.RequestID = &ev.RequestID.String
+	}
+	if len(ev.Payload) > 0 {
+		resp.Payload = ev.Payload
+	}
+	return resp
+}
+
+func auditCSVRecord(ev repository.AuditEvent) []string {
+	nullID := func(v int64, ok bool) string {
+		if !ok {
+			return ""
+		}
+		return strconv.FormatInt(v, 10)
+	}
+	return []string{
+		strconv.FormatInt(ev.ID, 10),
+		ev.CreatedAt.UTC().Format(time.RFC3339Nano),
+		nullID(ev.ActorID.Int64, ev.ActorID.Valid),
+		ev.Action,
+		ev.TargetType,
+		nullID(ev.TargetID.Int64, ev.TargetID.Valid),
+		nullID(ev.TeamID.Int64, ev.TeamID.Valid),
+		ev.IP.String,
+		ev.RequestID.String,
+		string(ev.Payload),
+	}
+}
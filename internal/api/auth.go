@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"errors"
 	"math"
-	"net"
 	"net/http"
 	"strconv"
 	"strings"
@@ -13,6 +12,7 @@ import (
 
 	"github.com/go-sql-driver/mysql"
 
+	"MKK-Luna/internal/api/middleware"
 	"MKK-Luna/internal/domain/ratelimit"
 	authinfra "MKK-Luna/internal/infra/auth"
 	"MKK-Luna/internal/service"
@@ -102,7 +102,7 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 // @Failure 429 {object} response.ErrorResponse
 // @Router /api/v1/login [post]
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
-	ip := clientIP(r)
+	ip := middleware.ClientIP(r)
 	if ok, retry := h.loginLimiter.Allow(r.Context(), ip); !ok {
 		setRetryAfter(w, retry)
 		response.Error(w, http.StatusTooManyRequests, "too many requests")
@@ -189,7 +189,7 @@ func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pair, err := h.auth.Refresh(ctx, req.RefreshToken, clientIP(r), r.UserAgent())
+	pair, err := h.auth.Refresh(ctx, req.RefreshToken, middleware.ClientIP(r), r.UserAgent())
 	if err != nil {
 		if errors.Is(err, service.ErrTokenReuse) || errors.Is(err, service.ErrInvalidToken) {
 			response.Error(w, http.StatusUnauthorized, "invalid token")
@@ -210,20 +210,6 @@ func isDuplicate(err error) bool {
 	return false
 }
 
-func clientIP(r *http.Request) string {
-	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
-		parts := strings.Split(xff, ",")
-		if len(parts) > 0 {
-			return strings.TrimSpace(parts[0])
-		}
-	}
-	host, _, err := net.SplitHostPort(r.RemoteAddr)
-	if err == nil {
-		return host
-	}
-	return r.RemoteAddr
-}
-
 func setRetryAfter(w http.ResponseWriter, d time.Duration) {
 	if d <= 0 {
 		return
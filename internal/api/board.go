@@ -0,0 +1,260 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"MKK-Luna/internal/api/middleware"
+	"MKK-Luna/internal/repository"
+	"MKK-Luna/internal/service"
+	"MKK-Luna/pkg/api/response"
+)
+
+type boardResponse struct {
+	TeamID  int64                 `json:"team_id"`
+	Columns []boardColumnResponse `json:"columns"`
+}
+
+type boardColumnResponse struct {
+	Status   string         `json:"status"`
+	Total    int64          `json:"total"`
+	WIPLimit *int           `json:"wip_limit,omitempty"`
+	Tasks    []taskResponse `json:"tasks"`
+}
+
+type moveTaskRequest struct {
+	Status   string `json:"status"`
+	AfterID  *int64 `json:"after_id"`
+	BeforeID *int64 `json:"before_id"`
+}
+
+type wipLimitsRequest struct {
+	Limits map[string]int `json:"limits"`
+}
+
+type wipLimitsResponse struct {
+	TeamID int64          `json:"team_id"`
+	Limits map[string]int `json:"limits"`
+}
+
+// Board godoc
+// @Summary Get team board
+// @Description Tasks grouped by status in board order; limit applies per column.
+// @Tags board
+// @Produce json
+// @Param id path int true "Team ID"
+// @Param assignee_id query int false "Assignee ID"
+// @Param sprint_id query int false "Sprint ID"
+// @Param limit query int false "Tasks per column (1..200)"
+// @Success 200 {object} boardResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/teams/{id}/board [get]
+func (h *TaskHandler) Board(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	teamID, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || teamID <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	limit, err := parseStrictPositiveInt(r.URL.Query().Get("limit"), 50, 200)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	in := service.BoardInput{TeamID: teamID, Limit: limit}
+	if v := strings.TrimSpace(r.URL.Query().Get("assignee_id")); v != "" {
+		id, err := parseInt64(v)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid request")
+			return
+		}
+		in.AssigneeID = &id
+	}
+	if v := strings.TrimSpace(r.URL.Query().Get("sprint_id")); v != "" {
+		id, err := parseInt64(v)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid request")
+			return
+		}
+		in.SprintID = &id
+	}
+
+	columns, err := h.tasks.GetBoard(ctx, userID, in)
+	if mapServiceError(w, err) {
+		return
+	}
+
+	var tasks []repository.Task
+	for _, col := range columns {
+		for _, t := range col.Tasks {
+			tasks = append(tasks, t.Task)
+		}
+	}
+	users, err := h.tasks.UserSummaries(ctx, taskUserIDs(tasks))
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	checklists, err := h.tasks.ChecklistSummaries(ctx, taskIDs(tasks))
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	resp := boardResponse{TeamID: teamID, Columns: make([]boardColumnResponse, 0, len(columns))}
+	for _, col := range columns {
+		out := boardColumnResponse{
+			Status:   col.Status,
+			Total:    col.Total,
+			WIPLimit: col.WIPLimit,
+			Tasks:    make([]taskResponse, 0, len(col.Tasks)),
+		}
+		for _, t := range col.Tasks {
+			item := withTaskUsers(toTaskResponse(t.Task), users)
+			if c, ok := checklists[t.ID]; ok {
+				summary := toChecklistSummaryResponse(c)
+				item.Checklist = &summary
+			}
+			out.Tasks = append(out.Tasks, item)
+		}
+		resp.Columns = append(resp.Columns, out)
+	}
+	response.JSON(w, http.StatusOK, resp)
+}
+
+// Move godoc
+// @Summary Move task on board
+// @Description Sets status and places the task between after_id (above) and before_id (below); with neither it goes to the bottom of the column.
+// @Description Moving into a column at its WIP limit returns 409.
+// @Tags board
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param If-Match header string false "Task ETag; a stale tag gets 412"
+// @Param request body moveTaskRequest true "Target column and neighbours"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Failure 412 {object} response.ErrorResponse
+// @Router /api/v1/tasks/{id}/move [post]
+func (h *TaskHandler) Move(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	taskID, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || taskID <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	var req moveTaskRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	teamID, err := h.tasks.MoveTask(ctx, userID, taskID, service.BoardMove{
+		Status: req.Status, AfterID: req.AfterID, BeforeID: req.BeforeID,
+	}, parseIfMatch(r))
+	if mapServiceError(w, err) {
+		return
+	}
+	if h.cache != nil {
+		_ = h.cache.InvalidateTeam(ctx, teamID)
+	}
+	response.JSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// WIPLimits godoc
+// @Summary Get board WIP limits
+// @Tags board
+// @Produce json
+// @Param id path int true "Team ID"
+// @Success 200 {object} wipLimitsResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/teams/{id}/board/wip-limits [get]
+func (h *TaskHandler) WIPLimits(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	teamID, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || teamID <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	limits, err := h.tasks.GetWIPLimits(ctx, userID, teamID)
+	if mapServiceError(w, err) {
+		return
+	}
+	response.JSON(w, http.StatusOK, wipLimitsResponse{TeamID: teamID, Limits: limits})
+}
+
+// SetWIPLimits godoc
+// @Summary Replace board WIP limits
+// @Description Owner/admin only. limits maps status to 1..1000; a status left out has no limit.
+// @Tags board
+// @Accept json
+// @Produce json
+// @Param id path int true "Team ID"
+// @Param request body wipLimitsRequest true "Limits"
+// @Success 200 {object} wipLimitsResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/teams/{id}/board/wip-limits [put]
+func (h *TaskHandler) SetWIPLimits(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	teamID, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || teamID <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	var req wipLimitsRequest
+	if err := decodeJSON(r, &req); err != nil || req.Limits == nil {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	if err := h.tasks.SetWIPLimits(ctx, userID, teamID, req.Limits); mapServiceError(w, err) {
+		return
+	}
+	response.JSON(w, http.StatusOK, wipLimitsResponse{TeamID: teamID, Limits: req.Limits})
+}
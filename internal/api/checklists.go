@@ -0,0 +1,364 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"MKK-Luna/internal/api/middleware"
+	"MKK-Luna/internal/repository"
+	"MKK-Luna/internal/service"
+	"MKK-Luna/pkg/api/response"
+)
+
+type checklistItemRequest struct {
+	Body       string `json:"body"`
+	AssigneeID *int64 `json:"assignee_id"`
+}
+
+type addChecklistRequest struct {
+	Items []checklistItemRequest `json:"items"`
+}
+
+// updateChecklistItemRequest: assignee_id null clears the assignee, omitted keeps it.
+type updateChecklistItemRequest struct {
+	Body       *string         `json:"body"`
+	AssigneeID json.RawMessage `json:"assignee_id"`
+}
+
+type toggleChecklistItemRequest struct {
+	Done *bool `json:"done"`
+}
+
+type reorderChecklistRequest struct {
+	ItemIDs []int64 `json:"item_ids"`
+}
+
+type checklistItemResponse struct {
+	ID         int64   `json:"id"`
+	TaskID     int64   `json:"task_id"`
+	Body       string  `json:"body"`
+	Done       bool    `json:"done"`
+	Position   int     `json:"position"`
+	AssigneeID *int64  `json:"assignee_id,omitempty"`
+	DoneBy     *int64  `json:"done_by,omitempty"`
+	DoneAt     *string `json:"done_at,omitempty"`
+	CreatedAt  string  `json:"created_at"`
+	UpdatedAt  string  `json:"updated_at"`
+}
+
+type checklistResponse struct {
+	Items   []checklistItemResponse  `json:"items"`
+	Summary checklistSummaryResponse `json:"summary"`
+}
+
+type checklistSummaryResponse struct {
+	Total             int64 `json:"total"`
+	Done              int64 `json:"done"`
+	CompletionPercent int   `json:"completion_percent"`
+}
+
+// ListChecklist godoc
+// @Summary List task checklist
+// @Tags checklists
+// @Produce json
+// @Param id path int true "Task ID"
+// @Success 200 {object} checklistResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/tasks/{id}/checklist [get]
+func (h *TaskHandler) ListChecklist(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	taskID, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || taskID <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	items, _, err := h.tasks.ListChecklist(ctx, userID, taskID)
+	if mapServiceError(w, err) {
+		return
+	}
+	resp := checklistResponse{Items: make([]checklistItemResponse, 0, len(items))}
+	var summary service.ChecklistSummary
+	for _, item := range items {
+		resp.Items = append(resp.Items, toChecklistItemResponse(item))
+		summary.Total++
+		if item.Done {
+			summary.Done++
+		}
+	}
+	resp.Summary = toChecklistSummaryResponse(summary)
+	response.JSON(w, http.StatusOK, resp)
+}
+
+// AddChecklistItems godoc
+// @Summary Add checklist items
+// @Description Items are appended in order. Owner/admin only; an item assignee must be a team member.
+// @Tags checklists
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param request body addChecklistRequest true "Items"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/tasks/{id}/checklist [post]
+func (h *TaskHandler) AddChecklistItems(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	taskID, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || taskID <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	var req addChecklistRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	in := make([]service.ChecklistItemInput, 0, len(req.Items))
+	for _, item := range req.Items {
+		in = append(in, service.ChecklistItemInput{Body: item.Body, AssigneeID: item.AssigneeID})
+	}
+
+	ids, teamID, err := h.tasks.AddChecklistItems(ctx, userID, taskID, in)
+	if mapServiceError(w, err) {
+		return
+	}
+	if h.cache != nil {
+		_ = h.cache.InvalidateTeam(ctx, teamID)
+	}
+	response.JSON(w, http.StatusCreated, map[string]any{"status": "ok", "ids": ids})
+}
+
+// ReorderChecklist godoc
+// @Summary Reorder checklist
+// @Description item_ids must list every item of the task exactly once. Owner/admin only.
+// @Tags checklists
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param request body reorderChecklistRequest true "New order"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/tasks/{id}/checklist/order [put]
+func (h *TaskHandler) ReorderChecklist(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	taskID, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || taskID <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	var req reorderChecklistRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	teamID, err := h.tasks.ReorderChecklist(ctx, userID, taskID, req.ItemIDs)
+	if mapServiceError(w, err) {
+		return
+	}
+	if h.cache != nil {
+		_ = h.cache.InvalidateTeam(ctx, teamID)
+	}
+	response.JSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// UpdateChecklistItem godoc
+// @Summary Update checklist item
+// @Description Changes body and/or assignee; assignee_id null clears it. Owner/admin only.
+// @Tags checklists
+// @Accept json
+// @Produce json
+// @Param id path int true "Checklist item ID"
+// @Param request body updateChecklistItemRequest true "Changes"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/checklist-items/{id} [patch]
+func (h *TaskHandler) UpdateChecklistItem(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	itemID, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || itemID <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	var req updateChecklistItemRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	patch := service.ChecklistItemPatch{Body: req.Body}
+	if req.AssigneeID != nil {
+		if string(req.AssigneeID) == "null" {
+			patch.ClearAssignee = true
+		} else {
+			var id int64
+			if err := json.Unmarshal(req.AssigneeID, &id); err != nil || id <= 0 {
+				response.Error(w, http.StatusBadRequest, "invalid request")
+				return
+			}
+			patch.AssigneeID = &id
+		}
+	}
+
+	teamID, err := h.tasks.UpdateChecklistItem(ctx, userID, itemID, patch)
+	if mapServiceError(w, err) {
+		return
+	}
+	if h.cache != nil {
+		_ = h.cache.InvalidateTeam(ctx, teamID)
+	}
+	response.JSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// ToggleChecklistItem godoc
+// @Summary Check or uncheck checklist item
+// @Description Any team member may toggle; the change is recorded in task history.
+// @Tags checklists
+// @Accept json
+// @Produce json
+// @Param id path int true "Checklist item ID"
+// @Param request body toggleChecklistItemRequest true "Done flag"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/checklist-items/{id}/toggle [post]
+func (h *TaskHandler) ToggleChecklistItem(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	itemID, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || itemID <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	var req toggleChecklistItemRequest
+	if err := decodeJSON(r, &req); err != nil || req.Done == nil {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	teamID, err := h.tasks.ToggleChecklistItem(ctx, userID, itemID, *req.Done)
+	if mapServiceError(w, err) {
+		return
+	}
+	if h.cache != nil {
+		_ = h.cache.InvalidateTeam(ctx, teamID)
+	}
+	response.JSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// DeleteChecklistItem godoc
+// @Summary Delete checklist item
+// @Description Owner/admin only.
+// @Tags checklists
+// @Produce json
+// @Param id path int true "Checklist item ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/checklist-items/{id} [delete]
+func (h *TaskHandler) DeleteChecklistItem(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	itemID, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || itemID <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	teamID, err := h.tasks.DeleteChecklistItem(ctx, userID, itemID)
+	if mapServiceError(w, err) {
+		return
+	}
+	if h.cache != nil {
+		_ = h.cache.InvalidateTeam(ctx, teamID)
+	}
+	response.JSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func toChecklistItemResponse(item repository.ChecklistItem) checklistItemResponse {
+	resp := checklistItemResponse{
+		ID:        item.ID,
+		TaskID:    item.TaskID,
+		Body:      item.Body,
+		Done:      item.Done,
+		Position:  item.Position,
+		CreatedAt: item.CreatedAt.UTC().Format(time.RFC3339Nano),
+		UpdatedAt: item.UpdatedAt.UTC().Format(time.RFC3339Nano),
+	}
+	if item.AssigneeID.Valid {
+		v := item.AssigneeID.Int64
+		resp.AssigneeID = &v
+	}
+	if item.DoneBy.Valid {
+		v := item.DoneBy.Int64
+		resp.DoneBy = &v
+	}
+	if item.DoneAt.Valid {
+		v := item.DoneAt.Time.UTC().Format(time.RFC3339Nano)
+		resp.DoneAt = &v
+	}
+	return resp
+}
+
+func toChecklistSummaryResponse(s service.ChecklistSummary) checklistSummaryResponse {
+	return checklistSummaryResponse{Total: s.Total, Done: s.Done, CompletionPercent: s.CompletionPercent()}
+}
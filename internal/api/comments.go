@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"strings"
 	"time"
@@ -9,6 +10,7 @@ import (
 	"github.com/go-chi/chi/v5"
 
 	"MKK-Luna/internal/api/middleware"
+	"MKK-Luna/internal/repository"
 	"MKK-Luna/internal/service"
 	"MKK-Luna/pkg/api/response"
 )
@@ -26,16 +28,43 @@ type commentRequest struct {
 }
 
 type commentResponse struct {
+	ID        int64                     `json:"id"`
+	TaskID    int64                     `json:"task_id"`
+	ParentID  *int64                    `json:"parent_id,omitempty"`
+	UserID    *int64                    `json:"user_id,omitempty"`
+	Body      string                    `json:"body"`
+	Edited    bool                      `json:"edited"`
+	EditedAt  *string                   `json:"edited_at,omitempty"`
+	Deleted   bool                      `json:"deleted"`
+	Mentions  []commentMentionResponse  `json:"mentions,omitempty"`
+	Reactions []commentReactionResponse `json:"reactions,omitempty"`
+	ETag      string                    `json:"etag,omitempty"`
+	CreatedAt string                    `json:"created_at"`
+	UpdatedAt string                    `json:"updated_at"`
+}
+
+type commentReactionResponse struct {
+	Emoji   string `json:"emoji"`
+	Count   int64  `json:"count"`
+	Reacted bool   `json:"reacted"`
+}
+
+type commentEditResponse struct {
 	ID        int64  `json:"id"`
-	TaskID    int64  `json:"task_id"`
-	UserID    int64  `json:"user_id"`
+	EditedBy  *int64 `json:"edited_by,omitempty"`
 	Body      string `json:"body"`
 	CreatedAt string `json:"created_at"`
-	UpdatedAt string `json:"updated_at"`
+}
+
+type commentMentionResponse struct {
+	UserID      int64   `json:"user_id"`
+	Username    string  `json:"username"`
+	DisplayName *string `json:"display_name,omitempty"`
 }
 
 // Create godoc
 // @Summary Create comment
+// @Description @username mentions of team members are stored and the mentioned users are notified.
 // @Tags comments
 // @Accept json
 // @Produce json
@@ -83,10 +112,13 @@ func (h *CommentHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 // ListByTask godoc
 // @Summary List comments by task
+// @Description The response ETag hashes the whole listing; each comment's own etag is what If-Match on edits and deletes takes.
 // @Tags comments
 // @Produce json
 // @Param id path int true "Task ID"
+// @Param If-None-Match header string false "ETag from a previous read"
 // @Success 200 {object} map[string]interface{}
+// @Success 304
 // @Failure 400 {object} response.ErrorResponse
 // @Failure 401 {object} response.ErrorResponse
 // @Failure 403 {object} response.ErrorResponse
@@ -116,18 +148,50 @@ func (h *CommentHandler) ListByTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	ids := make([]int64, 0, len(items))
+	for _, c := range items {
+		ids = append(ids, c.ID)
+	}
+	mentions, err := h.tasks.CommentMentions(ctx, ids)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	reactions, err := h.tasks.CommentReactions(ctx, userID, ids)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
 	resp := make([]commentResponse, 0, len(items))
 	for _, c := range items {
-		resp = append(resp, commentResponse{
-			ID:        c.ID,
-			TaskID:    c.TaskID,
-			UserID:    c.UserID,
-			Body:      c.Body,
-			CreatedAt: c.CreatedAt.Format(time.RFC3339Nano),
-			UpdatedAt: c.UpdatedAt.Format(time.RFC3339Nano),
-		})
+		item := toCommentResponse(c)
+		if item.Deleted {
+			resp = append(resp, item)
+			continue
+		}
+		for _, rc := range reactions[c.ID] {
+			item.Reactions = append(item.Reactions, commentReactionResponse{Emoji: rc.Emoji, Count: rc.Count, Reacted: rc.Reacted})
+		}
+		for _, m := range mentions[c.ID] {
+			var displayName *string
+			if m.DisplayName.Valid {
+				displayName = &m.DisplayName.String
+			}
+			item.Mentions = append(item.Mentions, commentMentionResponse{
+				UserID:      m.UserID,
+				Username:    m.Username,
+				DisplayName: displayName,
+			})
+		}
+		resp = append(resp, item)
+	}
+	body, err := json.Marshal(map[string]any{"comments": resp})
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "internal error")
+		return
 	}
-	response.JSON(w, http.StatusOK, map[string]any{"comments": resp})
+	writeTaggedJSON(w, r, body)
 }
 
 // Update godoc
@@ -136,7 +200,9 @@ func (h *CommentHandler) ListByTask(w http.ResponseWriter, r *http.Request) {
 // @Accept json
 // @Produce json
 // @Param id path int true "Comment ID"
+// @Description If-Match takes the etag from the comment listing; a stale tag gets 412.
 // @Param Idempotency-Key header string false "Idempotency key for safe retries"
+// @Param If-Match header string false "Comment ETag"
 // @Param request body commentRequest true "Comment payload"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} response.ErrorResponse
@@ -144,6 +210,7 @@ func (h *CommentHandler) ListByTask(w http.ResponseWriter, r *http.Request) {
 // @Failure 403 {object} response.ErrorResponse
 // @Failure 404 {object} response.ErrorResponse
 // @Failure 409 {object} response.ErrorResponse
+// @Failure 412 {object} response.ErrorResponse
 // @Router /api/v1/comments/{id} [patch]
 func (h *CommentHandler) Update(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
@@ -166,7 +233,7 @@ func (h *CommentHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.tasks.UpdateComment(ctx, userID, commentID, strings.TrimSpace(req.Body)); err != nil {
+	if err := h.tasks.UpdateCommentIfMatch(ctx, userID, commentID, strings.TrimSpace(req.Body), parseIfMatch(r)); err != nil {
 		if mapServiceError(w, err) {
 			return
 		}
@@ -182,12 +249,14 @@ func (h *CommentHandler) Update(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param id path int true "Comment ID"
 // @Param Idempotency-Key header string false "Idempotency key for safe retries"
+// @Param If-Match header string false "Comment ETag"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} response.ErrorResponse
 // @Failure 401 {object} response.ErrorResponse
 // @Failure 403 {object} response.ErrorResponse
 // @Failure 404 {object} response.ErrorResponse
 // @Failure 409 {object} response.ErrorResponse
+// @Failure 412 {object} response.ErrorResponse
 // @Router /api/v1/comments/{id} [delete]
 func (h *CommentHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
@@ -204,7 +273,174 @@ func (h *CommentHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.tasks.DeleteComment(ctx, userID, commentID); err != nil {
+	if err := h.tasks.DeleteCommentIfMatch(ctx, userID, commentID, parseIfMatch(r)); err != nil {
+		if mapServiceError(w, err) {
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	response.JSON(w, http.StatusOK, map[string]any{"status": "ok"})
+}
+
+// Reply godoc
+// @Summary Reply to comment
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param id path int true "Parent comment ID"
+// @Param Idempotency-Key header string false "Idempotency key for safe retries"
+// @Param request body commentRequest true "Comment payload"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/comments/{id}/replies [post]
+func (h *CommentHandler) Reply(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	parentID, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || parentID <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	var req commentRequest
+	if err := decodeJSON(r, &req); err != nil || strings.TrimSpace(req.Body) == "" {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	id, err := h.tasks.ReplyToComment(ctx, userID, parentID, strings.TrimSpace(req.Body))
+	if err != nil {
+		if mapServiceError(w, err) {
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	response.JSON(w, http.StatusCreated, map[string]any{"status": "ok", "id": id})
+}
+
+// Edits godoc
+// @Summary List prior versions of a comment
+// @Tags comments
+// @Produce json
+// @Param id path int true "Comment ID"
+// @Param If-None-Match header string false "ETag from a previous read"
+// @Success 200 {object} map[string]interface{}
+// @Success 304
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/comments/{id}/edits [get]
+func (h *CommentHandler) Edits(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	commentID, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || commentID <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	items, err := h.tasks.ListCommentEdits(ctx, userID, commentID)
+	if err != nil {
+		if mapServiceError(w, err) {
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	resp := make([]commentEditResponse, 0, len(items))
+	for _, e := range items {
+		var editedBy *int64
+		if e.EditedBy.Valid {
+			editedBy = &e.EditedBy.Int64
+		}
+		resp = append(resp, commentEditResponse{
+			ID:        e.ID,
+			EditedBy:  editedBy,
+			Body:      e.Body,
+			CreatedAt: e.CreatedAt.Format(time.RFC3339Nano),
+		})
+	}
+	body, err := json.Marshal(map[string]any{"edits": resp})
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	writeTaggedJSON(w, r, body)
+}
+
+// AddReaction godoc
+// @Summary Add reaction to comment
+// @Tags comments
+// @Produce json
+// @Param id path int true "Comment ID"
+// @Param emoji path string true "Emoji (up to 8 characters)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/comments/{id}/reactions/{emoji} [put]
+func (h *CommentHandler) AddReaction(w http.ResponseWriter, r *http.Request) {
+	h.setReaction(w, r, true)
+}
+
+// RemoveReaction godoc
+// @Summary Remove reaction from comment
+// @Tags comments
+// @Produce json
+// @Param id path int true "Comment ID"
+// @Param emoji path string true "Emoji"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/comments/{id}/reactions/{emoji} [delete]
+func (h *CommentHandler) RemoveReaction(w http.ResponseWriter, r *http.Request) {
+	h.setReaction(w, r, false)
+}
+
+func (h *CommentHandler) setReaction(w http.ResponseWriter, r *http.Request, add bool) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	commentID, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || commentID <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	emoji := chi.URLParam(r, "emoji")
+
+	if add {
+		err = h.tasks.AddReaction(ctx, userID, commentID, emoji)
+	} else {
+		err = h.tasks.RemoveReaction(ctx, userID, commentID, emoji)
+	}
+	if err != nil {
 		if mapServiceError(w, err) {
 			return
 		}
@@ -213,3 +449,38 @@ func (h *CommentHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	}
 	response.JSON(w, http.StatusOK, map[string]any{"status": "ok"})
 }
+
+func toCommentResponse(c repository.TaskComment) commentResponse {
+	var userID *int64
+	if c.UserID.Valid {
+		userID = &c.UserID.Int64
+	}
+	var parentID *int64
+	if c.ParentID.Valid {
+		parentID = &c.ParentID.Int64
+	}
+	var editedAt *string
+	if c.EditedAt.Valid {
+		s := c.EditedAt.Time.Format(time.RFC3339Nano)
+		editedAt = &s
+	}
+	resp := commentResponse{
+		ID:        c.ID,
+		TaskID:    c.TaskID,
+		ParentID:  parentID,
+		UserID:    userID,
+		Body:      c.Body,
+		Edited:    c.EditedAt.Valid,
+		EditedAt:  editedAt,
+		CreatedAt: c.CreatedAt.Format(time.RFC3339Nano),
+		UpdatedAt: c.UpdatedAt.Format(time.RFC3339Nano),
+		ETag:      versionETag(c.Version),
+	}
+	if c.DeletedAt.Valid {
+		resp.Deleted = true
+		resp.Body = ""
+		resp.UserID = nil
+		resp.ETag = ""
+	}
+	return resp
+}
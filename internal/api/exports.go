@@ -0,0 +1,398 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"MKK-Luna/internal/api/middleware"
+	"MKK-Luna/internal/repository"
+	"MKK-Luna/internal/service"
+	"MKK-Luna/pkg/api/response"
+)
+
+type ExportHandler struct {
+	exports       *service.ExportService
+	streamTimeout time.Duration
+}
+
+func NewExportHandler(exports *service.ExportService, streamTimeout time.Duration) *ExportHandler {
+	if streamTimeout <= 0 {
+		streamTimeout = 5 * time.Minute
+	}
+	return &ExportHandler{exports: exports, streamTimeout: streamTimeout}
+}
+
+type exportJobRequest struct {
+	Kind       string  `json:"kind"`
+	Format     string  `json:"format"`
+	Status     *string `json:"status,omitempty"`
+	AssigneeID *int64  `json:"assignee_id,omitempty"`
+	SprintID   *int64  `json:"sprint_id,omitempty"`
+	Sort       string  `json:"sort,omitempty"`
+	From       *string `json:"from,omitempty"`
+	To         *string `json:"to,omitempty"`
+}
+
+type exportJobResponse struct {
+	ID           int64   `json:"id"`
+	TeamID       int64   `json:"team_id"`
+	Kind         string  `json:"kind"`
+	Format       string  `json:"format"`
+	Status       string  `json:"status"`
+	Rows         *int64  `json:"rows,omitempty"`
+	SizeBytes    *int64  `json:"size_bytes,omitempty"`
+	Error        *string `json:"error,omitempty"`
+	DownloadURL  string  `json:"download_url,omitempty"`
+	URLExpiresAt string  `json:"url_expires_at,omitempty"`
+	CreatedAt    string  `json:"created_at"`
+	FinishedAt   *string `json:"finished_at,omitempty"`
+	ExpiresAt    *string `json:"expires_at,omitempty"`
+}
+
+// ExportTasks godoc
+// @Summary Export team tasks
+// @Description Streams the team's tasks as CSV or NDJSON, using the GET /tasks filters and sort.
+// @Description Exports above the configured row limit return 422; create an export job for those.
+// @Tags exports
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Param id path int true "Team ID"
+// @Param format query string false "csv (default) or ndjson"
+// @Param status query string false "Status"
+// @Param assignee_id query int false "Assignee ID"
+// @Param sprint_id query int false "Sprint ID"
+// @Param sort query string false "updated_at, created_at, due_date or priority; prefix - for descending (default -updated_at)"
+// @Success 200 {string} string
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 422 {object} response.ErrorResponse
+// @Router /api/v1/teams/{id}/export/tasks [get]
+func (h *ExportHandler) ExportTasks(w http.ResponseWriter, r *http.Request) {
+	teamID, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || teamID <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	q := r.URL.Query()
+	req := service.ExportRequest{
+		TeamID: teamID, Kind: service.ExportKindTasks, Format: exportFormat(q), Sort: strings.TrimSpace(q.Get("sort")),
+	}
+	if v := strings.TrimSpace(q.Get("status")); v != "" {
+		req.Status = &v
+	}
+	if req.AssigneeID, err = optionalQueryID(q, "assignee_id"); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	if req.SprintID, err = optionalQueryID(q, "sprint_id"); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	h.stream(w, r, req)
+}
+
+// ExportHistory godoc
+// @Summary Export team task history
+// @Description Streams history entries of the team's tasks created in [from, to) as CSV or NDJSON, oldest first.
+// @Tags exports
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Param id path int true "Team ID"
+// @Param format query string false "csv (default) or ndjson"
+// @Param from query string true "RFC3339 UTC from (inclusive)"
+// @Param to query string true "RFC3339 UTC to (exclusive)"
+// @Success 200 {string} string
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 422 {object} response.ErrorResponse
+// @Router /api/v1/teams/{id}/export/history [get]
+func (h *ExportHandler) ExportHistory(w http.ResponseWriter, r *http.Request) {
+	h.streamRange(w, r, service.ExportKindHistory)
+}
+
+// ExportComments godoc
+// @Summary Export team comments
+// @Description Streams comments on the team's tasks created in [from, to) as CSV or NDJSON, oldest first. Deleted comments are left out.
+// @Tags exports
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Param id path int true "Team ID"
+// @Param format query string false "csv (default) or ndjson"
+// @Param from query string true "RFC3339 UTC from (inclusive)"
+// @Param to query string true "RFC3339 UTC to (exclusive)"
+// @Success 200 {string} string
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 422 {object} response.ErrorResponse
+// @Router /api/v1/teams/{id}/export/comments [get]
+func (h *ExportHandler) ExportComments(w http.ResponseWriter, r *http.Request) {
+	h.streamRange(w, r, service.ExportKindComments)
+}
+
+// CreateJob godoc
+// @Summary Start export job
+// @Description Queues an export of any size; poll GET /exports/{id} for a download link. kind is tasks, history or comments.
+// @Description Task jobs take status, assignee_id, sprint_id and sort; history and comment jobs take from and to (RFC3339 UTC).
+// @Tags exports
+// @Accept json
+// @Produce json
+// @Param id path int true "Team ID"
+// @Param request body exportJobRequest true "Export"
+// @Success 202 {object} exportJobResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/teams/{id}/exports [post]
+func (h *ExportHandler) CreateJob(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	teamID, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || teamID <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	var body exportJobRequest
+	if err := decodeJSON(r, &body); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	req := service.ExportRequest{
+		TeamID: teamID, Kind: body.Kind, Format: body.Format, Status: body.Status,
+		AssigneeID: body.AssigneeID, SprintID: body.SprintID, Sort: body.Sort,
+	}
+	if req.Format == "" {
+		req.Format = service.ExportFormatCSV
+	}
+	for _, p := range []struct {
+		raw *string
+		dst **time.Time
+	}{{body.From, &req.From}, {body.To, &req.To}} {
+		if p.raw == nil {
+			continue
+		}
+		tm, err := parseRFC3339UTC(*p.raw)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid request")
+			return
+		}
+		*p.dst = &tm
+	}
+
+	job, err := h.exports.CreateJob(ctx, userID, req)
+	if mapServiceError(w, err) {
+		return
+	}
+	response.JSON(w, http.StatusAccepted, h.toExportJobResponse(*job))
+}
+
+// GetJob godoc
+// @Summary Get export job
+// @Description Visible to the user who started the job. download_url is set once status is done.
+// @Tags exports
+// @Produce json
+// @Param id path int true "Export job ID"
+// @Success 200 {object} exportJobResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/exports/{id} [get]
+func (h *ExportHandler) GetJob(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	id, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || id <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	job, err := h.exports.GetJob(ctx, userID, id)
+	if mapServiceError(w, err) {
+		return
+	}
+	response.JSON(w, http.StatusOK, h.toExportJobResponse(*job))
+}
+
+// Download godoc
+// @Summary Download export file
+// @Description Public route authorised by the signed link from GET /exports/{id}.
+// @Tags exports
+// @Produce octet-stream
+// @Param id path int true "Export job ID"
+// @Param expires query int true "Unix expiry"
+// @Param sig query string true "Signature"
+// @Success 200 {file} file
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/exports/{id}/download [get]
+func (h *ExportHandler) Download(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.streamTimeout)
+	defer cancel()
+
+	id, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || id <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	expires, err := parseInt64(r.URL.Query().Get("expires"))
+	sig := r.URL.Query().Get("sig")
+	if err != nil || sig == "" {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	job, body, err := h.exports.OpenSigned(ctx, id, expires, sig)
+	if errors.Is(err, service.ErrInvalidToken) {
+		response.Error(w, http.StatusUnauthorized, "invalid or expired link")
+		return
+	}
+	if mapServiceError(w, err) {
+		return
+	}
+	defer body.Close()
+
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(h.streamTimeout))
+	w.Header().Set("Content-Type", service.ExportContentType(job.Format))
+	if job.SizeBytes.Valid {
+		w.Header().Set("Content-Length", strconv.FormatInt(job.SizeBytes.Int64, 10))
+	}
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+		"filename": exportFilename(job.TeamID, job.Kind, job.Format),
+	}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.Copy(w, body)
+}
+
+func (h *ExportHandler) streamRange(w http.ResponseWriter, r *http.Request, kind string) {
+	teamID, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || teamID <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	from, to, err := parseFromToUTC(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	h.stream(w, r, service.ExportRequest{TeamID: teamID, Kind: kind, Format: exportFormat(r.URL.Query()), From: &from, To: &to})
+}
+
+func (h *ExportHandler) stream(w http.ResponseWriter, r *http.Request, req service.ExportRequest) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.streamTimeout)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	// The server write timeout is sized for regular requests; an export may take longer.
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(h.streamTimeout))
+	started := false
+	_, err := h.exports.Export(ctx, userID, req, w, func() {
+		started = true
+		w.Header().Set("Content-Type", service.ExportContentType(req.Format))
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+			"filename": exportFilename(req.TeamID, req.Kind, req.Format),
+		}))
+		w.WriteHeader(http.StatusOK)
+	})
+	if started {
+		// The status is already sent; a failure can only cut the body short.
+		return
+	}
+	if errors.Is(err, service.ErrExportTooLarge) {
+		response.Error(w, http.StatusUnprocessableEntity, "export too large, start an export job instead")
+		return
+	}
+	mapServiceError(w, err)
+}
+
+func (h *ExportHandler) toExportJobResponse(j repository.ExportJob) exportJobResponse {
+	resp := exportJobResponse{
+		ID:        j.ID,
+		TeamID:    j.TeamID,
+		Kind:      j.Kind,
+		Format:    j.Format,
+		Status:    j.Status,
+		CreatedAt: j.CreatedAt.UTC().Format(time.RFC3339Nano),
+	}
+	if j.RowCount.Valid {
+		resp.Rows = &j.RowCount.Int64
+	}
+	if j.SizeBytes.Valid {
+		resp.SizeBytes = &j.SizeBytes.Int64
+	}
+	if j.Error.Valid {
+		resp.Error = &j.Error.String
+	}
+	if j.FinishedAt.Valid {
+		v := j.FinishedAt.Time.UTC().Format(time.RFC3339Nano)
+		resp.FinishedAt = &v
+	}
+	if j.ExpiresAt.Valid {
+		v := j.ExpiresAt.Time.UTC().Format(time.RFC3339Nano)
+		resp.ExpiresAt = &v
+	}
+	if j.Status == service.ExportJobDone {
+		expires, sig := h.exports.SignDownload(j.ID)
+		q := url.Values{}
+		q.Set("expires", strconv.FormatInt(expires, 10))
+		q.Set("sig", sig)
+		resp.DownloadURL = "/api/v1/exports/" + strconv.FormatInt(j.ID, 10) + "/download?" + q.Encode()
+		resp.URLExpiresAt = time.Unix(expires, 0).UTC().Format(time.RFC3339Nano)
+	}
+	return resp
+}
+
+func exportFormat(q url.Values) string {
+	if v := q.Get("format"); v != "" {
+		return v
+	}
+	return service.ExportFormatCSV
+}
+
+func exportFilename(teamID int64, kind, format string) string {
+	return "team-" + strconv.FormatInt(teamID, 10) + "-" + kind + "." + format
+}
+
+func optionalQueryID(q url.Values, name string) (*int64, error) {
+	v := strings.TrimSpace(q.Get(name))
+	if v == "" {
+		return nil, nil
+	}
+	id, err := parseInt64(v)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
@@ -4,9 +4,11 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 
+	"MKK-Luna/internal/repository"
 	"MKK-Luna/internal/service"
 )
 
@@ -20,6 +22,7 @@ func TestMapServiceError_AllKnown(t *testing.T) {
 		{name: "forbidden", err: service.ErrForbidden, wantStatus: http.StatusForbidden},
 		{name: "conflict", err: service.ErrConflict, wantStatus: http.StatusConflict},
 		{name: "bad request", err: service.ErrBadRequest, wantStatus: http.StatusBadRequest},
+		{name: "precondition failed", err: service.ErrPreconditionFailed, wantStatus: http.StatusPreconditionFailed},
 		{name: "unknown", err: errors.New("boom"), wantStatus: http.StatusInternalServerError},
 	}
 
@@ -49,3 +52,93 @@ func TestMapServiceError_Nil(t *testing.T) {
 		t.Fatalf("unexpected status for nil mapping: %d", w.Code)
 	}
 }
+
+func TestParseIfMatch(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPut, "/", nil)
+	if cond := parseIfMatch(r); cond != nil {
+		t.Fatalf("expected nil without header, got %+v", cond)
+	}
+
+	r.Header.Set("If-Match", "*")
+	if cond := parseIfMatch(r); cond == nil || !cond.Any {
+		t.Fatalf("expected wildcard, got %+v", cond)
+	}
+
+	r.Header.Set("If-Match", `"3", W/"4", "x", "5-0a1b2c3d4e5f6071"`)
+	cond := parseIfMatch(r)
+	if cond == nil || cond.Any || len(cond.Versions) != 2 || cond.Versions[0] != 3 || cond.Versions[1] != 5 {
+		t.Fatalf("expected strong versions 3 and 5, got %+v", cond)
+	}
+}
+
+func TestETagMatches(t *testing.T) {
+	etag := versionETag(7)
+	if etag != `"7"` {
+		t.Fatalf("etag=%s", etag)
+	}
+	for header, want := range map[string]bool{
+		``:           false,
+		`*`:          true,
+		`"7"`:        true,
+		`W/"7"`:      true,
+		`"6", "7"`:   true,
+		`"6"`:        false,
+		`"77"`:       false,
+		`"6",W/"7" `: true,
+	} {
+		if got := etagMatches(header, etag); got != want {
+			t.Fatalf("etagMatches(%q)=%v want %v", header, got, want)
+		}
+	}
+}
+
+func TestRepresentationETag(t *testing.T) {
+	a := representationETag(7, []byte(`{"watchers":[]}`))
+	b := representationETag(7, []byte(`{"watchers":[{"id":2}]}`))
+	if a == b || !strings.HasPrefix(a, `"7-`) {
+		t.Fatalf("expected distinct tags with version prefix, got %s %s", a, b)
+	}
+	if !etagMatches(a, a) || etagMatches(a, b) {
+		t.Fatalf("unexpected comparison for %s", a)
+	}
+}
+
+func TestApplySavedView(t *testing.T) {
+	view := repository.SavedView{TeamID: 7, Filters: []byte(`{"status":"todo","assignee_id":3}`), Sort: "-priority"}
+
+	query := url.Values{"status": {"done"}}
+	if !applySavedView(query, view) {
+		t.Fatalf("expected view to apply")
+	}
+	if query.Get("team_id") != "7" || query.Get("status") != "done" || query.Get("assignee_id") != "3" || query.Get("sort") != "-priority" {
+		t.Fatalf("unexpected query: %v", query)
+	}
+
+	if applySavedView(url.Values{"team_id": {"8"}}, view) {
+		t.Fatalf("expected team mismatch to be rejected")
+	}
+}
+
+func TestWriteTaggedJSON(t *testing.T) {
+	body := []byte(`{"comments":[]}`)
+	rec := httptest.NewRecorder()
+	writeTaggedJSON(rec, httptest.NewRequest(http.MethodGet, "/", nil), body)
+	etag := rec.Header().Get("ETag")
+	if rec.Code != http.StatusOK || etag != bodyETag(body) || rec.Body.String() != string(body) {
+		t.Fatalf("unexpected first read: %d %q %q", rec.Code, etag, rec.Body.String())
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	writeTaggedJSON(rec, req, body)
+	if rec.Code != http.StatusNotModified || rec.Body.Len() != 0 || rec.Header().Get("ETag") != etag {
+		t.Fatalf("expected 304 with tag, got %d %q", rec.Code, rec.Header().Get("ETag"))
+	}
+
+	rec = httptest.NewRecorder()
+	writeTaggedJSON(rec, req, []byte(`{"comments":[{"id":1}]}`))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("changed body must be served, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,216 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"MKK-Luna/internal/api/middleware"
+	"MKK-Luna/internal/domain/cache"
+	"MKK-Luna/internal/repository"
+	"MKK-Luna/internal/service"
+	"MKK-Luna/pkg/api/response"
+)
+
+type IntegrityHandler struct {
+	integrity *service.IntegrityService
+	cache     cache.TaskCache
+}
+
+func NewIntegrityHandler(integrity *service.IntegrityService, cache cache.TaskCache) *IntegrityHandler {
+	return &IntegrityHandler{integrity: integrity, cache: cache}
+}
+
+type integrityRunRequest struct {
+	Checks []string `json:"checks"`
+	Repair bool     `json:"repair"`
+	DryRun bool     `json:"dry_run"`
+}
+
+type integrityRunResponse struct {
+	ID            int64           `json:"id"`
+	Check         string          `json:"check"`
+	TriggerSource string          `json:"trigger_source"`
+	TriggeredBy   *int64          `json:"triggered_by,omitempty"`
+	Repair        string          `json:"repair"`
+	Found         int             `json:"found"`
+	Repaired      int             `json:"repaired"`
+	Sample        json.RawMessage `json:"sample,omitempty" swaggertype:"array,object"`
+	Error         *string         `json:"error,omitempty"`
+	StartedAt     string          `json:"started_at"`
+	FinishedAt    string          `json:"finished_at"`
+}
+
+type integrityCheckResponse struct {
+	Name        string                `json:"name"`
+	Description string                `json:"description"`
+	LastRun     *integrityRunResponse `json:"last_run,omitempty"`
+}
+
+type integrityChecksResponse struct {
+	Items []integrityCheckResponse `json:"items"`
+}
+
+type integrityRunsResponse struct {
+	Items []integrityRunResponse `json:"items"`
+}
+
+// Checks godoc
+// @Summary Integrity checks
+// @Description system_admin only. Every registered check with its latest run.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} integrityChecksResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Router /api/v1/admin/integrity/checks [get]
+func (h *IntegrityHandler) Checks(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	checks, err := h.integrity.ListChecks(ctx, userID)
+	if err != nil {
+		if mapServiceError(w, err) {
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	resp := integrityChecksResponse{Items: make([]integrityCheckResponse, 0, len(checks))}
+	for _, c := range checks {
+		item := integrityCheckResponse{Name: c.Name, Description: c.Description}
+		if c.LastRun != nil {
+			run := toIntegrityRunResponse(*c.LastRun)
+			item.LastRun = &run
+		}
+		resp.Items = append(resp.Items, item)
+	}
+	response.JSON(w, http.StatusOK, resp)
+}
+
+// Run godoc
+// @Summary Run integrity checks
+// @Description system_admin only. Runs the named checks, or all of them. With repair the findings are fixed; dry_run repairs inside a rolled-back transaction and reports what would change.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body integrityRunRequest true "Checks to run"
+// @Success 200 {object} integrityRunsResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Router /api/v1/admin/integrity/runs [post]
+func (h *IntegrityHandler) Run(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 60*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	var req integrityRunRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	results, err := h.integrity.RunChecks(ctx, userID, req.Checks, req.Repair, req.DryRun)
+	if err != nil {
+		if mapServiceError(w, err) {
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	resp := integrityRunsResponse{Items: make([]integrityRunResponse, 0, len(results))}
+	for _, res := range results {
+		if h.cache != nil {
+			for _, teamID := range res.TeamIDs {
+				_ = h.cache.InvalidateTeam(ctx, teamID)
+			}
+		}
+		resp.Items = append(resp.Items, toIntegrityRunResponse(res.Run))
+	}
+	response.JSON(w, http.StatusOK, resp)
+}
+
+// Runs godoc
+// @Summary Integrity run history
+// @Description system_admin only. Newest first.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param check query string false "Check name"
+// @Param limit query int false "Limit (1..200)"
+// @Success 200 {object} integrityRunsResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Router /api/v1/admin/integrity/runs [get]
+func (h *IntegrityHandler) Runs(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	limit := 0
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 200 {
+			response.Error(w, http.StatusBadRequest, "invalid request")
+			return
+		}
+		limit = n
+	}
+
+	runs, err := h.integrity.ListRuns(ctx, userID, r.URL.Query().Get("check"), limit)
+	if err != nil {
+		if mapServiceError(w, err) {
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	resp := integrityRunsResponse{Items: make([]integrityRunResponse, 0, len(runs))}
+	for _, run := range runs {
+		resp.Items = append(resp.Items, toIntegrityRunResponse(run))
+	}
+	response.JSON(w, http.StatusOK, resp)
+}
+
+func toIntegrityRunResponse(run repository.IntegrityRun) integrityRunResponse {
+	resp := integrityRunResponse{
+		ID:            run.ID,
+		Check:         run.CheckName,
+		TriggerSource: run.TriggerSource,
+		Repair:        run.Repair,
+		Found:         run.Found,
+		Repaired:      run.Repaired,
+		StartedAt:     run.StartedAt.UTC().Format(time.RFC3339),
+		FinishedAt:    run.FinishedAt.UTC().Format(time.RFC3339),
+	}
+	if run.TriggeredBy.Valid {
+		resp.TriggeredBy = &run.TriggeredBy.Int64
+	}
+	if len(run.Sample) > 0 {
+		resp.Sample = json.RawMessage(run.Sample)
+	}
+	if run.Error.Valid {
+		resp.Error = &run.Error.String
+	}
+	return resp
+}
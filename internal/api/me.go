@@ -0,0 +1,329 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"MKK-Luna/internal/api/middleware"
+	"MKK-Luna/internal/repository"
+	"MKK-Luna/internal/service"
+	"MKK-Luna/pkg/api/response"
+)
+
+type MeHandler struct {
+	users *service.UserService
+}
+
+func NewMeHandler(users *service.UserService) *MeHandler {
+	return &MeHandler{users: users}
+}
+
+type profileResponse struct {
+	ID          int64   `json:"id"`
+	Email       string  `json:"email"`
+	Username    string  `json:"username"`
+	DisplayName *string `json:"display_name,omitempty"`
+	AvatarURL   *string `json:"avatar_url,omitempty"`
+	Timezone    string  `json:"timezone"`
+	Locale      string  `json:"locale"`
+	CreatedAt   string  `json:"created_at"`
+	UpdatedAt   string  `json:"updated_at"`
+}
+
+type userSearchItemResponse struct {
+	ID          int64   `json:"id"`
+	Username    string  `json:"username"`
+	Email       string  `json:"email"`
+	DisplayName *string `json:"display_name,omitempty"`
+}
+
+type emailChangeRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type emailConfirmRequest struct {
+	Token string `json:"token"`
+}
+
+type ownershipTransferRequest struct {
+	TeamID int64 `json:"team_id"`
+	UserID int64 `json:"user_id"`
+}
+
+type deleteAccountRequest struct {
+	Password  string                     `json:"password"`
+	Transfers []ownershipTransferRequest `json:"transfers"`
+}
+
+// Get godoc
+// @Summary Get current user profile
+// @Tags me
+// @Produce json
+// @Success 200 {object} profileResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/me [get]
+func (h *MeHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	profile, err := h.users.GetProfile(ctx, userID)
+	if err != nil {
+		if mapServiceError(w, err) {
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	response.JSON(w, http.StatusOK, toProfileResponse(*profile))
+}
+
+// Update godoc
+// @Summary Patch current user profile
+// @Description Accepts display_name, avatar_url, timezone (IANA) and locale.
+// @Tags me
+// @Accept json
+// @Produce json
+// @Param Idempotency-Key header string false "Idempotency key for safe retries"
+// @Param request body map[string]interface{} true "Patch payload"
+// @Success 200 {object} profileResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/me [patch]
+func (h *MeHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&raw); err != nil || len(raw) == 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	if err := h.users.UpdateProfile(ctx, userID, raw); err != nil {
+		if mapServiceError(w, err) {
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	profile, err := h.users.GetProfile(ctx, userID)
+	if err != nil {
+		if mapServiceError(w, err) {
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	response.JSON(w, http.StatusOK, toProfileResponse(*profile))
+}
+
+// SearchUsers godoc
+// @Summary Search users sharing a team with the caller
+// @Description Prefix match on username, email or display name. Only users that share at least one team with the caller are returned.
+// @Tags users
+// @Produce json
+// @Param q query string true "Search prefix"
+// @Param limit query int false "Max results (default 20, max 50)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Router /api/v1/users/search [get]
+func (h *MeHandler) SearchUsers(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	limit, err := parseStrictPositiveInt(r.URL.Query().Get("limit"), 20, 50)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	items, err := h.users.SearchUsers(ctx, userID, r.URL.Query().Get("q"), limit)
+	if err != nil {
+		if mapServiceError(w, err) {
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	resp := make([]userSearchItemResponse, 0, len(items))
+	for _, u := range items {
+		var displayName *string
+		if u.DisplayName.Valid {
+			displayName = &u.DisplayName.String
+		}
+		resp = append(resp, userSearchItemResponse{ID: u.ID, Username: u.Username, Email: u.Email, DisplayName: displayName})
+	}
+	response.JSON(w, http.StatusOK, map[string]any{"users": resp})
+}
+
+// RequestEmailChange godoc
+// @Summary Request email change
+// @Description Sends a verification token to the new address; the email is changed only after confirmation.
+// @Tags me
+// @Accept json
+// @Produce json
+// @Param Idempotency-Key header string false "Idempotency key for safe retries"
+// @Param request body emailChangeRequest true "New email and current password"
+// @Success 202 {object} map[string]interface{}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Failure 503 {object} response.ErrorResponse
+// @Router /api/v1/me/email [post]
+func (h *MeHandler) RequestEmailChange(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req emailChangeRequest
+	if err := decodeJSON(r, &req); err != nil || strings.TrimSpace(req.Email) == "" || req.Password == "" {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	if err := h.users.RequestEmailChange(ctx, userID, req.Email, req.Password); err != nil {
+		if mapServiceError(w, err) {
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	response.JSON(w, http.StatusAccepted, map[string]any{"status": "ok"})
+}
+
+// ConfirmEmailChange godoc
+// @Summary Confirm email change
+// @Tags me
+// @Accept json
+// @Produce json
+// @Param request body emailConfirmRequest true "Verification token"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /api/v1/email/confirm [post]
+func (h *MeHandler) ConfirmEmailChange(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	var req emailConfirmRequest
+	if err := decodeJSON(r, &req); err != nil || strings.TrimSpace(req.Token) == "" {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	if err := h.users.ConfirmEmailChange(ctx, strings.TrimSpace(req.Token)); err != nil {
+		if errors.Is(err, service.ErrInvalidToken) {
+			response.Error(w, http.StatusBadRequest, "invalid token")
+			return
+		}
+		if mapServiceError(w, err) {
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	response.JSON(w, http.StatusOK, map[string]any{"status": "ok"})
+}
+
+// Delete godoc
+// @Summary Delete current user account
+// @Description Revokes all sessions, anonymises comments and deletes the account. Teams where the user is the only owner must be transferred to another member via transfers.
+// @Tags me
+// @Accept json
+// @Produce json
+// @Param request body deleteAccountRequest true "Password and ownership transfers"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /api/v1/me [delete]
+func (h *MeHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req deleteAccountRequest
+	if err := decodeJSON(r, &req); err != nil || req.Password == "" {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	transfers := make(map[int64]int64, len(req.Transfers))
+	for _, t := range req.Transfers {
+		if t.TeamID <= 0 || t.UserID <= 0 {
+			response.Error(w, http.StatusBadRequest, "invalid request")
+			return
+		}
+		transfers[t.TeamID] = t.UserID
+	}
+
+	if err := h.users.DeleteAccount(ctx, userID, req.Password, transfers); err != nil {
+		if mapServiceError(w, err) {
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	response.JSON(w, http.StatusOK, map[string]any{"status": "ok"})
+}
+
+func toProfileResponse(p repository.UserProfile) profileResponse {
+	var displayName *string
+	if p.DisplayName.Valid {
+		displayName = &p.DisplayName.String
+	}
+	var avatarURL *string
+	if p.AvatarURL.Valid {
+		avatarURL = &p.AvatarURL.String
+	}
+	return profileResponse{
+		ID:          p.ID,
+		Email:       p.Email,
+		Username:    p.Username,
+		DisplayName: displayName,
+		AvatarURL:   avatarURL,
+		Timezone:    p.Timezone,
+		Locale:      p.Locale,
+		CreatedAt:   p.CreatedAt.Format(time.RFC3339Nano),
+		UpdatedAt:   p.UpdatedAt.Format(time.RFC3339Nano),
+	}
+}
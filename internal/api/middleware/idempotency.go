@@ -95,7 +95,7 @@ func (m *IdempotencyMiddleware) Handler(next http.Handler) http.Handler {
 
 		reqHash := ideminfra.BuildRequestHash(r.Method, routePattern, r.Header.Get("Content-Type"), r.URL.Query(), body)
 		routeHash := ideminfra.BuildRouteHash(routePattern)
-		responseKey := "idem:resp:" + strconv.FormatInt(userID, 10) + ":" + routeHash + ":" + idemKey
+		responseKey := ideminfra.ResponseKey(userID, routeHash, idemKey)
 		if m.store == nil || m.locker == nil {
 			if m.metrics != nil {
 				m.metrics.IdempotencyBypass.WithLabelValues("unavailable").Inc()
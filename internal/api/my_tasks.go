@@ -0,0 +1,138 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"MKK-Luna/internal/api/middleware"
+	"MKK-Luna/internal/service"
+	"MKK-Luna/pkg/api/response"
+)
+
+type myTasksResponse struct {
+	Items   []taskResponse         `json:"items"`
+	Total   int64                  `json:"total"`
+	Limit   int                    `json:"limit"`
+	Offset  int                    `json:"offset"`
+	Summary myTasksSummaryResponse `json:"summary"`
+}
+
+type myTasksSummaryResponse struct {
+	Total       int64            `json:"total"`
+	ByStatus    map[string]int64 `json:"by_status"`
+	Overdue     int64            `json:"overdue"`
+	DueThisWeek int64            `json:"due_this_week"`
+}
+
+// MyTasks godoc
+// @Summary List my tasks across teams
+// @Description Tasks assigned to or created by the caller in every team they belong to, sorted like team lists.
+// @Description The summary covers the same filters except status; overdue and due_this_week count open tasks, weeks run Monday to Sunday (UTC).
+// @Tags tasks
+// @Produce json
+// @Param relation query string false "assigned, created or empty for both"
+// @Param team_id query int false "Team ID"
+// @Param status query string false "Status"
+// @Param assignee_id query int false "Assignee ID"
+// @Param sprint_id query int false "Sprint ID"
+// @Param sort query string false "updated_at, created_at, due_date or priority; prefix - for descending (default -updated_at)"
+// @Param limit query int false "Limit"
+// @Param offset query int false "Offset"
+// @Success 200 {object} myTasksResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Router /api/v1/me/tasks [get]
+func (h *TaskHandler) MyTasks(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	limit := parseQueryInt(r.URL.Query().Get("limit"), 20)
+	if limit > 100 {
+		limit = 100
+	}
+	offset := parseQueryInt(r.URL.Query().Get("offset"), 0)
+
+	in := service.MyTasksInput{
+		Relation: strings.TrimSpace(r.URL.Query().Get("relation")),
+		Sort:     strings.TrimSpace(r.URL.Query().Get("sort")),
+		Limit:    limit,
+		Offset:   offset,
+	}
+	if v := strings.TrimSpace(r.URL.Query().Get("status")); v != "" {
+		in.Status = &v
+	}
+	if v := strings.TrimSpace(r.URL.Query().Get("team_id")); v != "" {
+		id, err := parseInt64(v)
+		if err != nil || id <= 0 {
+			response.Error(w, http.StatusBadRequest, "invalid request")
+			return
+		}
+		in.TeamID = &id
+	}
+	if v := strings.TrimSpace(r.URL.Query().Get("assignee_id")); v != "" {
+		id, err := parseInt64(v)
+		if err != nil || id <= 0 {
+			response.Error(w, http.StatusBadRequest, "invalid request")
+			return
+		}
+		in.AssigneeID = &id
+	}
+	if v := strings.TrimSpace(r.URL.Query().Get("sprint_id")); v != "" {
+		id, err := parseInt64(v)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid request")
+			return
+		}
+		in.SprintID = &id
+	}
+
+	items, total, summary, err := h.tasks.ListMyTasks(ctx, userID, in)
+	if mapServiceError(w, err) {
+		return
+	}
+	users, err := h.tasks.UserSummaries(ctx, taskUserIDs(items))
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	checklists, err := h.tasks.ChecklistSummaries(ctx, taskIDs(items))
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	resp := myTasksResponse{
+		Items:  make([]taskResponse, 0, len(items)),
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+		Summary: myTasksSummaryResponse{
+			Total: summary.Total,
+			ByStatus: map[string]int64{
+				"todo":        summary.Todo,
+				"in_progress": summary.InProgress,
+				"done":        summary.Done,
+			},
+			Overdue:     summary.Overdue,
+			DueThisWeek: summary.DueThisWeek,
+		},
+	}
+	for _, t := range items {
+		item := withTaskUsers(toTaskResponse(t), users)
+		if c, ok := checklists[t.ID]; ok {
+			s := toChecklistSummaryResponse(c)
+			item.Checklist = &s
+		}
+		resp.Items = append(resp.Items, item)
+	}
+	response.JSON(w, http.StatusOK, resp)
+}
@@ -27,6 +27,51 @@ type Router struct {
 	auth   *service.AuthService
 }
 
+type Option func(*options)
+
+type options struct {
+	users       *service.UserService
+	attachments *service.AttachmentService
+	sprints     *service.SprintService
+	audit       *service.AuditService
+	templates   *service.TaskTemplateService
+	views       *service.SavedViewService
+	exports     *service.ExportService
+	integrity   *service.IntegrityService
+}
+
+func WithUserService(users *service.UserService) Option {
+	return func(o *options) { o.users = users }
+}
+
+func WithAttachmentService(attachments *service.AttachmentService) Option {
+	return func(o *options) { o.attachments = attachments }
+}
+
+func WithSprintService(sprints *service.SprintService) Option {
+	return func(o *options) { o.sprints = sprints }
+}
+
+func WithAuditService(audit *service.AuditService) Option {
+	return func(o *options) { o.audit = audit }
+}
+
+func WithTaskTemplateService(templates *service.TaskTemplateService) Option {
+	return func(o *options) { o.templates = templates }
+}
+
+func WithSavedViewService(views *service.SavedViewService) Option {
+	return func(o *options) { o.views = views }
+}
+
+func WithExportService(exports *service.ExportService) Option {
+	return func(o *options) { o.exports = exports }
+}
+
+func WithIntegrityService(integrity *service.IntegrityService) Option {
+	return func(o *options) { o.integrity = integrity }
+}
+
 func New(
 	cfg *config.Config,
 	logger *slog.Logger,
@@ -41,10 +86,17 @@ func New(
 	idemStore *ideminfra.Store,
 	locker *redislock.Locker,
 	metrics *metricsinfra.Metrics,
+	opts ...Option,
 ) *Router {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	r := chi.NewRouter()
 
 	r.Use(chiMiddleware.RequestID)
+	r.Use(middlewarex.RequestMeta)
 	r.Use(chiMiddleware.Recoverer)
 	r.Use(middlewarex.Logger(logger))
 	r.Use(middlewarex.Metrics(metrics))
@@ -54,6 +106,38 @@ func New(
 	taskHandler := NewTaskHandler(tasks, teams, taskCache)
 	commentHandler := NewCommentHandler(tasks)
 	statsHandler := NewStatsHandler(stats)
+	var meHandler *MeHandler
+	if o.users != nil {
+		meHandler = NewMeHandler(o.users)
+	}
+	var sprintHandler *SprintHandler
+	if o.sprints != nil {
+		sprintHandler = NewSprintHandler(o.sprints, taskCache)
+	}
+	var templateHandler *TaskTemplateHandler
+	if o.templates != nil {
+		templateHandler = NewTaskTemplateHandler(o.templates)
+	}
+	var viewHandler *SavedViewHandler
+	if o.views != nil {
+		viewHandler = NewSavedViewHandler(o.views)
+	}
+	var exportHandler *ExportHandler
+	if o.exports != nil {
+		exportHandler = NewExportHandler(o.exports, cfg.Export.StreamTimeout)
+	}
+	var integrityHandler *IntegrityHandler
+	if o.integrity != nil {
+		integrityHandler = NewIntegrityHandler(o.integrity, taskCache)
+	}
+	var auditHandler *AuditHandler
+	if o.audit != nil {
+		auditHandler = NewAuditHandler(o.audit)
+	}
+	var attachmentHandler *AttachmentHandler
+	if o.attachments != nil {
+		attachmentHandler = NewAttachmentHandler(o.attachments)
+	}
 
 	r.Get("/health", func(w http.ResponseWriter, _ *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -67,6 +151,15 @@ func New(
 		r.Post("/register", authHandler.Register)
 		r.Post("/login", authHandler.Login)
 		r.Post("/refresh", authHandler.Refresh)
+		if meHandler != nil {
+			r.Post("/email/confirm", meHandler.ConfirmEmailChange)
+		}
+		if attachmentHandler != nil {
+			r.Get("/attachments/{id}/download", attachmentHandler.Download)
+		}
+		if exportHandler != nil {
+			r.Get("/exports/{id}/download", exportHandler.Download)
+		}
 
 		r.Group(func(r chi.Router) {
 			r.Use(middlewarex.AuthMiddleware(auth))
@@ -81,25 +174,118 @@ func New(
 			).Handler)
 			r.Use(middlewarex.UserRateLimit(userLimiter, cfg.RateLimit.WindowSeconds, logger))
 
+			if meHandler != nil {
+				r.Get("/me", meHandler.Get)
+				r.Patch("/me", meHandler.Update)
+				r.Delete("/me", meHandler.Delete)
+				r.Post("/me/email", meHandler.RequestEmailChange)
+				r.Get("/users/search", meHandler.SearchUsers)
+			}
+
 			r.Post("/teams", teamHandler.Create)
 			r.Get("/teams", teamHandler.List)
+			r.Get("/teams/{id}/members", teamHandler.Members)
 			r.Post("/teams/{id}/invite", teamHandler.Invite)
+			r.Get("/teams/{id}/trash", taskHandler.Trash)
+			r.Post("/teams/{id}/import", taskHandler.Import)
+			if auditHandler != nil {
+				r.Get("/teams/{id}/audit", auditHandler.TeamAudit)
+			}
+
+			if sprintHandler != nil {
+				r.Post("/teams/{id}/sprints", sprintHandler.Create)
+				r.Get("/teams/{id}/sprints", sprintHandler.List)
+				r.Get("/sprints/{id}", sprintHandler.Get)
+				r.Patch("/sprints/{id}", sprintHandler.Update)
+				r.Delete("/sprints/{id}", sprintHandler.Delete)
+				r.Post("/sprints/{id}/close", sprintHandler.Close)
+			}
+
+			if exportHandler != nil {
+				r.Get("/teams/{id}/export/tasks", exportHandler.ExportTasks)
+				r.Get("/teams/{id}/export/history", exportHandler.ExportHistory)
+				r.Get("/teams/{id}/export/comments", exportHandler.ExportComments)
+				r.Post("/teams/{id}/exports", exportHandler.CreateJob)
+				r.Get("/exports/{id}", exportHandler.GetJob)
+			}
+
+			if viewHandler != nil {
+				r.Post("/teams/{id}/views", viewHandler.Create)
+				r.Get("/teams/{id}/views", viewHandler.List)
+				r.Get("/views/{id}", viewHandler.Get)
+				r.Put("/views/{id}", viewHandler.Update)
+				r.Delete("/views/{id}", viewHandler.Delete)
+			}
+			if templateHandler != nil {
+				r.Post("/teams/{id}/task-templates", templateHandler.Create)
+				r.Get("/teams/{id}/task-templates", templateHandler.List)
+				r.Get("/task-templates/{id}", templateHandler.Get)
+				r.Put("/task-templates/{id}", templateHandler.Update)
+				r.Delete("/task-templates/{id}", templateHandler.Delete)
+				r.Post("/tasks/from-template/{id}", taskHandler.CreateFromTemplate)
+			}
 
 			r.Post("/tasks", taskHandler.Create)
 			r.Get("/tasks", taskHandler.List)
+			r.Get("/me/tasks", taskHandler.MyTasks)
 			r.Get("/tasks/{id}", taskHandler.Get)
 			r.Get("/tasks/{id}/history", taskHandler.History)
+			r.Post("/tasks/{id}/watch", taskHandler.Watch)
+			r.Delete("/tasks/{id}/watch", taskHandler.Unwatch)
 			r.Put("/tasks/{id}", taskHandler.Update)
+			r.Patch("/tasks/{id}", taskHandler.Patch)
 			r.Delete("/tasks/{id}", taskHandler.Delete)
+			r.Post("/tasks/{id}/revert", taskHandler.Revert)
+			r.Post("/tasks/{id}/undelete", taskHandler.Undelete)
+			r.Post("/tasks/{id}/worklogs", taskHandler.LogWork)
+			r.Get("/tasks/{id}/worklogs", taskHandler.ListWorkLogs)
+			r.Delete("/worklogs/{id}", taskHandler.DeleteWorkLog)
+			r.Post("/tasks/{id}/timer/start", taskHandler.StartTimer)
+			r.Post("/tasks/{id}/timer/stop", taskHandler.StopTimer)
+			r.Post("/tasks/{id}/move", taskHandler.Move)
+			r.Get("/teams/{id}/board", taskHandler.Board)
+			r.Get("/teams/{id}/board/wip-limits", taskHandler.WIPLimits)
+			r.Put("/teams/{id}/board/wip-limits", taskHandler.SetWIPLimits)
+			r.Get("/tasks/{id}/checklist", taskHandler.ListChecklist)
+			r.Post("/tasks/{id}/checklist", taskHandler.AddChecklistItems)
+			r.Put("/tasks/{id}/checklist/order", taskHandler.ReorderChecklist)
+			r.Patch("/checklist-items/{id}", taskHandler.UpdateChecklistItem)
+			r.Delete("/checklist-items/{id}", taskHandler.DeleteChecklistItem)
+			r.Post("/checklist-items/{id}/toggle", taskHandler.ToggleChecklistItem)
 
 			r.Post("/tasks/{id}/comments", commentHandler.Create)
 			r.Get("/tasks/{id}/comments", commentHandler.ListByTask)
 			r.Patch("/comments/{id}", commentHandler.Update)
 			r.Delete("/comments/{id}", commentHandler.Delete)
+			r.Post("/comments/{id}/replies", commentHandler.Reply)
+			r.Get("/comments/{id}/edits", commentHandler.Edits)
+			r.Put("/comments/{id}/reactions/{emoji}", commentHandler.AddReaction)
+			r.Delete("/comments/{id}/reactions/{emoji}", commentHandler.RemoveReaction)
+
+			if attachmentHandler != nil {
+				r.Post("/tasks/{id}/attachments", attachmentHandler.Upload)
+				r.Get("/tasks/{id}/attachments", attachmentHandler.List)
+				r.Get("/attachments/{id}", attachmentHandler.Get)
+				r.Delete("/attachments/{id}", attachmentHandler.Delete)
+			}
 
 			r.Get("/stats/teams/done", statsHandler.TeamDoneStats)
+			r.Get("/stats/teams/done/series", statsHandler.TeamDoneSeries)
 			r.Get("/stats/teams/top-creators", statsHandler.TopCreators)
+			r.Get("/stats/teams/logged-time", statsHandler.LoggedTime)
+			r.Get("/stats/teams/flow", statsHandler.TeamFlow)
+			r.Get("/stats/teams/workload", statsHandler.MemberWorkload)
+			r.Get("/stats/sprints/{id}/burndown", statsHandler.SprintBurndown)
 			r.Get("/admin/integrity/tasks", statsHandler.IntegrityTasks)
+			if integrityHandler != nil {
+				r.Get("/admin/integrity/checks", integrityHandler.Checks)
+				r.Post("/admin/integrity/runs", integrityHandler.Run)
+				r.Get("/admin/integrity/runs", integrityHandler.Runs)
+			}
+			if auditHandler != nil {
+				r.Get("/admin/audit", auditHandler.List)
+				r.Get("/admin/audit/export", auditHandler.Export)
+			}
 		})
 	})
 
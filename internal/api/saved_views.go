@@ -0,0 +1,278 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"MKK-Luna/internal/api/middleware"
+	"MKK-Luna/internal/repository"
+	"MKK-Luna/internal/service"
+	"MKK-Luna/pkg/api/response"
+)
+
+type SavedViewHandler struct {
+	views *service.SavedViewService
+}
+
+func NewSavedViewHandler(views *service.SavedViewService) *SavedViewHandler {
+	return &SavedViewHandler{views: views}
+}
+
+type savedViewFiltersPayload struct {
+	Status     *string `json:"status,omitempty"`
+	AssigneeID *int64  `json:"assignee_id,omitempty"`
+	SprintID   *int64  `json:"sprint_id,omitempty"`
+}
+
+type savedViewRequest struct {
+	Name    string                  `json:"name"`
+	Shared  bool                    `json:"shared"`
+	Filters savedViewFiltersPayload `json:"filters"`
+	Sort    string                  `json:"sort"`
+	Columns []string                `json:"columns"`
+}
+
+type savedViewResponse struct {
+	ID        int64                   `json:"id"`
+	TeamID    int64                   `json:"team_id"`
+	Name      string                  `json:"name"`
+	Shared    bool                    `json:"shared"`
+	OwnerID   *int64                  `json:"owner_id,omitempty"`
+	Filters   savedViewFiltersPayload `json:"filters"`
+	Sort      string                  `json:"sort"`
+	Columns   []string                `json:"columns"`
+	CreatedBy *int64                  `json:"created_by,omitempty"`
+	CreatedAt string                  `json:"created_at"`
+	UpdatedAt string                  `json:"updated_at"`
+}
+
+// Create godoc
+// @Summary Create saved view
+// @Description Personal views are visible to their owner only; shared views (shared=true) are visible to the team and created by owners/admins.
+// @Description sort is one of updated_at, created_at, due_date, priority, optionally prefixed with "-" for descending; empty means -updated_at.
+// @Tags views
+// @Accept json
+// @Produce json
+// @Param id path int true "Team ID"
+// @Param request body savedViewRequest true "View"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /api/v1/teams/{id}/views [post]
+func (h *SavedViewHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	teamID, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || teamID <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	var req savedViewRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	id, err := h.views.CreateView(ctx, userID, toSavedViewInput(teamID, req))
+	if mapServiceError(w, err) {
+		return
+	}
+	response.JSON(w, http.StatusCreated, map[string]any{"status": "ok", "id": id})
+}
+
+// List godoc
+// @Summary List saved views
+// @Description The team's shared views followed by the caller's personal ones.
+// @Tags views
+// @Produce json
+// @Param id path int true "Team ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/teams/{id}/views [get]
+func (h *SavedViewHandler) List(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	teamID, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || teamID <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	items, err := h.views.ListViews(ctx, userID, teamID)
+	if mapServiceError(w, err) {
+		return
+	}
+	resp := make([]savedViewResponse, 0, len(items))
+	for _, v := range items {
+		resp = append(resp, toSavedViewResponse(v))
+	}
+	response.JSON(w, http.StatusOK, map[string]any{"views": resp})
+}
+
+// Get godoc
+// @Summary Get saved view
+// @Tags views
+// @Produce json
+// @Param id path int true "View ID"
+// @Success 200 {object} savedViewResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/views/{id} [get]
+func (h *SavedViewHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	viewID, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || viewID <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	view, err := h.views.GetView(ctx, userID, viewID)
+	if mapServiceError(w, err) {
+		return
+	}
+	response.JSON(w, http.StatusOK, toSavedViewResponse(*view))
+}
+
+// Update godoc
+// @Summary Replace saved view
+// @Description Every field is replaced. Shared views, and sharing or unsharing a view, need owner/admin.
+// @Tags views
+// @Accept json
+// @Produce json
+// @Param id path int true "View ID"
+// @Param request body savedViewRequest true "View"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /api/v1/views/{id} [put]
+func (h *SavedViewHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	viewID, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || viewID <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	var req savedViewRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	if mapServiceError(w, h.views.UpdateView(ctx, userID, viewID, toSavedViewInput(0, req))) {
+		return
+	}
+	response.JSON(w, http.StatusOK, map[string]any{"status": "ok"})
+}
+
+// Delete godoc
+// @Summary Delete saved view
+// @Tags views
+// @Produce json
+// @Param id path int true "View ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/views/{id} [delete]
+func (h *SavedViewHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	viewID, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || viewID <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	if mapServiceError(w, h.views.DeleteView(ctx, userID, viewID)) {
+		return
+	}
+	response.JSON(w, http.StatusOK, map[string]any{"status": "ok"})
+}
+
+func toSavedViewInput(teamID int64, req savedViewRequest) service.SavedViewInput {
+	return service.SavedViewInput{
+		TeamID: teamID,
+		Name:   req.Name,
+		Shared: req.Shared,
+		Filters: service.SavedViewFilters{
+			Status:     req.Filters.Status,
+			AssigneeID: req.Filters.AssigneeID,
+			SprintID:   req.Filters.SprintID,
+		},
+		Sort:    req.Sort,
+		Columns: req.Columns,
+	}
+}
+
+func toSavedViewResponse(v repository.SavedView) savedViewResponse {
+	f := service.SavedViewFiltersOf(v)
+	resp := savedViewResponse{
+		ID:        v.ID,
+		TeamID:    v.TeamID,
+		Name:      v.Name,
+		Shared:    !v.OwnerID.Valid,
+		Filters:   savedViewFiltersPayload{Status: f.Status, AssigneeID: f.AssigneeID, SprintID: f.SprintID},
+		Sort:      v.Sort,
+		Columns:   service.SavedViewColumnsOf(v),
+		CreatedAt: v.CreatedAt.UTC().Format(time.RFC3339Nano),
+		UpdatedAt: v.UpdatedAt.UTC().Format(time.RFC3339Nano),
+	}
+	if v.OwnerID.Valid {
+		id := v.OwnerID.Int64
+		resp.OwnerID = &id
+	}
+	if v.CreatedBy.Valid {
+		id := v.CreatedBy.Int64
+		resp.CreatedBy = &id
+	}
+	return resp
+}
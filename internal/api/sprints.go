@@ -0,0 +1,390 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"MKK-Luna/internal/api/middleware"
+	"MKK-Luna/internal/domain/cache"
+	"MKK-Luna/internal/repository"
+	"MKK-Luna/internal/service"
+	"MKK-Luna/pkg/api/response"
+)
+
+type SprintHandler struct {
+	sprints *service.SprintService
+	cache   cache.TaskCache
+}
+
+func NewSprintHandler(sprints *service.SprintService, cache cache.TaskCache) *SprintHandler {
+	return &SprintHandler{sprints: sprints, cache: cache}
+}
+
+type createSprintRequest struct {
+	Name     string `json:"name"`
+	Goal     string `json:"goal"`
+	StartsOn string `json:"starts_on"`
+	EndsOn   string `json:"ends_on"`
+}
+
+type updateSprintRequest struct {
+	Name     *string `json:"name"`
+	Goal     *string `json:"goal"`
+	StartsOn *string `json:"starts_on"`
+	EndsOn   *string `json:"ends_on"`
+}
+
+type closeSprintRequest struct {
+	RolloverSprintID *int64 `json:"rollover_sprint_id"`
+}
+
+type sprintResponse struct {
+	ID         int64   `json:"id"`
+	TeamID     int64   `json:"team_id"`
+	Name       string  `json:"name"`
+	Goal       *string `json:"goal,omitempty"`
+	StartsOn   string  `json:"starts_on"`
+	EndsOn     string  `json:"ends_on"`
+	Status     string  `json:"status"`
+	ClosedAt   *string `json:"closed_at,omitempty"`
+	CreatedBy  *int64  `json:"created_by,omitempty"`
+	TasksTotal int64   `json:"tasks_total"`
+	TasksDone  int64   `json:"tasks_done"`
+	CreatedAt  string  `json:"created_at"`
+	UpdatedAt  string  `json:"updated_at"`
+}
+
+type closeSprintResponse struct {
+	Status           string  `json:"status"`
+	MovedTaskIDs     []int64 `json:"moved_task_ids"`
+	RolloverSprintID *int64  `json:"rollover_sprint_id,omitempty"`
+}
+
+// Create godoc
+// @Summary Create sprint
+// @Description Owners and admins only. Dates are YYYY-MM-DD; ends_on is inclusive.
+// @Tags sprints
+// @Accept json
+// @Produce json
+// @Param id path int true "Team ID"
+// @Param Idempotency-Key header string false "Idempotency key for safe retries"
+// @Param request body createSprintRequest true "Sprint"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/teams/{id}/sprints [post]
+func (h *SprintHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	teamID, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || teamID <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	var req createSprintRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	startsOn, err1 := time.Parse("2006-01-02", req.StartsOn)
+	endsOn, err2 := time.Parse("2006-01-02", req.EndsOn)
+	if err1 != nil || err2 != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	id, err := h.sprints.CreateSprint(ctx, userID, service.SprintInput{
+		TeamID:   teamID,
+		Name:     req.Name,
+		Goal:     req.Goal,
+		StartsOn: startsOn,
+		EndsOn:   endsOn,
+	})
+	if mapServiceError(w, err) {
+		return
+	}
+	response.JSON(w, http.StatusCreated, map[string]any{"status": "ok", "id": id})
+}
+
+// List godoc
+// @Summary List team sprints
+// @Tags sprints
+// @Produce json
+// @Param id path int true "Team ID"
+// @Param include_closed query bool false "Include closed sprints"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/teams/{id}/sprints [get]
+func (h *SprintHandler) List(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	teamID, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || teamID <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	includeClosed := false
+	switch r.URL.Query().Get("include_closed") {
+	case "", "false":
+	case "true":
+		includeClosed = true
+	default:
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	items, err := h.sprints.ListSprints(ctx, userID, teamID, includeClosed)
+	if mapServiceError(w, err) {
+		return
+	}
+	ids := make([]int64, 0, len(items))
+	for _, s := range items {
+		ids = append(ids, s.ID)
+	}
+	counts, err := h.sprints.SprintTaskCounts(ctx, ids)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	now := time.Now().UTC()
+	resp := make([]sprintResponse, 0, len(items))
+	for _, s := range items {
+		resp = append(resp, toSprintResponse(s, counts[s.ID], now))
+	}
+	response.JSON(w, http.StatusOK, map[string]any{"sprints": resp})
+}
+
+// Get godoc
+// @Summary Get sprint
+// @Tags sprints
+// @Produce json
+// @Param id path int true "Sprint ID"
+// @Success 200 {object} sprintResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/sprints/{id} [get]
+func (h *SprintHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	sprintID, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || sprintID <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	sprint, err := h.sprints.GetSprint(ctx, userID, sprintID)
+	if mapServiceError(w, err) {
+		return
+	}
+	counts, err := h.sprints.SprintTaskCounts(ctx, []int64{sprintID})
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	response.JSON(w, http.StatusOK, toSprintResponse(*sprint, counts[sprintID], time.Now().UTC()))
+}
+
+// Update godoc
+// @Summary Update sprint
+// @Description Owners and admins only; closed sprints are read-only. An empty goal clears it.
+// @Tags sprints
+// @Accept json
+// @Produce json
+// @Param id path int true "Sprint ID"
+// @Param Idempotency-Key header string false "Idempotency key for safe retries"
+// @Param request body updateSprintRequest true "Sprint patch"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /api/v1/sprints/{id} [patch]
+func (h *SprintHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	sprintID, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || sprintID <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	var req updateSprintRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	in := service.SprintUpdateInput{Name: req.Name, Goal: req.Goal}
+	if req.StartsOn != nil {
+		tm, err := time.Parse("2006-01-02", *req.StartsOn)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid request")
+			return
+		}
+		in.StartsOn = &tm
+	}
+	if req.EndsOn != nil {
+		tm, err := time.Parse("2006-01-02", *req.EndsOn)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid request")
+			return
+		}
+		in.EndsOn = &tm
+	}
+
+	if mapServiceError(w, h.sprints.UpdateSprint(ctx, userID, sprintID, in)) {
+		return
+	}
+	response.JSON(w, http.StatusOK, map[string]any{"status": "ok"})
+}
+
+// Delete godoc
+// @Summary Delete sprint
+// @Description Owners and admins only. Tasks in the sprint return to the backlog.
+// @Tags sprints
+// @Param id path int true "Sprint ID"
+// @Success 204
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /api/v1/sprints/{id} [delete]
+func (h *SprintHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	sprintID, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || sprintID <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	teamID, err := h.sprints.DeleteSprint(ctx, userID, sprintID)
+	if mapServiceError(w, err) {
+		return
+	}
+	if h.cache != nil {
+		_ = h.cache.InvalidateTeam(ctx, teamID)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Close godoc
+// @Summary Close sprint
+// @Description Owners and admins only. Tasks that are not done move to rollover_sprint_id, or to the backlog when it is omitted.
+// @Tags sprints
+// @Accept json
+// @Produce json
+// @Param id path int true "Sprint ID"
+// @Param Idempotency-Key header string false "Idempotency key for safe retries"
+// @Param request body closeSprintRequest false "Rollover target"
+// @Success 200 {object} closeSprintResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /api/v1/sprints/{id}/close [post]
+func (h *SprintHandler) Close(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	sprintID, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || sprintID <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	var req closeSprintRequest
+	if err := decodeJSON(r, &req); err != nil && !errors.Is(err, io.EOF) {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	teamID, res, err := h.sprints.CloseSprint(ctx, userID, sprintID, req.RolloverSprintID)
+	if mapServiceError(w, err) {
+		return
+	}
+	if h.cache != nil {
+		_ = h.cache.InvalidateTeam(ctx, teamID)
+	}
+	moved := res.MovedTaskIDs
+	if moved == nil {
+		moved = []int64{}
+	}
+	response.JSON(w, http.StatusOK, closeSprintResponse{Status: "ok", MovedTaskIDs: moved, RolloverSprintID: res.RolloverTo})
+}
+
+func toSprintResponse(s repository.Sprint, counts repository.SprintTaskCounts, now time.Time) sprintResponse {
+	resp := sprintResponse{
+		ID:         s.ID,
+		TeamID:     s.TeamID,
+		Name:       s.Name,
+		StartsOn:   s.StartsOn.Format("2006-01-02"),
+		EndsOn:     s.EndsOn.Format("2006-01-02"),
+		Status:     service.SprintStatus(s, now),
+		TasksTotal: counts.TotalCount,
+		TasksDone:  counts.DoneCount,
+		CreatedAt:  s.CreatedAt.Format(time.RFC3339Nano),
+		UpdatedAt:  s.UpdatedAt.Format(time.RFC3339Nano),
+	}
+	if s.Goal.Valid {
+		resp.Goal = &s.Goal.String
+	}
+	if s.ClosedAt.Valid {
+		v := s.ClosedAt.Time.Format(time.RFC3339Nano)
+		resp.ClosedAt = &v
+	}
+	if s.CreatedBy.Valid {
+		resp.CreatedBy = &s.CreatedBy.Int64
+	}
+	return resp
+}
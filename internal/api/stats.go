@@ -7,6 +7,8 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/go-chi/chi/v5"
+
 	"MKK-Luna/internal/api/middleware"
 	"MKK-Luna/internal/repository"
 	"MKK-Luna/internal/service"
@@ -43,6 +45,106 @@ type teamTopCreatorsResponse struct {
 	Items []teamTopCreatorResponse `json:"items"`
 }
 
+type loggedTimeStatResponse struct {
+	TeamID       int64 `json:"team_id"`
+	UserID       int64 `json:"user_id"`
+	Minutes      int64 `json:"minutes"`
+	EntriesCount int64 `json:"entries_count"`
+	TasksCount   int64 `json:"tasks_count"`
+}
+
+type loggedTimeResponse struct {
+	Items []loggedTimeStatResponse `json:"items"`
+}
+
+type durationPercentilesResponse struct {
+	Count int     `json:"count"`
+	P50   float64 `json:"p50_hours"`
+	P85   float64 `json:"p85_hours"`
+	P95   float64 `json:"p95_hours"`
+}
+
+type throughputPointResponse struct {
+	WeekStart string `json:"week_start"`
+	Done      int64  `json:"done"`
+}
+
+type wipAgeBucketResponse struct {
+	Age   string `json:"age"`
+	Count int64  `json:"count"`
+}
+
+type teamFlowStatResponse struct {
+	TeamID       int64                       `json:"team_id"`
+	TeamName     string                      `json:"team_name"`
+	CycleTime    durationPercentilesResponse `json:"cycle_time"`
+	LeadTime     durationPercentilesResponse `json:"lead_time"`
+	Throughput   []throughputPointResponse   `json:"throughput"`
+	WIP          int64                       `json:"wip"`
+	WIPAge       []wipAgeBucketResponse      `json:"wip_age"`
+	DueCount     int64                       `json:"due_count"`
+	OverdueCount int64                       `json:"overdue_count"`
+	OverdueRate  float64                     `json:"overdue_rate"`
+}
+
+type teamFlowStatsResponse struct {
+	Items []teamFlowStatResponse `json:"items"`
+}
+
+type seriesPointResponse struct {
+	Start string `json:"start"`
+	Done  int64  `json:"done"`
+}
+
+type teamDoneSeriesResponse struct {
+	TeamID   int64                 `json:"team_id"`
+	TeamName string                `json:"team_name"`
+	Points   []seriesPointResponse `json:"points"`
+}
+
+type teamDoneSeriesListResponse struct {
+	Bucket string                   `json:"bucket"`
+	TZ     string                   `json:"tz"`
+	Items  []teamDoneSeriesResponse `json:"items"`
+}
+
+type openLoadResponse struct {
+	High   int64 `json:"high"`
+	Medium int64 `json:"medium"`
+	Low    int64 `json:"low"`
+}
+
+type memberWorkloadResponse struct {
+	TeamID             int64            `json:"team_id"`
+	UserID             int64            `json:"user_id"`
+	Username           string           `json:"username"`
+	Role               string           `json:"role"`
+	AssignedCount      int64            `json:"assigned_count"`
+	CompletedCount     int64            `json:"completed_count"`
+	AvgCompletionHours *float64         `json:"avg_completion_hours,omitempty"`
+	CommentsCount      int64            `json:"comments_count"`
+	OpenLoad           openLoadResponse `json:"open_load"`
+}
+
+type memberWorkloadsResponse struct {
+	Items []memberWorkloadResponse `json:"items"`
+}
+
+type burndownPointResponse struct {
+	Date           string  `json:"date"`
+	Scope          int64   `json:"scope"`
+	Completed      int64   `json:"completed"`
+	Remaining      int64   `json:"remaining"`
+	IdealRemaining float64 `json:"ideal_remaining"`
+}
+
+type sprintBurndownResponse struct {
+	SprintID int64                   `json:"sprint_id"`
+	StartsOn string                  `json:"starts_on"`
+	EndsOn   string                  `json:"ends_on"`
+	Items    []burndownPointResponse `json:"items"`
+}
+
 type taskIntegrityIssueResponse struct {
 	TaskID     int64 `json:"task_id"`
 	TeamID     int64 `json:"team_id"`
@@ -98,6 +200,76 @@ func (h *StatsHandler) TeamDoneStats(w http.ResponseWriter, r *http.Request) {
 	response.JSON(w, http.StatusOK, resp)
 }
 
+// TeamDoneSeries godoc
+// @Summary Team done stats as a time series
+// @Description Same count as /stats/teams/done, split into buckets starting at local midnight in tz (weeks start on Monday). Buckets without completions are returned with done=0; the first and last buckets only count the part inside [from, to).
+// @Tags stats
+// @Produce json
+// @Security BearerAuth
+// @Param from query string true "RFC3339 UTC from"
+// @Param to query string true "RFC3339 UTC to"
+// @Param bucket query string false "day (default), week or month"
+// @Param tz query string false "IANA time zone, default UTC"
+// @Param team_id query int false "Team ID"
+// @Success 200 {object} teamDoneSeriesListResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /api/v1/stats/teams/done/series [get]
+func (h *StatsHandler) TeamDoneSeries(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	from, to, err := parseFromToUTC(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	q := r.URL.Query()
+	bucket := q.Get("bucket")
+	if bucket == "" {
+		bucket = service.StatsBucketDay
+	}
+	tz := q.Get("tz")
+	if tz == "" {
+		tz = "UTC"
+	}
+	var teamID int64
+	if v := q.Get("team_id"); v != "" {
+		teamID, err = parseInt64(v)
+		if err != nil || teamID <= 0 {
+			response.Error(w, http.StatusBadRequest, "invalid request")
+			return
+		}
+	}
+
+	rows, err := h.stats.GetTeamDoneSeries(ctx, userID, from, to, bucket, tz, teamID)
+	if err != nil {
+		if mapServiceError(w, err) {
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	resp := teamDoneSeriesListResponse{Bucket: bucket, TZ: tz, Items: make([]teamDoneSeriesResponse, 0, len(rows))}
+	for _, row := range rows {
+		item := teamDoneSeriesResponse{TeamID: row.TeamID, TeamName: row.TeamName, Points: make([]seriesPointResponse, 0, len(row.Points))}
+		for _, p := range row.Points {
+			item.Points = append(item.Points, seriesPointResponse{Start: p.Start.Format(time.RFC3339), Done: p.Count})
+		}
+		resp.Items = append(resp.Items, item)
+	}
+	response.JSON(w, http.StatusOK, resp)
+}
+
 // TopCreators godoc
 // @Summary Top creators by team
 // @Tags stats
@@ -149,6 +321,237 @@ func (h *StatsHandler) TopCreators(w http.ResponseWriter, r *http.Request) {
 	response.JSON(w, http.StatusOK, resp)
 }
 
+// LoggedTime godoc
+// @Summary Logged time by team and user
+// @Description Sums work logs whose work_date falls in [from, to) for teams where the caller is owner/admin.
+// @Tags stats
+// @Produce json
+// @Security BearerAuth
+// @Param from query string true "RFC3339 UTC from"
+// @Param to query string true "RFC3339 UTC to"
+// @Param team_id query int false "Team ID"
+// @Param user_id query int false "User ID"
+// @Success 200 {object} loggedTimeResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /api/v1/stats/teams/logged-time [get]
+func (h *StatsHandler) LoggedTime(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	from, to, err := parseFromToUTC(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	var filter repository.LoggedTimeFilter
+	if v := r.URL.Query().Get("team_id"); v != "" {
+		id, err := parseInt64(v)
+		if err != nil || id <= 0 {
+			response.Error(w, http.StatusBadRequest, "invalid request")
+			return
+		}
+		filter.TeamID = &id
+	}
+	if v := r.URL.Query().Get("user_id"); v != "" {
+		id, err := parseInt64(v)
+		if err != nil || id <= 0 {
+			response.Error(w, http.StatusBadRequest, "invalid request")
+			return
+		}
+		filter.UserID = &id
+	}
+
+	rows, err := h.stats.GetLoggedTime(ctx, userID, from, to, filter)
+	if err != nil {
+		if mapServiceError(w, err) {
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	resp := loggedTimeResponse{Items: make([]loggedTimeStatResponse, 0, len(rows))}
+	for _, row := range rows {
+		resp.Items = append(resp.Items, loggedTimeStatResponse{
+			TeamID:       row.TeamID,
+			UserID:       row.UserID,
+			Minutes:      row.Minutes,
+			EntriesCount: row.EntriesCount,
+			TasksCount:   row.TasksCount,
+		})
+	}
+	response.JSON(w, http.StatusOK, resp)
+}
+
+// TeamFlow godoc
+// @Summary Team flow metrics
+// @Description For teams where the caller is owner/admin. Cycle time runs from the first move to in_progress to done, lead time from creation to done, both for tasks done within the window. Throughput is weekly (Mondays, UTC). WIP counts in_progress tasks now, bucketed by time since they entered in_progress. overdue_rate is the share of tasks due within the window that were done late or are still open past their due date.
+// @Tags stats
+// @Produce json
+// @Security BearerAuth
+// @Param from query string true "RFC3339 UTC from"
+// @Param to query string true "RFC3339 UTC to"
+// @Param team_id query int false "Team ID"
+// @Success 200 {object} teamFlowStatsResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /api/v1/stats/teams/flow [get]
+func (h *StatsHandler) TeamFlow(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	from, to, err := parseFromToUTC(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	var teamID int64
+	if v := r.URL.Query().Get("team_id"); v != "" {
+		teamID, err = parseInt64(v)
+		if err != nil || teamID <= 0 {
+			response.Error(w, http.StatusBadRequest, "invalid request")
+			return
+		}
+	}
+
+	rows, err := h.stats.GetTeamFlowStats(ctx, userID, from, to, teamID)
+	if err != nil {
+		if mapServiceError(w, err) {
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	resp := teamFlowStatsResponse{Items: make([]teamFlowStatResponse, 0, len(rows))}
+	for _, row := range rows {
+		resp.Items = append(resp.Items, toTeamFlowStatResponse(row))
+	}
+	response.JSON(w, http.StatusOK, resp)
+}
+
+// MemberWorkload godoc
+// @Summary Workload per team member
+// @Description For teams where the caller is owner/admin, one row per current member. assigned_count = tasks assigned to the member within the window; completed_count and avg_completion_hours (created to done) = tasks they hold that were done within the window; comments_count = comments written within the window; open_load = their unfinished tasks now, by priority.
+// @Tags stats
+// @Produce json
+// @Security BearerAuth
+// @Param from query string true "RFC3339 UTC from"
+// @Param to query string true "RFC3339 UTC to"
+// @Param team_id query int false "Team ID"
+// @Success 200 {object} memberWorkloadsResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 500 {object} response.ErrorResponse
+// @Router /api/v1/stats/teams/workload [get]
+func (h *StatsHandler) MemberWorkload(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	from, to, err := parseFromToUTC(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	var teamID int64
+	if v := r.URL.Query().Get("team_id"); v != "" {
+		teamID, err = parseInt64(v)
+		if err != nil || teamID <= 0 {
+			response.Error(w, http.StatusBadRequest, "invalid request")
+			return
+		}
+	}
+
+	rows, err := h.stats.GetMemberWorkload(ctx, userID, from, to, teamID)
+	if err != nil {
+		if mapServiceError(w, err) {
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	resp := memberWorkloadsResponse{Items: make([]memberWorkloadResponse, 0, len(rows))}
+	for _, row := range rows {
+		resp.Items = append(resp.Items, toMemberWorkloadResponse(row))
+	}
+	response.JSON(w, http.StatusOK, resp)
+}
+
+// SprintBurndown godoc
+// @Summary Sprint burndown and burnup series
+// @Description One point per sprint day (up to today or the close time), replayed from task_history. scope/completed give the burnup, remaining the burndown.
+// @Tags stats
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Sprint ID"
+// @Success 200 {object} sprintBurndownResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/stats/sprints/{id}/burndown [get]
+func (h *StatsHandler) SprintBurndown(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	sprintID, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || sprintID <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	sprint, points, err := h.stats.GetSprintBurndown(ctx, userID, sprintID)
+	if mapServiceError(w, err) {
+		return
+	}
+	resp := sprintBurndownResponse{
+		SprintID: sprint.ID,
+		StartsOn: sprint.StartsOn.Format("2006-01-02"),
+		EndsOn:   sprint.EndsOn.Format("2006-01-02"),
+		Items:    make([]burndownPointResponse, 0, len(points)),
+	}
+	for _, p := range points {
+		resp.Items = append(resp.Items, burndownPointResponse{
+			Date:           p.Date.Format("2006-01-02"),
+			Scope:          p.Scope,
+			Completed:      p.Completed,
+			Remaining:      p.Remaining,
+			IdealRemaining: p.IdealRemaining,
+		})
+	}
+	response.JSON(w, http.StatusOK, resp)
+}
+
 // IntegrityTasks godoc
 // @Summary Integrity issues for tasks
 // @Tags admin
@@ -242,6 +645,45 @@ func toTeamTopCreatorResponse(s repository.TeamTopCreator) teamTopCreatorRespons
 	}
 }
 
+func toTeamFlowStatResponse(s repository.TeamFlowStat) teamFlowStatResponse {
+	resp := teamFlowStatResponse{
+		TeamID:       s.TeamID,
+		TeamName:     s.TeamName,
+		CycleTime:    durationPercentilesResponse(s.CycleTime),
+		LeadTime:     durationPercentilesResponse(s.LeadTime),
+		Throughput:   make([]throughputPointResponse, 0, len(s.Throughput)),
+		WIP:          s.WIP,
+		WIPAge:       make([]wipAgeBucketResponse, 0, len(s.WIPAge)),
+		DueCount:     s.DueCount,
+		OverdueCount: s.OverdueCount,
+		OverdueRate:  s.OverdueRate,
+	}
+	for _, p := range s.Throughput {
+		resp.Throughput = append(resp.Throughput, throughputPointResponse{WeekStart: p.WeekStart.Format("2006-01-02"), Done: p.Done})
+	}
+	for _, b := range s.WIPAge {
+		resp.WIPAge = append(resp.WIPAge, wipAgeBucketResponse{Age: b.Label, Count: b.Count})
+	}
+	return resp
+}
+
+func toMemberWorkloadResponse(s repository.MemberWorkloadStat) memberWorkloadResponse {
+	resp := memberWorkloadResponse{
+		TeamID:         s.TeamID,
+		UserID:         s.UserID,
+		Username:       s.Username,
+		Role:           s.Role,
+		AssignedCount:  s.AssignedCount,
+		CompletedCount: s.CompletedCount,
+		CommentsCount:  s.CommentsCount,
+		OpenLoad:       openLoadResponse{High: s.OpenHigh, Medium: s.OpenMedium, Low: s.OpenLow},
+	}
+	if s.AvgCompletionHours.Valid {
+		resp.AvgCompletionHours = &s.AvgCompletionHours.Float64
+	}
+	return resp
+}
+
 func toTaskIntegrityResponse(s repository.TaskIntegrityIssue) taskIntegrityIssueResponse {
 	return taskIntegrityIssueResponse{
 		TaskID:     s.TaskID,
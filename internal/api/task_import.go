@@ -0,0 +1,166 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"MKK-Luna/internal/api/middleware"
+	"MKK-Luna/internal/service"
+	"MKK-Luna/pkg/api/response"
+)
+
+const importMaxBytes = 10 << 20
+
+type importRowResponse struct {
+	Row        int      `json:"row"`
+	ExternalID string   `json:"external_id"`
+	Action     string   `json:"action"`
+	TaskID     *int64   `json:"task_id,omitempty"`
+	Errors     []string `json:"errors,omitempty"`
+	Warnings   []string `json:"warnings,omitempty"`
+}
+
+type importReportResponse struct {
+	DryRun  bool                `json:"dry_run"`
+	Total   int                 `json:"total"`
+	Created int                 `json:"created"`
+	Skipped int                 `json:"skipped"`
+	Failed  int                 `json:"failed"`
+	Rows    []importRowResponse `json:"rows"`
+}
+
+// Import godoc
+// @Summary Import tasks
+// @Description Owners/admins. Multipart upload of a CSV or JSON file. CSV needs a header line; mapping is a JSON object from
+// @Description field (external_id, title, description, status, priority, assignee_email, due_date, estimate_minutes) to CSV header.
+// @Description JSON is an array of tasks or {"tasks"|"issues": [...]}; common tracker keys (key, summary, fields, state, ...) are understood.
+// @Description Every row needs an external_id; rows imported before are skipped, so re-runs are safe. Assignees are matched by email.
+// @Description With dry_run=true nothing is written. A run with any invalid row writes nothing and returns 422 with the report.
+// @Tags tasks
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Team ID"
+// @Param file formData file true "CSV or JSON file"
+// @Param format formData string false "csv or json (default from the file name, else csv)"
+// @Param mapping formData string false "CSV column mapping as JSON"
+// @Param dry_run formData bool false "Validate only"
+// @Success 200 {object} importReportResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Failure 413 {object} response.ErrorResponse
+// @Failure 422 {object} importReportResponse
+// @Router /api/v1/teams/{id}/import [post]
+func (h *TaskHandler) Import(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 60*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	teamID, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || teamID <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, importMaxBytes)
+	if err := r.ParseMultipartForm(attachmentMemoryLimit); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			response.Error(w, http.StatusRequestEntityTooLarge, "file too large")
+			return
+		}
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	defer func() { _ = r.MultipartForm.RemoveAll() }()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	defer file.Close()
+
+	format := r.FormValue("format")
+	if format == "" {
+		format = service.ImportSourceCSV
+		if strings.HasSuffix(strings.ToLower(header.Filename), ".json") {
+			format = service.ImportSourceJSON
+		}
+	}
+	dryRun := false
+	if v := r.FormValue("dry_run"); v != "" {
+		if dryRun, err = strconv.ParseBool(v); err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid request")
+			return
+		}
+	}
+
+	var rows []service.ImportTask
+	switch format {
+	case service.ImportSourceCSV:
+		var mapping map[string]string
+		if v := r.FormValue("mapping"); v != "" {
+			if err := json.Unmarshal([]byte(v), &mapping); err != nil {
+				response.Error(w, http.StatusBadRequest, "invalid request")
+				return
+			}
+		}
+		rows, err = service.ParseImportCSV(file, mapping)
+	case service.ImportSourceJSON:
+		rows, err = service.ParseImportJSON(file)
+	default:
+		err = service.ErrBadRequest
+	}
+	if mapServiceError(w, err) {
+		return
+	}
+
+	report, err := h.tasks.ImportTasks(ctx, userID, teamID, format, rows, dryRun)
+	if mapServiceError(w, err) {
+		return
+	}
+	status := http.StatusOK
+	if report.Failed > 0 && !report.DryRun {
+		status = http.StatusUnprocessableEntity
+	}
+	if report.Created > 0 && h.cache != nil {
+		_ = h.cache.InvalidateTeam(ctx, teamID)
+	}
+	response.JSON(w, status, toImportReportResponse(*report))
+}
+
+func toImportReportResponse(r service.ImportReport) importReportResponse {
+	resp := importReportResponse{
+		DryRun:  r.DryRun,
+		Total:   r.Total,
+		Created: r.Created,
+		Skipped: r.Skipped,
+		Failed:  r.Failed,
+		Rows:    make([]importRowResponse, 0, len(r.Rows)),
+	}
+	for _, row := range r.Rows {
+		resp.Rows = append(resp.Rows, importRowResponse{
+			Row:        row.Row,
+			ExternalID: row.ExternalID,
+			Action:     row.Action,
+			TaskID:     row.TaskID,
+			Errors:     row.Errors,
+			Warnings:   row.Warnings,
+		})
+	}
+	return resp
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"MKK-Luna/internal/api/middleware"
+	"MKK-Luna/internal/service"
+	"MKK-Luna/pkg/api/response"
+)
+
+const (
+	mediaTypeMergePatch = "application/merge-patch+json"
+	mediaTypeJSONPatch  = "application/json-patch+json"
+)
+
+// Patch godoc
+// @Summary Patch task
+// @Description application/merge-patch+json (or plain application/json) changes the sent fields, null clears them.
+// @Description application/json-patch+json takes test, replace and remove ops on top-level fields; a failed test gets 409.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param Idempotency-Key header string false "Idempotency key for safe retries"
+// @Param If-Match header string false "Task ETag; a stale tag gets 412"
+// @Param request body map[string]interface{} true "Merge patch object or JSON Patch array"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Failure 412 {object} response.ErrorResponse
+// @Failure 415 {object} response.ErrorResponse
+// @Router /api/v1/tasks/{id} [patch]
+func (h *TaskHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	taskID, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || taskID <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	var teamID int64
+	switch mediaType {
+	case mediaTypeMergePatch, "application/json":
+		var raw map[string]json.RawMessage
+		if err := json.NewDecoder(r.Body).Decode(&raw); err != nil || len(raw) == 0 {
+			response.Error(w, http.StatusBadRequest, "invalid request")
+			return
+		}
+		teamID, err = h.tasks.UpdateTaskIfMatch(ctx, userID, taskID, raw, parseIfMatch(r))
+	case mediaTypeJSONPatch:
+		var ops []service.TaskPatchOp
+		if err := json.NewDecoder(r.Body).Decode(&ops); err != nil || len(ops) == 0 {
+			response.Error(w, http.StatusBadRequest, "invalid request")
+			return
+		}
+		teamID, err = h.tasks.ApplyTaskJSONPatch(ctx, userID, taskID, ops, parseIfMatch(r))
+	default:
+		w.Header().Set("Accept-Patch", mediaTypeMergePatch+", "+mediaTypeJSONPatch)
+		response.Error(w, http.StatusUnsupportedMediaType, "unsupported media type")
+		return
+	}
+	if err != nil {
+		if mapServiceError(w, err) {
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	if h.cache != nil {
+		_ = h.cache.InvalidateTeam(ctx, teamID)
+	}
+	response.JSON(w, http.StatusOK, map[string]any{"status": "ok"})
+}
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"MKK-Luna/internal/api/middleware"
+	"MKK-Luna/internal/repository"
+	"MKK-Luna/pkg/api/response"
+)
+
+type revertTaskRequest struct {
+	HistoryID int64 `json:"history_id"`
+}
+
+func (h *TaskHandler) getAsOf(ctx context.Context, w http.ResponseWriter, userID, taskID int64, asOf time.Time) {
+	task, err := h.tasks.GetTaskAsOf(ctx, userID, taskID, asOf)
+	if mapServiceError(w, err) {
+		return
+	}
+	users, err := h.tasks.UserSummaries(ctx, taskUserIDs([]repository.Task{*task}))
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	resp := withTaskUsers(toTaskResponse(*task), users)
+	v := asOf.UTC().Format(time.RFC3339Nano)
+	resp.AsOf = &v
+	response.JSON(w, http.StatusOK, resp)
+}
+
+// Revert godoc
+// @Summary Revert task to a history entry
+// @Description Restores the fields to their values right after the given history entry. Applied as a regular update, so the usual role rules apply and the revert itself is recorded in history.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param Idempotency-Key header string false "Idempotency key for safe retries"
+// @Param request body revertTaskRequest true "History entry"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/tasks/{id}/revert [post]
+func (h *TaskHandler) Revert(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	taskID, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || taskID <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	var req revertTaskRequest
+	if err := decodeJSON(r, &req); err != nil || req.HistoryID <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	teamID, err := h.tasks.RevertTask(ctx, userID, taskID, req.HistoryID)
+	if mapServiceError(w, err) {
+		return
+	}
+	if h.cache != nil {
+		_ = h.cache.InvalidateTeam(ctx, teamID)
+	}
+	response.JSON(w, http.StatusOK, map[string]any{"status": "ok"})
+}
+
+// Undelete godoc
+// @Summary Undelete task
+// @Description Owners and admins only. Restores the task from the team trash; tasks deleted before the trash existed are recreated from their delete snapshot.
+// @Tags tasks
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param Idempotency-Key header string false "Idempotency key for safe retries"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /api/v1/tasks/{id}/undelete [post]
+func (h *TaskHandler) Undelete(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	taskID, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || taskID <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	teamID, err := h.tasks.UndeleteTask(ctx, userID, taskID)
+	if mapServiceError(w, err) {
+		return
+	}
+	if h.cache != nil {
+		_ = h.cache.InvalidateTeam(ctx, teamID)
+	}
+	response.JSON(w, http.StatusOK, map[string]any{"status": "ok", "id": taskID})
+}
@@ -0,0 +1,359 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"MKK-Luna/internal/api/middleware"
+	"MKK-Luna/internal/repository"
+	"MKK-Luna/internal/service"
+	"MKK-Luna/pkg/api/response"
+)
+
+type TaskTemplateHandler struct {
+	templates *service.TaskTemplateService
+}
+
+func NewTaskTemplateHandler(templates *service.TaskTemplateService) *TaskTemplateHandler {
+	return &TaskTemplateHandler{templates: templates}
+}
+
+type taskTemplateRequest struct {
+	Name              string   `json:"name"`
+	TitlePattern      string   `json:"title_pattern"`
+	Description       string   `json:"description"`
+	Priority          string   `json:"priority"`
+	DefaultAssigneeID *int64   `json:"default_assignee_id"`
+	Labels            []string `json:"labels"`
+	Checklist         []string `json:"checklist"`
+}
+
+type taskTemplateResponse struct {
+	ID                int64    `json:"id"`
+	TeamID            int64    `json:"team_id"`
+	Name              string   `json:"name"`
+	TitlePattern      string   `json:"title_pattern"`
+	Description       *string  `json:"description,omitempty"`
+	Priority          *string  `json:"priority,omitempty"`
+	DefaultAssigneeID *int64   `json:"default_assignee_id,omitempty"`
+	Labels            []string `json:"labels"`
+	Checklist         []string `json:"checklist"`
+	CreatedBy         *int64   `json:"created_by,omitempty"`
+	CreatedAt         string   `json:"created_at"`
+	UpdatedAt         string   `json:"updated_at"`
+}
+
+type taskFromTemplateRequest struct {
+	Variables  map[string]string `json:"variables"`
+	Status     string            `json:"status"`
+	AssigneeID *int64            `json:"assignee_id"`
+	DueDate    *string           `json:"due_date"`
+	SprintID   *int64            `json:"sprint_id"`
+}
+
+// Create godoc
+// @Summary Create task template
+// @Description Owners and admins only. title_pattern, description and checklist items may use {{name}} placeholders.
+// @Tags task-templates
+// @Accept json
+// @Produce json
+// @Param id path int true "Team ID"
+// @Param Idempotency-Key header string false "Idempotency key for safe retries"
+// @Param request body taskTemplateRequest true "Template"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /api/v1/teams/{id}/task-templates [post]
+func (h *TaskTemplateHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	teamID, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || teamID <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	var req taskTemplateRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	id, err := h.templates.CreateTemplate(ctx, userID, toTaskTemplateInput(teamID, req))
+	if mapServiceError(w, err) {
+		return
+	}
+	response.JSON(w, http.StatusCreated, map[string]any{"status": "ok", "id": id})
+}
+
+// List godoc
+// @Summary List team task templates
+// @Tags task-templates
+// @Produce json
+// @Param id path int true "Team ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/teams/{id}/task-templates [get]
+func (h *TaskTemplateHandler) List(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	teamID, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || teamID <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	items, err := h.templates.ListTemplates(ctx, userID, teamID)
+	if mapServiceError(w, err) {
+		return
+	}
+	resp := make([]taskTemplateResponse, 0, len(items))
+	for _, t := range items {
+		resp = append(resp, toTaskTemplateResponse(t))
+	}
+	response.JSON(w, http.StatusOK, map[string]any{"templates": resp})
+}
+
+// Get godoc
+// @Summary Get task template
+// @Tags task-templates
+// @Produce json
+// @Param id path int true "Template ID"
+// @Success 200 {object} taskTemplateResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/task-templates/{id} [get]
+func (h *TaskTemplateHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	templateID, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || templateID <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	tpl, err := h.templates.GetTemplate(ctx, userID, templateID)
+	if mapServiceError(w, err) {
+		return
+	}
+	response.JSON(w, http.StatusOK, toTaskTemplateResponse(*tpl))
+}
+
+// Update godoc
+// @Summary Replace task template
+// @Description Owners and admins only; every field is replaced.
+// @Tags task-templates
+// @Accept json
+// @Produce json
+// @Param id path int true "Template ID"
+// @Param Idempotency-Key header string false "Idempotency key for safe retries"
+// @Param request body taskTemplateRequest true "Template"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /api/v1/task-templates/{id} [put]
+func (h *TaskTemplateHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	templateID, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || templateID <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	var req taskTemplateRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	if mapServiceError(w, h.templates.UpdateTemplate(ctx, userID, templateID, toTaskTemplateInput(0, req))) {
+		return
+	}
+	response.JSON(w, http.StatusOK, map[string]any{"status": "ok"})
+}
+
+// Delete godoc
+// @Summary Delete task template
+// @Description Owners and admins only. Tasks created from the template are not affected.
+// @Tags task-templates
+// @Produce json
+// @Param id path int true "Template ID"
+// @Param Idempotency-Key header string false "Idempotency key for safe retries"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/task-templates/{id} [delete]
+func (h *TaskTemplateHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	templateID, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || templateID <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	if mapServiceError(w, h.templates.DeleteTemplate(ctx, userID, templateID)) {
+		return
+	}
+	response.JSON(w, http.StatusOK, map[string]any{"status": "ok"})
+}
+
+// CreateFromTemplate godoc
+// @Summary Create task from template
+// @Description Any team member. {{date}} is today's UTC date; every other placeholder needs a value in variables.
+// @Description assignee_id overrides the template's default assignee, who is skipped if no longer a member.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path int true "Template ID"
+// @Param Idempotency-Key header string false "Idempotency key for safe retries"
+// @Param request body taskFromTemplateRequest true "Variables and overrides"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /api/v1/tasks/from-template/{id} [post]
+func (h *TaskHandler) CreateFromTemplate(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	templateID, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || templateID <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	var req taskFromTemplateRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	var due *time.Time
+	if req.DueDate != nil {
+		tm, err := time.Parse("2006-01-02", *req.DueDate)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid request")
+			return
+		}
+		due = &tm
+	}
+
+	res, err := h.tasks.CreateTaskFromTemplate(ctx, userID, templateID, service.TaskFromTemplateInput{
+		Variables:  req.Variables,
+		Status:     strings.TrimSpace(req.Status),
+		AssigneeID: req.AssigneeID,
+		DueDate:    due,
+		SprintID:   req.SprintID,
+	})
+	if err != nil {
+		if mapServiceError(w, err) {
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	if h.cache != nil {
+		_ = h.cache.InvalidateTeam(ctx, res.TeamID)
+	}
+	response.JSON(w, http.StatusCreated, map[string]any{
+		"status":    "ok",
+		"id":        res.TaskID,
+		"labels":    res.Labels,
+		"checklist": res.Checklist,
+	})
+}
+
+func toTaskTemplateInput(teamID int64, req taskTemplateRequest) service.TaskTemplateInput {
+	return service.TaskTemplateInput{
+		TeamID:            teamID,
+		Name:              req.Name,
+		TitlePattern:      req.TitlePattern,
+		Description:       req.Description,
+		Priority:          req.Priority,
+		DefaultAssigneeID: req.DefaultAssigneeID,
+		Labels:            req.Labels,
+		Checklist:         req.Checklist,
+	}
+}
+
+func toTaskTemplateResponse(t repository.TaskTemplate) taskTemplateResponse {
+	resp := taskTemplateResponse{
+		ID:           t.ID,
+		TeamID:       t.TeamID,
+		Name:         t.Name,
+		TitlePattern: t.TitlePattern,
+		Labels:       service.TemplateList(t.Labels),
+		Checklist:    service.TemplateList(t.Checklist),
+		CreatedAt:    t.CreatedAt.Format(time.RFC3339Nano),
+		UpdatedAt:    t.UpdatedAt.Format(time.RFC3339Nano),
+	}
+	if t.Description.Valid {
+		resp.Description = &t.Description.String
+	}
+	if t.Priority.Valid {
+		resp.Priority = &t.Priority.String
+	}
+	if t.DefaultAssigneeID.Valid {
+		resp.DefaultAssigneeID = &t.DefaultAssigneeID.Int64
+	}
+	if t.CreatedBy.Valid {
+		resp.CreatedBy = &t.CreatedBy.Int64
+	}
+	return resp
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -35,20 +36,47 @@ type createTaskRequest struct {
 	Priority    string  `json:"priority"`
 	AssigneeID  *int64  `json:"assignee_id"`
 	DueDate     *string `json:"due_date"`
+
+	OriginalEstimateMinutes *int64 `json:"original_estimate_minutes"`
+	SprintID                *int64 `json:"sprint_id"`
 }
 
 type taskResponse struct {
+	ID          int64                 `json:"id"`
+	TeamID      int64                 `json:"team_id"`
+	Title       string                `json:"title"`
+	Description *string               `json:"description,omitempty"`
+	Status      string                `json:"status"`
+	Priority    string                `json:"priority"`
+	AssigneeID  *int64                `json:"assignee_id,omitempty"`
+	Assignee    *userSummaryResponse  `json:"assignee,omitempty"`
+	CreatedBy   *int64                `json:"created_by,omitempty"`
+	Creator     *userSummaryResponse  `json:"creator,omitempty"`
+	DueDate     *string               `json:"due_date,omitempty"`
+	Watchers    []userSummaryResponse `json:"watchers,omitempty"`
+	CreatedAt   string                `json:"created_at"`
+	UpdatedAt   string                `json:"updated_at"`
+
+	OriginalEstimateMinutes  *int64                `json:"original_estimate_minutes,omitempty"`
+	RemainingEstimateMinutes *int64                `json:"remaining_estimate_minutes,omitempty"`
+	TimeTracking             *timeTrackingResponse `json:"time_tracking,omitempty"`
+	SprintID                 *int64                `json:"sprint_id,omitempty"`
+	AsOf                     *string               `json:"as_of,omitempty"`
+	DeletedAt                *string               `json:"deleted_at,omitempty"`
+	DeletedBy                *userSummaryResponse  `json:"deleted_by,omitempty"`
+
+	Checklist *checklistSummaryResponse `json:"checklist,omitempty"`
+}
+
+type timeTrackingResponse struct {
+	LoggedMinutes  int64   `json:"logged_minutes"`
+	TimerStartedAt *string `json:"timer_started_at,omitempty"`
+}
+
+type userSummaryResponse struct {
 	ID          int64   `json:"id"`
-	TeamID      int64   `json:"team_id"`
-	Title       string  `json:"title"`
-	Description *string `json:"description,omitempty"`
-	Status      string  `json:"status"`
-	Priority    string  `json:"priority"`
-	AssigneeID  *int64  `json:"assignee_id,omitempty"`
-	CreatedBy   *int64  `json:"created_by,omitempty"`
-	DueDate     *string `json:"due_date,omitempty"`
-	CreatedAt   string  `json:"created_at"`
-	UpdatedAt   string  `json:"updated_at"`
+	Username    string  `json:"username"`
+	DisplayName *string `json:"display_name,omitempty"`
 }
 
 type listTasksResponse struct {
@@ -59,13 +87,14 @@ type listTasksResponse struct {
 }
 
 type taskHistoryItemResponse struct {
-	ID        int64  `json:"id"`
-	TaskID    int64  `json:"task_id"`
-	ChangedBy *int64 `json:"changed_by,omitempty"`
-	FieldName string `json:"field_name"`
-	OldValue  any    `json:"old_value,omitempty"`
-	NewValue  any    `json:"new_value,omitempty"`
-	CreatedAt string `json:"created_at"`
+	ID            int64                `json:"id"`
+	TaskID        int64                `json:"task_id"`
+	ChangedBy     *int64               `json:"changed_by,omitempty"`
+	ChangedByUser *userSummaryResponse `json:"changed_by_user,omitempty"`
+	FieldName     string               `json:"field_name"`
+	OldValue      any                  `json:"old_value,omitempty"`
+	NewValue      any                  `json:"new_value,omitempty"`
+	CreatedAt     string               `json:"created_at"`
 }
 
 type listTaskHistoryResponse struct {
@@ -127,6 +156,9 @@ func (h *TaskHandler) Create(w http.ResponseWriter, r *http.Request) {
 		Priority:    strings.TrimSpace(req.Priority),
 		AssigneeID:  req.AssigneeID,
 		DueDate:     due,
+
+		OriginalEstimate: req.OriginalEstimateMinutes,
+		SprintID:         req.SprintID,
 	})
 	if err != nil {
 		if mapServiceError(w, err) {
@@ -146,9 +178,12 @@ func (h *TaskHandler) Create(w http.ResponseWriter, r *http.Request) {
 // @Summary List tasks
 // @Tags tasks
 // @Produce json
-// @Param team_id query int true "Team ID"
+// @Param team_id query int false "Team ID; required unless view is given"
 // @Param status query string false "Status"
 // @Param assignee_id query int false "Assignee ID"
+// @Param sprint_id query int false "Sprint ID"
+// @Param sort query string false "updated_at, created_at, due_date or priority; prefix - for descending (default -updated_at)"
+// @Param view query int false "Saved view ID; its filters and sort fill in parameters not given explicitly"
 // @Param limit query int false "Limit (max 100)"
 // @Param offset query int false "Offset"
 // @Success 200 {object} listTasksResponse
@@ -167,7 +202,24 @@ func (h *TaskHandler) List(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	teamID, err := parseInt64(r.URL.Query().Get("team_id"))
+	query := r.URL.Query()
+	if v := query.Get("view"); v != "" {
+		viewID, err := parseInt64(v)
+		if err != nil || viewID <= 0 {
+			response.Error(w, http.StatusBadRequest, "invalid request")
+			return
+		}
+		view, err := h.tasks.TaskListView(ctx, userID, viewID)
+		if mapServiceError(w, err) {
+			return
+		}
+		if !applySavedView(query, *view) {
+			response.Error(w, http.StatusBadRequest, "invalid request")
+			return
+		}
+	}
+
+	teamID, err := parseInt64(query.Get("team_id"))
 	if err != nil || teamID <= 0 {
 		response.Error(w, http.StatusBadRequest, "invalid request")
 		return
@@ -182,15 +234,18 @@ func (h *TaskHandler) List(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	limit := parseQueryInt(r.URL.Query().Get("limit"), 20)
+	limit := parseQueryInt(query.Get("limit"), 20)
 	if limit > 100 {
 		limit = 100
 	}
-	offset := parseQueryInt(r.URL.Query().Get("offset"), 0)
+	offset := parseQueryInt(query.Get("offset"), 0)
+	sort := strings.TrimSpace(query.Get("sort"))
 
 	filters := map[string]string{
-		"status":      r.URL.Query().Get("status"),
-		"assignee_id": r.URL.Query().Get("assignee_id"),
+		"status":      query.Get("status"),
+		"assignee_id": query.Get("assignee_id"),
+		"sprint_id":   query.Get("sprint_id"),
+		"sort":        sort,
 		"limit":       strconv.Itoa(limit),
 		"offset":      strconv.Itoa(offset),
 	}
@@ -203,11 +258,11 @@ func (h *TaskHandler) List(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var status *string
-	if v := strings.TrimSpace(r.URL.Query().Get("status")); v != "" {
+	if v := strings.TrimSpace(query.Get("status")); v != "" {
 		status = &v
 	}
 	var assigneeID *int64
-	if v := strings.TrimSpace(r.URL.Query().Get("assignee_id")); v != "" {
+	if v := strings.TrimSpace(query.Get("assignee_id")); v != "" {
 		id, err := parseInt64(v)
 		if err != nil {
 			response.Error(w, http.StatusBadRequest, "invalid request")
@@ -215,11 +270,22 @@ func (h *TaskHandler) List(w http.ResponseWriter, r *http.Request) {
 		}
 		assigneeID = &id
 	}
+	var sprintID *int64
+	if v := strings.TrimSpace(query.Get("sprint_id")); v != "" {
+		id, err := parseInt64(v)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid request")
+			return
+		}
+		sprintID = &id
+	}
 
 	items, total, err := h.tasks.ListTasks(ctx, userID, service.TaskListInput{
 		TeamID:     teamID,
 		Status:     status,
 		AssigneeID: assigneeID,
+		SprintID:   sprintID,
+		Sort:       sort,
 		Limit:      limit,
 		Offset:     offset,
 	})
@@ -231,14 +297,30 @@ func (h *TaskHandler) List(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	users, err := h.tasks.UserSummaries(ctx, taskUserIDs(items))
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
 	resp := listTasksResponse{
 		Items:  make([]taskResponse, 0, len(items)),
 		Total:  total,
 		Limit:  limit,
 		Offset: offset,
 	}
+	checklists, err := h.tasks.ChecklistSummaries(ctx, taskIDs(items))
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "internal error")
+		return
+	}
 	for _, t := range items {
-		resp.Items = append(resp.Items, toTaskResponse(t))
+		item := withTaskUsers(toTaskResponse(t), users)
+		if c, ok := checklists[t.ID]; ok {
+			summary := toChecklistSummaryResponse(c)
+			item.Checklist = &summary
+		}
+		resp.Items = append(resp.Items, item)
 	}
 
 	data, _ := json.Marshal(resp)
@@ -250,10 +332,16 @@ func (h *TaskHandler) List(w http.ResponseWriter, r *http.Request) {
 
 // Get godoc
 // @Summary Get task by id
+// @Description With as_of the task is rebuilt from task_history as it was at that moment; watchers and time tracking are omitted then.
+// @Description The ETag covers the whole body, watchers and time tracking included, and starts with the row version that If-Match checks; as_of responses carry none.
+// @Description A cached tag lets If-None-Match answer 304 without loading the task.
 // @Tags tasks
 // @Produce json
 // @Param id path int true "Task ID"
+// @Param as_of query string false "RFC3339 UTC timestamp"
+// @Param If-None-Match header string false "ETag from a previous read"
 // @Success 200 {object} taskResponse
+// @Success 304
 // @Failure 400 {object} response.ErrorResponse
 // @Failure 401 {object} response.ErrorResponse
 // @Failure 403 {object} response.ErrorResponse
@@ -273,6 +361,32 @@ func (h *TaskHandler) Get(w http.ResponseWriter, r *http.Request) {
 		response.Error(w, http.StatusBadRequest, "invalid request")
 		return
 	}
+	if v := r.URL.Query().Get("as_of"); v != "" {
+		asOf, err := parseRFC3339UTC(v)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid request")
+			return
+		}
+		h.getAsOf(ctx, w, userID, taskID, asOf)
+		return
+	}
+
+	// The body carries the caller's own timer, so the tag differs per user.
+	w.Header().Set("Vary", "Authorization")
+	ifNoneMatch := r.Header.Get("If-None-Match")
+	if ifNoneMatch != "" && h.cache != nil {
+		if teamID, etag, ok, err := h.cache.GetTaskETag(ctx, taskID, userID); err == nil && ok && etagMatches(ifNoneMatch, etag) {
+			if _, err := h.teams.EnsureMemberRole(ctx, teamID, userID); err != nil {
+				if mapServiceError(w, err) {
+					return
+				}
+				response.Error(w, http.StatusInternalServerError, "internal error")
+				return
+			}
+			writeNotModified(w, etag)
+			return
+		}
+	}
 
 	task, err := h.tasks.GetTask(ctx, userID, taskID)
 	if err != nil {
@@ -282,23 +396,152 @@ func (h *TaskHandler) Get(w http.ResponseWriter, r *http.Request) {
 		response.Error(w, http.StatusInternalServerError, "internal error")
 		return
 	}
-	response.JSON(w, http.StatusOK, toTaskResponse(*task))
+	watchers, err := h.tasks.ListWatchers(ctx, userID, taskID)
+	if err != nil {
+		if mapServiceError(w, err) {
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	ids := taskUserIDs([]repository.Task{*task})
+	for _, wt := range watchers {
+		ids = append(ids, wt.UserID)
+	}
+	users, err := h.tasks.UserSummaries(ctx, ids)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	resp := withTaskUsers(toTaskResponse(*task), users)
+	resp.Watchers = make([]userSummaryResponse, 0, len(watchers))
+	for _, wt := range watchers {
+		if ref := userSummaryRef(users, &wt.UserID); ref != nil {
+			resp.Watchers = append(resp.Watchers, *ref)
+			continue
+		}
+		resp.Watchers = append(resp.Watchers, userSummaryResponse{ID: wt.UserID})
+	}
+
+	summary, err := h.tasks.TaskTimeSummary(ctx, userID, taskID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	if summary != nil {
+		resp.TimeTracking = &timeTrackingResponse{LoggedMinutes: summary.LoggedMinutes}
+		if summary.TimerStartedAt != nil {
+			v := summary.TimerStartedAt.UTC().Format(time.RFC3339Nano)
+			resp.TimeTracking.TimerStartedAt = &v
+		}
+	}
+	checklists, err := h.tasks.ChecklistSummaries(ctx, []int64{task.ID})
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	if c, ok := checklists[task.ID]; ok {
+		summary := toChecklistSummaryResponse(c)
+		resp.Checklist = &summary
+	}
+
+	body, err := json.Marshal(resp)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	etag := representationETag(task.Version, body)
+	if h.cache != nil {
+		_ = h.cache.SetTaskETag(ctx, task.ID, userID, task.TeamID, etag)
+	}
+	if etagMatches(ifNoneMatch, etag) {
+		writeNotModified(w, etag)
+		return
+	}
+	w.Header().Set("ETag", etag)
+	writeJSONBytes(w, http.StatusOK, body)
+}
+
+// Watch godoc
+// @Summary Watch task
+// @Description Subscribes the caller to notifications for the task.
+// @Tags tasks
+// @Produce json
+// @Param id path int true "Task ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/tasks/{id}/watch [post]
+func (h *TaskHandler) Watch(w http.ResponseWriter, r *http.Request) {
+	h.setWatching(w, r, true)
+}
+
+// Unwatch godoc
+// @Summary Unwatch task
+// @Tags tasks
+// @Produce json
+// @Param id path int true "Task ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/tasks/{id}/watch [delete]
+func (h *TaskHandler) Unwatch(w http.ResponseWriter, r *http.Request) {
+	h.setWatching(w, r, false)
+}
+
+func (h *TaskHandler) setWatching(w http.ResponseWriter, r *http.Request, watch bool) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	taskID, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || taskID <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	if watch {
+		err = h.tasks.WatchTask(ctx, userID, taskID)
+	} else {
+		err = h.tasks.UnwatchTask(ctx, userID, taskID)
+	}
+	if err != nil {
+		if mapServiceError(w, err) {
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	response.JSON(w, http.StatusOK, map[string]any{"status": "ok", "watching": watch})
 }
 
 // Update godoc
-// @Summary Patch task
+// @Summary Replace task
+// @Description Full replacement: title, status and priority are required, omitted optional fields are cleared.
+// @Description Fields sent with their current value do not count as changes for the role check.
 // @Tags tasks
 // @Accept json
 // @Produce json
 // @Param id path int true "Task ID"
 // @Param Idempotency-Key header string false "Idempotency key for safe retries"
-// @Param request body map[string]interface{} true "Patch payload"
+// @Param If-Match header string false "Task ETag; a stale tag gets 412"
+// @Param request body map[string]interface{} true "Task representation"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} response.ErrorResponse
 // @Failure 401 {object} response.ErrorResponse
 // @Failure 403 {object} response.ErrorResponse
 // @Failure 404 {object} response.ErrorResponse
 // @Failure 409 {object} response.ErrorResponse
+// @Failure 412 {object} response.ErrorResponse
 // @Router /api/v1/tasks/{id} [put]
 func (h *TaskHandler) Update(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
@@ -326,7 +569,7 @@ func (h *TaskHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	teamID, err := h.tasks.UpdateTask(ctx, userID, taskID, raw)
+	teamID, err := h.tasks.ReplaceTask(ctx, userID, taskID, raw, parseIfMatch(r))
 	if err != nil {
 		if mapServiceError(w, err) {
 			return
@@ -343,16 +586,19 @@ func (h *TaskHandler) Update(w http.ResponseWriter, r *http.Request) {
 
 // Delete godoc
 // @Summary Delete task
+// @Description Moves the task to the team trash; it is purged after the retention period.
 // @Tags tasks
 // @Produce json
 // @Param id path int true "Task ID"
 // @Param Idempotency-Key header string false "Idempotency key for safe retries"
+// @Param If-Match header string false "Task ETag; a stale tag gets 412"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} response.ErrorResponse
 // @Failure 401 {object} response.ErrorResponse
 // @Failure 403 {object} response.ErrorResponse
 // @Failure 404 {object} response.ErrorResponse
 // @Failure 409 {object} response.ErrorResponse
+// @Failure 412 {object} response.ErrorResponse
 // @Router /api/v1/tasks/{id} [delete]
 func (h *TaskHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
@@ -369,7 +615,7 @@ func (h *TaskHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	teamID, err := h.tasks.DeleteTask(ctx, userID, taskID)
+	teamID, err := h.tasks.DeleteTaskIfMatch(ctx, userID, taskID, parseIfMatch(r))
 	if err != nil {
 		if mapServiceError(w, err) {
 			return
@@ -431,6 +677,18 @@ func (h *TaskHandler) History(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	ids := make([]int64, 0, len(items))
+	for _, item := range items {
+		if item.ChangedBy.Valid {
+			ids = append(ids, item.ChangedBy.Int64)
+		}
+	}
+	users, err := h.tasks.UserSummaries(ctx, ids)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
 	resp := listTaskHistoryResponse{
 		Items:  make([]taskHistoryItemResponse, 0, len(items)),
 		Total:  total,
@@ -438,7 +696,9 @@ func (h *TaskHandler) History(w http.ResponseWriter, r *http.Request) {
 		Offset: offset,
 	}
 	for _, item := range items {
-		resp.Items = append(resp.Items, toTaskHistoryResponse(item))
+		entry := toTaskHistoryResponse(item)
+		entry.ChangedByUser = userSummaryRef(users, entry.ChangedBy)
+		resp.Items = append(resp.Items, entry)
 	}
 	response.JSON(w, http.StatusOK, resp)
 }
@@ -494,7 +754,18 @@ func toTaskResponse(t repository.Task) taskResponse {
 		s := t.DueDate.Time.Format("2006-01-02")
 		due = &s
 	}
-	return taskResponse{
+	var original, remaining *int64
+	if t.OriginalEstimate.Valid {
+		original = &t.OriginalEstimate.Int64
+	}
+	if t.RemainingEstimate.Valid {
+		remaining = &t.RemainingEstimate.Int64
+	}
+	var sprintID *int64
+	if t.SprintID.Valid {
+		sprintID = &t.SprintID.Int64
+	}
+	resp := taskResponse{
 		ID:          t.ID,
 		TeamID:      t.TeamID,
 		Title:       t.Title,
@@ -506,7 +777,58 @@ func toTaskResponse(t repository.Task) taskResponse {
 		DueDate:     due,
 		CreatedAt:   t.CreatedAt.Format(time.RFC3339Nano),
 		UpdatedAt:   t.UpdatedAt.Format(time.RFC3339Nano),
+
+		OriginalEstimateMinutes:  original,
+		RemainingEstimateMinutes: remaining,
+		SprintID:                 sprintID,
+	}
+	if t.DeletedAt.Valid {
+		v := t.DeletedAt.Time.Format(time.RFC3339Nano)
+		resp.DeletedAt = &v
+	}
+	return resp
+}
+
+func taskIDs(items []repository.Task) []int64 {
+	ids := make([]int64, 0, len(items))
+	for _, t := range items {
+		ids = append(ids, t.ID)
 	}
+	return ids
+}
+
+func taskUserIDs(items []repository.Task) []int64 {
+	ids := make([]int64, 0, len(items)*2)
+	for _, t := range items {
+		if t.AssigneeID.Valid {
+			ids = append(ids, t.AssigneeID.Int64)
+		}
+		if t.CreatedBy.Valid {
+			ids = append(ids, t.CreatedBy.Int64)
+		}
+	}
+	return ids
+}
+
+func withTaskUsers(resp taskResponse, users map[int64]repository.UserSummary) taskResponse {
+	resp.Assignee = userSummaryRef(users, resp.AssigneeID)
+	resp.Creator = userSummaryRef(users, resp.CreatedBy)
+	return resp
+}
+
+func userSummaryRef(users map[int64]repository.UserSummary, id *int64) *userSummaryResponse {
+	if id == nil {
+		return nil
+	}
+	u, ok := users[*id]
+	if !ok {
+		return nil
+	}
+	var displayName *string
+	if u.DisplayName.Valid {
+		displayName = &u.DisplayName.String
+	}
+	return &userSummaryResponse{ID: u.ID, Username: u.Username, DisplayName: displayName}
 }
 
 func toTaskHistoryResponse(h repository.TaskHistory) taskHistoryItemResponse {
@@ -534,3 +856,28 @@ func toTaskHistoryResponse(h repository.TaskHistory) taskHistoryItemResponse {
 		CreatedAt: h.CreatedAt.Format(time.RFC3339Nano),
 	}
 }
+
+// applySavedView fills query parameters the request left empty from the view. An explicit
+// team_id must match the view's team.
+func applySavedView(query url.Values, view repository.SavedView) bool {
+	teamID := strconv.FormatInt(view.TeamID, 10)
+	if v := query.Get("team_id"); v != "" && v != teamID {
+		return false
+	}
+	query.Set("team_id", teamID)
+
+	f := service.SavedViewFiltersOf(view)
+	if query.Get("status") == "" && f.Status != nil {
+		query.Set("status", *f.Status)
+	}
+	if query.Get("assignee_id") == "" && f.AssigneeID != nil {
+		query.Set("assignee_id", strconv.FormatInt(*f.AssigneeID, 10))
+	}
+	if query.Get("sprint_id") == "" && f.SprintID != nil {
+		query.Set("sprint_id", strconv.FormatInt(*f.SprintID, 10))
+	}
+	if query.Get("sort") == "" {
+		query.Set("sort", view.Sort)
+	}
+	return true
+}
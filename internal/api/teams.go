@@ -30,6 +30,15 @@ type teamResponse struct {
 	Name string `json:"name"`
 }
 
+type teamMemberResponse struct {
+	UserID      int64   `json:"user_id"`
+	Username    string  `json:"username"`
+	Email       string  `json:"email"`
+	DisplayName *string `json:"display_name,omitempty"`
+	Role        string  `json:"role"`
+	JoinedAt    string  `json:"joined_at"`
+}
+
 type inviteRequest struct {
 	Email string `json:"email"`
 	Role  string `json:"role"`
@@ -105,6 +114,60 @@ func (h *TeamHandler) List(w http.ResponseWriter, r *http.Request) {
 	response.JSON(w, http.StatusOK, map[string]any{"teams": resp})
 }
 
+// Members godoc
+// @Summary List team members
+// @Tags teams
+// @Produce json
+// @Param id path int true "Team ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/teams/{id}/members [get]
+func (h *TeamHandler) Members(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	teamID, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || teamID <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	items, err := h.teams.ListMembers(ctx, userID, teamID)
+	if err != nil {
+		if mapServiceError(w, err) {
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	resp := make([]teamMemberResponse, 0, len(items))
+	for _, m := range items {
+		var displayName *string
+		if m.DisplayName.Valid {
+			displayName = &m.DisplayName.String
+		}
+		resp = append(resp, teamMemberResponse{
+			UserID:      m.UserID,
+			Username:    m.Username,
+			Email:       m.Email,
+			DisplayName: displayName,
+			Role:        m.Role,
+			JoinedAt:    m.CreatedAt.Format(time.RFC3339Nano),
+		})
+	}
+	response.JSON(w, http.StatusOK, map[string]any{"members": resp})
+}
+
 // Invite godoc
 // @Summary Invite user by email
 // @Tags teams
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"MKK-Luna/internal/api/middleware"
+	"MKK-Luna/internal/repository"
+	"MKK-Luna/pkg/api/response"
+)
+
+// Trash godoc
+// @Summary List team trash
+// @Description Owners and admins only. Soft-deleted tasks, newest first; restore with POST /tasks/{id}/undelete.
+// @Tags tasks
+// @Produce json
+// @Param id path int true "Team ID"
+// @Param limit query int false "Limit (1..100)"
+// @Param offset query int false "Offset (>=0)"
+// @Success 200 {object} listTasksResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/teams/{id}/trash [get]
+func (h *TaskHandler) Trash(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	teamID, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || teamID <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	limit, err := parseStrictPositiveInt(r.URL.Query().Get("limit"), 20, 100)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	offset, err := parseStrictNonNegativeInt(r.URL.Query().Get("offset"), 0)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	items, total, err := h.tasks.ListTrash(ctx, userID, teamID, limit, offset)
+	if mapServiceError(w, err) {
+		return
+	}
+
+	tasks := make([]repository.Task, 0, len(items))
+	for _, item := range items {
+		tasks = append(tasks, item.Task)
+	}
+	ids := taskUserIDs(tasks)
+	for _, item := range items {
+		if item.DeletedBy.Valid {
+			ids = append(ids, item.DeletedBy.Int64)
+		}
+	}
+	users, err := h.tasks.UserSummaries(ctx, ids)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	resp := listTasksResponse{
+		Items:  make([]taskResponse, 0, len(items)),
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}
+	for _, item := range items {
+		entry := withTaskUsers(toTaskResponse(item.Task), users)
+		if item.DeletedBy.Valid {
+			entry.DeletedBy = userSummaryRef(users, &item.DeletedBy.Int64)
+			if entry.DeletedBy == nil {
+				entry.DeletedBy = &userSummaryResponse{ID: item.DeletedBy.Int64}
+			}
+		}
+		resp.Items = append(resp.Items, entry)
+	}
+	response.JSON(w, http.StatusOK, resp)
+}
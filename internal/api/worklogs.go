@@ -0,0 +1,281 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"MKK-Luna/internal/api/middleware"
+	"MKK-Luna/internal/repository"
+	"MKK-Luna/internal/service"
+	"MKK-Luna/pkg/api/response"
+)
+
+type workLogRequest struct {
+	Minutes  int64   `json:"minutes"`
+	WorkDate *string `json:"work_date"`
+	Note     string  `json:"note"`
+}
+
+type stopTimerRequest struct {
+	Note string `json:"note"`
+}
+
+type workLogResponse struct {
+	ID        int64   `json:"id"`
+	TaskID    int64   `json:"task_id"`
+	UserID    *int64  `json:"user_id,omitempty"`
+	Minutes   int64   `json:"minutes"`
+	WorkDate  string  `json:"work_date"`
+	Note      *string `json:"note,omitempty"`
+	CreatedAt string  `json:"created_at"`
+}
+
+type stopTimerResponse struct {
+	Items []workLogResponse `json:"items"`
+}
+
+type listWorkLogsResponse struct {
+	Items  []workLogResponse `json:"items"`
+	Total  int64             `json:"total"`
+	Limit  int               `json:"limit"`
+	Offset int               `json:"offset"`
+}
+
+// LogWork godoc
+// @Summary Log work on task
+// @Description minutes must be 1..1440; work_date (YYYY-MM-DD) defaults to today (UTC).
+// @Tags worklogs
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param Idempotency-Key header string false "Idempotency key for safe retries"
+// @Param request body workLogRequest true "Work log"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/tasks/{id}/worklogs [post]
+func (h *TaskHandler) LogWork(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	taskID, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || taskID <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	var req workLogRequest
+	if err := decodeJSON(r, &req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	in := service.WorkLogInput{Minutes: req.Minutes, Note: req.Note}
+	if req.WorkDate != nil {
+		tm, err := time.Parse("2006-01-02", *req.WorkDate)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid request")
+			return
+		}
+		in.WorkDate = tm
+	}
+
+	id, err := h.tasks.LogWork(ctx, userID, taskID, in)
+	if mapServiceError(w, err) {
+		return
+	}
+	response.JSON(w, http.StatusCreated, map[string]any{"status": "ok", "id": id})
+}
+
+// ListWorkLogs godoc
+// @Summary List task work logs
+// @Tags worklogs
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param limit query int false "Limit (1..100)"
+// @Param offset query int false "Offset (>=0)"
+// @Success 200 {object} listWorkLogsResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/tasks/{id}/worklogs [get]
+func (h *TaskHandler) ListWorkLogs(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	taskID, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || taskID <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	limit, err := parseStrictPositiveInt(r.URL.Query().Get("limit"), 20, 100)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	offset, err := parseStrictNonNegativeInt(r.URL.Query().Get("offset"), 0)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	items, total, err := h.tasks.ListWorkLogs(ctx, userID, taskID, limit, offset)
+	if mapServiceError(w, err) {
+		return
+	}
+	resp := listWorkLogsResponse{
+		Items:  make([]workLogResponse, 0, len(items)),
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}
+	for _, item := range items {
+		resp.Items = append(resp.Items, toWorkLogResponse(item))
+	}
+	response.JSON(w, http.StatusOK, resp)
+}
+
+// DeleteWorkLog godoc
+// @Summary Delete work log
+// @Description Allowed for the author and team owners/admins.
+// @Tags worklogs
+// @Param id path int true "Work log ID"
+// @Success 204
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/worklogs/{id} [delete]
+func (h *TaskHandler) DeleteWorkLog(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	id, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || id <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	if mapServiceError(w, h.tasks.DeleteWorkLog(ctx, userID, id)) {
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// StartTimer godoc
+// @Summary Start timer on task
+// @Description Each user can run one timer per task; a second start returns 409.
+// @Tags worklogs
+// @Produce json
+// @Param id path int true "Task ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 409 {object} response.ErrorResponse
+// @Router /api/v1/tasks/{id}/timer/start [post]
+func (h *TaskHandler) StartTimer(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	taskID, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || taskID <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	if mapServiceError(w, h.tasks.StartTimer(ctx, userID, taskID)) {
+		return
+	}
+	response.JSON(w, http.StatusOK, map[string]any{"status": "ok"})
+}
+
+// StopTimer godoc
+// @Summary Stop timer on task
+// @Description Converts the running timer into work logs, one per UTC day it ran, each rounded up to whole minutes.
+// @Tags worklogs
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param request body stopTimerRequest false "Optional note"
+// @Success 200 {object} stopTimerResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/tasks/{id}/timer/stop [post]
+func (h *TaskHandler) StopTimer(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		response.Error(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	taskID, err := parseInt64(chi.URLParam(r, "id"))
+	if err != nil || taskID <= 0 {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+	var req stopTimerRequest
+	if err := decodeJSON(r, &req); err != nil && !errors.Is(err, io.EOF) {
+		response.Error(w, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	entries, err := h.tasks.StopTimer(ctx, userID, taskID, req.Note)
+	if mapServiceError(w, err) {
+		return
+	}
+	resp := stopTimerResponse{Items: make([]workLogResponse, 0, len(entries))}
+	for _, entry := range entries {
+		resp.Items = append(resp.Items, toWorkLogResponse(entry))
+	}
+	response.JSON(w, http.StatusOK, resp)
+}
+
+func toWorkLogResponse(l repository.TaskWorkLog) workLogResponse {
+	resp := workLogResponse{
+		ID:        l.ID,
+		TaskID:    l.TaskID,
+		Minutes:   l.Minutes,
+		WorkDate:  l.WorkDate.Format("2006-01-02"),
+		CreatedAt: l.CreatedAt.UTC().Format(time.RFC3339Nano),
+	}
+	if l.UserID.Valid {
+		resp.UserID = &l.UserID.Int64
+	}
+	if l.Note.Valid {
+		resp.Note = &l.Note.String
+	}
+	return resp
+}
@@ -17,14 +17,17 @@ import (
 	"MKK-Luna/internal/api"
 	"MKK-Luna/internal/config"
 	drl "MKK-Luna/internal/domain/ratelimit"
+	dstorage "MKK-Luna/internal/domain/storage"
 	authinfra "MKK-Luna/internal/infra/auth"
 	"MKK-Luna/internal/infra/cache"
 	emailinfra "MKK-Luna/internal/infra/email"
+	"MKK-Luna/internal/infra/events"
 	ideminfra "MKK-Luna/internal/infra/idempotency"
 	metricsinfra "MKK-Luna/internal/infra/metrics"
 	rl "MKK-Luna/internal/infra/ratelimit"
 	redisinfra "MKK-Luna/internal/infra/redis"
 	redislock "MKK-Luna/internal/infra/redislock"
+	storageinfra "MKK-Luna/internal/infra/storage"
 	"MKK-Luna/internal/repository"
 	"MKK-Luna/internal/service"
 	"MKK-Luna/pkg/nethttp/runner"
@@ -39,6 +42,14 @@ type Application struct {
 	teamSvc        *service.TeamService
 	taskSvc        *service.TaskService
 	statsSvc       *service.StatsService
+	userSvc        *service.UserService
+	attachSvc      *service.AttachmentService
+	sprintSvc      *service.SprintService
+	auditSvc       *service.AuditService
+	templateSvc    *service.TaskTemplateService
+	viewSvc        *service.SavedViewService
+	exportSvc      *service.ExportService
+	integritySvc   *service.IntegrityService
 	redis          *redis.Client
 	loginLimiter   drl.Limiter
 	refreshLimiter drl.Limiter
@@ -77,6 +88,10 @@ func (a *Application) Start(ctx context.Context, build string) error {
 		return fmt.Errorf("initMetricsServer(): %w", err)
 	}
 
+	a.startTrashPurge(ctx)
+	a.startExportWorker(ctx)
+	a.startIntegrityChecks(ctx)
+
 	a.logger.Info("application started", slog.String("env", build))
 	a.ready = true
 	return nil
@@ -206,6 +221,9 @@ func (a *Application) initRedis() error {
 }
 
 func (a *Application) initServices() error {
+	if err := checkSigningKeys(a.cfg); err != nil {
+		return err
+	}
 	userRepo := repository.NewUserRepository(a.db)
 	teamRepo := repository.NewTeamRepository(a.db)
 	memberRepo := repository.NewTeamMemberRepository(a.db)
@@ -213,9 +231,22 @@ func (a *Application) initServices() error {
 	commentRepo := repository.NewTaskCommentRepository(a.db)
 	historyRepo := repository.NewTaskHistoryRepository(a.db)
 	analyticsRepo := repository.NewAnalyticsRepository(a.db)
+	attachmentRepo := repository.NewTaskAttachmentRepository(a.db)
+	sprintRepo := repository.NewSprintRepository(a.db)
+	templateRepo := repository.NewTaskTemplateRepository(a.db)
+	checklistRepo := repository.NewTaskChecklistRepository(a.db)
+	boardRepo := repository.NewBoardRepository(a.db)
+	viewRepo := repository.NewSavedViewRepository(a.db)
+	a.auditSvc = service.NewAuditService(repository.NewAuditRepository(a.db), teamRepo, memberRepo, a.cfg.Admin.UserIDs, a.logger)
+	blobs, err := newBlobStorage(a.cfg.Attach.Storage)
+	if err != nil {
+		return err
+	}
 
 	sessionRepo := repository.NewSessionRepository(a.db)
-	authSvc, err := service.NewAuthService(userRepo, sessionRepo, *a.cfg, a.logger, a.metrics, authinfra.NewJWTBlacklist(a.redis))
+	authSvc, err := service.NewAuthService(userRepo, sessionRepo, *a.cfg, a.logger, a.metrics, authinfra.NewJWTBlacklist(a.redis),
+		service.WithAuthAudit(a.auditSvc),
+	)
 	if err != nil {
 		return err
 	}
@@ -226,9 +257,93 @@ func (a *Application) initServices() error {
 		a.logger,
 		a.metrics,
 	)
-	a.teamSvc = service.NewTeamService(a.db, teamRepo, memberRepo, userRepo, emailSender, a.locker, a.cfg.Idem.LockTTL, a.logger, a.metrics)
-	a.taskSvc = service.NewTaskService(a.db, taskRepo, teamRepo, memberRepo, commentRepo, historyRepo)
-	a.statsSvc = service.NewStatsService(analyticsRepo, a.statsCache, a.cfg.Admin.UserIDs, a.logger)
+	a.teamSvc = service.NewTeamService(a.db, teamRepo, memberRepo, userRepo, emailSender, a.locker, a.cfg.Idem.LockTTL, a.logger, a.metrics,
+		service.WithTeamAudit(a.auditSvc),
+	)
+	a.userSvc = service.NewUserService(
+		a.db,
+		userRepo,
+		repository.NewUserEmailChangeRepository(a.db),
+		sessionRepo,
+		teamRepo,
+		memberRepo,
+		commentRepo,
+		emailSender,
+		a.cfg.Auth.EmailChange.TokenTTL,
+		a.logger,
+		service.WithUserAudit(a.auditSvc),
+	)
+	a.taskSvc = service.NewTaskService(
+		a.db, taskRepo, teamRepo, memberRepo, commentRepo, historyRepo,
+		service.WithUserDirectory(userRepo),
+		service.WithWatchers(repository.NewTaskWatcherRepository(a.db)),
+		service.WithTaskEvents(events.NewPublisher(a.redis, a.logger, a.metrics)),
+		service.WithTaskTagCache(a.taskCache),
+		service.WithTaskLogger(a.logger),
+		service.WithMentions(repository.NewCommentMentionRepository(a.db), memberRepo),
+		service.WithCommentThreads(commentRepo, repository.NewCommentReactionRepository(a.db)),
+		service.WithAttachmentCleanup(attachmentRepo, blobs),
+		service.WithTimeTracking(repository.NewTaskWorkLogRepository(a.db)),
+		service.WithSprints(sprintRepo),
+		service.WithTaskReplay(historyRepo, taskRepo),
+		service.WithTrash(taskRepo),
+		service.WithTaskAudit(a.auditSvc),
+		service.WithTaskTemplates(templateRepo),
+		service.WithChecklists(checklistRepo),
+		service.WithBoard(boardRepo),
+		service.WithMyTasks(taskRepo),
+		service.WithSavedViews(viewRepo),
+		service.WithImports(repository.NewTaskImportRepository(a.db), taskRepo, userRepo),
+	)
+	a.sprintSvc = service.NewSprintService(a.db, sprintRepo, teamRepo, memberRepo, historyRepo)
+	a.templateSvc = service.NewTaskTemplateService(templateRepo, teamRepo, memberRepo)
+	a.viewSvc = service.NewSavedViewService(viewRepo, teamRepo, memberRepo)
+	a.exportSvc = service.NewExportService(repository.NewExportRepository(a.db), teamRepo, memberRepo, blobs, a.cfg.Export, a.cfg.Export.SigningKey, a.logger,
+		service.WithExportAudit(a.auditSvc),
+	)
+	a.attachSvc = service.NewAttachmentService(
+		a.taskSvc,
+		attachmentRepo,
+		commentRepo,
+		memberRepo,
+		blobs,
+		a.cfg.Attach.MaxSizeBytes,
+		a.cfg.Attach.AllowedTypes,
+		a.cfg.Attach.SigningKey,
+		a.cfg.Attach.URLTTL,
+		a.logger,
+	)
+	a.integritySvc = service.NewIntegrityService(a.db, repository.NewIntegrityRepository(a.db),
+		service.DefaultIntegrityChecks(repository.NewIntegrityRepository(a.db), analyticsRepo, historyRepo),
+		a.cfg.Integrity.SampleSize, a.cfg.Admin.UserIDs, a.logger,
+		service.WithIntegrityMetrics(a.metrics),
+		service.WithIntegrityAudit(a.auditSvc),
+	)
+	a.statsSvc = service.NewStatsService(analyticsRepo, a.statsCache, a.cfg.Admin.UserIDs, a.logger,
+		service.WithSprintLookup(sprintRepo, memberRepo),
+		service.WithStatsAudit(a.auditSvc),
+	)
+	return nil
+}
+
+// checkSigningKeys requires dedicated keys for download links, so a leaked link key
+// cannot be used to forge access tokens or links of the other kind.
+func checkSigningKeys(cfg *config.Config) error {
+	keys := []struct{ name, value string }{
+		{"attachments.signing_key", cfg.Attach.SigningKey},
+		{"exports.signing_key", cfg.Export.SigningKey},
+	}
+	for _, k := range keys {
+		if k.value == "" {
+			return fmt.Errorf("%s is required", k.name)
+		}
+		if k.value == cfg.JWT.Secret {
+			return fmt.Errorf("%s must differ from jwt.secret", k.name)
+		}
+	}
+	if cfg.Attach.SigningKey == cfg.Export.SigningKey {
+		return fmt.Errorf("attachments.signing_key and exports.signing_key must differ")
+	}
 	return nil
 }
 
@@ -257,6 +372,14 @@ func (a *Application) initPublicRouter(ctx context.Context) error {
 		a.idemStore,
 		a.locker,
 		a.metrics,
+		api.WithUserService(a.userSvc),
+		api.WithAttachmentService(a.attachSvc),
+		api.WithSprintService(a.sprintSvc),
+		api.WithAuditService(a.auditSvc),
+		api.WithTaskTemplateService(a.templateSvc),
+		api.WithSavedViewService(a.viewSvc),
+		api.WithExportService(a.exportSvc),
+		api.WithIntegrityService(a.integritySvc),
 	)
 
 	port, err := parsePort(a.cfg.HTTP.Addr)
@@ -271,6 +394,17 @@ func (a *Application) initPublicRouter(ctx context.Context) error {
 	return nil
 }
 
+func newBlobStorage(cfg config.BlobStoreConfig) (dstorage.BlobStorage, error) {
+	switch cfg.Driver {
+	case "local":
+		return storageinfra.NewLocalStorage(cfg.LocalDir)
+	case "s3":
+		return storageinfra.NewS3Storage(cfg.Endpoint, cfg.Region, cfg.Bucket, cfg.AccessKey, cfg.SecretKey, &http.Client{Timeout: 60 * time.Second})
+	default:
+		return nil, fmt.Errorf("unknown attachments storage driver %q", cfg.Driver)
+	}
+}
+
 func parsePort(addr string) (string, error) {
 	if strings.HasPrefix(addr, ":") {
 		return strings.TrimPrefix(addr, ":"), nil
@@ -0,0 +1,143 @@
+package application
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// startTrashPurge hard-deletes tasks that have been in the trash longer than the retention period.
+func (a *Application) startTrashPurge(ctx context.Context) {
+	cfg := a.cfg.Trash
+	if cfg.RetentionDays <= 0 || cfg.PurgeInterval <= 0 {
+		return
+	}
+	retention := time.Duration(cfg.RetentionDays) * 24 * time.Hour
+	batch := cfg.PurgeBatch
+	if batch <= 0 {
+		batch = 500
+	}
+
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		ticker := time.NewTicker(cfg.PurgeInterval)
+		defer ticker.Stop()
+		for {
+			runCtx, cancel := context.WithTimeout(ctx, cfg.PurgeInterval)
+			purged, err := a.taskSvc.PurgeDeletedTasks(runCtx, time.Now().UTC().Add(-retention), batch)
+			cancel()
+			if err != nil && ctx.Err() == nil {
+				a.logger.Error("trash purge failed", slog.String("error", err.Error()))
+			} else if purged > 0 {
+				a.logger.Info("trash purged", slog.Int("tasks", purged))
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// startExportWorker runs queued export jobs and removes expired export files.
+func (a *Application) startExportWorker(ctx context.Context) {
+	interval := a.cfg.Export.PollInterval
+	if interval <= 0 {
+		return
+	}
+
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if ran, err := a.exportSvc.RunPendingJobs(ctx); err != nil && ctx.Err() == nil {
+				a.logger.Error("export jobs failed", slog.String("error", err.Error()))
+			} else if ran > 0 {
+				a.logger.Info("export jobs finished", slog.Int("jobs", ran))
+			}
+			if purged, err := a.exportSvc.PurgeExpiredJobs(ctx, time.Now().UTC()); err != nil && ctx.Err() == nil {
+				a.logger.Error("export purge failed", slog.String("error", err.Error()))
+			} else if purged > 0 {
+				a.logger.Info("expired exports purged", slog.Int("jobs", purged))
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// startIntegrityChecks runs the integrity checks on a schedule. With several replicas the
+// Redis lock keeps it to one run per interval.
+func (a *Application) startIntegrityChecks(ctx context.Context) {
+	cfg := a.cfg.Integrity
+	if cfg.Interval <= 0 || a.integritySvc == nil {
+		return
+	}
+
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		ticker := time.NewTicker(cfg.Interval)
+		defer ticker.Stop()
+		for {
+			a.runIntegrityChecks(ctx, cfg.Interval, cfg.Repair, cfg.Retention)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (a *Application) runIntegrityChecks(ctx context.Context, interval time.Duration, repair bool, retention time.Duration) {
+	runCtx, cancel := context.WithTimeout(ctx, interval)
+	defer cancel()
+
+	// The lock is not released: holding it for the whole interval stops other replicas
+	// from repeating the run right after this one.
+	if a.locker != nil {
+		if _, ok, err := a.locker.Acquire(runCtx, "lock:integrity", interval); err != nil || !ok {
+			if err != nil {
+				a.logger.Warn("integrity lock failed", slog.String("error", err.Error()))
+			}
+			return
+		}
+	}
+
+	results, err := a.integritySvc.RunScheduled(runCtx, repair)
+	if err != nil && ctx.Err() == nil {
+		a.logger.Error("integrity checks failed", slog.String("error", err.Error()))
+	}
+	for _, res := range results {
+		if res.Run.Found > 0 {
+			a.logger.Warn("integrity findings",
+				slog.String("check", res.Run.CheckName),
+				slog.Int("found", res.Run.Found),
+				slog.Int("repaired", res.Run.Repaired),
+			)
+		}
+		if a.taskCache != nil {
+			for _, teamID := range res.TeamIDs {
+				_ = a.taskCache.InvalidateTeam(runCtx, teamID)
+			}
+		}
+	}
+	if retention > 0 {
+		if purged, err := a.integritySvc.PurgeRuns(runCtx, time.Now().UTC().Add(-retention)); err != nil && ctx.Err() == nil {
+			a.logger.Error("integrity run purge failed", slog.String("error", err.Error()))
+		} else if purged > 0 {
+			a.logger.Info("integrity runs purged", slog.Int64("runs", purged))
+		}
+	}
+}
@@ -22,6 +22,10 @@ type Config struct {
 	Circuit   CircuitBreakerConfig `yaml:"circuit_breaker"`
 	Admin     AdminConfig          `yaml:"admin"`
 	Log       LogConfig            `yaml:"log"`
+	Attach    AttachmentsConfig    `yaml:"attachments"`
+	Trash     TrashConfig          `yaml:"trash"`
+	Export    ExportConfig         `yaml:"exports"`
+	Integrity IntegrityConfig      `yaml:"integrity"`
 }
 
 type HTTPConfig struct {
@@ -74,6 +78,7 @@ type AuthConfig struct {
 	LoginPerMin   int               `yaml:"login_per_min" default:"5"`
 	RefreshPerMin int               `yaml:"refresh_per_min" default:"20"`
 	Lockout       AuthLockoutConfig `yaml:"lockout"`
+	EmailChange   EmailChangeConfig `yaml:"email_change"`
 }
 
 type AuthLockoutConfig struct {
@@ -82,6 +87,10 @@ type AuthLockoutConfig struct {
 	KeyMaxLen   int           `yaml:"key_max_len" default:"128"`
 }
 
+type EmailChangeConfig struct {
+	TokenTTL time.Duration `yaml:"token_ttl" default:"24h"`
+}
+
 type CacheConfig struct {
 	Enabled      bool          `yaml:"enabled" default:"true"`
 	TaskCacheTTL time.Duration `yaml:"task_ttl" default:"5m"`
@@ -115,6 +124,51 @@ type AdminConfig struct {
 	UserIDs []int64 `yaml:"user_ids"`
 }
 
+type AttachmentsConfig struct {
+	MaxSizeBytes int64           `yaml:"max_size_bytes" default:"10485760"`
+	AllowedTypes []string        `yaml:"allowed_types" default:"[\"image/png\",\"image/jpeg\",\"image/gif\",\"image/webp\",\"text/plain\",\"application/pdf\",\"application/zip\"]"`
+	URLTTL       time.Duration   `yaml:"url_ttl" default:"15m"`
+	SigningKey   string          `yaml:"signing_key" default:""`
+	Storage      BlobStoreConfig `yaml:"storage"`
+}
+
+type BlobStoreConfig struct {
+	Driver    string `yaml:"driver" default:"local"`
+	LocalDir  string `yaml:"local_dir" default:"./data/attachments"`
+	Endpoint  string `yaml:"endpoint" default:""`
+	Region    string `yaml:"region" default:"us-east-1"`
+	Bucket    string `yaml:"bucket" default:""`
+	AccessKey string `yaml:"access_key" default:""`
+	SecretKey string `yaml:"secret_key" default:""`
+}
+
+type TrashConfig struct {
+	RetentionDays int           `yaml:"retention_days" default:"30"`
+	PurgeInterval time.Duration `yaml:"purge_interval" default:"1h"`
+	PurgeBatch    int           `yaml:"purge_batch" default:"500"`
+}
+
+// ExportConfig bounds synchronous exports; larger ones go through background jobs whose
+// files are kept for Retention. Jobs running longer than StaleAfter are picked up again.
+type ExportConfig struct {
+	SyncMaxRows   int64         `yaml:"sync_max_rows" default:"50000"`
+	StreamTimeout time.Duration `yaml:"stream_timeout" default:"5m"`
+	PollInterval  time.Duration `yaml:"poll_interval" default:"10s"`
+	StaleAfter    time.Duration `yaml:"stale_after" default:"30m"`
+	Retention     time.Duration `yaml:"retention" default:"24h"`
+	URLTTL        time.Duration `yaml:"url_ttl" default:"15m"`
+	SigningKey    string        `yaml:"signing_key" default:""`
+}
+
+// IntegrityConfig schedules the data integrity checks. Scheduled runs only report unless
+// Repair is set; Retention bounds the stored run history.
+type IntegrityConfig struct {
+	Interval   time.Duration `yaml:"interval" default:"1h"`
+	Repair     bool          `yaml:"repair" default:"false"`
+	SampleSize int           `yaml:"sample_size" default:"20"`
+	Retention  time.Duration `yaml:"retention" default:"720h"`
+}
+
 type LogConfig struct {
 	LevelStr string `yaml:"level" default:"info"`
 }
@@ -11,6 +11,9 @@ type TaskCache interface {
 	GetList(ctx context.Context, teamID int64, filters map[string]string) (data []byte, ok bool, err error)
 	SetList(ctx context.Context, teamID int64, filters map[string]string, data []byte) error
 	InvalidateTeam(ctx context.Context, teamID int64) error
+	GetTaskETag(ctx context.Context, taskID, userID int64) (teamID int64, etag string, ok bool, err error)
+	SetTaskETag(ctx context.Context, taskID, userID, teamID int64, etag string) error
+	InvalidateTask(ctx context.Context, taskID int64) error
 }
 
 type StatsCache interface {
@@ -18,4 +21,10 @@ type StatsCache interface {
 	SetDone(ctx context.Context, userID int64, from, to time.Time, items []repository.TeamDoneStat) error
 	GetTop(ctx context.Context, userID int64, from, to time.Time, limit int) (items []repository.TeamTopCreator, ok bool, err error)
 	SetTop(ctx context.Context, userID int64, from, to time.Time, limit int, items []repository.TeamTopCreator) error
+	GetFlow(ctx context.Context, userID int64, from, to time.Time) (items []repository.TeamFlowStat, ok bool, err error)
+	SetFlow(ctx context.Context, userID int64, from, to time.Time, items []repository.TeamFlowStat) error
+	GetWorkload(ctx context.Context, userID int64, from, to time.Time) (items []repository.MemberWorkloadStat, ok bool, err error)
+	SetWorkload(ctx context.Context, userID int64, from, to time.Time, items []repository.MemberWorkloadStat) error
+	GetDoneSeries(ctx context.Context, userID int64, from, to time.Time, bucket, tz string) (items []repository.TeamDoneSeries, ok bool, err error)
+	SetDoneSeries(ctx context.Context, userID int64, from, to time.Time, bucket, tz string, items []repository.TeamDoneSeries) error
 }
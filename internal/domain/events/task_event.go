@@ -0,0 +1,21 @@
+package events
+
+import (
+	"context"
+	"time"
+)
+
+type TaskEvent struct {
+	Type       string    `json:"type"`
+	TaskID     int64     `json:"task_id"`
+	TeamID     int64     `json:"team_id"`
+	ActorID    int64     `json:"actor_id"`
+	CommentID  int64     `json:"comment_id,omitempty"`
+	Fields     []string  `json:"fields,omitempty"`
+	Recipients []int64   `json:"recipients"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+type TaskEventPublisher interface {
+	PublishTaskEvent(ctx context.Context, ev TaskEvent) error
+}
@@ -104,6 +104,49 @@ func (l *Lockout) OnSuccess(ctx context.Context, normalized string) error {
 	return nil
 }
 
+// Unlock clears the lock and the failure counter of a login; it reports whether either existed.
+func (l *Lockout) Unlock(ctx context.Context, normalized string) (bool, error) {
+	if l == nil || l.client == nil {
+		return false, nil
+	}
+	n, err := l.client.Del(ctx, l.failKey(normalized), l.lockKey(normalized)).Result()
+	if err != nil {
+		l.onRedisError(err)
+		return false, err
+	}
+	return n > 0, nil
+}
+
+type LockedLogin struct {
+	Login string
+	TTL   time.Duration
+}
+
+// Locked lists logins that are locked right now, up to limit.
+func (l *Lockout) Locked(ctx context.Context, limit int) ([]LockedLogin, error) {
+	if l == nil || l.client == nil {
+		return nil, nil
+	}
+	var out []LockedLogin
+	iter := l.client.Scan(ctx, 0, l.lockKey("*"), 500).Iterator()
+	for iter.Next(ctx) && len(out) < limit {
+		ttl, err := l.client.TTL(ctx, iter.Val()).Result()
+		if err != nil {
+			l.onRedisError(err)
+			return nil, err
+		}
+		if ttl <= 0 {
+			continue
+		}
+		out = append(out, LockedLogin{Login: strings.TrimPrefix(iter.Val(), l.lockKey("")), TTL: ttl})
+	}
+	if err := iter.Err(); err != nil {
+		l.onRedisError(err)
+		return nil, err
+	}
+	return out, nil
+}
+
 func (l *Lockout) failKey(login string) string {
 	return "auth:fail:" + login
 }
@@ -93,6 +93,90 @@ func (c *StatsCache) SetTop(ctx context.Context, userID int64, from, to time.Tim
 	return nil
 }
 
+func (c *StatsCache) GetFlow(ctx context.Context, userID int64, from, to time.Time) ([]repository.TeamFlowStat, bool, error) {
+	var items []repository.TeamFlowStat
+	ok, err := c.get(ctx, flowKey(userID, from, to), &items)
+	return items, ok, err
+}
+
+func (c *StatsCache) SetFlow(ctx context.Context, userID int64, from, to time.Time, items []repository.TeamFlowStat) error {
+	return c.set(ctx, flowKey(userID, from, to), items)
+}
+
+func (c *StatsCache) GetWorkload(ctx context.Context, userID int64, from, to time.Time) ([]repository.MemberWorkloadStat, bool, error) {
+	var items []repository.MemberWorkloadStat
+	ok, err := c.get(ctx, workloadKey(userID, from, to), &items)
+	return items, ok, err
+}
+
+func (c *StatsCache) SetWorkload(ctx context.Context, userID int64, from, to time.Time, items []repository.MemberWorkloadStat) error {
+	return c.set(ctx, workloadKey(userID, from, to), items)
+}
+
+func (c *StatsCache) GetDoneSeries(ctx context.Context, userID int64, from, to time.Time, bucket, tz string) ([]repository.TeamDoneSeries, bool, error) {
+	var items []repository.TeamDoneSeries
+	ok, err := c.get(ctx, doneSeriesKey(userID, from, to, bucket, tz), &items)
+	return items, ok, err
+}
+
+func (c *StatsCache) SetDoneSeries(ctx context.Context, userID int64, from, to time.Time, bucket, tz string, items []repository.TeamDoneSeries) error {
+	return c.set(ctx, doneSeriesKey(userID, from, to, bucket, tz), items)
+}
+
+func (c *StatsCache) get(ctx context.Context, key string, v any) (bool, error) {
+	if !c.enabled || c.client == nil {
+		return false, nil
+	}
+	raw, err := c.client.Get(ctx, key).Bytes()
+	if err == redis.Nil {
+		return false, nil
+	}
+	if err != nil {
+		c.onRedisError(err)
+		return false, err
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (c *StatsCache) set(ctx context.Context, key string, v any) error {
+	if !c.enabled || c.client == nil {
+		return nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err := c.client.Set(ctx, key, raw, c.ttl).Err(); err != nil {
+		c.onRedisError(err)
+		return err
+	}
+	return nil
+}
+
+// Flush deletes every cached stats entry and returns how many keys were removed.
+func (c *StatsCache) Flush(ctx context.Context) (int, error) {
+	if c.client == nil {
+		return 0, nil
+	}
+	n := 0
+	iter := c.client.Scan(ctx, 0, "stats:*", 500).Iterator()
+	for iter.Next(ctx) {
+		if err := c.client.Del(ctx, iter.Val()).Err(); err != nil {
+			c.onRedisError(err)
+			return n, err
+		}
+		n++
+	}
+	if err := iter.Err(); err != nil {
+		c.onRedisError(err)
+		return n, err
+	}
+	return n, nil
+}
+
 func doneKey(userID int64, from, to time.Time) string {
 	return "stats:done:u:" + strconv.FormatInt(userID, 10) + ":f:" + dayKey(from) + ":t:" + dayKey(to)
 }
@@ -101,10 +185,29 @@ func topKey(userID int64, from, to time.Time, limit int) string {
 	return "stats:top:u:" + strconv.FormatInt(userID, 10) + ":f:" + dayKey(from) + ":t:" + dayKey(to) + ":l:" + strconv.Itoa(limit)
 }
 
+// flowKey keeps the exact window, since flow metrics are computed over instants.
+func flowKey(userID int64, from, to time.Time) string {
+	return "stats:flow:u:" + strconv.FormatInt(userID, 10) + ":f:" + instantKey(from) + ":t:" + instantKey(to)
+}
+
+// workloadKey keeps the exact window for the same reason as flowKey.
+func workloadKey(userID int64, from, to time.Time) string {
+	return "stats:workload:u:" + strconv.FormatInt(userID, 10) + ":f:" + instantKey(from) + ":t:" + instantKey(to)
+}
+
+// doneSeriesKey keeps the exact window: with a time zone, from and to are rarely UTC midnight.
+func doneSeriesKey(userID int64, from, to time.Time, bucket, tz string) string {
+	return "stats:done_series:u:" + strconv.FormatInt(userID, 10) + ":f:" + instantKey(from) + ":t:" + instantKey(to) + ":b:" + bucket + ":tz:" + tz
+}
+
 func dayKey(tm time.Time) string {
 	return tm.UTC().Format("20060102")
 }
 
+func instantKey(tm time.Time) string {
+	return tm.UTC().Format("20060102T150405")
+}
+
 func (c *StatsCache) onRedisError(err error) {
 	if c.logger != nil {
 		c.logger.Warn("stats cache redis error", "err", err)
@@ -20,4 +20,14 @@ func TestStatsCacheKeyDayNormalized(t *testing.T) {
 	if gotTop != wantTop {
 		t.Fatalf("top key=%q want=%q", gotTop, wantTop)
 	}
+
+	if got := flowKey(42, from, to); got != "stats:flow:u:42:f:20260213T102211:t:20260220T235959" {
+		t.Fatalf("flow key=%q", got)
+	}
+	if got := workloadKey(42, from, to); got != "stats:workload:u:42:f:20260213T102211:t:20260220T235959" {
+		t.Fatalf("workload key=%q", got)
+	}
+	if got := doneSeriesKey(42, from, to, "week", "Europe/Berlin"); got != "stats:done_series:u:42:f:20260213T102211:t:20260220T235959:b:week:tz:Europe/Berlin" {
+		t.Fatalf("done series key=%q", got)
+	}
 }
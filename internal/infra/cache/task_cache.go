@@ -76,6 +76,94 @@ func (c *TaskCache) InvalidateTeam(ctx context.Context, teamID int64) error {
 	return err
 }
 
+// InvalidateAll bumps the list version of every team that has one and returns how many
+// were bumped. Versions only grow, so entries written before stay unreachable.
+func (c *TaskCache) InvalidateAll(ctx context.Context) (int, error) {
+	if c.client == nil {
+		return 0, nil
+	}
+	n := 0
+	iter := c.client.Scan(ctx, 0, "tasks:team:*:ver", 500).Iterator()
+	for iter.Next(ctx) {
+		if err := c.client.Incr(ctx, iter.Val()).Err(); err != nil {
+			c.onRedisError(err)
+			return n, err
+		}
+		n++
+	}
+	if err := iter.Err(); err != nil {
+		c.onRedisError(err)
+		return n, err
+	}
+	return n, nil
+}
+
+// GetTaskETag returns the last ETag served to userID for a task. Entries are tagged with the
+// team's list version, so any InvalidateTeam after a write makes them stale; changes that do
+// not bump the task row (watchers, time tracking) go through InvalidateTask.
+func (c *TaskCache) GetTaskETag(ctx context.Context, taskID, userID int64) (int64, string, bool, error) {
+	if !c.enabled || c.client == nil {
+		return 0, "", false, nil
+	}
+	val, err := c.client.HGet(ctx, etagKey(taskID), itoa(userID)).Result()
+	if err == redis.Nil {
+		return 0, "", false, nil
+	}
+	if err != nil {
+		c.onRedisError(err)
+		return 0, "", false, err
+	}
+	parts := strings.SplitN(val, ":", 3)
+	if len(parts) != 3 {
+		return 0, "", false, nil
+	}
+	teamID, err1 := strconv.ParseInt(parts[0], 10, 64)
+	entryVer, err2 := strconv.ParseInt(parts[1], 10, 64)
+	if err1 != nil || err2 != nil {
+		return 0, "", false, nil
+	}
+	ver, err := c.getVersion(ctx, teamID)
+	if err != nil {
+		return 0, "", false, err
+	}
+	if ver != entryVer {
+		return 0, "", false, nil
+	}
+	return teamID, parts[2], true, nil
+}
+
+// SetTaskETag stores the tag per user, since the body carries the caller's own timer.
+func (c *TaskCache) SetTaskETag(ctx context.Context, taskID, userID, teamID int64, etag string) error {
+	if !c.enabled || c.client == nil {
+		return nil
+	}
+	ver, err := c.getVersion(ctx, teamID)
+	if err != nil {
+		return err
+	}
+	key := etagKey(taskID)
+	pipe := c.client.TxPipeline()
+	pipe.HSet(ctx, key, itoa(userID), itoa(teamID)+":"+itoa(ver)+":"+etag)
+	pipe.Expire(ctx, key, c.ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		c.onRedisError(err)
+		return err
+	}
+	return nil
+}
+
+// InvalidateTask drops every user's cached tag for the task.
+func (c *TaskCache) InvalidateTask(ctx context.Context, taskID int64) error {
+	if !c.enabled || c.client == nil {
+		return nil
+	}
+	if err := c.client.Del(ctx, etagKey(taskID)).Err(); err != nil {
+		c.onRedisError(err)
+		return err
+	}
+	return nil
+}
+
 func (c *TaskCache) getVersion(ctx context.Context, teamID int64) (int64, error) {
 	val, err := c.client.Get(ctx, versionKey(teamID)).Result()
 	if err == redis.Nil {
@@ -109,6 +197,10 @@ func versionKey(teamID int64) string {
 	return "tasks:team:" + itoa(teamID) + ":ver"
 }
 
+func etagKey(taskID int64) string {
+	return "tasks:task:" + itoa(taskID) + ":etag"
+}
+
 func cacheKey(teamID, ver int64, filters map[string]string) string {
 	return "tasks:team:" + itoa(teamID) + ":v:" + itoa(ver) + ":" + filtersHash(filters)
 }
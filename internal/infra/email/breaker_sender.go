@@ -20,6 +20,7 @@ type BreakerSender struct {
 
 type Sender interface {
 	SendInvite(ctx context.Context, toEmail, teamName string) error
+	SendEmailVerification(ctx context.Context, toEmail, token string) error
 }
 
 func NewBreakerSender(next Sender, cfg config.CircuitBreakerConfig, logger *slog.Logger, metrics *metricsinfra.Metrics) *BreakerSender {
@@ -40,9 +41,23 @@ func (s *BreakerSender) SendInvite(ctx context.Context, toEmail, teamName string
 	if s.next == nil {
 		return errors.New("email sender is nil")
 	}
+	return s.execute(func() error {
+		return s.next.SendInvite(ctx, toEmail, teamName)
+	})
+}
+
+func (s *BreakerSender) SendEmailVerification(ctx context.Context, toEmail, token string) error {
+	if s.next == nil {
+		return errors.New("email sender is nil")
+	}
+	return s.execute(func() error {
+		return s.next.SendEmailVerification(ctx, toEmail, token)
+	})
+}
 
+func (s *BreakerSender) execute(fn func() error) error {
 	_, err := s.cb.Execute(func() (any, error) {
-		return nil, s.next.SendInvite(ctx, toEmail, teamName)
+		return nil, fn()
 	})
 	s.observeState()
 	if err != nil {
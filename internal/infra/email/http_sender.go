@@ -20,6 +20,12 @@ type invitePayload struct {
 	TeamName string `json:"team_name"`
 }
 
+type verificationPayload struct {
+	Email    string `json:"email"`
+	Template string `json:"template"`
+	Token    string `json:"token"`
+}
+
 func NewHTTPSender(cfg config.EmailConfig) *HTTPSender {
 	return &HTTPSender{
 		baseURL: cfg.BaseURL,
@@ -30,10 +36,19 @@ func NewHTTPSender(cfg config.EmailConfig) *HTTPSender {
 }
 
 func (s *HTTPSender) SendInvite(ctx context.Context, toEmail, teamName string) error {
+	body, _ := json.Marshal(invitePayload{Email: toEmail, TeamName: teamName})
+	return s.send(ctx, body)
+}
+
+func (s *HTTPSender) SendEmailVerification(ctx context.Context, toEmail, token string) error {
+	body, _ := json.Marshal(verificationPayload{Email: toEmail, Template: "email_verification", Token: token})
+	return s.send(ctx, body)
+}
+
+func (s *HTTPSender) send(ctx context.Context, body []byte) error {
 	if s.baseURL == "" {
 		return errors.New("email base url is empty")
 	}
-	body, _ := json.Marshal(invitePayload{Email: toEmail, TeamName: teamName})
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.baseURL+"/send", bytes.NewReader(body))
 	if err != nil {
 		return err
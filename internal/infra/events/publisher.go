@@ -0,0 +1,57 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/redis/go-redis/v9"
+
+	devents "MKK-Luna/internal/domain/events"
+	metricsinfra "MKK-Luna/internal/infra/metrics"
+)
+
+const TaskEventsChannel = "events:tasks"
+
+type Publisher struct {
+	client  *redis.Client
+	logger  *slog.Logger
+	metrics *metricsinfra.Metrics
+}
+
+func NewPublisher(client *redis.Client, logger *slog.Logger, metrics *metricsinfra.Metrics) *Publisher {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Publisher{client: client, logger: logger, metrics: metrics}
+}
+
+func (p *Publisher) PublishTaskEvent(ctx context.Context, ev devents.TaskEvent) error {
+	if p.client == nil {
+		p.log(ev)
+		return nil
+	}
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	if err := p.client.Publish(ctx, TaskEventsChannel, data).Err(); err != nil {
+		p.logger.Warn("task event publish failed", "err", err, "type", ev.Type, "task_id", ev.TaskID)
+		if p.metrics != nil {
+			p.metrics.RedisDegraded.WithLabelValues("events").Inc()
+		}
+		p.log(ev)
+		return err
+	}
+	return nil
+}
+
+func (p *Publisher) log(ev devents.TaskEvent) {
+	p.logger.Info("task_event",
+		"type", ev.Type,
+		"task_id", ev.TaskID,
+		"team_id", ev.TeamID,
+		"actor_id", ev.ActorID,
+		"recipients", ev.Recipients,
+	)
+}
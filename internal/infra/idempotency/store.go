@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/url"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -60,6 +61,58 @@ func (s *Store) Set(ctx context.Context, key string, ttl time.Duration, v Stored
 	return s.client.Set(ctx, key, raw, ttl).Err()
 }
 
+// Entry is a stored response as seen by operators.
+type Entry struct {
+	Key      string
+	TTL      time.Duration
+	Response StoredResponse
+}
+
+// ResponseKey is the key a response for an Idempotency-Key is stored under.
+func ResponseKey(userID int64, routeHash, idemKey string) string {
+	return "idem:resp:" + strconv.FormatInt(userID, 10) + ":" + routeHash + ":" + idemKey
+}
+
+// ListByUser returns the stored responses of a user, up to limit.
+func (s *Store) ListByUser(ctx context.Context, userID int64, limit int) ([]Entry, error) {
+	if s == nil || s.client == nil {
+		return nil, nil
+	}
+	var out []Entry
+	iter := s.client.Scan(ctx, 0, "idem:resp:"+strconv.FormatInt(userID, 10)+":*", 500).Iterator()
+	for iter.Next(ctx) && len(out) < limit {
+		key := iter.Val()
+		v, ok, err := s.Get(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		ttl, err := s.client.TTL(ctx, key).Result()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, Entry{Key: key, TTL: ttl, Response: *v})
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Delete drops a stored response so the next request with its key runs again.
+func (s *Store) Delete(ctx context.Context, key string) (bool, error) {
+	if s == nil || s.client == nil {
+		return false, nil
+	}
+	n, err := s.client.Del(ctx, key).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
 func BuildRequestHash(method, routePattern, contentType string, query url.Values, body []byte) string {
 	contentType = strings.TrimSpace(strings.ToLower(strings.Split(contentType, ";")[0]))
 	parts := []string{
@@ -1,6 +1,10 @@
 package metrics
 
-import "github.com/prometheus/client_golang/prometheus"
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
 
 type Metrics struct {
 	Registry                *prometheus.Registry
@@ -20,6 +24,9 @@ type Metrics struct {
 	JWTBlacklistRedisErrors prometheus.Counter
 	LoginLockouts           prometheus.Counter
 	LockReleaseErrors       prometheus.Counter
+	IntegrityFindings       *prometheus.GaugeVec
+	IntegrityLastRun        *prometheus.GaugeVec
+	IntegrityLastRunOK      *prometheus.GaugeVec
 }
 
 func New() *Metrics {
@@ -131,6 +138,27 @@ func New() *Metrics {
 				Help: "Total distributed lock release errors.",
 			},
 		),
+		IntegrityFindings: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "integrity_findings",
+				Help: "Rows flagged by the last run of each integrity check, less those it repaired.",
+			},
+			[]string{"check"},
+		),
+		IntegrityLastRun: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "integrity_last_run_timestamp_seconds",
+				Help: "Unix time the last run of each integrity check finished.",
+			},
+			[]string{"check"},
+		),
+		IntegrityLastRunOK: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "integrity_last_run_success",
+				Help: "1 if the last run of each integrity check finished without error.",
+			},
+			[]string{"check"},
+		),
 	}
 
 	reg.MustRegister(
@@ -150,6 +178,9 @@ func New() *Metrics {
 		m.JWTBlacklistRedisErrors,
 		m.LoginLockouts,
 		m.LockReleaseErrors,
+		m.IntegrityFindings,
+		m.IntegrityLastRun,
+		m.IntegrityLastRunOK,
 	)
 
 	return m
@@ -182,3 +213,16 @@ func (m *Metrics) IncLockReleaseError() {
 	}
 	m.LockReleaseErrors.Inc()
 }
+
+func (m *Metrics) SetIntegrityRun(check string, findings int, ok bool, finishedAt time.Time) {
+	if m == nil {
+		return
+	}
+	m.IntegrityLastRun.WithLabelValues(check).Set(float64(finishedAt.Unix()))
+	if !ok {
+		m.IntegrityLastRunOK.WithLabelValues(check).Set(0)
+		return
+	}
+	m.IntegrityLastRunOK.WithLabelValues(check).Set(1)
+	m.IntegrityFindings.WithLabelValues(check).Set(float64(findings))
+}
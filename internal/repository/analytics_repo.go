@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"strconv"
 	"time"
 
 	"github.com/jmoiron/sqlx"
@@ -21,12 +23,128 @@ type TeamTopCreator struct {
 	Rank         int   `db:"rn"`
 }
 
+type LoggedTimeStat struct {
+	TeamID       int64 `db:"team_id"`
+	UserID       int64 `db:"user_id"`
+	Minutes      int64 `db:"minutes"`
+	EntriesCount int64 `db:"entries_count"`
+	TasksCount   int64 `db:"tasks_count"`
+}
+
+type LoggedTimeFilter struct {
+	TeamID *int64
+	UserID *int64
+}
+
+type SprintTaskState struct {
+	TaskID    int64         `db:"id"`
+	Status    string        `db:"status"`
+	SprintID  sql.NullInt64 `db:"sprint_id"`
+	CreatedAt time.Time     `db:"created_at"`
+}
+
 type TaskIntegrityIssue struct {
 	TaskID     int64 `db:"task_id"`
 	TeamID     int64 `db:"team_id"`
 	AssigneeID int64 `db:"assignee_id"`
 }
 
+// StatsTeam is a team whose stats the caller may see (owner or admin).
+type StatsTeam struct {
+	TeamID   int64  `db:"team_id"`
+	TeamName string `db:"team_name"`
+}
+
+// FlowCompletion is a task whose latest move to done falls in the stats window.
+// StartedAt is its first move to in_progress, if it ever had one.
+type FlowCompletion struct {
+	TeamID    int64        `db:"team_id"`
+	TaskID    int64        `db:"task_id"`
+	CreatedAt time.Time    `db:"created_at"`
+	StartedAt sql.NullTime `db:"started_at"`
+	DoneAt    time.Time    `db:"done_at"`
+}
+
+// WIPTask is an in_progress task and the time it last entered in_progress.
+type WIPTask struct {
+	TeamID int64     `db:"team_id"`
+	TaskID int64     `db:"task_id"`
+	Since  time.Time `db:"since"`
+}
+
+type OverdueStat struct {
+	TeamID       int64 `db:"team_id"`
+	DueCount     int64 `db:"due_count"`
+	OverdueCount int64 `db:"overdue_count"`
+}
+
+// DurationPercentiles are in hours, nearest-rank over Count samples.
+type DurationPercentiles struct {
+	Count int
+	P50   float64
+	P85   float64
+	P95   float64
+}
+
+type ThroughputPoint struct {
+	WeekStart time.Time
+	Done      int64
+}
+
+type WIPAgeBucket struct {
+	Label string
+	Count int64
+}
+
+type TeamFlowStat struct {
+	TeamID       int64
+	TeamName     string
+	CycleTime    DurationPercentiles
+	LeadTime     DurationPercentiles
+	Throughput   []ThroughputPoint
+	WIP          int64
+	WIPAge       []WIPAgeBucket
+	DueCount     int64
+	OverdueCount int64
+	OverdueRate  float64
+}
+
+// MemberWorkloadStat covers current team members. Assigned counts tasks handed to the member in
+// the window; Completed and AvgCompletionHours (created to done) count tasks they hold that were
+// done in the window; the Open* counts are their unfinished tasks now.
+type MemberWorkloadStat struct {
+	TeamID             int64           `db:"team_id"`
+	UserID             int64           `db:"user_id"`
+	Username           string          `db:"username"`
+	Role               string          `db:"role"`
+	AssignedCount      int64           `db:"assigned_count"`
+	CompletedCount     int64           `db:"completed_count"`
+	AvgCompletionHours sql.NullFloat64 `db:"avg_completion_hours"`
+	CommentsCount      int64           `db:"comments_count"`
+	OpenHigh           int64           `db:"open_high"`
+	OpenMedium         int64           `db:"open_medium"`
+	OpenLow            int64           `db:"open_low"`
+}
+
+// DoneSlot counts tasks done (as in TeamDoneStat) in one 15-minute UTC slot, numbered from the
+// Unix epoch. Every time zone offset is a multiple of 15 minutes, so slots fall into local buckets whole.
+type DoneSlot struct {
+	TeamID    int64 `db:"team_id"`
+	Slot      int64 `db:"slot"`
+	DoneCount int64 `db:"done_count"`
+}
+
+type SeriesPoint struct {
+	Start time.Time
+	Count int64
+}
+
+type TeamDoneSeries struct {
+	TeamID   int64
+	TeamName string
+	Points   []SeriesPoint
+}
+
 type AnalyticsRepository struct {
 	db *sqlx.DB
 }
@@ -51,6 +169,7 @@ LEFT JOIN (
     SELECT team_id, COUNT(*) AS done_count
     FROM tasks
     WHERE status='done'
+      AND deleted_at IS NULL
       AND updated_at >= ?
       AND updated_at < ?
     GROUP BY team_id
@@ -71,6 +190,33 @@ func (r *AnalyticsRepository) GetTeamDoneStats(ctx context.Context, userID int64
 	return rows, nil
 }
 
+const doneSlotsSQL = `
+SELECT
+  team_id,
+  TIMESTAMPDIFF(MINUTE, '1970-01-01 00:00:00', updated_at) DIV 15 AS slot,
+  COUNT(*) AS done_count
+FROM tasks
+WHERE status='done'
+  AND deleted_at IS NULL
+  AND updated_at >= ?
+  AND updated_at < ?
+  AND team_id IN (
+    SELECT team_id
+    FROM team_members
+    WHERE user_id = ?
+      AND role IN ('owner','admin')
+  )
+GROUP BY team_id, slot
+`
+
+func (r *AnalyticsRepository) GetDoneSlots(ctx context.Context, userID int64, from, to time.Time) ([]DoneSlot, error) {
+	var rows []DoneSlot
+	if err := r.db.SelectContext(ctx, &rows, doneSlotsSQL, from, to, userID); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
 const topCreatorsSQL = `
 SELECT *
 FROM (
@@ -90,6 +236,7 @@ FROM (
       FROM tasks
       WHERE created_at >= ?
         AND created_at < ?
+        AND deleted_at IS NULL
         AND team_id IN (
           SELECT team_id
           FROM team_members
@@ -110,6 +257,91 @@ func (r *AnalyticsRepository) GetTopCreatorsByTeam(ctx context.Context, userID i
 	return rows, nil
 }
 
+const loggedTimeSQL = `
+SELECT
+  t.team_id,
+  w.user_id,
+  SUM(w.minutes) AS minutes,
+  COUNT(*) AS entries_count,
+  COUNT(DISTINCT w.task_id) AS tasks_count
+FROM task_work_logs w
+JOIN tasks t ON t.id = w.task_id
+WHERE w.work_date >= ?
+  AND w.work_date < ?
+  AND w.user_id IS NOT NULL
+  AND t.deleted_at IS NULL
+  AND t.team_id IN (
+    SELECT team_id
+    FROM team_members
+    WHERE user_id = ?
+      AND role IN ('owner','admin')
+  )
+`
+
+// GetLoggedTime sums work logs per team and user; work_date is a DATE, so from/to select whole days.
+func (r *AnalyticsRepository) GetLoggedTime(ctx context.Context, userID int64, from, to time.Time, f LoggedTimeFilter) ([]LoggedTimeStat, error) {
+	query := loggedTimeSQL
+	args := []any{from, to, userID}
+	if f.TeamID != nil {
+		query += "  AND t.team_id = ?\n"
+		args = append(args, *f.TeamID)
+	}
+	if f.UserID != nil {
+		query += "  AND w.user_id = ?\n"
+		args = append(args, *f.UserID)
+	}
+	query += "GROUP BY t.team_id, w.user_id\nORDER BY t.team_id, minutes DESC, w.user_id"
+
+	var rows []LoggedTimeStat
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// Tasks that ever entered the sprint are candidates: current members plus tasks whose sprint_id history mentions it.
+const sprintCandidateTasksSQL = `
+SELECT id, status, sprint_id, created_at
+FROM tasks
+WHERE sprint_id = ? AND deleted_at IS NULL
+UNION
+SELECT t.id, t.status, t.sprint_id, t.created_at
+FROM tasks t
+JOIN task_history h ON h.task_id = t.id
+WHERE t.deleted_at IS NULL
+  AND h.field_name = 'sprint_id'
+  AND (h.old_value = CAST(? AS JSON) OR h.new_value = CAST(? AS JSON))
+`
+
+func (r *AnalyticsRepository) GetSprintTaskStates(ctx context.Context, sprintID int64) ([]SprintTaskState, error) {
+	idJSON := strconv.FormatInt(sprintID, 10)
+	var rows []SprintTaskState
+	if err := r.db.SelectContext(ctx, &rows, sprintCandidateTasksSQL, sprintID, idJSON, idJSON); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (r *AnalyticsRepository) ListHistoryForFields(ctx context.Context, taskIDs []int64, fields []string) ([]TaskHistory, error) {
+	if len(taskIDs) == 0 {
+		return nil, nil
+	}
+	query, args, err := sqlx.In(`
+		SELECT id, task_id, changed_by, field_name, old_value, new_value, created_at
+		FROM task_history
+		WHERE task_id IN (?) AND field_name IN (?)
+		ORDER BY task_id, created_at, id
+	`, taskIDs, fields)
+	if err != nil {
+		return nil, err
+	}
+	var rows []TaskHistory
+	if err := r.db.SelectContext(ctx, &rows, r.db.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
 const integrityIssuesSQL = `
 SELECT t.id AS task_id, t.team_id, t.assignee_id
 FROM tasks t
@@ -117,6 +349,7 @@ LEFT JOIN team_members tm
   ON tm.team_id = t.team_id
  AND tm.user_id = t.assignee_id
 WHERE t.assignee_id IS NOT NULL
+  AND t.deleted_at IS NULL
   AND tm.user_id IS NULL
 `
 
@@ -127,3 +360,237 @@ func (r *AnalyticsRepository) FindTasksWithAssigneeNotMember(ctx context.Context
 	}
 	return rows, nil
 }
+
+// UnassignNonMemberTx clears the assignee of a task assigned to someone outside its team.
+// It re-checks the membership, so a user re-added in the meantime keeps the task.
+func (r *AnalyticsRepository) UnassignNonMemberTx(ctx context.Context, tx *sqlx.Tx, issue TaskIntegrityIssue) (bool, error) {
+	res, err := tx.ExecContext(ctx, `
+		UPDATE tasks t
+		SET t.assignee_id = NULL, t.version = t.version + 1
+		WHERE t.id = ? AND t.assignee_id = ? AND t.deleted_at IS NULL
+		  AND NOT EXISTS (SELECT 1 FROM team_members tm WHERE tm.team_id = t.team_id AND tm.user_id = t.assignee_id)
+	`, issue.TaskID, issue.AssigneeID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+const statsTeamsSQL = `
+SELECT t.id AS team_id, t.name AS team_name
+FROM teams t
+JOIN team_members tm ON tm.team_id = t.id
+WHERE tm.user_id = ?
+  AND tm.role IN ('owner','admin')
+ORDER BY t.id
+`
+
+func (r *AnalyticsRepository) ListStatsTeams(ctx context.Context, userID int64) ([]StatsTeam, error) {
+	var rows []StatsTeam
+	if err := r.db.SelectContext(ctx, &rows, statsTeamsSQL, userID); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// Tasks reopened after the window are left out: only tasks that are still done count as completed.
+const flowCompletionsSQL = `
+SELECT
+  t.team_id,
+  t.id AS task_id,
+  t.created_at,
+  MIN(CASE WHEN h.new_value = CAST('"in_progress"' AS JSON) THEN h.created_at END) AS started_at,
+  MAX(CASE WHEN h.new_value = CAST('"done"' AS JSON) THEN h.created_at END) AS done_at
+FROM tasks t
+JOIN task_history h ON h.task_id = t.id AND h.field_name = 'status'
+WHERE t.status = 'done'
+  AND t.deleted_at IS NULL
+  AND t.team_id IN (
+    SELECT team_id
+    FROM team_members
+    WHERE user_id = ?
+      AND role IN ('owner','admin')
+  )
+GROUP BY t.team_id, t.id, t.created_at
+HAVING done_at >= ? AND done_at < ?
+`
+
+func (r *AnalyticsRepository) GetFlowCompletions(ctx context.Context, userID int64, from, to time.Time) ([]FlowCompletion, error) {
+	var rows []FlowCompletion
+	if err := r.db.SelectContext(ctx, &rows, flowCompletionsSQL, userID, from, to); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+const wipTasksSQL = `
+SELECT
+  t.team_id,
+  t.id AS task_id,
+  COALESCE((
+    SELECT MAX(h.created_at)
+    FROM task_history h
+    WHERE h.task_id = t.id
+      AND h.field_name = 'status'
+      AND h.new_value = CAST('"in_progress"' AS JSON)
+  ), t.created_at) AS since
+FROM tasks t
+WHERE t.status = 'in_progress'
+  AND t.deleted_at IS NULL
+  AND t.team_id IN (
+    SELECT team_id
+    FROM team_members
+    WHERE user_id = ?
+      AND role IN ('owner','admin')
+  )
+`
+
+func (r *AnalyticsRepository) GetWIPTasks(ctx context.Context, userID int64) ([]WIPTask, error) {
+	var rows []WIPTask
+	if err := r.db.SelectContext(ctx, &rows, wipTasksSQL, userID); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// A task due in the window is overdue when it was done after its due date, or is still open
+// and the due date has passed. The done time falls back to updated_at for tasks without history.
+const overdueStatsSQL = `
+SELECT
+  t.team_id,
+  COUNT(*) AS due_count,
+  SUM(CASE
+    WHEN t.status = 'done' THEN COALESCE((
+      SELECT MAX(h.created_at)
+      FROM task_history h
+      WHERE h.task_id = t.id
+        AND h.field_name = 'status'
+        AND h.new_value = CAST('"done"' AS JSON)
+    ), t.updated_at) >= t.due_date + INTERVAL 1 DAY
+    ELSE t.due_date < ?
+  END) AS overdue_count
+FROM tasks t
+WHERE t.due_date IS NOT NULL
+  AND t.due_date >= ?
+  AND t.due_date < ?
+  AND t.deleted_at IS NULL
+  AND t.team_id IN (
+    SELECT team_id
+    FROM team_members
+    WHERE user_id = ?
+      AND role IN ('owner','admin')
+  )
+GROUP BY t.team_id
+`
+
+func (r *AnalyticsRepository) GetOverdueStats(ctx context.Context, userID int64, from, to, today time.Time) ([]OverdueStat, error) {
+	var rows []OverdueStat
+	if err := r.db.SelectContext(ctx, &rows, overdueStatsSQL, today, from, to, userID); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// A task counts as assigned in the window when task_history sets its assignee to the member there,
+// or when it was created there already assigned to them and never reassigned.
+const memberWorkloadSQL = `
+WITH scope AS (
+  SELECT team_id
+  FROM team_members
+  WHERE user_id = ?
+    AND role IN ('owner','admin')
+)
+SELECT
+  tm.team_id,
+  tm.user_id,
+  u.username,
+  tm.role,
+  COALESCE(a.assigned_count, 0) AS assigned_count,
+  COALESCE(d.completed_count, 0) AS completed_count,
+  d.avg_completion_hours,
+  COALESCE(c.comments_count, 0) AS comments_count,
+  COALESCE(o.open_high, 0) AS open_high,
+  COALESCE(o.open_medium, 0) AS open_medium,
+  COALESCE(o.open_low, 0) AS open_low
+FROM team_members tm
+JOIN users u ON u.id = tm.user_id
+LEFT JOIN (
+    SELECT t.team_id, m.user_id, COUNT(DISTINCT t.id) AS assigned_count
+    FROM tasks t
+    JOIN team_members m ON m.team_id = t.team_id
+    WHERE t.deleted_at IS NULL
+      AND t.team_id IN (SELECT team_id FROM scope)
+      AND (
+        EXISTS (
+          SELECT 1 FROM task_history h
+          WHERE h.task_id = t.id
+            AND h.field_name = 'assignee_id'
+            AND h.new_value = CAST(m.user_id AS JSON)
+            AND h.created_at >= ?
+            AND h.created_at < ?
+        )
+        OR (
+          t.assignee_id = m.user_id
+          AND t.created_at >= ?
+          AND t.created_at < ?
+          AND NOT EXISTS (SELECT 1 FROM task_history h WHERE h.task_id = t.id AND h.field_name = 'assignee_id')
+        )
+      )
+    GROUP BY t.team_id, m.user_id
+) a ON a.team_id = tm.team_id AND a.user_id = tm.user_id
+LEFT JOIN (
+    SELECT team_id, assignee_id AS user_id, COUNT(*) AS completed_count,
+      ROUND(AVG(TIMESTAMPDIFF(SECOND, created_at, done_at)) / 3600, 1) AS avg_completion_hours
+    FROM (
+        SELECT t.team_id, t.assignee_id, t.created_at, MAX(h.created_at) AS done_at
+        FROM tasks t
+        JOIN task_history h ON h.task_id = t.id AND h.field_name = 'status' AND h.new_value = CAST('"done"' AS JSON)
+        WHERE t.status = 'done'
+          AND t.assignee_id IS NOT NULL
+          AND t.deleted_at IS NULL
+          AND t.team_id IN (SELECT team_id FROM scope)
+        GROUP BY t.id, t.team_id, t.assignee_id, t.created_at
+    ) done_tasks
+    WHERE done_at >= ?
+      AND done_at < ?
+    GROUP BY team_id, assignee_id
+) d ON d.team_id = tm.team_id AND d.user_id = tm.user_id
+LEFT JOIN (
+    SELECT t.team_id, c.user_id, COUNT(*) AS comments_count
+    FROM task_comments c
+    JOIN tasks t ON t.id = c.task_id
+    WHERE c.user_id IS NOT NULL
+      AND c.deleted_at IS NULL
+      AND t.deleted_at IS NULL
+      AND t.team_id IN (SELECT team_id FROM scope)
+      AND c.created_at >= ?
+      AND c.created_at < ?
+    GROUP BY t.team_id, c.user_id
+) c ON c.team_id = tm.team_id AND c.user_id = tm.user_id
+LEFT JOIN (
+    SELECT team_id, assignee_id AS user_id,
+      SUM(priority = 'high') AS open_high,
+      SUM(priority = 'medium') AS open_medium,
+      SUM(priority = 'low') AS open_low
+    FROM tasks
+    WHERE status <> 'done'
+      AND assignee_id IS NOT NULL
+      AND deleted_at IS NULL
+      AND team_id IN (SELECT team_id FROM scope)
+    GROUP BY team_id, assignee_id
+) o ON o.team_id = tm.team_id AND o.user_id = tm.user_id
+WHERE tm.team_id IN (SELECT team_id FROM scope)
+ORDER BY tm.team_id, tm.user_id
+`
+
+func (r *AnalyticsRepository) GetMemberWorkload(ctx context.Context, userID int64, from, to time.Time) ([]MemberWorkloadStat, error) {
+	var rows []MemberWorkloadStat
+	if err := r.db.SelectContext(ctx, &rows, memberWorkloadSQL, userID, from, to, from, to, from, to, from, to); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type AuditEvent struct {
+	ID         int64          `db:"id"`
+	ActorID    sql.NullInt64  `db:"actor_id"`
+	Action     string         `db:"action"`
+	TargetType string         `db:"target_type"`
+	TargetID   sql.NullInt64  `db:"target_id"`
+	TeamID     sql.NullInt64  `db:"team_id"`
+	IP         sql.NullString `db:"ip"`
+	RequestID  sql.NullString `db:"request_id"`
+	Payload    []byte         `db:"payload"`
+	CreatedAt  time.Time      `db:"created_at"`
+}
+
+type AuditEventCreate struct {
+	ActorID    *int64
+	Action     string
+	TargetType string
+	TargetID   *int64
+	TeamID     *int64
+	IP         string
+	RequestID  string
+	Payload    json.RawMessage
+}
+
+// AuditFilter narrows audit listings; BeforeID is a keyset cursor used by exports.
+type AuditFilter struct {
+	TeamID     *int64
+	ActorID    *int64
+	Action     *string
+	TargetType *string
+	TargetID   *int64
+	From       *time.Time
+	To         *time.Time
+	BeforeID   *int64
+}
+
+const auditColumns = `id, actor_id, action, target_type, target_id, team_id, ip, request_id, payload, created_at`
+
+type AuditRepository struct {
+	db *sqlx.DB
+}
+
+func NewAuditRepository(db *sqlx.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+func (r *AuditRepository) Create(ctx context.Context, e AuditEventCreate) error {
+	var payload any
+	if len(e.Payload) > 0 {
+		payload = []byte(e.Payload)
+	}
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO audit_events (actor_id, action, target_type, target_id, team_id, ip, request_id, payload)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, nullableInt64Ptr(e.ActorID), e.Action, e.TargetType, nullableInt64Ptr(e.TargetID), nullableInt64Ptr(e.TeamID),
+		nullableText(e.IP), nullableText(e.RequestID), payload)
+	return err
+}
+
+func (r *AuditRepository) List(ctx context.Context, f AuditFilter, limit, offset int) ([]AuditEvent, int64, error) {
+	whereSQL, args := auditWhere(f)
+
+	var total int64
+	if err := r.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM audit_events`+whereSQL, args...); err != nil {
+		return nil, 0, err
+	}
+
+	items := make([]AuditEvent, 0)
+	if err := r.db.SelectContext(ctx, &items, `
+		SELECT `+auditColumns+`
+		FROM audit_events`+whereSQL+`
+		ORDER BY id DESC
+		LIMIT ? OFFSET ?
+	`, append(args, limit, offset)...); err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// ListPage returns the next page newest first without counting, for streaming exports.
+func (r *AuditRepository) ListPage(ctx context.Context, f AuditFilter, limit int) ([]AuditEvent, error) {
+	whereSQL, args := auditWhere(f)
+	items := make([]AuditEvent, 0)
+	if err := r.db.SelectContext(ctx, &items, `
+		SELECT `+auditColumns+`
+		FROM audit_events`+whereSQL+`
+		ORDER BY id DESC
+		LIMIT ?
+	`, append(args, limit)...); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func auditWhere(f AuditFilter) (string, []any) {
+	var where []string
+	var args []any
+	if f.TeamID != nil {
+		where = append(where, "team_id = ?")
+		args = append(args, *f.TeamID)
+	}
+	if f.ActorID != nil {
+		where = append(where, "actor_id = ?")
+		args = append(args, *f.ActorID)
+	}
+	if f.Action != nil {
+		where = append(where, "action = ?")
+		args = append(args, *f.Action)
+	}
+	if f.TargetType != nil {
+		where = append(where, "target_type = ?")
+		args = append(args, *f.TargetType)
+	}
+	if f.TargetID != nil {
+		where = append(where, "target_id = ?")
+		args = append(args, *f.TargetID)
+	}
+	if f.From != nil {
+		where = append(where, "created_at >= ?")
+		args = append(args, *f.From)
+	}
+	if f.To != nil {
+		where = append(where, "created_at < ?")
+		args = append(args, *f.To)
+	}
+	if f.BeforeID != nil {
+		where = append(where, "id < ?")
+		args = append(args, *f.BeforeID)
+	}
+	if len(where) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(where, " AND "), args
+}
+
+func nullableInt64Ptr(v *int64) any {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+func nullableText(v string) any {
+	if v == "" {
+		return nil
+	}
+	return v
+}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// BoardTask is a task with its position in its board column. Unranked tasks follow the
+// ranked ones, oldest first.
+type BoardTask struct {
+	Task
+	BoardRank sql.NullString `db:"board_rank"`
+}
+
+type BoardFilter struct {
+	TeamID     int64
+	AssigneeID *int64
+	SprintID   *int64
+}
+
+type BoardRepository struct {
+	db *sqlx.DB
+}
+
+func NewBoardRepository(db *sqlx.DB) *BoardRepository {
+	return &BoardRepository{db: db}
+}
+
+func (f BoardFilter) where(status string) (string, []any) {
+	where := []string{"team_id = ?", "status = ?", "deleted_at IS NULL"}
+	args := []any{f.TeamID, status}
+	if f.AssigneeID != nil {
+		where = append(where, "assignee_id = ?")
+		args = append(args, *f.AssigneeID)
+	}
+	if f.SprintID != nil {
+		where = append(where, "sprint_id = ?")
+		args = append(args, *f.SprintID)
+	}
+	return strings.Join(where, " AND "), args
+}
+
+func (r *BoardRepository) ListColumn(ctx context.Context, f BoardFilter, status string, limit int) ([]BoardTask, int64, error) {
+	whereSQL, args := f.where(status)
+	var total int64
+	if err := r.db.GetContext(ctx, &total, "SELECT COUNT(*) FROM tasks WHERE "+whereSQL, args...); err != nil {
+		return nil, 0, err
+	}
+	items := make([]BoardTask, 0)
+	err := r.db.SelectContext(ctx, &items, `
+		SELECT `+taskColumns+`, board_rank
+		FROM tasks
+		WHERE `+whereSQL+`
+		ORDER BY board_rank IS NULL, board_rank, id
+		LIMIT ?
+	`, append(args, limit)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// GetForUpdateTx locks a live task and returns its column and rank.
+func (r *BoardRepository) GetForUpdateTx(ctx context.Context, tx *sqlx.Tx, taskID int64) (*BoardTask, error) {
+	var t BoardTask
+	err := tx.GetContext(ctx, &t, `
+		SELECT `+taskColumns+`, board_rank
+		FROM tasks WHERE id = ? AND deleted_at IS NULL FOR UPDATE
+	`, taskID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+// ListUnrankedIDsForUpdateTx returns the column's unranked tasks in display order.
+func (r *BoardRepository) ListUnrankedIDsForUpdateTx(ctx context.Context, tx *sqlx.Tx, teamID int64, status string, excludeID int64) ([]int64, error) {
+	var ids []int64
+	err := tx.SelectContext(ctx, &ids, `
+		SELECT id FROM tasks
+		WHERE team_id = ? AND status = ? AND deleted_at IS NULL AND board_rank IS NULL AND id <> ?
+		ORDER BY id
+		FOR UPDATE
+	`, teamID, status, excludeID)
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// MaxRankTx returns the last rank in the column, or "" when nothing is ranked.
+func (r *BoardRepository) MaxRankTx(ctx context.Context, tx *sqlx.Tx, teamID int64, status string, excludeID int64) (string, error) {
+	return r.neighbourRankTx(ctx, tx, `
+		SELECT board_rank FROM tasks
+		WHERE team_id = ? AND status = ? AND deleted_at IS NULL AND board_rank IS NOT NULL AND id <> ?
+		ORDER BY board_rank DESC LIMIT 1
+		FOR UPDATE
+	`, teamID, status, excludeID)
+}
+
+// NextRankTx returns the first rank after rank in the column, or "" at the bottom.
+func (r *BoardRepository) NextRankTx(ctx context.Context, tx *sqlx.Tx, teamID int64, status, rank string, excludeID int64) (string, error) {
+	return r.neighbourRankTx(ctx, tx, `
+		SELECT board_rank FROM tasks
+		WHERE team_id = ? AND status = ? AND deleted_at IS NULL AND board_rank > ? AND id <> ?
+		ORDER BY board_rank LIMIT 1
+		FOR UPDATE
+	`, teamID, status, rank, excludeID)
+}
+
+// PrevRankTx returns the last rank before rank in the column, or "" at the top.
+func (r *BoardRepository) PrevRankTx(ctx context.Context, tx *sqlx.Tx, teamID int64, status, rank string, excludeID int64) (string, error) {
+	return r.neighbourRankTx(ctx, tx, `
+		SELECT board_rank FROM tasks
+		WHERE team_id = ? AND status = ? AND deleted_at IS NULL AND board_rank < ? AND id <> ?
+		ORDER BY board_rank DESC LIMIT 1
+		FOR UPDATE
+	`, teamID, status, rank, excludeID)
+}
+
+func (r *BoardRepository) neighbourRankTx(ctx context.Context, tx *sqlx.Tx, query string, args ...any) (string, error) {
+	var rank string
+	if err := tx.GetContext(ctx, &rank, query, args...); err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		return "", err
+	}
+	return rank, nil
+}
+
+// SetRankTx changes only the position; version and updated_at are left alone.
+func (r *BoardRepository) SetRankTx(ctx context.Context, tx *sqlx.Tx, taskID int64, rank string) error {
+	_, err := tx.ExecContext(ctx, `UPDATE tasks SET board_rank = ?, updated_at = updated_at WHERE id = ?`, rank, taskID)
+	return err
+}
+
+func (r *BoardRepository) ListWIPLimits(ctx context.Context, teamID int64) (map[string]int, error) {
+	var rows []struct {
+		Status string `db:"status"`
+		Limit  int    `db:"wip_limit"`
+	}
+	if err := r.db.SelectContext(ctx, &rows, `SELECT status, wip_limit FROM team_wip_limits WHERE team_id = ?`, teamID); err != nil {
+		return nil, err
+	}
+	out := make(map[string]int, len(rows))
+	for _, row := range rows {
+		out[row.Status] = row.Limit
+	}
+	return out, nil
+}
+
+// DeleteWIPLimitsTx clears the team's limits, leaving every column unlimited.
+func (r *BoardRepository) DeleteWIPLimitsTx(ctx context.Context, tx *sqlx.Tx, teamID int64) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM team_wip_limits WHERE team_id = ?`, teamID)
+	return err
+}
+
+func (r *BoardRepository) InsertWIPLimitTx(ctx context.Context, tx *sqlx.Tx, teamID int64, status string, limit int, userID int64) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO team_wip_limits (team_id, status, wip_limit, updated_by) VALUES (?, ?, ?, ?)
+	`, teamID, status, limit, userID)
+	return err
+}
+
+// WIPLimitForUpdateTx locks the column's limit row so concurrent moves into the column
+// are counted one after another. ok is false when the column is unlimited.
+func (r *BoardRepository) WIPLimitForUpdateTx(ctx context.Context, tx *sqlx.Tx, teamID int64, status string) (int, bool, error) {
+	var limit int
+	err := tx.GetContext(ctx, &limit, `
+		SELECT wip_limit FROM team_wip_limits WHERE team_id = ? AND status = ? FOR UPDATE
+	`, teamID, status)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+	return limit, true, nil
+}
+
+func (r *BoardRepository) CountColumnTx(ctx context.Context, tx *sqlx.Tx, teamID int64, status string) (int64, error) {
+	var n int64
+	err := tx.GetContext(ctx, &n, `
+		SELECT COUNT(*) FROM tasks WHERE team_id = ? AND status = ? AND deleted_at IS NULL
+	`, teamID, status)
+	return n, err
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type CommentMention struct {
+	CommentID   int64          `db:"comment_id"`
+	UserID      int64          `db:"user_id"`
+	Username    string         `db:"username"`
+	DisplayName sql.NullString `db:"display_name"`
+}
+
+type CommentMentionRepository struct {
+	db *sqlx.DB
+}
+
+func NewCommentMentionRepository(db *sqlx.DB) *CommentMentionRepository {
+	return &CommentMentionRepository{db: db}
+}
+
+func (r *CommentMentionRepository) AddTx(ctx context.Context, tx *sqlx.Tx, commentID int64, userIDs []int64) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	placeholders := make([]string, 0, len(userIDs))
+	args := make([]any, 0, len(userIDs)*2)
+	for _, id := range userIDs {
+		placeholders = append(placeholders, "(?, ?)")
+		args = append(args, commentID, id)
+	}
+	query := `INSERT IGNORE INTO comment_mentions (comment_id, user_id) VALUES ` + strings.Join(placeholders, ", ")
+	_, err := tx.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (r *CommentMentionRepository) RemoveTx(ctx context.Context, tx *sqlx.Tx, commentID int64, userIDs []int64) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	query, args, err := sqlx.In(`DELETE FROM comment_mentions WHERE comment_id = ? AND user_id IN (?)`, commentID, userIDs)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, tx.Rebind(query), args...)
+	return err
+}
+
+func (r *CommentMentionRepository) ListUserIDsByCommentTx(ctx context.Context, tx *sqlx.Tx, commentID int64) ([]int64, error) {
+	var ids []int64
+	if err := tx.SelectContext(ctx, &ids, `SELECT user_id FROM comment_mentions WHERE comment_id = ?`, commentID); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (r *CommentMentionRepository) ListByComments(ctx context.Context, commentIDs []int64) ([]CommentMention, error) {
+	if len(commentIDs) == 0 {
+		return nil, nil
+	}
+	query, args, err := sqlx.In(`
+		SELECT cm.comment_id, cm.user_id, u.username, u.display_name
+		FROM comment_mentions cm
+		JOIN users u ON u.id = cm.user_id
+		WHERE cm.comment_id IN (?)
+		ORDER BY cm.comment_id ASC, u.username ASC
+	`, commentIDs)
+	if err != nil {
+		return nil, err
+	}
+	var items []CommentMention
+	if err := r.db.SelectContext(ctx, &items, r.db.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
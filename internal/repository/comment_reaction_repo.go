@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type CommentReactionCount struct {
+	CommentID int64  `db:"comment_id"`
+	Emoji     string `db:"emoji"`
+	Count     int64  `db:"cnt"`
+	Reacted   bool   `db:"reacted"`
+}
+
+type CommentReactionRepository struct {
+	db *sqlx.DB
+}
+
+func NewCommentReactionRepository(db *sqlx.DB) *CommentReactionRepository {
+	return &CommentReactionRepository{db: db}
+}
+
+func (r *CommentReactionRepository) Add(ctx context.Context, commentID, userID int64, emoji string) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT IGNORE INTO comment_reactions (comment_id, user_id, emoji) VALUES (?, ?, ?)`,
+		commentID, userID, emoji,
+	)
+	return err
+}
+
+func (r *CommentReactionRepository) Remove(ctx context.Context, commentID, userID int64, emoji string) error {
+	_, err := r.db.ExecContext(ctx,
+		`DELETE FROM comment_reactions WHERE comment_id = ? AND user_id = ? AND emoji = ?`,
+		commentID, userID, emoji,
+	)
+	return err
+}
+
+func (r *CommentReactionRepository) CountsByComments(ctx context.Context, viewerID int64, commentIDs []int64) ([]CommentReactionCount, error) {
+	if len(commentIDs) == 0 {
+		return nil, nil
+	}
+	query, args, err := sqlx.In(`
+		SELECT comment_id, emoji, COUNT(*) AS cnt, MAX(user_id = ?) AS reacted
+		FROM comment_reactions
+		WHERE comment_id IN (?)
+		GROUP BY comment_id, emoji
+		ORDER BY comment_id ASC, MIN(created_at) ASC
+	`, viewerID, commentIDs)
+	if err != nil {
+		return nil, err
+	}
+	var items []CommentReactionCount
+	if err := r.db.SelectContext(ctx, &items, r.db.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,246 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ExportJob is a background export of one team's data. Params holds the export request as
+// JSON; StorageKey, RowCount and SizeBytes are set once the file is written.
+type ExportJob struct {
+	ID          int64          `db:"id"`
+	TeamID      int64          `db:"team_id"`
+	RequestedBy int64          `db:"requested_by"`
+	Kind        string         `db:"kind"`
+	Format      string         `db:"format"`
+	Params      []byte         `db:"params"`
+	Status      string         `db:"status"`
+	StorageKey  sql.NullString `db:"storage_key"`
+	RowCount    sql.NullInt64  `db:"row_count"`
+	SizeBytes   sql.NullInt64  `db:"size_bytes"`
+	Error       sql.NullString `db:"error"`
+	CreatedAt   time.Time      `db:"created_at"`
+	StartedAt   sql.NullTime   `db:"started_at"`
+	FinishedAt  sql.NullTime   `db:"finished_at"`
+	ExpiresAt   sql.NullTime   `db:"expires_at"`
+}
+
+const exportJobColumns = `id, team_id, requested_by, kind, format, params, status, storage_key, row_count, size_bytes, error,
+	created_at, started_at, finished_at, expires_at`
+
+// ExportRepository reads export data row by row from an open cursor, so memory use does not
+// grow with the team; fn runs while the connection is held.
+type ExportRepository struct {
+	db *sqlx.DB
+}
+
+func NewExportRepository(db *sqlx.DB) *ExportRepository {
+	return &ExportRepository{db: db}
+}
+
+func (r *ExportRepository) CountTasks(ctx context.Context, f TaskListFilter) (int64, error) {
+	whereSQL, args := taskListWhere(f)
+	var total int64
+	err := r.db.GetContext(ctx, &total, "SELECT COUNT(*) FROM tasks WHERE "+whereSQL, args...)
+	return total, err
+}
+
+func (r *ExportRepository) StreamTasks(ctx context.Context, f TaskListFilter, fn func(Task) error) error {
+	if !IsTaskSort(f.Sort) {
+		return fmt.Errorf("unknown task sort %q", f.Sort)
+	}
+	whereSQL, args := taskListWhere(f)
+	rows, err := r.db.QueryxContext(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks
+		WHERE `+whereSQL+`
+		ORDER BY `+taskSortOrders[f.Sort], args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var t Task
+		if err := rows.StructScan(&t); err != nil {
+			return err
+		}
+		if err := fn(t); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// CountHistory counts history entries of the team's live tasks created in [from, to).
+func (r *ExportRepository) CountHistory(ctx context.Context, teamID int64, from, to time.Time) (int64, error) {
+	var total int64
+	err := r.db.GetContext(ctx, &total, `
+		SELECT COUNT(*)
+		FROM task_history h
+		JOIN tasks t ON t.id = h.task_id
+		WHERE t.team_id = ? AND t.deleted_at IS NULL AND h.created_at >= ? AND h.created_at < ?
+	`, teamID, from, to)
+	return total, err
+}
+
+func (r *ExportRepository) StreamHistory(ctx context.Context, teamID int64, from, to time.Time, fn func(TaskHistory) error) error {
+	rows, err := r.db.QueryxContext(ctx, `
+		SELECT h.id, h.task_id, h.changed_by, h.field_name, h.old_value, h.new_value, h.created_at
+		FROM task_history h
+		JOIN tasks t ON t.id = h.task_id
+		WHERE t.team_id = ? AND t.deleted_at IS NULL AND h.created_at >= ? AND h.created_at < ?
+		ORDER BY h.created_at, h.id
+	`, teamID, from, to)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var h TaskHistory
+		if err := rows.StructScan(&h); err != nil {
+			return err
+		}
+		if err := fn(h); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// CountComments counts visible comments on the team's live tasks created in [from, to).
+func (r *ExportRepository) CountComments(ctx context.Context, teamID int64, from, to time.Time) (int64, error) {
+	var total int64
+	err := r.db.GetContext(ctx, &total, `
+		SELECT COUNT(*)
+		FROM task_comments c
+		JOIN tasks t ON t.id = c.task_id
+		WHERE t.team_id = ? AND t.deleted_at IS NULL AND c.deleted_at IS NULL AND c.created_at >= ? AND c.created_at < ?
+	`, teamID, from, to)
+	return total, err
+}
+
+func (r *ExportRepository) StreamComments(ctx context.Context, teamID int64, from, to time.Time, fn func(TaskComment) error) error {
+	rows, err := r.db.QueryxContext(ctx, `
+		SELECT c.id, c.task_id, c.parent_id, c.user_id, c.body, c.edited_at, c.deleted_at, c.created_at, c.updated_at, c.version
+		FROM task_comments c
+		JOIN tasks t ON t.id = c.task_id
+		WHERE t.team_id = ? AND t.deleted_at IS NULL AND c.deleted_at IS NULL AND c.created_at >= ? AND c.created_at < ?
+		ORDER BY c.created_at, c.id
+	`, teamID, from, to)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var c TaskComment
+		if err := rows.StructScan(&c); err != nil {
+			return err
+		}
+		if err := fn(c); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (r *ExportRepository) CreateJob(ctx context.Context, j ExportJob) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `
+		INSERT INTO export_jobs (team_id, requested_by, kind, format, params)
+		VALUES (?, ?, ?, ?, ?)
+	`, j.TeamID, j.RequestedBy, j.Kind, j.Format, j.Params)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+func (r *ExportRepository) GetJob(ctx context.Context, id int64) (*ExportJob, error) {
+	var j ExportJob
+	err := r.db.GetContext(ctx, &j, `SELECT `+exportJobColumns+` FROM export_jobs WHERE id = ?`, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &j, nil
+}
+
+// ClaimJob marks the oldest pending job as running and returns it, or nil when there is none.
+// Running jobs started before staleBefore are claimed again, so a crashed worker does not
+// strand them; SKIP LOCKED lets several instances poll at once.
+func (r *ExportRepository) ClaimJob(ctx context.Context, staleBefore time.Time) (*ExportJob, error) {
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var j ExportJob
+	err = tx.GetContext(ctx, &j, `
+		SELECT `+exportJobColumns+`
+		FROM export_jobs
+		WHERE status = 'pending' OR (status = 'running' AND started_at < ?)
+		ORDER BY id
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED
+	`, staleBefore)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	now := time.Now().UTC()
+	if _, err := tx.ExecContext(ctx, `UPDATE export_jobs SET status = 'running', started_at = ? WHERE id = ?`, now, j.ID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	j.Status = "running"
+	j.StartedAt = sql.NullTime{Time: now, Valid: true}
+	return &j, nil
+}
+
+func (r *ExportRepository) FinishJob(ctx context.Context, id int64, storageKey string, rowCount, sizeBytes int64, expiresAt time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE export_jobs
+		SET status = 'done', storage_key = ?, row_count = ?, size_bytes = ?, error = NULL, finished_at = ?, expires_at = ?
+		WHERE id = ?
+	`, storageKey, rowCount, sizeBytes, time.Now().UTC(), expiresAt, id)
+	return err
+}
+
+func (r *ExportRepository) FailJob(ctx context.Context, id int64, reason string, expiresAt time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE export_jobs
+		SET status = 'failed', error = ?, finished_at = ?, expires_at = ?
+		WHERE id = ?
+	`, reason, time.Now().UTC(), expiresAt, id)
+	return err
+}
+
+// ListExpiredJobs returns finished jobs whose files may be removed.
+func (r *ExportRepository) ListExpiredJobs(ctx context.Context, before time.Time, limit int) ([]ExportJob, error) {
+	items := make([]ExportJob, 0)
+	if err := r.db.SelectContext(ctx, &items, `
+		SELECT `+exportJobColumns+`
+		FROM export_jobs
+		WHERE expires_at < ?
+		ORDER BY expires_at
+		LIMIT ?
+	`, before, limit); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *ExportRepository) DeleteJob(ctx context.Context, id int64) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM export_jobs WHERE id = ?`, id)
+	return err
+}
@@ -0,0 +1,222 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// IntegrityFinding is one row an integrity check flagged. EntityID is the row at fault;
+// RefID is the row it points at, e.g. the missing task of an orphaned history entry.
+type IntegrityFinding struct {
+	EntityID int64         `db:"entity_id"`
+	TeamID   sql.NullInt64 `db:"team_id"`
+	RefID    sql.NullInt64 `db:"ref_id"`
+}
+
+type IntegrityRun struct {
+	ID            int64          `db:"id"`
+	CheckName     string         `db:"check_name"`
+	TriggerSource string         `db:"trigger_source"`
+	TriggeredBy   sql.NullInt64  `db:"triggered_by"`
+	Repair        string         `db:"repair"`
+	Found         int            `db:"found"`
+	Repaired      int            `db:"repaired"`
+	Sample        []byte         `db:"sample"`
+	Error         sql.NullString `db:"error"`
+	StartedAt     time.Time      `db:"started_at"`
+	FinishedAt    time.Time      `db:"finished_at"`
+}
+
+const integrityRunColumns = `id, check_name, trigger_source, triggered_by, repair, found, repaired, sample, error, started_at, finished_at`
+
+// IntegrityRepository holds the queries behind the integrity checks and the stored runs.
+// Every repair re-checks its condition, so rows fixed by hand since Find are left alone.
+type IntegrityRepository struct {
+	db *sqlx.DB
+}
+
+func NewIntegrityRepository(db *sqlx.DB) *IntegrityRepository {
+	return &IntegrityRepository{db: db}
+}
+
+// FindAssigneeNotMember reports live tasks assigned to someone outside the task's team;
+// RefID is the assignee.
+func (r *IntegrityRepository) FindAssigneeNotMember(ctx context.Context, limit int) ([]IntegrityFinding, error) {
+	return r.find(ctx, `
+		SELECT t.id AS entity_id, t.team_id, t.assignee_id AS ref_id
+		FROM tasks t
+		WHERE t.assignee_id IS NOT NULL AND t.deleted_at IS NULL
+		  AND NOT EXISTS (SELECT 1 FROM team_members tm WHERE tm.team_id = t.team_id AND tm.user_id = t.assignee_id)
+		ORDER BY t.id
+		LIMIT ?
+	`, limit)
+}
+
+// FindTeamsWithoutOwner reports teams with no owner; RefID is the member who would be
+// promoted: the longest-standing admin, else the longest-standing member.
+func (r *IntegrityRepository) FindTeamsWithoutOwner(ctx context.Context, limit int) ([]IntegrityFinding, error) {
+	return r.find(ctx, `
+		SELECT t.id AS entity_id, t.id AS team_id,
+		  (SELECT tm.user_id FROM team_members tm WHERE tm.team_id = t.id
+		   ORDER BY FIELD(tm.role, 'admin', 'member'), tm.created_at, tm.user_id LIMIT 1) AS ref_id
+		FROM teams t
+		WHERE NOT EXISTS (SELECT 1 FROM team_members o WHERE o.team_id = t.id AND o.role = 'owner')
+		ORDER BY t.id
+		LIMIT ?
+	`, limit)
+}
+
+// PromoteOwnerTx makes the FindTeamsWithoutOwner candidate owner, if the team still has none.
+func (r *IntegrityRepository) PromoteOwnerTx(ctx context.Context, tx *sqlx.Tx, teamID int64) (bool, error) {
+	var members []struct {
+		UserID int64  `db:"user_id"`
+		Role   string `db:"role"`
+	}
+	err := tx.SelectContext(ctx, &members, `
+		SELECT user_id, role FROM team_members WHERE team_id = ?
+		ORDER BY FIELD(role, 'owner', 'admin', 'member'), created_at, user_id
+		FOR UPDATE
+	`, teamID)
+	if err != nil {
+		return false, err
+	}
+	if len(members) == 0 || members[0].Role == "owner" {
+		return false, nil
+	}
+	_, err = tx.ExecContext(ctx, `UPDATE team_members SET role = 'owner' WHERE team_id = ? AND user_id = ?`, teamID, members[0].UserID)
+	return err == nil, err
+}
+
+func (r *IntegrityRepository) FindOrphanedHistory(ctx context.Context, limit int) ([]IntegrityFinding, error) {
+	return r.find(ctx, `
+		SELECT h.id AS entity_id, NULL AS team_id, h.task_id AS ref_id
+		FROM task_history h
+		WHERE NOT EXISTS (SELECT 1 FROM tasks t WHERE t.id = h.task_id)
+		ORDER BY h.id
+		LIMIT ?
+	`, limit)
+}
+
+func (r *IntegrityRepository) DeleteOrphanedHistoryTx(ctx context.Context, tx *sqlx.Tx, ids []int64) (int64, error) {
+	return execIn(ctx, tx, `
+		DELETE FROM task_history
+		WHERE id IN (?) AND NOT EXISTS (SELECT 1 FROM tasks t WHERE t.id = task_history.task_id)
+	`, ids)
+}
+
+func (r *IntegrityRepository) FindOrphanedSessions(ctx context.Context, limit int) ([]IntegrityFinding, error) {
+	return r.find(ctx, `
+		SELECT s.id AS entity_id, NULL AS team_id, s.user_id AS ref_id
+		FROM sessions s
+		WHERE NOT EXISTS (SELECT 1 FROM users u WHERE u.id = s.user_id)
+		ORDER BY s.id
+		LIMIT ?
+	`, limit)
+}
+
+func (r *IntegrityRepository) DeleteOrphanedSessionsTx(ctx context.Context, tx *sqlx.Tx, ids []int64) (int64, error) {
+	return execIn(ctx, tx, `
+		DELETE FROM sessions
+		WHERE id IN (?) AND NOT EXISTS (SELECT 1 FROM users u WHERE u.id = sessions.user_id)
+	`, ids)
+}
+
+func (r *IntegrityRepository) FindCreatorNotMember(ctx context.Context, limit int) ([]IntegrityFinding, error) {
+	return r.find(ctx, `
+		SELECT t.id AS entity_id, t.team_id, t.created_by AS ref_id
+		FROM tasks t
+		WHERE t.created_by IS NOT NULL AND t.deleted_at IS NULL
+		  AND NOT EXISTS (SELECT 1 FROM team_members tm WHERE tm.team_id = t.team_id AND tm.user_id = t.created_by)
+		ORDER BY t.id
+		LIMIT ?
+	`, limit)
+}
+
+func (r *IntegrityRepository) FindUpdatedBeforeCreated(ctx context.Context, limit int) ([]IntegrityFinding, error) {
+	return r.find(ctx, `
+		SELECT id AS entity_id, team_id, NULL AS ref_id
+		FROM tasks
+		WHERE updated_at < created_at
+		ORDER BY id
+		LIMIT ?
+	`, limit)
+}
+
+func (r *IntegrityRepository) FixUpdatedBeforeCreatedTx(ctx context.Context, tx *sqlx.Tx, ids []int64) (int64, error) {
+	return execIn(ctx, tx, `UPDATE tasks SET updated_at = created_at, version = version + 1 WHERE id IN (?) AND updated_at < created_at`, ids)
+}
+
+func (r *IntegrityRepository) CreateRun(ctx context.Context, run IntegrityRun) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `
+		INSERT INTO integrity_runs (check_name, trigger_source, triggered_by, repair, found, repaired, sample, error, started_at, finished_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, run.CheckName, run.TriggerSource, run.TriggeredBy, run.Repair, run.Found, run.Repaired, run.Sample, run.Error, run.StartedAt, run.FinishedAt)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+// ListRuns returns the newest runs first, optionally of one check.
+func (r *IntegrityRepository) ListRuns(ctx context.Context, check string, limit int) ([]IntegrityRun, error) {
+	query := `SELECT ` + integrityRunColumns + ` FROM integrity_runs`
+	args := []any{}
+	if check != "" {
+		query += ` WHERE check_name = ?`
+		args = append(args, check)
+	}
+	query += ` ORDER BY id DESC LIMIT ?`
+	args = append(args, limit)
+	var runs []IntegrityRun
+	if err := r.db.SelectContext(ctx, &runs, query, args...); err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
+
+// LatestRuns returns the most recent run of every check that has run.
+func (r *IntegrityRepository) LatestRuns(ctx context.Context) ([]IntegrityRun, error) {
+	var runs []IntegrityRun
+	err := r.db.SelectContext(ctx, &runs, `
+		SELECT `+integrityRunColumns+` FROM integrity_runs
+		WHERE id IN (SELECT MAX(id) FROM integrity_runs GROUP BY check_name)
+	`)
+	if err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
+
+func (r *IntegrityRepository) DeleteRunsBefore(ctx context.Context, before time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM integrity_runs WHERE started_at < ?`, before)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (r *IntegrityRepository) find(ctx context.Context, query string, limit int) ([]IntegrityFinding, error) {
+	var rows []IntegrityFinding
+	if err := r.db.SelectContext(ctx, &rows, query, limit); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func execIn(ctx context.Context, tx *sqlx.Tx, query string, ids []int64) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	q, args, err := sqlx.In(query, ids)
+	if err != nil {
+		return 0, err
+	}
+	res, err := tx.ExecContext(ctx, tx.Rebind(q), args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
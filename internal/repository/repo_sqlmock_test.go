@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"regexp"
 	"testing"
 	"time"
@@ -181,9 +182,9 @@ func TestTaskRepository(t *testing.T) {
 		t.Fatalf("create err=%v", err)
 	}
 
-	rows := sqlmock.NewRows([]string{"id", "team_id", "title", "description", "status", "priority", "assignee_id", "created_by", "due_date", "created_at", "updated_at"}).
-		AddRow(1, 1, "t", nil, "todo", "medium", nil, nil, nil, time.Now(), time.Now())
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, team_id, title, description, status, priority, assignee_id, created_by, due_date, created_at, updated_at FROM tasks WHERE id = ?")).
+	rows := sqlmock.NewRows([]string{"id", "team_id", "title", "description", "status", "priority", "assignee_id", "created_by", "due_date", "created_at", "updated_at", "original_estimate_minutes", "remaining_estimate_minutes", "sprint_id"}).
+		AddRow(1, 1, "t", nil, "todo", "medium", nil, nil, nil, time.Now(), time.Now(), 60, 30, nil)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT " + taskColumns + " FROM tasks WHERE id = ? AND deleted_at IS NULL")).
 		WithArgs(int64(1)).
 		WillReturnRows(rows)
 	_, err = repo.GetByID(context.Background(), 1)
@@ -192,7 +193,7 @@ func TestTaskRepository(t *testing.T) {
 	}
 
 	mock.ExpectBegin()
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, team_id, title, description, status, priority, assignee_id, created_by, due_date, created_at, updated_at FROM tasks WHERE id = ? FOR UPDATE")).
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT " + taskColumns + " FROM tasks WHERE id = ? AND deleted_at IS NULL FOR UPDATE")).
 		WithArgs(int64(1)).
 		WillReturnRows(rows)
 	tx, err := db.BeginTxx(context.Background(), nil)
@@ -205,10 +206,10 @@ func TestTaskRepository(t *testing.T) {
 	}
 	_ = tx.Rollback()
 
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM tasks WHERE team_id = ?")).
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM tasks WHERE team_id = ? AND deleted_at IS NULL")).
 		WithArgs(int64(1)).
 		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, team_id, title, description, status, priority, assignee_id, created_by, due_date, created_at, updated_at FROM tasks WHERE team_id = ? ORDER BY updated_at DESC, id DESC LIMIT ? OFFSET ?")).
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT "+taskColumns+" FROM tasks WHERE team_id = ? AND deleted_at IS NULL ORDER BY updated_at DESC, id DESC LIMIT ? OFFSET ?")).
 		WithArgs(int64(1), 10, 0).
 		WillReturnRows(rows)
 	_, _, err = repo.List(context.Background(), TaskListFilter{TeamID: 1, Limit: 10, Offset: 0})
@@ -236,7 +237,7 @@ func TestTaskRepository(t *testing.T) {
 	}
 	_ = tx.Rollback()
 
-	mock.ExpectExec("DELETE FROM tasks WHERE id = ?").
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE tasks SET deleted_at = CURRENT_TIMESTAMP(3), updated_at = updated_at, version = version + 1 WHERE id = ? AND deleted_at IS NULL")).
 		WithArgs(int64(1)).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 	if err := repo.Delete(context.Background(), 1); err != nil {
@@ -244,7 +245,7 @@ func TestTaskRepository(t *testing.T) {
 	}
 
 	mock.ExpectBegin()
-	mock.ExpectExec("DELETE FROM tasks WHERE id = ?").
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE tasks SET deleted_at = CURRENT_TIMESTAMP(3), updated_at = updated_at, version = version + 1 WHERE id = ? AND deleted_at IS NULL")).
 		WithArgs(int64(1)).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 	tx, err = db.BeginTxx(context.Background(), nil)
@@ -297,17 +298,31 @@ func TestTaskCommentRepository(t *testing.T) {
 	db, mock := newMockDB(t)
 	repo := NewTaskCommentRepository(db)
 
+	mock.ExpectBegin()
 	mock.ExpectExec("INSERT INTO task_comments").
 		WithArgs(int64(1), int64(2), "body").
 		WillReturnResult(sqlmock.NewResult(1, 1))
-	_, err := repo.Create(context.Background(), 1, 2, "body")
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE task_comments SET body = ?, version = version + 1 WHERE id = ?")).
+		WithArgs("new", int64(1)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	tx, err := db.BeginTxx(context.Background(), nil)
 	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	if _, err := repo.CreateTx(context.Background(), tx, 1, 2, "body"); err != nil {
 		t.Fatalf("create err=%v", err)
 	}
+	if err := repo.UpdateTx(context.Background(), tx, 1, "new"); err != nil {
+		t.Fatalf("update err=%v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
 
-	rows := sqlmock.NewRows([]string{"id", "task_id", "user_id", "body", "created_at", "updated_at"}).
-		AddRow(1, 1, 2, "body", time.Now(), time.Now())
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, task_id, user_id, body, created_at, updated_at FROM task_comments WHERE task_id = ?")).
+	rows := sqlmock.NewRows([]string{"id", "task_id", "parent_id", "user_id", "body", "edited_at", "deleted_at", "created_at", "updated_at"}).
+		AddRow(1, 1, nil, 2, "body", nil, nil, time.Now(), time.Now())
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, task_id, parent_id, user_id, body, edited_at, deleted_at, created_at, updated_at, version FROM task_comments WHERE task_id = ?")).
 		WithArgs(int64(1)).
 		WillReturnRows(rows)
 	_, err = repo.ListByTask(context.Background(), 1)
@@ -315,7 +330,7 @@ func TestTaskCommentRepository(t *testing.T) {
 		t.Fatalf("list err=%v", err)
 	}
 
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, task_id, user_id, body, created_at, updated_at FROM task_comments WHERE id = ?")).
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, task_id, parent_id, user_id, body, edited_at, deleted_at, created_at, updated_at, version FROM task_comments WHERE id = ?")).
 		WithArgs(int64(1)).
 		WillReturnRows(rows)
 	_, err = repo.GetByID(context.Background(), 1)
@@ -323,13 +338,6 @@ func TestTaskCommentRepository(t *testing.T) {
 		t.Fatalf("get err=%v", err)
 	}
 
-	mock.ExpectExec(regexp.QuoteMeta("UPDATE task_comments SET body = ? WHERE id = ?")).
-		WithArgs("new", int64(1)).
-		WillReturnResult(sqlmock.NewResult(1, 1))
-	if err := repo.Update(context.Background(), 1, "new"); err != nil {
-		t.Fatalf("update err=%v", err)
-	}
-
 	mock.ExpectExec("DELETE FROM task_comments WHERE id = ?").
 		WithArgs(int64(1)).
 		WillReturnResult(sqlmock.NewResult(1, 1))
@@ -384,6 +392,17 @@ func TestSessionRepository(t *testing.T) {
 		t.Fatalf("revoke all err=%v", err)
 	}
 
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE sessions SET revoked_at = ? WHERE user_id = ? AND revoked_at IS NULL")).
+		WithArgs(sqlmock.AnyArg(), int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+	if err := repo.WithTx(context.Background(), func(tx *sqlx.Tx) error {
+		return repo.RevokeAllByUserTx(context.Background(), tx, 1, time.Now())
+	}); err != nil {
+		t.Fatalf("revoke all in tx err=%v", err)
+	}
+
 	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, user_id, token_hash, expires_at, revoked_at, last_used_at, user_agent, ip, created_at FROM sessions WHERE user_id = ? AND revoked_at IS NULL")).
 		WithArgs(int64(1)).
 		WillReturnRows(rows)
@@ -432,3 +451,1067 @@ func TestNewMySQL(t *testing.T) {
 	}
 	_ = db.Close()
 }
+
+func TestUserRepository_ProfileAndAccount(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewUserRepository(db)
+
+	rows := sqlmock.NewRows([]string{"id", "email", "username", "display_name", "avatar_url", "timezone", "locale", "created_at", "updated_at"}).
+		AddRow(1, "a@test.com", "user", "Jane", nil, "UTC", "en", time.Now(), time.Now())
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, email, username, display_name, avatar_url, timezone, locale, created_at, updated_at FROM users WHERE id = ?")).
+		WithArgs(int64(1)).
+		WillReturnRows(rows)
+	p, err := repo.GetProfile(context.Background(), 1)
+	if err != nil || p == nil || !p.DisplayName.Valid || p.AvatarURL.Valid {
+		t.Fatalf("get profile err=%v profile=%+v", err, p)
+	}
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET locale = ? WHERE id = ?")).
+		WithArgs("ru", int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := repo.UpdateProfile(context.Background(), 1, map[string]any{"locale": "ru"}); err != nil {
+		t.Fatalf("update profile err=%v", err)
+	}
+	if err := repo.UpdateProfile(context.Background(), 1, nil); err == nil {
+		t.Fatalf("expected error for empty update")
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM users WHERE id = ?")).
+		WithArgs(int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	tx, _ := db.BeginTxx(context.Background(), nil)
+	if err := repo.DeleteTx(context.Background(), tx, 1); err != nil {
+		t.Fatalf("delete err=%v", err)
+	}
+	_ = tx.Commit()
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+}
+
+func TestUserRepository_Directory(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewUserRepository(db)
+
+	if items, err := repo.ListSummariesByIDs(context.Background(), nil); err != nil || items != nil {
+		t.Fatalf("expected no query for empty ids, got items=%v err=%v", items, err)
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, username, email, display_name FROM users WHERE id IN (?, ?)")).
+		WithArgs(int64(1), int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username", "email", "display_name"}).
+			AddRow(1, "alice", "a@test.com", nil).
+			AddRow(2, "bob", "b@test.com", "Bob"))
+	items, err := repo.ListSummariesByIDs(context.Background(), []int64{1, 2})
+	if err != nil || len(items) != 2 || !items[1].DisplayName.Valid {
+		t.Fatalf("list summaries err=%v items=%+v", err, items)
+	}
+
+	mock.ExpectQuery("FROM users u").
+		WithArgs(int64(1), `a\_b%`, `a\_b%`, `a\_b%`, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username", "email", "display_name"}).AddRow(3, "a_bc", "c@test.com", nil))
+	found, err := repo.SearchSharingTeam(context.Background(), 1, "a_b", 10)
+	if err != nil || len(found) != 1 {
+		t.Fatalf("search err=%v items=%+v", err, found)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+}
+
+func TestTeamMemberRepository_ListByTeam(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewTeamMemberRepository(db)
+
+	mock.ExpectQuery("FROM team_members tm JOIN users u").
+		WithArgs(int64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "username", "email", "display_name", "role", "created_at"}).
+			AddRow(1, "alice", "a@test.com", nil, "owner", time.Now()))
+	items, err := repo.ListByTeam(context.Background(), 7)
+	if err != nil || len(items) != 1 || items[0].Role != "owner" {
+		t.Fatalf("list members err=%v items=%+v", err, items)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+}
+
+func TestTeamMemberRepository_RemoveTx(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewTeamMemberRepository(db)
+	ctx := context.Background()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT user_id FROM team_members WHERE team_id = ? AND role = 'owner' ORDER BY user_id FOR UPDATE")).
+		WithArgs(int64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1).AddRow(2))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT role FROM team_members WHERE team_id = ? AND user_id = ? FOR UPDATE")).
+		WithArgs(int64(7), int64(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow("member"))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM team_members WHERE team_id = ? AND user_id = ?")).
+		WithArgs(int64(7), int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE w FROM task_watchers w")).WithArgs(int64(7), int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE tt FROM task_timers tt")).WithArgs(int64(7), int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM saved_views WHERE team_id = ? AND owner_id = ?")).WithArgs(int64(7), int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM team_members WHERE team_id = ? AND user_id = ?")).
+		WithArgs(int64(7), int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	tx, err := db.Beginx()
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	owners, err := repo.ListOwnerIDsForUpdateTx(ctx, tx, 7)
+	if err != nil || len(owners) != 2 {
+		t.Fatalf("owners=%v err=%v", owners, err)
+	}
+	if role, ok, err := repo.GetRoleForUpdateTx(ctx, tx, 7, 3); err != nil || !ok || role != "member" {
+		t.Fatalf("role=%q ok=%v err=%v", role, ok, err)
+	}
+	if ok, err := repo.RemoveTx(ctx, tx, 7, 3); err != nil || !ok {
+		t.Fatalf("remove ok=%v err=%v", ok, err)
+	}
+	if ok, err := repo.RemoveTx(ctx, tx, 7, 3); err != nil || ok {
+		t.Fatalf("second remove ok=%v err=%v", ok, err)
+	}
+	_ = tx.Rollback()
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+}
+
+func TestTaskWatcherRepository(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewTaskWatcherRepository(db)
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT IGNORE INTO task_watchers (task_id, user_id) VALUES (?, ?)")).
+		WithArgs(int64(1), int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := repo.Add(context.Background(), 1, 2); err != nil {
+		t.Fatalf("add err=%v", err)
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT task_id, user_id, created_at FROM task_watchers WHERE task_id = ?")).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"task_id", "user_id", "created_at"}).AddRow(1, 2, time.Now()))
+	items, err := repo.ListByTask(context.Background(), 1)
+	if err != nil || len(items) != 1 || items[0].UserID != 2 {
+		t.Fatalf("list err=%v items=%+v", err, items)
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta("JOIN team_members tm ON tm.team_id = t.team_id AND tm.user_id = w.user_id WHERE w.task_id = ?")).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(2))
+	ids, err := repo.ListMemberIDsByTask(context.Background(), 1)
+	if err != nil || len(ids) != 1 || ids[0] != 2 {
+		t.Fatalf("member ids err=%v ids=%v", err, ids)
+	}
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM task_watchers WHERE task_id = ? AND user_id = ?")).
+		WithArgs(int64(1), int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := repo.Remove(context.Background(), 1, 2); err != nil {
+		t.Fatalf("remove err=%v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+}
+
+func TestCommentMentionRepository(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewCommentMentionRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT IGNORE INTO comment_mentions (comment_id, user_id) VALUES (?, ?), (?, ?)")).
+		WithArgs(int64(1), int64(2), int64(1), int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT user_id FROM comment_mentions WHERE comment_id = ?")).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(2).AddRow(3))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM comment_mentions WHERE comment_id = ? AND user_id IN (?)")).
+		WithArgs(int64(1), int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	tx, err := db.BeginTxx(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	if err := repo.AddTx(context.Background(), tx, 1, []int64{2, 3}); err != nil {
+		t.Fatalf("add err=%v", err)
+	}
+	if ids, err := repo.ListUserIDsByCommentTx(context.Background(), tx, 1); err != nil || len(ids) != 2 {
+		t.Fatalf("list ids=%v err=%v", ids, err)
+	}
+	if err := repo.RemoveTx(context.Background(), tx, 1, []int64{2}); err != nil {
+		t.Fatalf("remove err=%v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	mock.ExpectQuery("FROM comment_mentions cm JOIN users u").
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"comment_id", "user_id", "username", "display_name"}).AddRow(1, 3, "bob", nil))
+	items, err := repo.ListByComments(context.Background(), []int64{1})
+	if err != nil || len(items) != 1 || items[0].Username != "bob" {
+		t.Fatalf("list err=%v items=%+v", err, items)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+}
+
+func TestTaskCommentRepository_Threads(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewTaskCommentRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO task_comments (task_id, parent_id, user_id, body) VALUES (?, ?, ?, ?)")).
+		WithArgs(int64(1), int64(5), int64(2), "reply").
+		WillReturnResult(sqlmock.NewResult(6, 1))
+	mock.ExpectCommit()
+	tx, err := db.BeginTxx(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	if id, err := repo.CreateReplyTx(context.Background(), tx, 1, 2, 5, "reply"); err != nil || id != 6 {
+		t.Fatalf("reply id=%d err=%v", id, err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE task_comments SET body = ?, edited_at = CURRENT_TIMESTAMP(3), version = version + 1 WHERE id = ? AND version = ?")).
+		WithArgs("reply v2", int64(6), int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO task_comment_edits (comment_id, edited_by, body) VALUES (?, ?, ?)")).
+		WithArgs(int64(6), int64(2), "reply").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	tx, err = db.BeginTxx(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	if ok, err := repo.UpdateBodyTx(context.Background(), tx, 6, 1, "reply v2"); err != nil || !ok {
+		t.Fatalf("update body ok=%v err=%v", ok, err)
+	}
+	if err := repo.InsertEditTx(context.Background(), tx, 6, 2, "reply"); err != nil {
+		t.Fatalf("insert edit: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE task_comments SET body = ?, edited_at = CURRENT_TIMESTAMP(3), version = version + 1 WHERE id = ? AND version = ?")).
+		WithArgs("stale", int64(6), int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+	tx, err = db.BeginTxx(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	if ok, err := repo.UpdateBodyTx(context.Background(), tx, 6, 1, "stale"); err != nil || ok {
+		t.Fatalf("stale update must not apply, ok=%v err=%v", ok, err)
+	}
+	_ = tx.Rollback()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, comment_id, edited_by, body, created_at FROM task_comment_edits WHERE comment_id = ?")).
+		WithArgs(int64(6)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "comment_id", "edited_by", "body", "created_at"}).AddRow(1, 6, 2, "reply", time.Now()))
+	if edits, err := repo.ListEdits(context.Background(), 6); err != nil || len(edits) != 1 {
+		t.Fatalf("list edits err=%v items=%+v", err, edits)
+	}
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE task_comments SET deleted_at = CURRENT_TIMESTAMP(3), version = version + 1 WHERE id = ? AND version = ? AND deleted_at IS NULL")).
+		WithArgs(int64(6), int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	if ok, err := repo.SoftDelete(context.Background(), 6, 2); err != nil || !ok {
+		t.Fatalf("soft delete ok=%v err=%v", ok, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+}
+
+func TestCommentReactionRepository(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewCommentReactionRepository(db)
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT IGNORE INTO comment_reactions (comment_id, user_id, emoji) VALUES (?, ?, ?)")).
+		WithArgs(int64(1), int64(2), "👍").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := repo.Add(context.Background(), 1, 2, "👍"); err != nil {
+		t.Fatalf("add err=%v", err)
+	}
+
+	mock.ExpectQuery("FROM comment_reactions").
+		WithArgs(int64(2), int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"comment_id", "emoji", "cnt", "reacted"}).AddRow(1, "👍", 3, true))
+	items, err := repo.CountsByComments(context.Background(), 2, []int64{1})
+	if err != nil || len(items) != 1 || items[0].Count != 3 || !items[0].Reacted {
+		t.Fatalf("counts err=%v items=%+v", err, items)
+	}
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM comment_reactions WHERE comment_id = ? AND user_id = ? AND emoji = ?")).
+		WithArgs(int64(1), int64(2), "👍").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := repo.Remove(context.Background(), 1, 2, "👍"); err != nil {
+		t.Fatalf("remove err=%v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+}
+
+func TestTaskAttachmentRepository(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewTaskAttachmentRepository(db)
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO task_attachments (task_id, comment_id, uploaded_by, filename, content_type, size_bytes, storage_key) VALUES (?, ?, ?, ?, ?, ?, ?)")).
+		WithArgs(int64(1), sqlmock.AnyArg(), sqlmock.AnyArg(), "a.png", "image/png", int64(10), "tasks/1/k").
+		WillReturnResult(sqlmock.NewResult(5, 1))
+	id, err := repo.Create(context.Background(), TaskAttachment{TaskID: 1, Filename: "a.png", ContentType: "image/png", SizeBytes: 10, StorageKey: "tasks/1/k"})
+	if err != nil || id != 5 {
+		t.Fatalf("create id=%d err=%v", id, err)
+	}
+
+	cols := []string{"id", "task_id", "comment_id", "uploaded_by", "filename", "content_type", "size_bytes", "storage_key", "created_at"}
+	mock.ExpectQuery(regexp.QuoteMeta("FROM task_attachments WHERE id = ?")).
+		WithArgs(int64(9)).
+		WillReturnError(sql.ErrNoRows)
+	if a, err := repo.GetByID(context.Background(), 9); err != nil || a != nil {
+		t.Fatalf("get missing a=%v err=%v", a, err)
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM task_attachments WHERE task_id = ?")).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows(cols).AddRow(5, 1, nil, 2, "a.png", "image/png", 10, "tasks/1/k", time.Now()))
+	items, err := repo.ListByTask(context.Background(), 1)
+	if err != nil || len(items) != 1 || items[0].StorageKey != "tasks/1/k" {
+		t.Fatalf("list err=%v items=%+v", err, items)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT storage_key FROM task_attachments WHERE task_id = ?")).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"storage_key"}).AddRow("tasks/1/k"))
+	mock.ExpectRollback()
+	tx, err := db.Beginx()
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	keys, err := repo.ListKeysByTaskTx(context.Background(), tx, 1)
+	_ = tx.Rollback()
+	if err != nil || len(keys) != 1 {
+		t.Fatalf("keys err=%v keys=%v", err, keys)
+	}
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM task_attachments WHERE id = ?")).
+		WithArgs(int64(5)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := repo.Delete(context.Background(), 5); err != nil {
+		t.Fatalf("delete err=%v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+}
+
+func TestTaskWorkLogRepository(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewTaskWorkLogRepository(db)
+	day := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO task_work_logs (task_id, user_id, minutes, work_date, note) VALUES (?, ?, ?, ?, ?)")).
+		WithArgs(int64(1), int64(2), int64(30), day, nil).
+		WillReturnResult(sqlmock.NewResult(7, 1))
+	id, err := repo.Create(context.Background(), TaskWorkLog{TaskID: 1, UserID: sql.NullInt64{Int64: 2, Valid: true}, Minutes: 30, WorkDate: day})
+	if err != nil || id != 7 {
+		t.Fatalf("create id=%d err=%v", id, err)
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COALESCE(SUM(minutes), 0) FROM task_work_logs WHERE task_id = ?")).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"total"}).AddRow(90))
+	total, err := repo.TotalMinutesByTask(context.Background(), 1)
+	if err != nil || total != 90 {
+		t.Fatalf("total=%d err=%v", total, err)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT task_id, user_id, started_at FROM task_timers WHERE task_id = ? AND user_id = ? FOR UPDATE")).
+		WithArgs(int64(1), int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"task_id", "user_id", "started_at"}).AddRow(1, 2, day))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM task_timers WHERE task_id = ? AND user_id = ?")).
+		WithArgs(int64(1), int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM task_timers WHERE task_id = ? AND user_id = ? FOR UPDATE")).
+		WithArgs(int64(1), int64(3)).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+	tx, err := db.Beginx()
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	timer, err := repo.TakeTimerTx(context.Background(), tx, 1, 2)
+	if err != nil || timer == nil || !timer.StartedAt.Equal(day) {
+		t.Fatalf("take timer=%+v err=%v", timer, err)
+	}
+	timer, err = repo.TakeTimerTx(context.Background(), tx, 1, 3)
+	if err != nil || timer != nil {
+		t.Fatalf("missing timer=%+v err=%v", timer, err)
+	}
+	_ = tx.Rollback()
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+}
+
+func TestSprintRepository(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewSprintRepository(db)
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO sprints (team_id, name, goal, starts_on, ends_on, created_by) VALUES (?, ?, ?, ?, ?, ?)")).
+		WithArgs(int64(10), "S1", nil, day, day.AddDate(0, 0, 13), int64(1)).
+		WillReturnResult(sqlmock.NewResult(3, 1))
+	id, err := repo.Create(context.Background(), Sprint{TeamID: 10, Name: "S1", StartsOn: day, EndsOn: day.AddDate(0, 0, 13), CreatedBy: sql.NullInt64{Int64: 1, Valid: true}})
+	if err != nil || id != 3 {
+		t.Fatalf("create id=%d err=%v", id, err)
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM sprints WHERE team_id = ? AND closed_at IS NULL ORDER BY starts_on DESC, id DESC")).
+		WithArgs(int64(10)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "team_id", "name", "goal", "starts_on", "ends_on", "closed_at", "created_by", "created_at", "updated_at"}).
+			AddRow(3, 10, "S1", nil, day, day.AddDate(0, 0, 13), nil, 1, day, day))
+	items, err := repo.ListByTeam(context.Background(), 10, false)
+	if err != nil || len(items) != 1 || items[0].Name != "S1" {
+		t.Fatalf("list items=%+v err=%v", items, err)
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT sprint_id, COUNT(*) AS total_count, SUM(status = 'done') AS done_count FROM tasks WHERE sprint_id IN (?, ?) AND deleted_at IS NULL GROUP BY sprint_id")).
+		WithArgs(int64(3), int64(4)).
+		WillReturnRows(sqlmock.NewRows([]string{"sprint_id", "total_count", "done_count"}).AddRow(3, 5, 2))
+	counts, err := repo.TaskCounts(context.Background(), []int64{3, 4})
+	if err != nil || len(counts) != 1 || counts[0].DoneCount != 2 {
+		t.Fatalf("counts=%+v err=%v", counts, err)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM tasks WHERE sprint_id = ? AND status <> 'done' AND deleted_at IS NULL ORDER BY id FOR UPDATE")).
+		WithArgs(int64(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5).AddRow(6))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE tasks SET sprint_id = ?, version = version + 1 WHERE id IN (?, ?)")).
+		WithArgs(nil, int64(5), int64(6)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE sprints SET closed_at = ? WHERE id = ?")).
+		WithArgs(day, int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	tx, err := db.Beginx()
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	ids, err := repo.ListOpenTaskIDsTx(context.Background(), tx, 3)
+	if err != nil || len(ids) != 2 {
+		t.Fatalf("open ids=%v err=%v", ids, err)
+	}
+	if err := repo.MoveTasksTx(context.Background(), tx, ids, nil); err != nil {
+		t.Fatalf("move: %v", err)
+	}
+	if err := repo.CloseTx(context.Background(), tx, 3, day); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+}
+
+func TestTaskTemplateRepository(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewTaskTemplateRepository(db)
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO task_templates (team_id, name, title_pattern, description, priority, default_assignee_id, labels, checklist, created_by) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)")).
+		WithArgs(int64(10), "Release", "Release {{version}}", nil, "high", nil, []byte(`["ops"]`), nil, int64(1)).
+		WillReturnResult(sqlmock.NewResult(4, 1))
+	id, err := repo.Create(context.Background(), TaskTemplate{
+		TeamID: 10, Name: "Release", TitlePattern: "Release {{version}}",
+		Priority: sql.NullString{String: "high", Valid: true}, Labels: []byte(`["ops"]`),
+		CreatedBy: sql.NullInt64{Int64: 1, Valid: true},
+	})
+	if err != nil || id != 4 {
+		t.Fatalf("create id=%d err=%v", id, err)
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM task_templates WHERE team_id = ? ORDER BY name, id")).
+		WithArgs(int64(10)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "team_id", "name", "title_pattern", "description", "priority", "default_assignee_id", "labels", "checklist", "created_by", "created_at", "updated_at"}).
+			AddRow(4, 10, "Release", "Release {{version}}", nil, "high", nil, []byte(`["ops"]`), nil, 1, now, now))
+	items, err := repo.ListByTeam(context.Background(), 10)
+	if err != nil || len(items) != 1 || string(items[0].Labels) != `["ops"]` || items[0].Checklist != nil {
+		t.Fatalf("list items=%+v err=%v", items, err)
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM task_templates WHERE id = ?")).
+		WithArgs(int64(5)).
+		WillReturnError(sql.ErrNoRows)
+	if tpl, err := repo.GetByID(context.Background(), 5); err != nil || tpl != nil {
+		t.Fatalf("missing template=%+v err=%v", tpl, err)
+	}
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE task_templates SET name = ? WHERE id = ?")).
+		WithArgs("Hotfix", int64(4)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := repo.Update(context.Background(), 4, map[string]any{"name": "Hotfix"}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+}
+
+func TestTaskChecklistRepository(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewTaskChecklistRepository(db)
+	ctx := context.Background()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COALESCE(MAX(position), 0) FROM task_checklist_items WHERE task_id = ?")).
+		WithArgs(int64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(2))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO task_checklist_items (task_id, body, position, assignee_id) VALUES (?, ?, ?, ?)")).
+		WithArgs(int64(7), "write docs", 3, nil).
+		WillReturnResult(sqlmock.NewResult(11, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO task_checklist_items (task_id, body, position, assignee_id) VALUES (?, ?, ?, ?)")).
+		WithArgs(int64(7), "review", 4, int64(2)).
+		WillReturnResult(sqlmock.NewResult(12, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE task_checklist_items SET position = CASE id WHEN ? THEN ? WHEN ? THEN ? END WHERE task_id = ?")).
+		WithArgs(int64(12), 1, int64(11), 2, int64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE tasks SET version = version + 1, updated_at = updated_at WHERE id = ?")).
+		WithArgs(int64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	tx, err := db.Beginx()
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	ids, err := repo.CreateTx(ctx, tx, 7, []ChecklistItem{
+		{Body: "write docs"},
+		{Body: "review", AssigneeID: sql.NullInt64{Int64: 2, Valid: true}},
+	})
+	if err != nil || len(ids) != 2 || ids[0] != 11 || ids[1] != 12 {
+		t.Fatalf("create ids=%v err=%v", ids, err)
+	}
+	if err := repo.ReorderTx(ctx, tx, 7, []int64{12, 11}); err != nil {
+		t.Fatalf("reorder: %v", err)
+	}
+	if err := repo.TouchTaskTx(ctx, tx, 7); err != nil {
+		t.Fatalf("touch: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT task_id, COUNT(*) AS total, COALESCE(SUM(done), 0) AS done FROM task_checklist_items WHERE task_id IN (?, ?) GROUP BY task_id")).
+		WithArgs(int64(7), int64(8)).
+		WillReturnRows(sqlmock.NewRows([]string{"task_id", "total", "done"}).AddRow(7, 2, 1))
+	summaries, err := repo.Summaries(ctx, []int64{7, 8})
+	if err != nil || len(summaries) != 1 || summaries[0].Total != 2 || summaries[0].Done != 1 {
+		t.Fatalf("summaries=%+v err=%v", summaries, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+}
+
+func TestBoardRepository(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewBoardRepository(db)
+	ctx := context.Background()
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	assignee := int64(3)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM tasks WHERE team_id = ? AND status = ? AND deleted_at IS NULL AND assignee_id = ?")).
+		WithArgs(int64(10), "todo", assignee).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectQuery(regexp.QuoteMeta("ORDER BY board_rank IS NULL, board_rank, id LIMIT ?")).
+		WithArgs(int64(10), "todo", assignee, 50).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "team_id", "title", "status", "priority", "created_at", "updated_at", "version", "board_rank"}).
+			AddRow(1, 10, "a", "todo", "medium", now, now, 1, "i").
+			AddRow(2, 10, "b", "todo", "medium", now, now, 1, nil))
+	items, total, err := repo.ListColumn(ctx, BoardFilter{TeamID: 10, AssigneeID: &assignee}, "todo", 50)
+	if err != nil || total != 2 || len(items) != 2 || items[0].BoardRank.String != "i" || items[1].BoardRank.Valid {
+		t.Fatalf("column items=%+v total=%d err=%v", items, total, err)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM team_wip_limits WHERE team_id = ?")).
+		WithArgs(int64(10)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO team_wip_limits (team_id, status, wip_limit, updated_by) VALUES (?, ?, ?, ?)")).
+		WithArgs(int64(10), "in_progress", 3, int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	tx, err := db.Beginx()
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	if err := repo.DeleteWIPLimitsTx(ctx, tx, 10); err != nil {
+		t.Fatalf("delete limits: %v", err)
+	}
+	if err := repo.InsertWIPLimitTx(ctx, tx, 10, "in_progress", 3, 1); err != nil {
+		t.Fatalf("insert limit: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT wip_limit FROM team_wip_limits WHERE team_id = ? AND status = ? FOR UPDATE")).
+		WithArgs(int64(10), "done").
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE tasks SET board_rank = ?, updated_at = updated_at WHERE id = ?")).
+		WithArgs("i", int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectRollback()
+	tx, err = db.Beginx()
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	if _, ok, err := repo.WIPLimitForUpdateTx(ctx, tx, 10, "done"); err != nil || ok {
+		t.Fatalf("limit ok=%v err=%v", ok, err)
+	}
+	if err := repo.SetRankTx(ctx, tx, 2, "i"); err != nil {
+		t.Fatalf("set rank: %v", err)
+	}
+	_ = tx.Rollback()
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+}
+
+func TestTaskRepository_ListForUser(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewTaskRepository(db)
+	ctx := context.Background()
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	status := "todo"
+
+	from := "FROM tasks JOIN (SELECT id AS task_id FROM tasks FORCE INDEX (idx_tasks_assignee_id) WHERE assignee_id = ? UNION SELECT id AS task_id FROM tasks FORCE INDEX (idx_tasks_created_by) WHERE created_by = ?) mine ON mine.task_id = tasks.id WHERE deleted_at IS NULL AND team_id IN (SELECT team_id FROM team_members WHERE user_id = ?)"
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) "+from+" AND status = ?")).
+		WithArgs(int64(7), int64(7), int64(7), status).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(from+" AND status = ? ORDER BY updated_at DESC, id DESC LIMIT ? OFFSET ?")).
+		WithArgs(int64(7), int64(7), int64(7), status, 20, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "team_id", "title", "status", "priority", "created_at", "updated_at", "version"}).
+			AddRow(1, 10, "a", "todo", "medium", now, now, 1))
+	items, total, err := repo.ListForUser(ctx, UserTaskFilter{UserID: 7, Status: &status, Limit: 20})
+	if err != nil || total != 1 || len(items) != 1 {
+		t.Fatalf("items=%+v total=%d err=%v", items, total, err)
+	}
+
+	assignee := int64(9)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) "+from+" AND assignee_id = ?")).
+		WithArgs(int64(7), int64(7), int64(7), assignee).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(regexp.QuoteMeta(from+" AND assignee_id = ? ORDER BY due_date IS NULL, due_date ASC, id ASC LIMIT ? OFFSET ?")).
+		WithArgs(int64(7), int64(7), int64(7), assignee, 20, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	if _, _, err := repo.ListForUser(ctx, UserTaskFilter{UserID: 7, AssigneeID: &assignee, Sort: "due_date", Limit: 20}); err != nil {
+		t.Fatalf("sorted list: %v", err)
+	}
+	if _, _, err := repo.ListForUser(ctx, UserTaskFilter{UserID: 7, Sort: "title; DROP TABLE tasks"}); err == nil {
+		t.Fatalf("expected unknown sort to be rejected")
+	}
+
+	today := time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)
+	weekEnd := today.AddDate(0, 0, 5)
+	mock.ExpectQuery(regexp.QuoteMeta("FROM tasks JOIN (SELECT id AS task_id FROM tasks FORCE INDEX (idx_tasks_assignee_id) WHERE assignee_id = ?) mine ON mine.task_id = tasks.id WHERE deleted_at IS NULL AND team_id IN (SELECT team_id FROM team_members WHERE user_id = ?)")).
+		WithArgs(today, today, weekEnd, int64(7), int64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"total", "todo", "in_progress", "done", "overdue", "due_this_week"}).AddRow(5, 2, 1, 2, 1, 2))
+	summary, err := repo.SummaryForUser(ctx, UserTaskFilter{UserID: 7, Relation: "assigned", Status: &status}, today, weekEnd)
+	if err != nil || summary.Total != 5 || summary.Overdue != 1 || summary.DueThisWeek != 2 {
+		t.Fatalf("summary=%+v err=%v", summary, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+}
+
+func TestSavedViewRepository(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewSavedViewRepository(db)
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO saved_views (team_id, owner_id, name, filters, sort, visible_columns, created_by) VALUES (?, ?, ?, ?, ?, ?, ?)")).
+		WithArgs(int64(10), nil, "Open", []byte(`{"status":"todo"}`), "-priority", nil, int64(1)).
+		WillReturnResult(sqlmock.NewResult(3, 1))
+	id, err := repo.Create(context.Background(), SavedView{
+		TeamID: 10, Name: "Open", Filters: []byte(`{"status":"todo"}`), Sort: "-priority",
+		CreatedBy: sql.NullInt64{Int64: 1, Valid: true},
+	})
+	if err != nil || id != 3 {
+		t.Fatalf("create id=%d err=%v", id, err)
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE team_id = ? AND (owner_id IS NULL OR owner_id = ?) ORDER BY owner_id IS NOT NULL, name, id")).
+		WithArgs(int64(10), int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "team_id", "owner_id", "name", "filters", "sort", "visible_columns", "created_by", "created_at", "updated_at"}).
+			AddRow(3, 10, nil, "Open", []byte(`{"status":"todo"}`), "-priority", nil, 1, now, now).
+			AddRow(4, 10, 1, "Mine", nil, "", []byte(`["title"]`), 1, now, now))
+	items, err := repo.ListVisible(context.Background(), 10, 1)
+	if err != nil || len(items) != 2 || items[0].OwnerID.Valid || items[1].OwnerID.Int64 != 1 || string(items[1].Columns) != `["title"]` {
+		t.Fatalf("list items=%+v err=%v", items, err)
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM saved_views WHERE id = ?")).
+		WithArgs(int64(9)).
+		WillReturnError(sql.ErrNoRows)
+	if v, err := repo.GetByID(context.Background(), 9); err != nil || v != nil {
+		t.Fatalf("missing view=%+v err=%v", v, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+}
+
+func TestExportRepository(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewExportRepository(db)
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	status := "todo"
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM tasks WHERE team_id = ? AND deleted_at IS NULL AND status = ? ORDER BY priority DESC, updated_at DESC, id DESC")).
+		WithArgs(int64(10), "todo").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "team_id", "title", "description", "status", "priority", "assignee_id", "created_by", "due_date", "created_at", "updated_at",
+			"original_estimate_minutes", "remaining_estimate_minutes", "sprint_id", "deleted_at", "version"}).
+			AddRow(1, 10, "A", nil, "todo", "high", nil, 1, nil, now, now, nil, nil, nil, nil, 1).
+			AddRow(2, 10, "B", nil, "todo", "low", nil, 1, nil, now, now, nil, nil, nil, nil, 1))
+	var ids []int64
+	err := repo.StreamTasks(context.Background(), TaskListFilter{TeamID: 10, Status: &status, Sort: "-priority"}, func(t Task) error {
+		ids = append(ids, t.ID)
+		return nil
+	})
+	if err != nil || len(ids) != 2 || ids[1] != 2 {
+		t.Fatalf("stream ids=%v err=%v", ids, err)
+	}
+
+	staleBefore := now.Add(-30 * time.Minute)
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("FROM export_jobs WHERE status = 'pending' OR (status = 'running' AND started_at < ?) ORDER BY id LIMIT 1 FOR UPDATE SKIP LOCKED")).
+		WithArgs(staleBefore).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "team_id", "requested_by", "kind", "format", "params", "status", "storage_key", "row_count", "size_bytes", "error",
+			"created_at", "started_at", "finished_at", "expires_at"}).
+			AddRow(5, 10, 1, "tasks", "csv", []byte(`{}`), "pending", nil, nil, nil, nil, now, nil, nil, nil))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE export_jobs SET status = 'running', started_at = ? WHERE id = ?")).
+		WithArgs(sqlmock.AnyArg(), int64(5)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	job, err := repo.ClaimJob(context.Background(), staleBefore)
+	if err != nil || job == nil || job.ID != 5 || job.Status != "running" || !job.StartedAt.Valid {
+		t.Fatalf("claim job=%+v err=%v", job, err)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("FROM export_jobs WHERE status = 'pending'")).
+		WithArgs(staleBefore).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+	if job, err := repo.ClaimJob(context.Background(), staleBefore); err != nil || job != nil {
+		t.Fatalf("empty claim job=%+v err=%v", job, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+}
+
+func TestTaskImportRepository(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewTaskImportRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT external_id, task_id FROM task_imports WHERE team_id = ? AND external_id IN (?, ?)")).
+		WithArgs(int64(10), "A-1", "A-2").
+		WillReturnRows(sqlmock.NewRows([]string{"external_id", "task_id"}).AddRow("A-1", 7))
+	ids, err := repo.TaskIDs(context.Background(), 10, []string{"A-1", "A-2"})
+	if err != nil || len(ids) != 1 || ids["A-1"] != 7 {
+		t.Fatalf("task ids=%v err=%v", ids, err)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO task_imports (team_id, external_id, task_id, source, imported_by)")).
+		WithArgs(int64(10), "A-2", int64(8), "csv", int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	tx, err := db.Beginx()
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	if err := repo.CreateTx(context.Background(), tx, 10, "A-2", 8, "csv", 1); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+}
+
+func TestOpsRepositoryMethods(t *testing.T) {
+	db, mock := newMockDB(t)
+	ctx := context.Background()
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET password_hash = ? WHERE id = ?")).
+		WithArgs("hash", int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	if err := NewUserRepository(db).UpdatePasswordHash(ctx, 3, "hash"); err != nil {
+		t.Fatalf("update password: %v", err)
+	}
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM team_members WHERE team_id = ? AND user_id = ?")).
+		WithArgs(int64(10), int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	if removed, err := NewTeamMemberRepository(db).Remove(ctx, 10, 3); err != nil || removed {
+		t.Fatalf("remove removed=%v err=%v", removed, err)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("SET t.assignee_id = NULL, t.version = t.version + 1")).
+		WithArgs(int64(7), int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	tx, err := db.Beginx()
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	ok, err := NewAnalyticsRepository(db).UnassignNonMemberTx(ctx, tx, TaskIntegrityIssue{TaskID: 7, TeamID: 10, AssigneeID: 3})
+	if err != nil || !ok {
+		t.Fatalf("unassign ok=%v err=%v", ok, err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+}
+
+func TestIntegrityRepository(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewIntegrityRepository(db)
+	ctx := context.Background()
+
+	mock.ExpectQuery("FROM teams t").WithArgs(50).
+		WillReturnRows(sqlmock.NewRows([]string{"entity_id", "team_id", "ref_id"}).AddRow(3, 3, 7))
+	findings, err := repo.FindTeamsWithoutOwner(ctx, 50)
+	if err != nil || len(findings) != 1 || findings[0].RefID.Int64 != 7 {
+		t.Fatalf("find findings=%+v err=%v", findings, err)
+	}
+	mock.ExpectQuery("t.assignee_id AS ref_id").WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"entity_id", "team_id", "ref_id"}).AddRow(11, 3, 5).AddRow(12, 3, 6))
+	findings, err = repo.FindAssigneeNotMember(ctx, 2)
+	if err != nil || len(findings) != 2 || findings[1].RefID.Int64 != 6 {
+		t.Fatalf("assignee findings=%+v err=%v", findings, err)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT user_id, role FROM team_members").WithArgs(int64(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "role"}).AddRow(7, "admin"))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE team_members SET role = 'owner' WHERE team_id = ? AND user_id = ?")).
+		WithArgs(int64(3), int64(7)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM task_history").WithArgs(int64(1), int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE tasks SET updated_at = created_at, version = version + 1 WHERE id IN (?) AND updated_at < created_at")).
+		WithArgs(int64(4)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectRollback()
+	tx, err := db.Beginx()
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	if ok, err := repo.PromoteOwnerTx(ctx, tx, 3); err != nil || !ok {
+		t.Fatalf("promote ok=%v err=%v", ok, err)
+	}
+	if n, err := repo.DeleteOrphanedHistoryTx(ctx, tx, []int64{1, 2}); err != nil || n != 2 {
+		t.Fatalf("delete history n=%d err=%v", n, err)
+	}
+	if n, err := repo.FixUpdatedBeforeCreatedTx(ctx, tx, []int64{4}); err != nil || n != 1 {
+		t.Fatalf("fix updated_at n=%d err=%v", n, err)
+	}
+	if n, err := repo.DeleteOrphanedSessionsTx(ctx, tx, nil); err != nil || n != 0 {
+		t.Fatalf("empty delete n=%d err=%v", n, err)
+	}
+	_ = tx.Rollback()
+
+	now := time.Now().UTC()
+	mock.ExpectExec("INSERT INTO integrity_runs").
+		WithArgs("team_without_owner", "schedule", sqlmock.AnyArg(), "none", 1, 0, sqlmock.AnyArg(), sqlmock.AnyArg(), now, now).
+		WillReturnResult(sqlmock.NewResult(9, 1))
+	id, err := repo.CreateRun(ctx, IntegrityRun{CheckName: "team_without_owner", TriggerSource: "schedule", Repair: "none", Found: 1, StartedAt: now, FinishedAt: now})
+	if err != nil || id != 9 {
+		t.Fatalf("create run id=%d err=%v", id, err)
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM integrity_runs WHERE check_name = ? ORDER BY id DESC LIMIT ?")).
+		WithArgs("team_without_owner", 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "check_name", "trigger_source", "triggered_by", "repair", "found", "repaired", "sample", "error", "started_at", "finished_at"}).
+			AddRow(9, "team_without_owner", "schedule", nil, "none", 1, 0, []byte(`[]`), nil, now, now))
+	runs, err := repo.ListRuns(ctx, "team_without_owner", 10)
+	if err != nil || len(runs) != 1 || runs[0].ID != 9 {
+		t.Fatalf("list runs=%+v err=%v", runs, err)
+	}
+
+	mock.ExpectExec("DELETE FROM integrity_runs WHERE started_at").WithArgs(now).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+	if n, err := repo.DeleteRunsBefore(ctx, now); err != nil || n != 4 {
+		t.Fatalf("purge n=%d err=%v", n, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+}
+
+func TestAnalyticsFlowQueries(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewAnalyticsRepository(db)
+	ctx := context.Background()
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	today := time.Date(2026, 3, 20, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta(statsTeamsSQL)).WithArgs(int64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"team_id", "team_name"}).AddRow(1, "core"))
+	teams, err := repo.ListStatsTeams(ctx, 7)
+	if err != nil || len(teams) != 1 || teams[0].TeamName != "core" {
+		t.Fatalf("teams=%+v err=%v", teams, err)
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta(doneSlotsSQL)).WithArgs(from, to, int64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"team_id", "slot", "done_count"}).AddRow(1, 1973088, 3))
+	slots, err := repo.GetDoneSlots(ctx, 7, from, to)
+	if err != nil || len(slots) != 1 || slots[0].Slot != 1973088 || slots[0].DoneCount != 3 {
+		t.Fatalf("slots=%+v err=%v", slots, err)
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta(flowCompletionsSQL)).WithArgs(int64(7), from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"team_id", "task_id", "created_at", "started_at", "done_at"}).
+			AddRow(1, 10, from, nil, from.Add(time.Hour)))
+	done, err := repo.GetFlowCompletions(ctx, 7, from, to)
+	if err != nil || len(done) != 1 || done[0].StartedAt.Valid {
+		t.Fatalf("completions=%+v err=%v", done, err)
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta(wipTasksSQL)).WithArgs(int64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"team_id", "task_id", "since"}).AddRow(1, 11, from))
+	wip, err := repo.GetWIPTasks(ctx, 7)
+	if err != nil || len(wip) != 1 || wip[0].TaskID != 11 {
+		t.Fatalf("wip=%+v err=%v", wip, err)
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta(overdueStatsSQL)).WithArgs(today, from, to, int64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"team_id", "due_count", "overdue_count"}).AddRow(1, 4, "1"))
+	overdue, err := repo.GetOverdueStats(ctx, 7, from, to, today)
+	if err != nil || len(overdue) != 1 || overdue[0].OverdueCount != 1 {
+		t.Fatalf("overdue=%+v err=%v", overdue, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+}
+
+func TestAnalyticsMemberWorkload(t *testing.T) {
+	db, mock := newMockDB(t)
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	mock.ExpectQuery(regexp.QuoteMeta(memberWorkloadSQL)).
+		WithArgs(int64(7), from, to, from, to, from, to, from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"team_id", "user_id", "username", "role", "assigned_count", "completed_count",
+			"avg_completion_hours", "comments_count", "open_high", "open_medium", "open_low"}).
+			AddRow(1, 7, "lead", "owner", 4, 2, 36.5, 9, "1", "0", "2").
+			AddRow(1, 8, "dev", "member", 0, 0, nil, 0, 0, 0, 0))
+	rows, err := NewAnalyticsRepository(db).GetMemberWorkload(context.Background(), 7, from, to)
+	if err != nil || len(rows) != 2 {
+		t.Fatalf("rows=%+v err=%v", rows, err)
+	}
+	if rows[0].AvgCompletionHours.Float64 != 36.5 || rows[0].OpenLow != 2 || rows[1].AvgCompletionHours.Valid {
+		t.Fatalf("unexpected rows %+v", rows)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+}
+
+func TestAuditRepository(t *testing.T) {
+	db, mock := newMockDB(t)
+	repo := NewAuditRepository(db)
+	at := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	actor, team := int64(1), int64(10)
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO audit_events (actor_id, action, target_type, target_id, team_id, ip, request_id, payload) VALUES (?, ?, ?, ?, ?, ?, ?, ?)")).
+		WithArgs(actor, "team.created", "team", team, team, "10.0.0.1", nil, []byte(`{"name":"a"}`)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	if err := repo.Create(context.Background(), AuditEventCreate{
+		ActorID: &actor, Action: "team.created", TargetType: "team", TargetID: &team, TeamID: &team,
+		IP: "10.0.0.1", Payload: json.RawMessage(`{"name":"a"}`),
+	}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	action := "team.created"
+	cols := []string{"id", "actor_id", "action", "target_type", "target_id", "team_id", "ip", "request_id", "payload", "created_at"}
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM audit_events WHERE team_id = ? AND action = ? AND created_at >= ?")).
+		WithArgs(team, action, at).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM audit_events WHERE team_id = ? AND action = ? AND created_at >= ? ORDER BY id DESC LIMIT ? OFFSET ?")).
+		WithArgs(team, action, at, 20, 0).
+		WillReturnRows(sqlmock.NewRows(cols).AddRow(1, 1, action, "team", 10, 10, "10.0.0.1", nil, []byte(`{"name":"a"}`), at))
+	items, total, err := repo.List(context.Background(), AuditFilter{TeamID: &team, Action: &action, From: &at}, 20, 0)
+	if err != nil || total != 1 || len(items) != 1 || items[0].RequestID.Valid || string(items[0].Payload) != `{"name":"a"}` {
+		t.Fatalf("list items=%+v total=%d err=%v", items, total, err)
+	}
+
+	before := int64(50)
+	mock.ExpectQuery(regexp.QuoteMeta("FROM audit_events WHERE id < ? ORDER BY id DESC LIMIT ?")).
+		WithArgs(before, 500).
+		WillReturnRows(sqlmock.NewRows(cols).AddRow(2, nil, "auth.login", "user", 1, nil, nil, nil, nil, at))
+	page, err := repo.ListPage(context.Background(), AuditFilter{BeforeID: &before}, 500)
+	if err != nil || len(page) != 1 || page[0].Payload != nil {
+		t.Fatalf("page=%+v err=%v", page, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// SavedView is a named task list query. OwnerID NULL marks a view shared with the whole
+// team; otherwise only the owner sees it. Filters is a JSON object and Columns a JSON array
+// of strings; both may be NULL.
+type SavedView struct {
+	ID        int64         `db:"id"`
+	TeamID    int64         `db:"team_id"`
+	OwnerID   sql.NullInt64 `db:"owner_id"`
+	Name      string        `db:"name"`
+	Filters   []byte        `db:"filters"`
+	Sort      string        `db:"sort"`
+	Columns   []byte        `db:"visible_columns"`
+	CreatedBy sql.NullInt64 `db:"created_by"`
+	CreatedAt time.Time     `db:"created_at"`
+	UpdatedAt time.Time     `db:"updated_at"`
+}
+
+const savedViewColumns = `id, team_id, owner_id, name, filters, sort, visible_columns, created_by, created_at, updated_at`
+
+type SavedViewRepository struct {
+	db *sqlx.DB
+}
+
+func NewSavedViewRepository(db *sqlx.DB) *SavedViewRepository {
+	return &SavedViewRepository{db: db}
+}
+
+func (r *SavedViewRepository) Create(ctx context.Context, v SavedView) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `
+		INSERT INTO saved_views (team_id, owner_id, name, filters, sort, visible_columns, created_by)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, v.TeamID, nullableInt64(v.OwnerID), v.Name, nullableJSON(v.Filters), v.Sort, nullableJSON(v.Columns), nullableInt64(v.CreatedBy))
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+func (r *SavedViewRepository) GetByID(ctx context.Context, id int64) (*SavedView, error) {
+	var v SavedView
+	err := r.db.GetContext(ctx, &v, `SELECT `+savedViewColumns+` FROM saved_views WHERE id = ?`, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &v, nil
+}
+
+// ListVisible returns the team's shared views followed by the user's own, each by name.
+func (r *SavedViewRepository) ListVisible(ctx context.Context, teamID, userID int64) ([]SavedView, error) {
+	items := make([]SavedView, 0)
+	if err := r.db.SelectContext(ctx, &items, `
+		SELECT `+savedViewColumns+` FROM saved_views
+		WHERE team_id = ? AND (owner_id IS NULL OR owner_id = ?)
+		ORDER BY owner_id IS NOT NULL, name, id
+	`, teamID, userID); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *SavedViewRepository) Update(ctx context.Context, id int64, fields map[string]any) error {
+	if len(fields) == 0 {
+		return fmt.Errorf("no fields to update")
+	}
+	cols := make([]string, 0, len(fields))
+	args := make([]any, 0, len(fields)+1)
+	for k, v := range fields {
+		cols = append(cols, k+" = ?")
+		args = append(args, v)
+	}
+	args = append(args, id)
+	_, err := r.db.ExecContext(ctx, "UPDATE saved_views SET "+strings.Join(cols, ", ")+" WHERE id = ?", args...)
+	return err
+}
+
+func (r *SavedViewRepository) Delete(ctx context.Context, id int64) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM saved_views WHERE id = ?`, id)
+	return err
+}
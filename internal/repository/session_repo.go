@@ -60,6 +60,11 @@ func (r *SessionRepository) RevokeAllByUser(ctx context.Context, userID int64, r
 	return err
 }
 
+func (r *SessionRepository) RevokeAllByUserTx(ctx context.Context, tx *sqlx.Tx, userID int64, revokedAt time.Time) error {
+	_, err := tx.ExecContext(ctx, `UPDATE sessions SET revoked_at = ? WHERE user_id = ? AND revoked_at IS NULL`, revokedAt, userID)
+	return err
+}
+
 func (r *SessionRepository) GetActiveSessionsByUser(ctx context.Context, userID int64) ([]Session, error) {
 	var sessions []Session
 	err := r.db.SelectContext(ctx, &sessions,
@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type Sprint struct {
+	ID        int64          `db:"id"`
+	TeamID    int64          `db:"team_id"`
+	Name      string         `db:"name"`
+	Goal      sql.NullString `db:"goal"`
+	StartsOn  time.Time      `db:"starts_on"`
+	EndsOn    time.Time      `db:"ends_on"`
+	ClosedAt  sql.NullTime   `db:"closed_at"`
+	CreatedBy sql.NullInt64  `db:"created_by"`
+	CreatedAt time.Time      `db:"created_at"`
+	UpdatedAt time.Time      `db:"updated_at"`
+}
+
+type SprintTaskCounts struct {
+	SprintID   int64 `db:"sprint_id"`
+	TotalCount int64 `db:"total_count"`
+	DoneCount  int64 `db:"done_count"`
+}
+
+const sprintColumns = `id, team_id, name, goal, starts_on, ends_on, closed_at, created_by, created_at, updated_at`
+
+type SprintRepository struct {
+	db *sqlx.DB
+}
+
+func NewSprintRepository(db *sqlx.DB) *SprintRepository {
+	return &SprintRepository{db: db}
+}
+
+func (r *SprintRepository) Create(ctx context.Context, s Sprint) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `
+		INSERT INTO sprints (team_id, name, goal, starts_on, ends_on, created_by)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, s.TeamID, s.Name, nullableString(s.Goal), s.StartsOn, s.EndsOn, nullableInt64(s.CreatedBy))
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+func (r *SprintRepository) GetByID(ctx context.Context, id int64) (*Sprint, error) {
+	var s Sprint
+	err := r.db.GetContext(ctx, &s, `SELECT `+sprintColumns+` FROM sprints WHERE id = ?`, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *SprintRepository) GetByIDForUpdateTx(ctx context.Context, tx *sqlx.Tx, id int64) (*Sprint, error) {
+	var s Sprint
+	err := tx.GetContext(ctx, &s, `SELECT `+sprintColumns+` FROM sprints WHERE id = ? FOR UPDATE`, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *SprintRepository) ListByTeam(ctx context.Context, teamID int64, includeClosed bool) ([]Sprint, error) {
+	query := `SELECT ` + sprintColumns + ` FROM sprints WHERE team_id = ?`
+	if !includeClosed {
+		query += ` AND closed_at IS NULL`
+	}
+	query += ` ORDER BY starts_on DESC, id DESC`
+	var items []Sprint
+	if err := r.db.SelectContext(ctx, &items, query, teamID); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *SprintRepository) TaskCounts(ctx context.Context, ids []int64) ([]SprintTaskCounts, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	query, args, err := sqlx.In(`
+		SELECT sprint_id, COUNT(*) AS total_count, SUM(status = 'done') AS done_count
+		FROM tasks
+		WHERE sprint_id IN (?) AND deleted_at IS NULL
+		GROUP BY sprint_id
+	`, ids)
+	if err != nil {
+		return nil, err
+	}
+	var rows []SprintTaskCounts
+	if err := r.db.SelectContext(ctx, &rows, r.db.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (r *SprintRepository) Update(ctx context.Context, id int64, fields map[string]any) error {
+	if len(fields) == 0 {
+		return fmt.Errorf("no fields to update")
+	}
+	cols := make([]string, 0, len(fields))
+	args := make([]any, 0, len(fields)+1)
+	for k, v := range fields {
+		cols = append(cols, k+" = ?")
+		args = append(args, v)
+	}
+	args = append(args, id)
+	_, err := r.db.ExecContext(ctx, "UPDATE sprints SET "+strings.Join(cols, ", ")+" WHERE id = ?", args...)
+	return err
+}
+
+func (r *SprintRepository) Delete(ctx context.Context, id int64) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM sprints WHERE id = ?`, id)
+	return err
+}
+
+func (r *SprintRepository) CloseTx(ctx context.Context, tx *sqlx.Tx, id int64, closedAt time.Time) error {
+	_, err := tx.ExecContext(ctx, `UPDATE sprints SET closed_at = ? WHERE id = ?`, closedAt, id)
+	return err
+}
+
+func (r *SprintRepository) ListOpenTaskIDsTx(ctx context.Context, tx *sqlx.Tx, sprintID int64) ([]int64, error) {
+	var ids []int64
+	err := tx.SelectContext(ctx, &ids, `
+		SELECT id FROM tasks WHERE sprint_id = ? AND status <> 'done' AND deleted_at IS NULL ORDER BY id FOR UPDATE
+	`, sprintID)
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (r *SprintRepository) MoveTasksTx(ctx context.Context, tx *sqlx.Tx, taskIDs []int64, target *int64) error {
+	if len(taskIDs) == 0 {
+		return nil
+	}
+	var targetVal any
+	if target != nil {
+		targetVal = *target
+	}
+	query, args, err := sqlx.In(`UPDATE tasks SET sprint_id = ?, version = version + 1 WHERE id IN (?)`, targetVal, taskIDs)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, tx.Rebind(query), args...)
+	return err
+}
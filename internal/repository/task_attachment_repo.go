@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type TaskAttachment struct {
+	ID          int64         `db:"id"`
+	TaskID      int64         `db:"task_id"`
+	CommentID   sql.NullInt64 `db:"comment_id"`
+	UploadedBy  sql.NullInt64 `db:"uploaded_by"`
+	Filename    string        `db:"filename"`
+	ContentType string        `db:"content_type"`
+	SizeBytes   int64         `db:"size_bytes"`
+	StorageKey  string        `db:"storage_key"`
+	CreatedAt   time.Time     `db:"created_at"`
+}
+
+type TaskAttachmentRepository struct {
+	db *sqlx.DB
+}
+
+func NewTaskAttachmentRepository(db *sqlx.DB) *TaskAttachmentRepository {
+	return &TaskAttachmentRepository{db: db}
+}
+
+func (r *TaskAttachmentRepository) Create(ctx context.Context, a TaskAttachment) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `
+		INSERT INTO task_attachments (task_id, comment_id, uploaded_by, filename, content_type, size_bytes, storage_key)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, a.TaskID, a.CommentID, a.UploadedBy, a.Filename, a.ContentType, a.SizeBytes, a.StorageKey)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+func (r *TaskAttachmentRepository) GetByID(ctx context.Context, id int64) (*TaskAttachment, error) {
+	var a TaskAttachment
+	err := r.db.GetContext(ctx, &a, `
+		SELECT id, task_id, comment_id, uploaded_by, filename, content_type, size_bytes, storage_key, created_at
+		FROM task_attachments WHERE id = ?
+	`, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *TaskAttachmentRepository) ListByTask(ctx context.Context, taskID int64) ([]TaskAttachment, error) {
+	var items []TaskAttachment
+	err := r.db.SelectContext(ctx, &items, `
+		SELECT id, task_id, comment_id, uploaded_by, filename, content_type, size_bytes, storage_key, created_at
+		FROM task_attachments
+		WHERE task_id = ?
+		ORDER BY created_at ASC, id ASC
+	`, taskID)
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *TaskAttachmentRepository) ListKeysByTaskTx(ctx context.Context, tx *sqlx.Tx, taskID int64) ([]string, error) {
+	var keys []string
+	if err := tx.SelectContext(ctx, &keys, `SELECT storage_key FROM task_attachments WHERE task_id = ?`, taskID); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (r *TaskAttachmentRepository) Delete(ctx context.Context, id int64) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM task_attachments WHERE id = ?`, id)
+	return err
+}
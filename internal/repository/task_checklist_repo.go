@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type ChecklistItem struct {
+	ID         int64         `db:"id"`
+	TaskID     int64         `db:"task_id"`
+	Body       string        `db:"body"`
+	Done       bool          `db:"done"`
+	Position   int           `db:"position"`
+	AssigneeID sql.NullInt64 `db:"assignee_id"`
+	DoneBy     sql.NullInt64 `db:"done_by"`
+	DoneAt     sql.NullTime  `db:"done_at"`
+	CreatedAt  time.Time     `db:"created_at"`
+	UpdatedAt  time.Time     `db:"updated_at"`
+}
+
+type ChecklistSummary struct {
+	TaskID int64 `db:"task_id"`
+	Total  int64 `db:"total"`
+	Done   int64 `db:"done"`
+}
+
+const checklistColumns = `id, task_id, body, done, position, assignee_id, done_by, done_at, created_at, updated_at`
+
+// touchTaskSQL bumps the parent task's version so its ETag changes with the checklist.
+// updated_at is kept so done-stats windows do not shift.
+const touchTaskSQL = `UPDATE tasks SET version = version + 1, updated_at = updated_at WHERE id = ?`
+
+type TaskChecklistRepository struct {
+	db *sqlx.DB
+}
+
+func NewTaskChecklistRepository(db *sqlx.DB) *TaskChecklistRepository {
+	return &TaskChecklistRepository{db: db}
+}
+
+func (r *TaskChecklistRepository) ListByTask(ctx context.Context, taskID int64) ([]ChecklistItem, error) {
+	items := make([]ChecklistItem, 0)
+	if err := r.db.SelectContext(ctx, &items, `
+		SELECT `+checklistColumns+` FROM task_checklist_items WHERE task_id = ? ORDER BY position, id
+	`, taskID); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *TaskChecklistRepository) GetByID(ctx context.Context, id int64) (*ChecklistItem, error) {
+	var item ChecklistItem
+	err := r.db.GetContext(ctx, &item, `SELECT `+checklistColumns+` FROM task_checklist_items WHERE id = ?`, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (r *TaskChecklistRepository) GetByIDForUpdateTx(ctx context.Context, tx *sqlx.Tx, id int64) (*ChecklistItem, error) {
+	var item ChecklistItem
+	err := tx.GetContext(ctx, &item, `SELECT `+checklistColumns+` FROM task_checklist_items WHERE id = ? FOR UPDATE`, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &item, nil
+}
+
+// CreateTx appends items after the current last position, in the given order.
+func (r *TaskChecklistRepository) CreateTx(ctx context.Context, tx *sqlx.Tx, taskID int64, items []ChecklistItem) ([]int64, error) {
+	var last int
+	if err := tx.GetContext(ctx, &last, `SELECT COALESCE(MAX(position), 0) FROM task_checklist_items WHERE task_id = ?`, taskID); err != nil {
+		return nil, err
+	}
+	ids := make([]int64, 0, len(items))
+	for i, item := range items {
+		res, err := tx.ExecContext(ctx, `
+			INSERT INTO task_checklist_items (task_id, body, position, assignee_id) VALUES (?, ?, ?, ?)
+		`, taskID, item.Body, last+i+1, nullableInt64(item.AssigneeID))
+		if err != nil {
+			return nil, err
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (r *TaskChecklistRepository) UpdateTx(ctx context.Context, tx *sqlx.Tx, id int64, fields map[string]any) error {
+	if len(fields) == 0 {
+		return fmt.Errorf("no fields to update")
+	}
+	cols := make([]string, 0, len(fields))
+	args := make([]any, 0, len(fields)+1)
+	for k, v := range fields {
+		cols = append(cols, k+" = ?")
+		args = append(args, v)
+	}
+	args = append(args, id)
+	_, err := tx.ExecContext(ctx, "UPDATE task_checklist_items SET "+strings.Join(cols, ", ")+" WHERE id = ?", args...)
+	return err
+}
+
+func (r *TaskChecklistRepository) SetDoneTx(ctx context.Context, tx *sqlx.Tx, id int64, done bool, userID int64) error {
+	if done {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE task_checklist_items SET done = 1, done_by = ?, done_at = CURRENT_TIMESTAMP(3) WHERE id = ?
+		`, userID, id)
+		return err
+	}
+	_, err := tx.ExecContext(ctx, `UPDATE task_checklist_items SET done = 0, done_by = NULL, done_at = NULL WHERE id = ?`, id)
+	return err
+}
+
+func (r *TaskChecklistRepository) DeleteTx(ctx context.Context, tx *sqlx.Tx, id int64) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM task_checklist_items WHERE id = ?`, id)
+	return err
+}
+
+func (r *TaskChecklistRepository) ListIDsForUpdateTx(ctx context.Context, tx *sqlx.Tx, taskID int64) ([]int64, error) {
+	var ids []int64
+	if err := tx.SelectContext(ctx, &ids, `
+		SELECT id FROM task_checklist_items WHERE task_id = ? ORDER BY position, id FOR UPDATE
+	`, taskID); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// ReorderTx renumbers the task's items 1..n in the order of ids.
+func (r *TaskChecklistRepository) ReorderTx(ctx context.Context, tx *sqlx.Tx, taskID int64, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	var sb strings.Builder
+	args := make([]any, 0, len(ids)*2+1)
+	sb.WriteString("UPDATE task_checklist_items SET position = CASE id")
+	for i, id := range ids {
+		sb.WriteString(" WHEN ? THEN ?")
+		args = append(args, id, i+1)
+	}
+	sb.WriteString(" END WHERE task_id = ?")
+	args = append(args, taskID)
+	_, err := tx.ExecContext(ctx, sb.String(), args...)
+	return err
+}
+
+func (r *TaskChecklistRepository) TouchTaskTx(ctx context.Context, tx *sqlx.Tx, taskID int64) error {
+	_, err := tx.ExecContext(ctx, touchTaskSQL, taskID)
+	return err
+}
+
+func (r *TaskChecklistRepository) Summaries(ctx context.Context, taskIDs []int64) ([]ChecklistSummary, error) {
+	if len(taskIDs) == 0 {
+		return nil, nil
+	}
+	query, args, err := sqlx.In(`
+		SELECT task_id, COUNT(*) AS total, COALESCE(SUM(done), 0) AS done
+		FROM task_checklist_items
+		WHERE task_id IN (?)
+		GROUP BY task_id
+	`, taskIDs)
+	if err != nil {
+		return nil, err
+	}
+	var rows []ChecklistSummary
+	if err := r.db.SelectContext(ctx, &rows, r.db.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
@@ -9,12 +9,24 @@ import (
 )
 
 type TaskComment struct {
-	ID        int64     `db:"id"`
-	TaskID    int64     `db:"task_id"`
-	UserID    int64     `db:"user_id"`
-	Body      string    `db:"body"`
-	CreatedAt time.Time `db:"created_at"`
-	UpdatedAt time.Time `db:"updated_at"`
+	ID        int64         `db:"id"`
+	TaskID    int64         `db:"task_id"`
+	ParentID  sql.NullInt64 `db:"parent_id"`
+	UserID    sql.NullInt64 `db:"user_id"`
+	Body      string        `db:"body"`
+	EditedAt  sql.NullTime  `db:"edited_at"`
+	DeletedAt sql.NullTime  `db:"deleted_at"`
+	CreatedAt time.Time     `db:"created_at"`
+	UpdatedAt time.Time     `db:"updated_at"`
+	Version   int64         `db:"version"`
+}
+
+type TaskCommentEdit struct {
+	ID        int64         `db:"id"`
+	CommentID int64         `db:"comment_id"`
+	EditedBy  sql.NullInt64 `db:"edited_by"`
+	Body      string        `db:"body"`
+	CreatedAt time.Time     `db:"created_at"`
 }
 
 type TaskCommentRepository struct {
@@ -25,8 +37,8 @@ func NewTaskCommentRepository(db *sqlx.DB) *TaskCommentRepository {
 	return &TaskCommentRepository{db: db}
 }
 
-func (r *TaskCommentRepository) Create(ctx context.Context, taskID, userID int64, body string) (int64, error) {
-	res, err := r.db.ExecContext(ctx,
+func (r *TaskCommentRepository) CreateTx(ctx context.Context, tx *sqlx.Tx, taskID, userID int64, body string) (int64, error) {
+	res, err := tx.ExecContext(ctx,
 		`INSERT INTO task_comments (task_id, user_id, body) VALUES (?, ?, ?)`,
 		taskID, userID, body,
 	)
@@ -39,7 +51,7 @@ func (r *TaskCommentRepository) Create(ctx context.Context, taskID, userID int64
 func (r *TaskCommentRepository) ListByTask(ctx context.Context, taskID int64) ([]TaskComment, error) {
 	var items []TaskComment
 	err := r.db.SelectContext(ctx, &items, `
-		SELECT id, task_id, user_id, body, created_at, updated_at
+		SELECT id, task_id, parent_id, user_id, body, edited_at, deleted_at, created_at, updated_at, version
 		FROM task_comments
 		WHERE task_id = ?
 		ORDER BY created_at ASC, id ASC
@@ -53,7 +65,7 @@ func (r *TaskCommentRepository) ListByTask(ctx context.Context, taskID int64) ([
 func (r *TaskCommentRepository) GetByID(ctx context.Context, commentID int64) (*TaskComment, error) {
 	var c TaskComment
 	err := r.db.GetContext(ctx, &c, `
-		SELECT id, task_id, user_id, body, created_at, updated_at
+		SELECT id, task_id, parent_id, user_id, body, edited_at, deleted_at, created_at, updated_at, version
 		FROM task_comments WHERE id = ?
 	`, commentID)
 	if err != nil {
@@ -65,8 +77,8 @@ func (r *TaskCommentRepository) GetByID(ctx context.Context, commentID int64) (*
 	return &c, nil
 }
 
-func (r *TaskCommentRepository) Update(ctx context.Context, commentID int64, body string) error {
-	_, err := r.db.ExecContext(ctx, `UPDATE task_comments SET body = ? WHERE id = ?`, body, commentID)
+func (r *TaskCommentRepository) UpdateTx(ctx context.Context, tx *sqlx.Tx, commentID int64, body string) error {
+	_, err := tx.ExecContext(ctx, `UPDATE task_comments SET body = ?, version = version + 1 WHERE id = ?`, body, commentID)
 	return err
 }
 
@@ -74,3 +86,72 @@ func (r *TaskCommentRepository) Delete(ctx context.Context, commentID int64) err
 	_, err := r.db.ExecContext(ctx, `DELETE FROM task_comments WHERE id = ?`, commentID)
 	return err
 }
+
+func (r *TaskCommentRepository) AnonymizeByUserTx(ctx context.Context, tx *sqlx.Tx, userID int64) error {
+	_, err := tx.ExecContext(ctx, `UPDATE task_comments SET user_id = NULL, version = version + 1 WHERE user_id = ?`, userID)
+	return err
+}
+
+func (r *TaskCommentRepository) CreateReplyTx(ctx context.Context, tx *sqlx.Tx, taskID, userID, parentID int64, body string) (int64, error) {
+	res, err := tx.ExecContext(ctx,
+		`INSERT INTO task_comments (task_id, parent_id, user_id, body) VALUES (?, ?, ?, ?)`,
+		taskID, parentID, userID, body,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+// UpdateBodyTx only applies when the comment is still at version; false means it changed since it was read.
+func (r *TaskCommentRepository) UpdateBodyTx(ctx context.Context, tx *sqlx.Tx, commentID, version int64, body string) (bool, error) {
+	res, err := tx.ExecContext(ctx,
+		`UPDATE task_comments SET body = ?, edited_at = CURRENT_TIMESTAMP(3), version = version + 1 WHERE id = ? AND version = ?`,
+		body, commentID, version,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (r *TaskCommentRepository) InsertEditTx(ctx context.Context, tx *sqlx.Tx, commentID, editorID int64, prevBody string) error {
+	_, err := tx.ExecContext(ctx,
+		`INSERT INTO task_comment_edits (comment_id, edited_by, body) VALUES (?, ?, ?)`,
+		commentID, editorID, prevBody,
+	)
+	return err
+}
+
+func (r *TaskCommentRepository) SoftDelete(ctx context.Context, commentID, version int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE task_comments SET deleted_at = CURRENT_TIMESTAMP(3), version = version + 1 WHERE id = ? AND version = ? AND deleted_at IS NULL`,
+		commentID, version,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (r *TaskCommentRepository) ListEdits(ctx context.Context, commentID int64) ([]TaskCommentEdit, error) {
+	var items []TaskCommentEdit
+	err := r.db.SelectContext(ctx, &items, `
+		SELECT id, comment_id, edited_by, body, created_at
+		FROM task_comment_edits
+		WHERE comment_id = ?
+		ORDER BY created_at DESC, id DESC
+	`, commentID)
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
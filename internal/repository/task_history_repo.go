@@ -83,3 +83,24 @@ func (r *TaskHistoryRepository) ListByTask(ctx context.Context, taskID int64, li
 
 	return items, total, nil
 }
+
+func (r *TaskHistoryRepository) ListAllByTask(ctx context.Context, taskID int64) ([]TaskHistory, error) {
+	return listAllTaskHistory(ctx, r.db, taskID)
+}
+
+func (r *TaskHistoryRepository) ListAllByTaskTx(ctx context.Context, tx *sqlx.Tx, taskID int64) ([]TaskHistory, error) {
+	return listAllTaskHistory(ctx, tx, taskID)
+}
+
+func listAllTaskHistory(ctx context.Context, q sqlx.QueryerContext, taskID int64) ([]TaskHistory, error) {
+	items := make([]TaskHistory, 0)
+	if err := sqlx.SelectContext(ctx, q, &items, `
+		SELECT id, task_id, changed_by, field_name, old_value, new_value, created_at
+		FROM task_history
+		WHERE task_id = ?
+		ORDER BY created_at ASC, id ASC
+	`, taskID); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// taskImportLookupBatch bounds the IN list when resolving external IDs.
+const taskImportLookupBatch = 500
+
+// TaskImportRepository maps external tracker IDs to the tasks imported from them, one
+// mapping per team and external ID, so re-running an import skips rows already done.
+type TaskImportRepository struct {
+	db *sqlx.DB
+}
+
+func NewTaskImportRepository(db *sqlx.DB) *TaskImportRepository {
+	return &TaskImportRepository{db: db}
+}
+
+// TaskIDs returns the task ID for every external ID of the team that was imported before.
+func (r *TaskImportRepository) TaskIDs(ctx context.Context, teamID int64, externalIDs []string) (map[string]int64, error) {
+	out := make(map[string]int64, len(externalIDs))
+	for start := 0; start < len(externalIDs); start += taskImportLookupBatch {
+		end := min(start+taskImportLookupBatch, len(externalIDs))
+		query, args, err := sqlx.In(`
+			SELECT external_id, task_id FROM task_imports WHERE team_id = ? AND external_id IN (?)
+		`, teamID, externalIDs[start:end])
+		if err != nil {
+			return nil, err
+		}
+		var rows []struct {
+			ExternalID string `db:"external_id"`
+			TaskID     int64  `db:"task_id"`
+		}
+		if err := r.db.SelectContext(ctx, &rows, r.db.Rebind(query), args...); err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			out[row.ExternalID] = row.TaskID
+		}
+	}
+	return out, nil
+}
+
+func (r *TaskImportRepository) CreateTx(ctx context.Context, tx *sqlx.Tx, teamID int64, externalID string, taskID int64, source string, importedBy int64) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO task_imports (team_id, external_id, task_id, source, imported_by)
+		VALUES (?, ?, ?, ?, ?)
+	`, teamID, externalID, taskID, source, importedBy)
+	return err
+}
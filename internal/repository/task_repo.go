@@ -22,8 +22,24 @@ type Task struct {
 	DueDate     sql.NullTime   `db:"due_date"`
 	CreatedAt   time.Time      `db:"created_at"`
 	UpdatedAt   time.Time      `db:"updated_at"`
+
+	OriginalEstimate  sql.NullInt64 `db:"original_estimate_minutes"`
+	RemainingEstimate sql.NullInt64 `db:"remaining_estimate_minutes"`
+	SprintID          sql.NullInt64 `db:"sprint_id"`
+	DeletedAt         sql.NullTime  `db:"deleted_at"`
+	// Version is bumped by every write to the row and backs the task ETag.
+	Version int64 `db:"version"`
+}
+
+// TrashedTask is a soft-deleted task; DeletedBy comes from its latest task_deleted history entry.
+type TrashedTask struct {
+	Task
+	DeletedBy sql.NullInt64 `db:"deleted_by"`
 }
 
+const taskColumns = `id, team_id, title, description, status, priority, assignee_id, created_by, due_date, created_at, updated_at,
+	original_estimate_minutes, remaining_estimate_minutes, sprint_id, deleted_at, version`
+
 type TaskRepository struct {
 	db *sqlx.DB
 }
@@ -32,11 +48,26 @@ func NewTaskRepository(db *sqlx.DB) *TaskRepository {
 	return &TaskRepository{db: db}
 }
 
+const insertTaskSQL = `
+	INSERT INTO tasks (team_id, title, description, status, priority, assignee_id, created_by, due_date, original_estimate_minutes, remaining_estimate_minutes, sprint_id)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+`
+
+func insertTaskArgs(t Task) []any {
+	return []any{t.TeamID, t.Title, nullableString(t.Description), t.Status, t.Priority, nullableInt64(t.AssigneeID), nullableInt64(t.CreatedBy), nullableTime(t.DueDate),
+		nullableInt64(t.OriginalEstimate), nullableInt64(t.RemainingEstimate), nullableInt64(t.SprintID)}
+}
+
 func (r *TaskRepository) Create(ctx context.Context, t Task) (int64, error) {
-	res, err := r.db.ExecContext(ctx, `
-		INSERT INTO tasks (team_id, title, description, status, priority, assignee_id, created_by, due_date)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
-	`, t.TeamID, t.Title, nullableString(t.Description), t.Status, t.Priority, nullableInt64(t.AssigneeID), nullableInt64(t.CreatedBy), nullableTime(t.DueDate))
+	res, err := r.db.ExecContext(ctx, insertTaskSQL, insertTaskArgs(t)...)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+func (r *TaskRepository) CreateTx(ctx context.Context, tx *sqlx.Tx, t Task) (int64, error) {
+	res, err := tx.ExecContext(ctx, insertTaskSQL, insertTaskArgs(t)...)
 	if err != nil {
 		return 0, err
 	}
@@ -46,8 +77,8 @@ func (r *TaskRepository) Create(ctx context.Context, t Task) (int64, error) {
 func (r *TaskRepository) GetByID(ctx context.Context, taskID int64) (*Task, error) {
 	var t Task
 	err := r.db.GetContext(ctx, &t, `
-		SELECT id, team_id, title, description, status, priority, assignee_id, created_by, due_date, created_at, updated_at
-		FROM tasks WHERE id = ?
+		SELECT `+taskColumns+`
+		FROM tasks WHERE id = ? AND deleted_at IS NULL
 	`, taskID)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -61,8 +92,8 @@ func (r *TaskRepository) GetByID(ctx context.Context, taskID int64) (*Task, erro
 func (r *TaskRepository) GetByIDForUpdateTx(ctx context.Context, tx *sqlx.Tx, taskID int64) (*Task, error) {
 	var t Task
 	err := tx.GetContext(ctx, &t, `
-		SELECT id, team_id, title, description, status, priority, assignee_id, created_by, due_date, created_at, updated_at
-		FROM tasks WHERE id = ? FOR UPDATE
+		SELECT `+taskColumns+`
+		FROM tasks WHERE id = ? AND deleted_at IS NULL FOR UPDATE
 	`, taskID)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -77,22 +108,36 @@ type TaskListFilter struct {
 	TeamID     int64
 	Status     *string
 	AssigneeID *int64
+	SprintID   *int64
+	Sort       string
 	Limit      int
 	Offset     int
 }
 
+// taskSortOrders maps the public sort names to ORDER BY clauses; "" is the default.
+// A leading "-" means descending. Priority sorts by ENUM position, low to high.
+var taskSortOrders = map[string]string{
+	"":            "updated_at DESC, id DESC",
+	"-updated_at": "updated_at DESC, id DESC",
+	"updated_at":  "updated_at ASC, id ASC",
+	"-created_at": "created_at DESC, id DESC",
+	"created_at":  "created_at ASC, id ASC",
+	"due_date":    "due_date IS NULL, due_date ASC, id ASC",
+	"-due_date":   "due_date IS NULL, due_date DESC, id DESC",
+	"priority":    "priority ASC, updated_at DESC, id DESC",
+	"-priority":   "priority DESC, updated_at DESC, id DESC",
+}
+
+func IsTaskSort(sort string) bool {
+	_, ok := taskSortOrders[sort]
+	return ok
+}
+
 func (r *TaskRepository) List(ctx context.Context, f TaskListFilter) ([]Task, int64, error) {
-	where := []string{"team_id = ?"}
-	args := []any{f.TeamID}
-	if f.Status != nil {
-		where = append(where, "status = ?")
-		args = append(args, *f.Status)
+	if !IsTaskSort(f.Sort) {
+		return nil, 0, fmt.Errorf("unknown task sort %q", f.Sort)
 	}
-	if f.AssigneeID != nil {
-		where = append(where, "assignee_id = ?")
-		args = append(args, *f.AssigneeID)
-	}
-	whereSQL := strings.Join(where, " AND ")
+	whereSQL, args := taskListWhere(f)
 
 	var total int64
 	countSQL := "SELECT COUNT(*) FROM tasks WHERE " + whereSQL
@@ -101,10 +146,10 @@ func (r *TaskRepository) List(ctx context.Context, f TaskListFilter) ([]Task, in
 	}
 
 	query := `
-		SELECT id, team_id, title, description, status, priority, assignee_id, created_by, due_date, created_at, updated_at
+		SELECT ` + taskColumns + `
 		FROM tasks
 		WHERE ` + whereSQL + `
-		ORDER BY updated_at DESC, id DESC
+		ORDER BY ` + taskSortOrders[f.Sort] + `
 		LIMIT ? OFFSET ?
 	`
 	args = append(args, f.Limit, f.Offset)
@@ -115,6 +160,24 @@ func (r *TaskRepository) List(ctx context.Context, f TaskListFilter) ([]Task, in
 	return tasks, total, nil
 }
 
+func taskListWhere(f TaskListFilter) (string, []any) {
+	where := []string{"team_id = ?", "deleted_at IS NULL"}
+	args := []any{f.TeamID}
+	if f.Status != nil {
+		where = append(where, "status = ?")
+		args = append(args, *f.Status)
+	}
+	if f.AssigneeID != nil {
+		where = append(where, "assignee_id = ?")
+		args = append(args, *f.AssigneeID)
+	}
+	if f.SprintID != nil {
+		where = append(where, "sprint_id = ?")
+		args = append(args, *f.SprintID)
+	}
+	return strings.Join(where, " AND "), args
+}
+
 func (r *TaskRepository) Update(ctx context.Context, taskID int64, fields map[string]any) error {
 	if len(fields) == 0 {
 		return fmt.Errorf("no fields to update")
@@ -125,7 +188,7 @@ func (r *TaskRepository) Update(ctx context.Context, taskID int64, fields map[st
 		cols = append(cols, k+" = ?")
 		args = append(args, v)
 	}
-	query := "UPDATE tasks SET " + strings.Join(cols, ", ") + " WHERE id = ?"
+	query := "UPDATE tasks SET " + strings.Join(cols, ", ") + ", version = version + 1 WHERE id = ?"
 	args = append(args, taskID)
 	_, err := r.db.ExecContext(ctx, query, args...)
 	return err
@@ -141,22 +204,101 @@ func (r *TaskRepository) UpdateTx(ctx context.Context, tx *sqlx.Tx, taskID int64
 		cols = append(cols, k+" = ?")
 		args = append(args, v)
 	}
-	query := "UPDATE tasks SET " + strings.Join(cols, ", ") + " WHERE id = ?"
+	query := "UPDATE tasks SET " + strings.Join(cols, ", ") + ", version = version + 1 WHERE id = ?"
 	args = append(args, taskID)
 	_, err := tx.ExecContext(ctx, query, args...)
 	return err
 }
 
+// Delete moves the task to the trash. updated_at is kept so done-stats windows do not shift.
 func (r *TaskRepository) Delete(ctx context.Context, taskID int64) error {
-	_, err := r.db.ExecContext(ctx, `DELETE FROM tasks WHERE id = ?`, taskID)
+	_, err := r.db.ExecContext(ctx, softDeleteTaskSQL, taskID)
 	return err
 }
 
 func (r *TaskRepository) DeleteTx(ctx context.Context, tx *sqlx.Tx, taskID int64) error {
-	_, err := tx.ExecContext(ctx, `DELETE FROM tasks WHERE id = ?`, taskID)
+	_, err := tx.ExecContext(ctx, softDeleteTaskSQL, taskID)
+	return err
+}
+
+const softDeleteTaskSQL = `UPDATE tasks SET deleted_at = CURRENT_TIMESTAMP(3), updated_at = updated_at, version = version + 1 WHERE id = ? AND deleted_at IS NULL`
+
+func (r *TaskRepository) GetDeletedForUpdateTx(ctx context.Context, tx *sqlx.Tx, taskID int64) (*Task, error) {
+	var t Task
+	err := tx.GetContext(ctx, &t, `
+		SELECT `+taskColumns+`
+		FROM tasks WHERE id = ? AND deleted_at IS NOT NULL FOR UPDATE
+	`, taskID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *TaskRepository) UndeleteTx(ctx context.Context, tx *sqlx.Tx, taskID int64, fields map[string]any) error {
+	cols := []string{"deleted_at = NULL", "updated_at = updated_at", "version = version + 1"}
+	args := make([]any, 0, len(fields)+1)
+	for k, v := range fields {
+		cols = append(cols, k+" = ?")
+		args = append(args, v)
+	}
+	args = append(args, taskID)
+	_, err := tx.ExecContext(ctx, "UPDATE tasks SET "+strings.Join(cols, ", ")+" WHERE id = ? AND deleted_at IS NOT NULL", args...)
 	return err
 }
 
+func (r *TaskRepository) ListDeleted(ctx context.Context, teamID int64, limit, offset int) ([]TrashedTask, int64, error) {
+	var total int64
+	if err := r.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM tasks WHERE team_id = ? AND deleted_at IS NOT NULL`, teamID); err != nil {
+		return nil, 0, err
+	}
+	items := make([]TrashedTask, 0)
+	err := r.db.SelectContext(ctx, &items, `
+		SELECT `+taskColumns+`,
+			(SELECT h.changed_by FROM task_history h
+			 WHERE h.task_id = tasks.id AND h.field_name = 'task_deleted'
+			 ORDER BY h.id DESC LIMIT 1) AS deleted_by
+		FROM tasks
+		WHERE team_id = ? AND deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC, id DESC
+		LIMIT ? OFFSET ?
+	`, teamID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+func (r *TaskRepository) ListPurgeable(ctx context.Context, deletedBefore time.Time, limit int) ([]int64, error) {
+	var ids []int64
+	err := r.db.SelectContext(ctx, &ids, `
+		SELECT id FROM tasks
+		WHERE deleted_at IS NOT NULL AND deleted_at < ?
+		ORDER BY deleted_at, id
+		LIMIT ?
+	`, deletedBefore, limit)
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// PurgeTx hard-deletes a trashed task; history, comments and the rest cascade with it.
+func (r *TaskRepository) PurgeTx(ctx context.Context, tx *sqlx.Tx, taskID int64, deletedBefore time.Time) (bool, error) {
+	res, err := tx.ExecContext(ctx, `DELETE FROM tasks WHERE id = ? AND deleted_at IS NOT NULL AND deleted_at < ?`, taskID, deletedBefore)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
 func nullableString(ns sql.NullString) any {
 	if ns.Valid {
 		return ns.String
@@ -177,3 +319,105 @@ func nullableTime(nt sql.NullTime) any {
 	}
 	return nil
 }
+
+// UserTaskFilter selects live tasks a user is assigned to and/or created, limited to teams
+// the user still belongs to. Relation is "assigned", "created" or "" for both.
+type UserTaskFilter struct {
+	UserID     int64
+	Relation   string
+	TeamID     *int64
+	Status     *string
+	AssigneeID *int64
+	SprintID   *int64
+	Sort       string
+	Limit      int
+	Offset     int
+}
+
+type UserTaskSummary struct {
+	Total       int64 `db:"total"`
+	Todo        int64 `db:"todo"`
+	InProgress  int64 `db:"in_progress"`
+	Done        int64 `db:"done"`
+	Overdue     int64 `db:"overdue"`
+	DueThisWeek int64 `db:"due_this_week"`
+}
+
+// userTaskSource returns the caller's task ids as a derived table. Each branch is a
+// single-column lookup on idx_tasks_assignee_id or idx_tasks_created_by, so the cost
+// follows the user's own tasks rather than the size of their teams.
+func (f UserTaskFilter) userTaskSource() (string, []any) {
+	assigned := "SELECT id AS task_id FROM tasks FORCE INDEX (idx_tasks_assignee_id) WHERE assignee_id = ?"
+	created := "SELECT id AS task_id FROM tasks FORCE INDEX (idx_tasks_created_by) WHERE created_by = ?"
+	switch f.Relation {
+	case "assigned":
+		return "(" + assigned + ")", []any{f.UserID}
+	case "created":
+		return "(" + created + ")", []any{f.UserID}
+	default:
+		return "(" + assigned + " UNION " + created + ")", []any{f.UserID, f.UserID}
+	}
+}
+
+func (f UserTaskFilter) userTaskWhere(withStatus bool) (string, []any) {
+	from, args := f.userTaskSource()
+	where := []string{"deleted_at IS NULL", "team_id IN (SELECT team_id FROM team_members WHERE user_id = ?)"}
+	args = append(args, f.UserID)
+	if f.TeamID != nil {
+		where = append(where, "team_id = ?")
+		args = append(args, *f.TeamID)
+	}
+	if withStatus && f.Status != nil {
+		where = append(where, "status = ?")
+		args = append(args, *f.Status)
+	}
+	if f.AssigneeID != nil {
+		where = append(where, "assignee_id = ?")
+		args = append(args, *f.AssigneeID)
+	}
+	if f.SprintID != nil {
+		where = append(where, "sprint_id = ?")
+		args = append(args, *f.SprintID)
+	}
+	return "tasks JOIN " + from + " mine ON mine.task_id = tasks.id WHERE " + strings.Join(where, " AND "), args
+}
+
+func (r *TaskRepository) ListForUser(ctx context.Context, f UserTaskFilter) ([]Task, int64, error) {
+	if !IsTaskSort(f.Sort) {
+		return nil, 0, fmt.Errorf("unknown task sort %q", f.Sort)
+	}
+	fromSQL, args := f.userTaskWhere(true)
+
+	var total int64
+	if err := r.db.GetContext(ctx, &total, "SELECT COUNT(*) FROM "+fromSQL, args...); err != nil {
+		return nil, 0, err
+	}
+	tasks := make([]Task, 0)
+	query := `
+		SELECT ` + taskColumns + `
+		FROM ` + fromSQL + `
+		ORDER BY ` + taskSortOrders[f.Sort] + `
+		LIMIT ? OFFSET ?
+	`
+	if err := r.db.SelectContext(ctx, &tasks, query, append(args, f.Limit, f.Offset)...); err != nil {
+		return nil, 0, err
+	}
+	return tasks, total, nil
+}
+
+// SummaryForUser counts the filter's tasks regardless of its status filter. Overdue and
+// due-this-week only count open tasks; weekEnd is exclusive.
+func (r *TaskRepository) SummaryForUser(ctx context.Context, f UserTaskFilter, today, weekEnd time.Time) (UserTaskSummary, error) {
+	fromSQL, args := f.userTaskWhere(false)
+	var s UserTaskSummary
+	err := r.db.GetContext(ctx, &s, `
+		SELECT
+			COUNT(*) AS total,
+			COALESCE(SUM(status = 'todo'), 0) AS todo,
+			COALESCE(SUM(status = 'in_progress'), 0) AS in_progress,
+			COALESCE(SUM(status = 'done'), 0) AS done,
+			COALESCE(SUM(status <> 'done' AND due_date < ?), 0) AS overdue,
+			COALESCE(SUM(status <> 'done' AND due_date >= ? AND due_date < ?), 0) AS due_this_week
+		FROM `+fromSQL, append([]any{today, today, weekEnd}, args...)...)
+	return s, err
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// TaskTemplate holds the defaults a task is stamped from. Labels and Checklist are JSON
+// arrays of strings; both may be NULL.
+type TaskTemplate struct {
+	ID                int64          `db:"id"`
+	TeamID            int64          `db:"team_id"`
+	Name              string         `db:"name"`
+	TitlePattern      string         `db:"title_pattern"`
+	Description       sql.NullString `db:"description"`
+	Priority          sql.NullString `db:"priority"`
+	DefaultAssigneeID sql.NullInt64  `db:"default_assignee_id"`
+	Labels            []byte         `db:"labels"`
+	Checklist         []byte         `db:"checklist"`
+	CreatedBy         sql.NullInt64  `db:"created_by"`
+	CreatedAt         time.Time      `db:"created_at"`
+	UpdatedAt         time.Time      `db:"updated_at"`
+}
+
+const taskTemplateColumns = `id, team_id, name, title_pattern, description, priority, default_assignee_id, labels, checklist, created_by, created_at, updated_at`
+
+type TaskTemplateRepository struct {
+	db *sqlx.DB
+}
+
+func NewTaskTemplateRepository(db *sqlx.DB) *TaskTemplateRepository {
+	return &TaskTemplateRepository{db: db}
+}
+
+func (r *TaskTemplateRepository) Create(ctx context.Context, t TaskTemplate) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `
+		INSERT INTO task_templates (team_id, name, title_pattern, description, priority, default_assignee_id, labels, checklist, created_by)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, t.TeamID, t.Name, t.TitlePattern, nullableString(t.Description), nullableString(t.Priority),
+		nullableInt64(t.DefaultAssigneeID), nullableJSON(t.Labels), nullableJSON(t.Checklist), nullableInt64(t.CreatedBy))
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+func (r *TaskTemplateRepository) GetByID(ctx context.Context, id int64) (*TaskTemplate, error) {
+	var t TaskTemplate
+	err := r.db.GetContext(ctx, &t, `SELECT `+taskTemplateColumns+` FROM task_templates WHERE id = ?`, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *TaskTemplateRepository) ListByTeam(ctx context.Context, teamID int64) ([]TaskTemplate, error) {
+	items := make([]TaskTemplate, 0)
+	if err := r.db.SelectContext(ctx, &items, `
+		SELECT `+taskTemplateColumns+` FROM task_templates WHERE team_id = ? ORDER BY name, id
+	`, teamID); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *TaskTemplateRepository) Update(ctx context.Context, id int64, fields map[string]any) error {
+	if len(fields) == 0 {
+		return fmt.Errorf("no fields to update")
+	}
+	cols := make([]string, 0, len(fields))
+	args := make([]any, 0, len(fields)+1)
+	for k, v := range fields {
+		cols = append(cols, k+" = ?")
+		args = append(args, v)
+	}
+	args = append(args, id)
+	_, err := r.db.ExecContext(ctx, "UPDATE task_templates SET "+strings.Join(cols, ", ")+" WHERE id = ?", args...)
+	return err
+}
+
+func (r *TaskTemplateRepository) Delete(ctx context.Context, id int64) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM task_templates WHERE id = ?`, id)
+	return err
+}
+
+func nullableJSON(v []byte) any {
+	if len(v) == 0 {
+		return nil
+	}
+	return v
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type TaskWatcher struct {
+	TaskID    int64     `db:"task_id"`
+	UserID    int64     `db:"user_id"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type TaskWatcherRepository struct {
+	db *sqlx.DB
+}
+
+func NewTaskWatcherRepository(db *sqlx.DB) *TaskWatcherRepository {
+	return &TaskWatcherRepository{db: db}
+}
+
+func (r *TaskWatcherRepository) Add(ctx context.Context, taskID, userID int64) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT IGNORE INTO task_watchers (task_id, user_id) VALUES (?, ?)`,
+		taskID, userID,
+	)
+	return err
+}
+
+func (r *TaskWatcherRepository) AddTx(ctx context.Context, tx *sqlx.Tx, taskID, userID int64) error {
+	_, err := tx.ExecContext(ctx,
+		`INSERT IGNORE INTO task_watchers (task_id, user_id) VALUES (?, ?)`,
+		taskID, userID,
+	)
+	return err
+}
+
+func (r *TaskWatcherRepository) Remove(ctx context.Context, taskID, userID int64) error {
+	_, err := r.db.ExecContext(ctx,
+		`DELETE FROM task_watchers WHERE task_id = ? AND user_id = ?`,
+		taskID, userID,
+	)
+	return err
+}
+
+func (r *TaskWatcherRepository) ListByTask(ctx context.Context, taskID int64) ([]TaskWatcher, error) {
+	var items []TaskWatcher
+	err := r.db.SelectContext(ctx, &items, `
+		SELECT task_id, user_id, created_at
+		FROM task_watchers
+		WHERE task_id = ?
+		ORDER BY created_at ASC, user_id ASC
+	`, taskID)
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// ListMemberIDsByTask returns the watchers who are still members of the task's team.
+func (r *TaskWatcherRepository) ListMemberIDsByTask(ctx context.Context, taskID int64) ([]int64, error) {
+	var ids []int64
+	err := r.db.SelectContext(ctx, &ids, `
+		SELECT w.user_id
+		FROM task_watchers w
+		JOIN tasks t ON t.id = w.task_id
+		JOIN team_members tm ON tm.team_id = t.team_id AND tm.user_id = w.user_id
+		WHERE w.task_id = ?
+		ORDER BY w.created_at ASC, w.user_id ASC
+	`, taskID)
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type TaskWorkLog struct {
+	ID        int64          `db:"id"`
+	TaskID    int64          `db:"task_id"`
+	UserID    sql.NullInt64  `db:"user_id"`
+	Minutes   int64          `db:"minutes"`
+	WorkDate  time.Time      `db:"work_date"`
+	Note      sql.NullString `db:"note"`
+	CreatedAt time.Time      `db:"created_at"`
+}
+
+type TaskTimer struct {
+	TaskID    int64     `db:"task_id"`
+	UserID    int64     `db:"user_id"`
+	StartedAt time.Time `db:"started_at"`
+}
+
+type TaskWorkLogRepository struct {
+	db *sqlx.DB
+}
+
+func NewTaskWorkLogRepository(db *sqlx.DB) *TaskWorkLogRepository {
+	return &TaskWorkLogRepository{db: db}
+}
+
+const insertWorkLogSQL = `
+	INSERT INTO task_work_logs (task_id, user_id, minutes, work_date, note)
+	VALUES (?, ?, ?, ?, ?)
+`
+
+func (r *TaskWorkLogRepository) Create(ctx context.Context, l TaskWorkLog) (int64, error) {
+	res, err := r.db.ExecContext(ctx, insertWorkLogSQL, l.TaskID, nullableInt64(l.UserID), l.Minutes, l.WorkDate, nullableString(l.Note))
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+func (r *TaskWorkLogRepository) CreateTx(ctx context.Context, tx *sqlx.Tx, l TaskWorkLog) (int64, error) {
+	res, err := tx.ExecContext(ctx, insertWorkLogSQL, l.TaskID, nullableInt64(l.UserID), l.Minutes, l.WorkDate, nullableString(l.Note))
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+func (r *TaskWorkLogRepository) GetByID(ctx context.Context, id int64) (*TaskWorkLog, error) {
+	var l TaskWorkLog
+	err := r.db.GetContext(ctx, &l, `
+		SELECT id, task_id, user_id, minutes, work_date, note, created_at
+		FROM task_work_logs WHERE id = ?
+	`, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &l, nil
+}
+
+func (r *TaskWorkLogRepository) ListByTask(ctx context.Context, taskID int64, limit, offset int) ([]TaskWorkLog, int64, error) {
+	var total int64
+	if err := r.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM task_work_logs WHERE task_id = ?`, taskID); err != nil {
+		return nil, 0, err
+	}
+	var items []TaskWorkLog
+	err := r.db.SelectContext(ctx, &items, `
+		SELECT id, task_id, user_id, minutes, work_date, note, created_at
+		FROM task_work_logs
+		WHERE task_id = ?
+		ORDER BY work_date DESC, id DESC
+		LIMIT ? OFFSET ?
+	`, taskID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+func (r *TaskWorkLogRepository) TotalMinutesByTask(ctx context.Context, taskID int64) (int64, error) {
+	var total int64
+	err := r.db.GetContext(ctx, &total, `SELECT COALESCE(SUM(minutes), 0) FROM task_work_logs WHERE task_id = ?`, taskID)
+	return total, err
+}
+
+func (r *TaskWorkLogRepository) Delete(ctx context.Context, id int64) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM task_work_logs WHERE id = ?`, id)
+	return err
+}
+
+func (r *TaskWorkLogRepository) StartTimer(ctx context.Context, taskID, userID int64) error {
+	_, err := r.db.ExecContext(ctx, `INSERT INTO task_timers (task_id, user_id) VALUES (?, ?)`, taskID, userID)
+	return err
+}
+
+func (r *TaskWorkLogRepository) GetTimer(ctx context.Context, taskID, userID int64) (*TaskTimer, error) {
+	var t TaskTimer
+	err := r.db.GetContext(ctx, &t, `
+		SELECT task_id, user_id, started_at FROM task_timers WHERE task_id = ? AND user_id = ?
+	`, taskID, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+// TakeTimerTx locks and removes the running timer so a concurrent stop cannot log the same interval twice.
+func (r *TaskWorkLogRepository) TakeTimerTx(ctx context.Context, tx *sqlx.Tx, taskID, userID int64) (*TaskTimer, error) {
+	var t TaskTimer
+	err := tx.GetContext(ctx, &t, `
+		SELECT task_id, user_id, started_at FROM task_timers WHERE task_id = ? AND user_id = ? FOR UPDATE
+	`, taskID, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM task_timers WHERE task_id = ? AND user_id = ?`, taskID, userID); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
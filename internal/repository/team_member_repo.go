@@ -39,6 +39,66 @@ func (r *TeamMemberRepository) Add(ctx context.Context, teamID, userID int64, ro
 	return err
 }
 
+func (r *TeamMemberRepository) Remove(ctx context.Context, teamID, userID int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM team_members WHERE team_id = ? AND user_id = ?`, teamID, userID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// RemoveTx deletes the membership together with what the user keeps in the team: task
+// watches, running timers and personal saved views.
+func (r *TeamMemberRepository) RemoveTx(ctx context.Context, tx *sqlx.Tx, teamID, userID int64) (bool, error) {
+	res, err := tx.ExecContext(ctx, `DELETE FROM team_members WHERE team_id = ? AND user_id = ?`, teamID, userID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil || n == 0 {
+		return false, err
+	}
+	for _, q := range []string{
+		`DELETE w FROM task_watchers w JOIN tasks t ON t.id = w.task_id WHERE t.team_id = ? AND w.user_id = ?`,
+		`DELETE tt FROM task_timers tt JOIN tasks t ON t.id = tt.task_id WHERE t.team_id = ? AND tt.user_id = ?`,
+		`DELETE FROM saved_views WHERE team_id = ? AND owner_id = ?`,
+	} {
+		if _, err := tx.ExecContext(ctx, q, teamID, userID); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// ListOwnerIDsForUpdateTx locks the team's owner rows, so a concurrent removal or demotion
+// cannot leave the team without an owner.
+func (r *TeamMemberRepository) ListOwnerIDsForUpdateTx(ctx context.Context, tx *sqlx.Tx, teamID int64) ([]int64, error) {
+	var ids []int64
+	err := tx.SelectContext(ctx, &ids, `
+		SELECT user_id FROM team_members WHERE team_id = ? AND role = 'owner' ORDER BY user_id FOR UPDATE
+	`, teamID)
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (r *TeamMemberRepository) GetRoleForUpdateTx(ctx context.Context, tx *sqlx.Tx, teamID, userID int64) (string, bool, error) {
+	var role string
+	err := tx.GetContext(ctx, &role, `SELECT role FROM team_members WHERE team_id = ? AND user_id = ? FOR UPDATE`, teamID, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	return role, true, nil
+}
+
 func (r *TeamMemberRepository) GetRole(ctx context.Context, teamID, userID int64) (string, bool, error) {
 	var role string
 	err := r.db.GetContext(ctx, &role, `SELECT role FROM team_members WHERE team_id = ? AND user_id = ?`, teamID, userID)
@@ -62,3 +122,84 @@ func (r *TeamMemberRepository) IsMember(ctx context.Context, teamID, userID int6
 	}
 	return true, nil
 }
+
+type TeamOwnership struct {
+	TeamID       int64 `db:"team_id"`
+	OwnersCount  int64 `db:"owners_count"`
+	MembersCount int64 `db:"members_count"`
+}
+
+// ListOwnershipByUserForUpdateTx locks the membership rows of every team the user owns,
+// so the counts hold until the transaction ends.
+func (r *TeamMemberRepository) ListOwnershipByUserForUpdateTx(ctx context.Context, tx *sqlx.Tx, userID int64) ([]TeamOwnership, error) {
+	var items []TeamOwnership
+	err := tx.SelectContext(ctx, &items, `
+		SELECT
+		  tm.team_id,
+		  SUM(tm.role = 'owner') AS owners_count,
+		  COUNT(*) AS members_count
+		FROM team_members tm
+		WHERE tm.team_id IN (
+		  SELECT team_id FROM team_members WHERE user_id = ? AND role = 'owner'
+		)
+		GROUP BY tm.team_id
+		ORDER BY tm.team_id
+		FOR UPDATE
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *TeamMemberRepository) SetRoleTx(ctx context.Context, tx *sqlx.Tx, teamID, userID int64, role string) error {
+	_, err := tx.ExecContext(ctx,
+		`UPDATE team_members SET role = ? WHERE team_id = ? AND user_id = ?`,
+		role, teamID, userID,
+	)
+	return err
+}
+
+type TeamMemberDetail struct {
+	UserID      int64          `db:"user_id"`
+	Username    string         `db:"username"`
+	Email       string         `db:"email"`
+	DisplayName sql.NullString `db:"display_name"`
+	Role        string         `db:"role"`
+	CreatedAt   time.Time      `db:"created_at"`
+}
+
+func (r *TeamMemberRepository) ListByTeam(ctx context.Context, teamID int64) ([]TeamMemberDetail, error) {
+	var items []TeamMemberDetail
+	err := r.db.SelectContext(ctx, &items, `
+		SELECT tm.user_id, u.username, u.email, u.display_name, tm.role, tm.created_at
+		FROM team_members tm
+		JOIN users u ON u.id = tm.user_id
+		WHERE tm.team_id = ?
+		ORDER BY FIELD(tm.role, 'owner', 'admin', 'member'), u.username ASC
+	`, teamID)
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *TeamMemberRepository) ListByUsernames(ctx context.Context, teamID int64, usernames []string) ([]UserSummary, error) {
+	if len(usernames) == 0 {
+		return nil, nil
+	}
+	query, args, err := sqlx.In(`
+		SELECT u.id, u.username, u.email, u.display_name
+		FROM team_members tm
+		JOIN users u ON u.id = tm.user_id
+		WHERE tm.team_id = ? AND u.username IN (?)
+	`, teamID, usernames)
+	if err != nil {
+		return nil, err
+	}
+	var items []UserSummary
+	if err := r.db.SelectContext(ctx, &items, r.db.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
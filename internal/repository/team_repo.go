@@ -60,3 +60,8 @@ func (r *TeamRepository) ListByUser(ctx context.Context, userID int64) ([]Team,
 	}
 	return teams, nil
 }
+
+func (r *TeamRepository) DeleteTx(ctx context.Context, tx *sqlx.Tx, teamID int64) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM teams WHERE id = ?`, teamID)
+	return err
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type UserEmailChange struct {
+	ID          int64      `db:"id"`
+	UserID      int64      `db:"user_id"`
+	NewEmail    string     `db:"new_email"`
+	TokenHash   string     `db:"token_hash"`
+	ExpiresAt   time.Time  `db:"expires_at"`
+	ConfirmedAt *time.Time `db:"confirmed_at"`
+	CreatedAt   time.Time  `db:"created_at"`
+}
+
+type UserEmailChangeRepository struct {
+	db *sqlx.DB
+}
+
+func NewUserEmailChangeRepository(db *sqlx.DB) *UserEmailChangeRepository {
+	return &UserEmailChangeRepository{db: db}
+}
+
+func (r *UserEmailChangeRepository) Create(ctx context.Context, userID int64, newEmail, tokenHash string, expiresAt time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx,
+		`INSERT INTO user_email_changes (user_id, new_email, token_hash, expires_at) VALUES (?, ?, ?, ?)`,
+		userID, newEmail, tokenHash, expiresAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+func (r *UserEmailChangeRepository) GetByTokenHashForUpdateTx(ctx context.Context, tx *sqlx.Tx, tokenHash string) (*UserEmailChange, error) {
+	var c UserEmailChange
+	err := tx.GetContext(ctx, &c, `
+		SELECT id, user_id, new_email, token_hash, expires_at, confirmed_at, created_at
+		FROM user_email_changes WHERE token_hash = ? FOR UPDATE
+	`, tokenHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *UserEmailChangeRepository) ConfirmTx(ctx context.Context, tx *sqlx.Tx, id int64, confirmedAt time.Time) error {
+	_, err := tx.ExecContext(ctx, `UPDATE user_email_changes SET confirmed_at = ? WHERE id = ?`, confirmedAt, id)
+	return err
+}
+
+func (r *UserEmailChangeRepository) DeletePendingByUser(ctx context.Context, userID int64) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM user_email_changes WHERE user_id = ? AND confirmed_at IS NULL`, userID)
+	return err
+}
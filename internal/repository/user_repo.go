@@ -3,6 +3,9 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 )
@@ -60,3 +63,121 @@ func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*U
 	}
 	return &u, nil
 }
+
+type UserProfile struct {
+	ID          int64          `db:"id"`
+	Email       string         `db:"email"`
+	Username    string         `db:"username"`
+	DisplayName sql.NullString `db:"display_name"`
+	AvatarURL   sql.NullString `db:"avatar_url"`
+	Timezone    string         `db:"timezone"`
+	Locale      string         `db:"locale"`
+	CreatedAt   time.Time      `db:"created_at"`
+	UpdatedAt   time.Time      `db:"updated_at"`
+}
+
+func (r *UserRepository) GetByID(ctx context.Context, userID int64) (*User, error) {
+	var u User
+	err := r.db.GetContext(ctx, &u, `SELECT id, email, username, password_hash FROM users WHERE id = ?`, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &u, nil
+}
+
+func (r *UserRepository) GetProfile(ctx context.Context, userID int64) (*UserProfile, error) {
+	var p UserProfile
+	err := r.db.GetContext(ctx, &p, `
+		SELECT id, email, username, display_name, avatar_url, timezone, locale, created_at, updated_at
+		FROM users WHERE id = ?
+	`, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *UserRepository) UpdateProfile(ctx context.Context, userID int64, fields map[string]any) error {
+	if len(fields) == 0 {
+		return fmt.Errorf("no fields to update")
+	}
+	cols := make([]string, 0, len(fields))
+	args := make([]any, 0, len(fields)+1)
+	for k, v := range fields {
+		cols = append(cols, k+" = ?")
+		args = append(args, v)
+	}
+	query := "UPDATE users SET " + strings.Join(cols, ", ") + " WHERE id = ?"
+	args = append(args, userID)
+	_, err := r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (r *UserRepository) UpdateEmailTx(ctx context.Context, tx *sqlx.Tx, userID int64, email string) error {
+	_, err := tx.ExecContext(ctx, `UPDATE users SET email = ? WHERE id = ?`, email, userID)
+	return err
+}
+
+func (r *UserRepository) UpdatePasswordHash(ctx context.Context, userID int64, passwordHash string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE users SET password_hash = ? WHERE id = ?`, passwordHash, userID)
+	return err
+}
+
+func (r *UserRepository) DeleteTx(ctx context.Context, tx *sqlx.Tx, userID int64) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM users WHERE id = ?`, userID)
+	return err
+}
+
+type UserSummary struct {
+	ID          int64          `db:"id"`
+	Username    string         `db:"username"`
+	Email       string         `db:"email"`
+	DisplayName sql.NullString `db:"display_name"`
+}
+
+func (r *UserRepository) ListSummariesByIDs(ctx context.Context, ids []int64) ([]UserSummary, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	query, args, err := sqlx.In(`SELECT id, username, email, display_name FROM users WHERE id IN (?)`, ids)
+	if err != nil {
+		return nil, err
+	}
+	var items []UserSummary
+	if err := r.db.SelectContext(ctx, &items, r.db.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *UserRepository) SearchSharingTeam(ctx context.Context, userID int64, q string, limit int) ([]UserSummary, error) {
+	pattern := escapeLike(q) + "%"
+	var items []UserSummary
+	err := r.db.SelectContext(ctx, &items, `
+		SELECT u.id, u.username, u.email, u.display_name
+		FROM users u
+		WHERE u.id IN (
+		  SELECT tm.user_id
+		  FROM team_members tm
+		  JOIN team_members mine ON mine.team_id = tm.team_id
+		  WHERE mine.user_id = ?
+		)
+		  AND (u.username LIKE ? OR u.email LIKE ? OR u.display_name LIKE ?)
+		ORDER BY u.username ASC
+		LIMIT ?
+	`, userID, pattern, pattern, pattern, limit)
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
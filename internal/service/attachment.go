@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	dstorage "MKK-Luna/internal/domain/storage"
+	"MKK-Luna/internal/repository"
+)
+
+type AttachmentService struct {
+	tasks        *TaskService
+	attachments  attachmentRepo
+	comments     attachmentCommentRepo
+	members      teamMemberRepo
+	blobs        dstorage.BlobStorage
+	maxSize      int64
+	allowedTypes map[string]bool
+	signingKey   []byte
+	urlTTL       time.Duration
+	logger       *slog.Logger
+}
+
+type attachmentRepo interface {
+	Create(ctx context.Context, a repository.TaskAttachment) (int64, error)
+	GetByID(ctx context.Context, id int64) (*repository.TaskAttachment, error)
+	ListByTask(ctx context.Context, taskID int64) ([]repository.TaskAttachment, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+type attachmentCommentRepo interface {
+	GetByID(ctx context.Context, commentID int64) (*repository.TaskComment, error)
+}
+
+type attachmentKeyRepo interface {
+	ListKeysByTaskTx(ctx context.Context, tx *sqlx.Tx, taskID int64) ([]string, error)
+}
+
+func WithAttachmentCleanup(keys attachmentKeyRepo, blobs dstorage.BlobStorage) TaskServiceOption {
+	return func(s *TaskService) {
+		s.attachmentKeys = keys
+		s.blobs = blobs
+	}
+}
+
+type AttachmentUpload struct {
+	TaskID      int64
+	CommentID   *int64
+	Filename    string
+	ContentType string
+	Size        int64
+	Body        io.Reader
+}
+
+func NewAttachmentService(
+	tasks *TaskService,
+	attachments attachmentRepo,
+	comments attachmentCommentRepo,
+	members teamMemberRepo,
+	blobs dstorage.BlobStorage,
+	maxSize int64,
+	allowedTypes []string,
+	signingKey string,
+	urlTTL time.Duration,
+	logger *slog.Logger,
+) *AttachmentService {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	allowed := make(map[string]bool, len(allowedTypes))
+	for _, t := range allowedTypes {
+		allowed[strings.ToLower(strings.TrimSpace(t))] = true
+	}
+	return &AttachmentService{
+		tasks: tasks, attachments: attachments, comments: comments, members: members, blobs: blobs,
+		maxSize: maxSize, allowedTypes: allowed, signingKey: []byte(signingKey), urlTTL: urlTTL, logger: logger,
+	}
+}
+
+func (s *AttachmentService) MaxSize() int64 {
+	return s.maxSize
+}
+
+func (s *AttachmentService) Upload(ctx context.Context, userID int64, in AttachmentUpload) (*repository.TaskAttachment, error) {
+	filename := sanitizeFilename(in.Filename)
+	if filename == "" || in.Size <= 0 {
+		return nil, ErrBadRequest
+	}
+	if in.Size > s.maxSize {
+		return nil, ErrBadRequest
+	}
+	contentType := normalizeContentType(in.ContentType)
+	if !s.allowedTypes[contentType] {
+		return nil, ErrBadRequest
+	}
+
+	task, err := s.tasks.GetTask(ctx, userID, in.TaskID)
+	if err != nil {
+		return nil, err
+	}
+	if in.CommentID != nil {
+		comment, err := s.comments.GetByID(ctx, *in.CommentID)
+		if err != nil {
+			return nil, err
+		}
+		if comment == nil || comment.TaskID != task.ID || comment.DeletedAt.Valid {
+			return nil, ErrBadRequest
+		}
+	}
+
+	key := "tasks/" + strconv.FormatInt(task.ID, 10) + "/" + uuid.NewString()
+	if err := s.blobs.Put(ctx, key, io.LimitReader(in.Body, in.Size), in.Size, contentType); err != nil {
+		return nil, err
+	}
+
+	a := repository.TaskAttachment{
+		TaskID:      task.ID,
+		UploadedBy:  sql.NullInt64{Int64: userID, Valid: true},
+		Filename:    filename,
+		ContentType: contentType,
+		SizeBytes:   in.Size,
+		StorageKey:  key,
+		CreatedAt:   time.Now().UTC(),
+	}
+	if in.CommentID != nil {
+		a.CommentID = sql.NullInt64{Int64: *in.CommentID, Valid: true}
+	}
+	id, err := s.attachments.Create(ctx, a)
+	if err != nil {
+		if delErr := s.blobs.Delete(ctx, key); delErr != nil {
+			s.logger.Warn("attachment blob cleanup failed", "err", delErr, "key", key)
+		}
+		return nil, err
+	}
+	a.ID = id
+	return &a, nil
+}
+
+func (s *AttachmentService) List(ctx context.Context, userID, taskID int64) ([]repository.TaskAttachment, error) {
+	if _, err := s.tasks.GetTask(ctx, userID, taskID); err != nil {
+		return nil, err
+	}
+	return s.attachments.ListByTask(ctx, taskID)
+}
+
+func (s *AttachmentService) Get(ctx context.Context, userID, attachmentID int64) (*repository.TaskAttachment, error) {
+	a, err := s.attachments.GetByID(ctx, attachmentID)
+	if err != nil {
+		return nil, err
+	}
+	if a == nil {
+		return nil, ErrNotFound
+	}
+	if _, err := s.tasks.GetTask(ctx, userID, a.TaskID); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (s *AttachmentService) Delete(ctx context.Context, userID, attachmentID int64) error {
+	a, err := s.attachments.GetByID(ctx, attachmentID)
+	if err != nil {
+		return err
+	}
+	if a == nil {
+		return ErrNotFound
+	}
+	task, err := s.tasks.GetTask(ctx, userID, a.TaskID)
+	if err != nil {
+		return err
+	}
+	uploader := a.UploadedBy.Valid && a.UploadedBy.Int64 == userID
+	if !uploader {
+		role, _, err := s.members.GetRole(ctx, task.TeamID, userID)
+		if err != nil {
+			return err
+		}
+		if role != RoleOwner && role != RoleAdmin {
+			return ErrForbidden
+		}
+	}
+	if err := s.attachments.Delete(ctx, attachmentID); err != nil {
+		return err
+	}
+	if err := s.blobs.Delete(ctx, a.StorageKey); err != nil {
+		s.logger.Warn("attachment blob cleanup failed", "err", err, "key", a.StorageKey)
+	}
+	return nil
+}
+
+// SignDownload returns query values for the public download route; the signature binds id and expiry.
+func (s *AttachmentService) SignDownload(attachmentID int64) (expires int64, signature string) {
+	expires = time.Now().Add(s.urlTTL).Unix()
+	return expires, s.sign(attachmentID, expires)
+}
+
+func (s *AttachmentService) OpenSigned(ctx context.Context, attachmentID, expires int64, signature string) (*repository.TaskAttachment, io.ReadCloser, error) {
+	if expires < time.Now().Unix() {
+		return nil, nil, ErrInvalidToken
+	}
+	if !hmac.Equal([]byte(signature), []byte(s.sign(attachmentID, expires))) {
+		return nil, nil, ErrInvalidToken
+	}
+	a, err := s.attachments.GetByID(ctx, attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if a == nil {
+		return nil, nil, ErrNotFound
+	}
+	body, err := s.blobs.Get(ctx, a.StorageKey)
+	if err != nil {
+		if err == dstorage.ErrBlobNotFound {
+			return nil, nil, ErrNotFound
+		}
+		return nil, nil, err
+	}
+	return a, body, nil
+}
+
+func (s *AttachmentService) sign(attachmentID, expires int64) string {
+	mac := hmac.New(sha256.New, s.signingKey)
+	mac.Write([]byte(strconv.FormatInt(attachmentID, 10) + ":" + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *TaskService) attachmentKeysTx(ctx context.Context, tx *sqlx.Tx, taskID int64) ([]string, error) {
+	if s.attachmentKeys == nil || s.blobs == nil {
+		return nil, nil
+	}
+	return s.attachmentKeys.ListKeysByTaskTx(ctx, tx, taskID)
+}
+
+// removeBlobs runs after the task row is gone; metadata rows cascade, so a failed blob delete only leaks storage.
+func (s *TaskService) removeBlobs(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		_ = s.blobs.Delete(ctx, key)
+	}
+}
+
+func sanitizeFilename(name string) string {
+	name = path.Base(strings.ReplaceAll(strings.TrimSpace(name), "\\", "/"))
+	if name == "." || name == "/" {
+		return ""
+	}
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	for utf8.RuneCountInString(name) > 255 {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	return name
+}
+
+func normalizeContentType(v string) string {
+	if i := strings.IndexByte(v, ';'); i >= 0 {
+		v = v[:i]
+	}
+	return strings.ToLower(strings.TrimSpace(v))
+}
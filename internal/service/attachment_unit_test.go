@@ -0,0 +1,288 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	dstorage "MKK-Luna/internal/domain/storage"
+	"MKK-Luna/internal/repository"
+)
+
+type fakeBlobStorage struct {
+	blobs   map[string][]byte
+	deleted []string
+}
+
+func (f *fakeBlobStorage) Put(_ context.Context, key string, r io.Reader, _ int64, _ string) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if f.blobs == nil {
+		f.blobs = map[string][]byte{}
+	}
+	f.blobs[key] = data
+	return nil
+}
+
+func (f *fakeBlobStorage) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	data, ok := f.blobs[key]
+	if !ok {
+		return nil, dstorage.ErrBlobNotFound
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (f *fakeBlobStorage) Delete(_ context.Context, key string) error {
+	delete(f.blobs, key)
+	f.deleted = append(f.deleted, key)
+	return nil
+}
+
+type fakeAttachmentRepo struct {
+	items     map[int64]repository.TaskAttachment
+	createErr error
+}
+
+func (f *fakeAttachmentRepo) Create(_ context.Context, a repository.TaskAttachment) (int64, error) {
+	if f.createErr != nil {
+		return 0, f.createErr
+	}
+	if f.items == nil {
+		f.items = map[int64]repository.TaskAttachment{}
+	}
+	a.ID = int64(len(f.items) + 1)
+	f.items[a.ID] = a
+	return a.ID, nil
+}
+
+func (f *fakeAttachmentRepo) GetByID(_ context.Context, id int64) (*repository.TaskAttachment, error) {
+	a, ok := f.items[id]
+	if !ok {
+		return nil, nil
+	}
+	return &a, nil
+}
+
+func (f *fakeAttachmentRepo) ListByTask(_ context.Context, taskID int64) ([]repository.TaskAttachment, error) {
+	var out []repository.TaskAttachment
+	for _, a := range f.items {
+		if a.TaskID == taskID {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeAttachmentRepo) Delete(_ context.Context, id int64) error {
+	delete(f.items, id)
+	return nil
+}
+
+func newAttachmentTestService(members *fakeMemberRepo, repo *fakeAttachmentRepo, blobs *fakeBlobStorage) *AttachmentService {
+	tasks := NewTaskService(
+		nil,
+		&fakeTaskRepo{getByID: func(_ context.Context, id int64) (*repository.Task, error) {
+			if id != 1 {
+				return nil, nil
+			}
+			return &repository.Task{ID: 1, TeamID: 10}, nil
+		}},
+		&fakeTeamRepo{},
+		members,
+		&fakeCommentRepo{},
+		&fakeHistoryRepo{},
+	)
+	return NewAttachmentService(tasks, repo, &fakeCommentRepo{}, members, blobs, 16, []string{"text/plain"}, "secret", time.Minute, nil)
+}
+
+func TestAttachmentService_Upload(t *testing.T) {
+	blobs := &fakeBlobStorage{}
+	repo := &fakeAttachmentRepo{}
+	svc := newAttachmentTestService(&fakeMemberRepo{role: RoleMember, hasRole: true}, repo, blobs)
+
+	upload := func(taskID int64, name, contentType, body string) error {
+		_, err := svc.Upload(context.Background(), 5, AttachmentUpload{
+			TaskID: taskID, Filename: name, ContentType: contentType, Size: int64(len(body)), Body: strings.NewReader(body),
+		})
+		return err
+	}
+
+	if err := upload(1, "log.txt", "image/svg+xml", "x"); err != ErrBadRequest {
+		t.Fatalf("expected ErrBadRequest for type, got %v", err)
+	}
+	if err := upload(1, "log.txt", "text/plain", strings.Repeat("x", 17)); err != ErrBadRequest {
+		t.Fatalf("expected ErrBadRequest for size, got %v", err)
+	}
+	if err := upload(2, "log.txt", "text/plain", "x"); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if len(blobs.blobs) != 0 {
+		t.Fatalf("rejected uploads must not store blobs")
+	}
+
+	a, err := svc.Upload(context.Background(), 5, AttachmentUpload{
+		TaskID: 1, Filename: "../../etc/app.log", ContentType: "text/plain; charset=utf-8", Size: 5, Body: strings.NewReader("hello"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if a.Filename != "app.log" || a.ContentType != "text/plain" || !strings.HasPrefix(a.StorageKey, "tasks/1/") {
+		t.Fatalf("unexpected attachment: %+v", a)
+	}
+	if string(blobs.blobs[a.StorageKey]) != "hello" {
+		t.Fatalf("blob not stored")
+	}
+
+	repo.createErr = errors.New("db down")
+	if err := upload(1, "b.txt", "text/plain", "x"); err == nil {
+		t.Fatalf("expected error")
+	}
+	if len(blobs.blobs) != 1 {
+		t.Fatalf("blob should be removed when metadata insert fails")
+	}
+}
+
+func TestAttachmentService_SignedDownload(t *testing.T) {
+	blobs := &fakeBlobStorage{}
+	repo := &fakeAttachmentRepo{}
+	svc := newAttachmentTestService(&fakeMemberRepo{role: RoleMember, hasRole: true}, repo, blobs)
+
+	a, err := svc.Upload(context.Background(), 5, AttachmentUpload{
+		TaskID: 1, Filename: "a.txt", ContentType: "text/plain", Size: 2, Body: strings.NewReader("hi"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+
+	expires, sig := svc.SignDownload(a.ID)
+	_, body, err := svc.OpenSigned(context.Background(), a.ID, expires, sig)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	data, _ := io.ReadAll(body)
+	_ = body.Close()
+	if string(data) != "hi" {
+		t.Fatalf("unexpected body %q", data)
+	}
+
+	if _, _, err := svc.OpenSigned(context.Background(), a.ID+1, expires, sig); err != ErrInvalidToken {
+		t.Fatalf("expected ErrInvalidToken for other id, got %v", err)
+	}
+	if _, _, err := svc.OpenSigned(context.Background(), a.ID, expires+60, sig); err != ErrInvalidToken {
+		t.Fatalf("expected ErrInvalidToken for tampered expiry, got %v", err)
+	}
+	past := time.Now().Add(-time.Minute).Unix()
+	if _, _, err := svc.OpenSigned(context.Background(), a.ID, past, svc.sign(a.ID, past)); err != ErrInvalidToken {
+		t.Fatalf("expected ErrInvalidToken for expired link, got %v", err)
+	}
+}
+
+func TestAttachmentService_Delete(t *testing.T) {
+	blobs := &fakeBlobStorage{blobs: map[string][]byte{"tasks/1/a": []byte("x")}}
+	repo := &fakeAttachmentRepo{items: map[int64]repository.TaskAttachment{
+		1: {ID: 1, TaskID: 1, UploadedBy: sql.NullInt64{Int64: 7, Valid: true}, StorageKey: "tasks/1/a"},
+	}}
+
+	svc := newAttachmentTestService(&fakeMemberRepo{role: RoleMember, hasRole: true}, repo, blobs)
+	if err := svc.Delete(context.Background(), 5, 1); err != ErrForbidden {
+		t.Fatalf("expected ErrForbidden, got %v", err)
+	}
+	if err := svc.Delete(context.Background(), 7, 1); err != nil {
+		t.Fatalf("uploader delete: %v", err)
+	}
+	if len(repo.items) != 0 || len(blobs.deleted) != 1 {
+		t.Fatalf("expected metadata and blob removed")
+	}
+	if err := svc.Delete(context.Background(), 7, 1); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+type fakeAttachmentKeys struct{ keys []string }
+
+func (f *fakeAttachmentKeys) ListKeysByTaskTx(context.Context, *sqlx.Tx, int64) ([]string, error) {
+	return f.keys, nil
+}
+
+type fakeTrashRepo struct {
+	purgeable []int64
+	purged    []int64
+}
+
+func (f *fakeTrashRepo) ListDeleted(context.Context, int64, int, int) ([]repository.TrashedTask, int64, error) {
+	return nil, 0, nil
+}
+
+func (f *fakeTrashRepo) ListPurgeable(context.Context, time.Time, int) ([]int64, error) {
+	return f.purgeable, nil
+}
+
+func (f *fakeTrashRepo) PurgeTx(_ context.Context, _ *sqlx.Tx, taskID int64, _ time.Time) (bool, error) {
+	// task 2 was restored between listing and purging
+	if taskID == 2 {
+		return false, nil
+	}
+	f.purged = append(f.purged, taskID)
+	return true, nil
+}
+
+func TestTaskService_DeleteTask_KeepsAttachmentBlobs(t *testing.T) {
+	db, mock := newMockDB(t)
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	task := &repository.Task{ID: 1, TeamID: 10, Title: "t", Status: "done", Priority: "medium"}
+	taskRepo := &fakeTaskRepo{
+		getByIDForUpdate: func(context.Context, *sqlx.Tx, int64) (*repository.Task, error) { return task, nil },
+	}
+	blobs := &fakeBlobStorage{blobs: map[string][]byte{"tasks/1/a": nil}}
+	svc := NewTaskService(db, taskRepo, &fakeTeamRepo{}, &fakeMemberRepo{role: RoleOwner, hasRole: true}, &fakeCommentRepo{}, &fakeHistoryRepo{},
+		WithAttachmentCleanup(&fakeAttachmentKeys{keys: []string{"tasks/1/a"}}, blobs),
+	)
+	if _, err := svc.DeleteTask(context.Background(), 1, 1); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if len(blobs.blobs) != 1 {
+		t.Fatalf("trashed task must keep its blobs, left %v", blobs.blobs)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+}
+
+func TestTaskService_PurgeDeletedTasks_RemovesAttachmentBlobs(t *testing.T) {
+	db, mock := newMockDB(t)
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	blobs := &fakeBlobStorage{blobs: map[string][]byte{"tasks/1/a": nil, "tasks/1/b": nil}}
+	trash := &fakeTrashRepo{purgeable: []int64{1, 2}}
+	svc := NewTaskService(db, &fakeTaskRepo{}, &fakeTeamRepo{}, &fakeMemberRepo{}, &fakeCommentRepo{}, &fakeHistoryRepo{},
+		WithAttachmentCleanup(&fakeAttachmentKeys{keys: []string{"tasks/1/a", "tasks/1/b"}}, blobs),
+		WithTrash(trash),
+	)
+	purged, err := svc.PurgeDeletedTasks(context.Background(), time.Now(), 10)
+	if err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	if purged != 1 || len(trash.purged) != 1 || trash.purged[0] != 1 {
+		t.Fatalf("purged=%d ids=%v", purged, trash.purged)
+	}
+	if len(blobs.blobs) != 0 {
+		t.Fatalf("expected blobs removed, left %v", blobs.blobs)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+}
@@ -0,0 +1,286 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"MKK-Luna/internal/repository"
+)
+
+const (
+	AuditTeamCreated       = "team.created"
+	AuditTeamDeleted       = "team.deleted"
+	AuditMemberInvited     = "team.member_invited"
+	AuditMemberRoleChanged = "team.member_role_changed"
+	AuditLogin             = "auth.login"
+	AuditLoginFailed       = "auth.login_failed"
+	AuditRefreshTokenReuse = "auth.refresh_token_reuse"
+	AuditSessionsRevoked   = "auth.sessions_revoked"
+	AuditEmailChanged      = "user.email_changed"
+	AuditAccountDeleted    = "user.account_deleted"
+	AuditCommentEdited     = "comment.edited"
+	AuditCommentDeleted    = "comment.deleted"
+	AuditTaskPurged        = "task.purged"
+	AuditWIPLimitsChanged  = "team.wip_limits_changed"
+	AuditIntegrityQueried  = "admin.integrity_queried"
+	AuditIntegrityRepaired = "admin.integrity_repaired"
+	AuditExported          = "admin.audit_exported"
+	AuditTeamExported      = "team.data_exported"
+	AuditTasksImported     = "team.tasks_imported"
+	AuditOpsUserCreated    = "ops.user_created"
+	AuditOpsPasswordReset  = "ops.password_reset"
+	AuditOpsMemberAdded    = "ops.member_added"
+	AuditOpsMemberRemoved  = "ops.member_removed"
+	AuditOpsAssigneesFixed = "ops.assignees_fixed"
+)
+
+const (
+	auditTargetTeam    = "team"
+	auditTargetUser    = "user"
+	auditTargetComment = "comment"
+	auditTargetTask    = "task"
+	auditTargetSystem  = "system"
+)
+
+const (
+	auditWriteTimeout = 2 * time.Second
+	auditExportBatch  = 500
+)
+
+type auditStore interface {
+	Create(ctx context.Context, e repository.AuditEventCreate) error
+	List(ctx context.Context, f repository.AuditFilter, limit, offset int) ([]repository.AuditEvent, int64, error)
+	ListPage(ctx context.Context, f repository.AuditFilter, limit int) ([]repository.AuditEvent, error)
+}
+
+// RequestMeta carries the caller's address and request ID from the HTTP layer into audit events.
+type RequestMeta struct {
+	IP        string
+	RequestID string
+}
+
+type requestMetaKey struct{}
+
+func WithRequestMeta(ctx context.Context, meta RequestMeta) context.Context {
+	return context.WithValue(ctx, requestMetaKey{}, meta)
+}
+
+func requestMetaFromContext(ctx context.Context) RequestMeta {
+	meta, _ := ctx.Value(requestMetaKey{}).(RequestMeta)
+	return meta
+}
+
+// AuditEntry describes one event; zero IDs are stored as NULL (ActorID 0 means the system).
+type AuditEntry struct {
+	ActorID    int64
+	Action     string
+	TargetType string
+	TargetID   int64
+	TeamID     int64
+	Payload    map[string]any
+}
+
+type AuditService struct {
+	store      auditStore
+	teams      teamRepo
+	members    teamMemberRepo
+	adminUsers map[int64]struct{}
+	logger     *slog.Logger
+}
+
+func NewAuditService(store auditStore, teams teamRepo, members teamMemberRepo, adminUserIDs []int64, logger *slog.Logger) *AuditService {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	admins := make(map[int64]struct{}, len(adminUserIDs))
+	for _, id := range adminUserIDs {
+		if id > 0 {
+			admins[id] = struct{}{}
+		}
+	}
+	return &AuditService{store: store, teams: teams, members: members, adminUsers: admins, logger: logger}
+}
+
+func WithTeamAudit(audit *AuditService) TeamServiceOption {
+	return func(s *TeamService) { s.audit = audit }
+}
+
+func WithAuthAudit(audit *AuditService) AuthServiceOption {
+	return func(s *AuthService) { s.audit = audit }
+}
+
+func WithUserAudit(audit *AuditService) UserServiceOption {
+	return func(s *UserService) { s.audit = audit }
+}
+
+func WithTaskAudit(audit *AuditService) TaskServiceOption {
+	return func(s *TaskService) { s.audit = audit }
+}
+
+func WithExportAudit(audit *AuditService) ExportServiceOption {
+	return func(s *ExportService) { s.audit = audit }
+}
+
+func WithStatsAudit(audit *AuditService) StatsServiceOption {
+	return func(s *StatsService) { s.audit = audit }
+}
+
+// Record appends an event after the audited change has happened. It never fails the caller:
+// write errors are logged, and a cancelled request context does not drop the event.
+func (s *AuditService) Record(ctx context.Context, e AuditEntry) {
+	if s == nil || s.store == nil {
+		return
+	}
+	meta := requestMetaFromContext(ctx)
+	ev := repository.AuditEventCreate{
+		ActorID:    positiveID(e.ActorID),
+		Action:     e.Action,
+		TargetType: e.TargetType,
+		TargetID:   positiveID(e.TargetID),
+		TeamID:     positiveID(e.TeamID),
+		IP:         meta.IP,
+		RequestID:  meta.RequestID,
+	}
+	if len(e.Payload) > 0 {
+		b, err := json.Marshal(e.Payload)
+		if err != nil {
+			s.logger.Warn("audit payload encode failed", "action", e.Action, "err", err)
+		} else {
+			ev.Payload = b
+		}
+	}
+
+	writeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), auditWriteTimeout)
+	defer cancel()
+	if err := s.store.Create(writeCtx, ev); err != nil {
+		s.logger.Error("audit write failed",
+			"action", e.Action,
+			"actor_id", e.ActorID,
+			"target_type", e.TargetType,
+			"target_id", e.TargetID,
+			"request_id", meta.RequestID,
+			"err", err,
+		)
+	}
+}
+
+// ListTeamEvents is the owner-only view of one team's trail.
+func (s *AuditService) ListTeamEvents(ctx context.Context, userID, teamID int64, f repository.AuditFilter, limit, offset int) ([]repository.AuditEvent, int64, error) {
+	team, err := s.teams.GetByID(ctx, teamID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if team == nil {
+		return nil, 0, ErrNotFound
+	}
+	role, ok, err := s.members.GetRole(ctx, teamID, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !ok || role != RoleOwner {
+		return nil, 0, ErrForbidden
+	}
+	if err := validateAuditFilter(f); err != nil {
+		return nil, 0, err
+	}
+	f.TeamID = &teamID
+	return s.store.List(ctx, f, limit, offset)
+}
+
+func (s *AuditService) ListEvents(ctx context.Context, userID int64, f repository.AuditFilter, limit, offset int) ([]repository.AuditEvent, int64, error) {
+	if !s.isAdmin(userID) {
+		return nil, 0, ErrForbidden
+	}
+	if err := validateAuditFilter(f); err != nil {
+		return nil, 0, err
+	}
+	return s.store.List(ctx, f, limit, offset)
+}
+
+// ExportEvents streams every matching event, newest first, to fn in keyset-paged batches.
+// The export itself is audited once it finishes, including partial ones.
+func (s *AuditService) ExportEvents(ctx context.Context, userID int64, f repository.AuditFilter, fn func(repository.AuditEvent) error) (int, error) {
+	if !s.isAdmin(userID) {
+		return 0, ErrForbidden
+	}
+	if err := validateAuditFilter(f); err != nil {
+		return 0, err
+	}
+	filter := f
+	exported := 0
+	var exportErr error
+	for {
+		page, err := s.store.ListPage(ctx, filter, auditExportBatch)
+		if err != nil {
+			exportErr = err
+			break
+		}
+		for _, ev := range page {
+			if err := fn(ev); err != nil {
+				exportErr = err
+				break
+			}
+			exported++
+		}
+		if exportErr != nil || len(page) < auditExportBatch {
+			break
+		}
+		lastID := page[len(page)-1].ID
+		filter.BeforeID = &lastID
+	}
+
+	s.Record(ctx, AuditEntry{
+		ActorID:    userID,
+		Action:     AuditExported,
+		TargetType: auditTargetSystem,
+		Payload:    map[string]any{"events": exported, "complete": exportErr == nil, "filter": auditFilterPayload(f)},
+	})
+	return exported, exportErr
+}
+
+func (s *AuditService) isAdmin(userID int64) bool {
+	_, ok := s.adminUsers[userID]
+	return ok
+}
+
+func validateAuditFilter(f repository.AuditFilter) error {
+	if f.From != nil && f.To != nil && !f.From.Before(*f.To) {
+		return ErrBadRequest
+	}
+	return nil
+}
+
+func auditFilterPayload(f repository.AuditFilter) map[string]any {
+	out := map[string]any{}
+	if f.TeamID != nil {
+		out["team_id"] = *f.TeamID
+	}
+	if f.ActorID != nil {
+		out["actor_id"] = *f.ActorID
+	}
+	if f.Action != nil {
+		out["action"] = *f.Action
+	}
+	if f.TargetType != nil {
+		out["target_type"] = *f.TargetType
+	}
+	if f.TargetID != nil {
+		out["target_id"] = *f.TargetID
+	}
+	if f.From != nil {
+		out["from"] = f.From.UTC().Format(time.RFC3339)
+	}
+	if f.To != nil {
+		out["to"] = f.To.UTC().Format(time.RFC3339)
+	}
+	return out
+}
+
+func positiveID(id int64) *int64 {
+	if id <= 0 {
+		return nil
+	}
+	return &id
+}
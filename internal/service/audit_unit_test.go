@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"MKK-Luna/internal/repository"
+)
+
+type fakeAuditStore struct {
+	created []repository.AuditEventCreate
+	events  []repository.AuditEvent
+	filters []repository.AuditFilter
+}
+
+func (f *fakeAuditStore) Create(_ context.Context, e repository.AuditEventCreate) error {
+	f.created = append(f.created, e)
+	return nil
+}
+
+func (f *fakeAuditStore) List(_ context.Context, filter repository.AuditFilter, _, _ int) ([]repository.AuditEvent, int64, error) {
+	f.filters = append(f.filters, filter)
+	return f.events, int64(len(f.events)), nil
+}
+
+func (f *fakeAuditStore) ListPage(_ context.Context, filter repository.AuditFilter, limit int) ([]repository.AuditEvent, error) {
+	f.filters = append(f.filters, filter)
+	var out []repository.AuditEvent
+	for _, ev := range f.events {
+		if filter.BeforeID != nil && ev.ID >= *filter.BeforeID {
+			continue
+		}
+		out = append(out, ev)
+		if len(out) == limit {
+			break
+		}
+	}
+	return out, nil
+}
+
+func TestAuditService_Record(t *testing.T) {
+	store := &fakeAuditStore{}
+	svc := NewAuditService(store, &fakeTeamRepo{}, &fakeMemberRepo{}, nil, nil)
+
+	ctx, cancel := context.WithCancel(WithRequestMeta(context.Background(), RequestMeta{IP: "10.0.0.1", RequestID: "req-1"}))
+	cancel()
+	svc.Record(ctx, AuditEntry{ActorID: 1, Action: AuditMemberInvited, TargetType: auditTargetUser, TargetID: 2, TeamID: 3, Payload: map[string]any{"role": "member"}})
+	svc.Record(ctx, AuditEntry{Action: AuditTaskPurged, TargetType: auditTargetTask, TargetID: 4})
+
+	if len(store.created) != 2 {
+		t.Fatalf("expected events recorded despite cancelled request, got %d", len(store.created))
+	}
+	ev := store.created[0]
+	if *ev.ActorID != 1 || *ev.TargetID != 2 || *ev.TeamID != 3 || ev.IP != "10.0.0.1" || ev.RequestID != "req-1" {
+		t.Fatalf("unexpected event: %+v", ev)
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(ev.Payload, &payload); err != nil || payload["role"] != "member" {
+		t.Fatalf("unexpected payload %s: %v", ev.Payload, err)
+	}
+	if sys := store.created[1]; sys.ActorID != nil || sys.TeamID != nil || sys.Payload != nil {
+		t.Fatalf("system event must have no actor, team or payload: %+v", sys)
+	}
+
+	var nilSvc *AuditService
+	nilSvc.Record(ctx, AuditEntry{Action: AuditLogin})
+}
+
+func TestAuditService_ListTeamEvents_OwnersOnly(t *testing.T) {
+	store := &fakeAuditStore{}
+	teams := &fakeTeamRepo{getByID: func(_ context.Context, id int64) (*repository.Team, error) {
+		return &repository.Team{ID: id}, nil
+	}}
+	members := &fakeMemberRepo{role: RoleAdmin, hasRole: true}
+	svc := NewAuditService(store, teams, members, nil, nil)
+
+	if _, _, err := svc.ListTeamEvents(context.Background(), 1, 10, repository.AuditFilter{}, 20, 0); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected forbidden for admin, got %v", err)
+	}
+
+	members.role = RoleOwner
+	otherTeam := int64(99)
+	if _, _, err := svc.ListTeamEvents(context.Background(), 1, 10, repository.AuditFilter{TeamID: &otherTeam}, 20, 0); err != nil {
+		t.Fatalf("owner list: %v", err)
+	}
+	if got := store.filters[0].TeamID; got == nil || *got != 10 {
+		t.Fatalf("team filter must be pinned to the path team, got %v", got)
+	}
+}
+
+func TestAuditService_ExportEvents(t *testing.T) {
+	store := &fakeAuditStore{}
+	for id := int64(auditExportBatch + 20); id > 0; id-- {
+		store.events = append(store.events, repository.AuditEvent{ID: id, Action: AuditLogin})
+	}
+	svc := NewAuditService(store, &fakeTeamRepo{}, &fakeMemberRepo{}, []int64{7}, nil)
+
+	if _, err := svc.ExportEvents(context.Background(), 8, repository.AuditFilter{}, nil); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected forbidden for non-admin, got %v", err)
+	}
+
+	var seen []int64
+	n, err := svc.ExportEvents(context.Background(), 7, repository.AuditFilter{}, func(ev repository.AuditEvent) error {
+		seen = append(seen, ev.ID)
+		return nil
+	})
+	if err != nil || n != auditExportBatch+20 || len(seen) != n {
+		t.Fatalf("export n=%d seen=%d err=%v", n, len(seen), err)
+	}
+	if seen[0] != int64(auditExportBatch+20) || seen[len(seen)-1] != 1 {
+		t.Fatalf("expected newest first without gaps, got first=%d last=%d", seen[0], seen[len(seen)-1])
+	}
+	if len(store.created) != 1 || store.created[0].Action != AuditExported || *store.created[0].ActorID != 7 {
+		t.Fatalf("expected the export to be audited, got %+v", store.created)
+	}
+}
+
+func TestStatsService_IntegrityQueryIsAudited(t *testing.T) {
+	store := &fakeAuditStore{}
+	audit := NewAuditService(store, &fakeTeamRepo{}, &fakeMemberRepo{}, nil, nil)
+	svc := NewStatsService(&fakeAnalyticsRepo{}, nil, []int64{1}, nil, WithStatsAudit(audit))
+
+	if _, err := svc.FindTasksWithAssigneeNotMember(context.Background(), 1); err != nil {
+		t.Fatalf("integrity: %v", err)
+	}
+	if len(store.created) != 1 || store.created[0].Action != AuditIntegrityQueried {
+		t.Fatalf("expected integrity query audited, got %+v", store.created)
+	}
+}
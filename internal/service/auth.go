@@ -38,6 +38,7 @@ type AuthService struct {
 	logger   *slog.Logger
 	metrics  AuthMetrics
 	bl       TokenBlacklist
+	audit    *AuditService
 }
 
 type TokenPair struct {
@@ -80,7 +81,9 @@ type TokenBlacklist interface {
 	Revoke(ctx context.Context, jti string, ttl time.Duration) error
 }
 
-func NewAuthService(users UserStore, sessions SessionStore, cfg config.Config, logger *slog.Logger, metrics AuthMetrics, blacklist TokenBlacklist) (*AuthService, error) {
+type AuthServiceOption func(*AuthService)
+
+func NewAuthService(users UserStore, sessions SessionStore, cfg config.Config, logger *slog.Logger, metrics AuthMetrics, blacklist TokenBlacklist, opts ...AuthServiceOption) (*AuthService, error) {
 	if len(cfg.JWT.Secret) < 32 {
 		return nil, errors.New("jwt secret must be at least 32 bytes")
 	}
@@ -90,7 +93,11 @@ func NewAuthService(users UserStore, sessions SessionStore, cfg config.Config, l
 	if logger == nil {
 		logger = slog.Default()
 	}
-	return &AuthService{users: users, sessions: sessions, cfg: cfg, logger: logger, metrics: metrics, bl: blacklist}, nil
+	s := &AuthService{users: users, sessions: sessions, cfg: cfg, logger: logger, metrics: metrics, bl: blacklist}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
 }
 
 func (s *AuthService) Register(ctx context.Context, email, username, password string) (int64, error) {
@@ -140,6 +147,10 @@ func (s *AuthService) Login(ctx context.Context, login, password, ip, userAgent
 			s.metrics.IncAuthEvent("login_fail")
 			s.metrics.IncAuthEventReason("login_fail", "bad_password")
 		}
+		s.audit.Record(ctx, AuditEntry{
+			ActorID: user.ID, Action: AuditLoginFailed, TargetType: auditTargetUser, TargetID: user.ID,
+			Payload: map[string]any{"reason": "bad_password", "user_agent": userAgent},
+		})
 		return nil, ErrInvalidCredentials
 	}
 
@@ -161,6 +172,10 @@ func (s *AuthService) Login(ctx context.Context, login, password, ip, userAgent
 	if s.metrics != nil {
 		s.metrics.IncAuthEvent("login_success")
 	}
+	s.audit.Record(ctx, AuditEntry{
+		ActorID: user.ID, Action: AuditLogin, TargetType: auditTargetUser, TargetID: user.ID,
+		Payload: map[string]any{"user_agent": userAgent},
+	})
 
 	return pair, nil
 }
@@ -244,6 +259,12 @@ func (s *AuthService) Refresh(ctx context.Context, refreshToken, ip, userAgent s
 				s.metrics.IncAuthEventReason("refresh_fail", "tx_error")
 			}
 		}
+		if err == ErrTokenReuse {
+			s.audit.Record(ctx, AuditEntry{
+				ActorID: userID, Action: AuditRefreshTokenReuse, TargetType: auditTargetUser, TargetID: userID,
+				Payload: map[string]any{"user_agent": userAgent},
+			})
+		}
 		return nil, err
 	}
 
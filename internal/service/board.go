@@ -0,0 +1,304 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/jmoiron/sqlx"
+
+	"MKK-Luna/internal/repository"
+)
+
+const maxWIPLimit = 1000
+
+// BoardStatuses are the board columns, left to right.
+var BoardStatuses = []string{"todo", "in_progress", "done"}
+
+type boardRepo interface {
+	ListColumn(ctx context.Context, f repository.BoardFilter, status string, limit int) ([]repository.BoardTask, int64, error)
+	GetForUpdateTx(ctx context.Context, tx *sqlx.Tx, taskID int64) (*repository.BoardTask, error)
+	ListUnrankedIDsForUpdateTx(ctx context.Context, tx *sqlx.Tx, teamID int64, status string, excludeID int64) ([]int64, error)
+	MaxRankTx(ctx context.Context, tx *sqlx.Tx, teamID int64, status string, excludeID int64) (string, error)
+	NextRankTx(ctx context.Context, tx *sqlx.Tx, teamID int64, status, rank string, excludeID int64) (string, error)
+	PrevRankTx(ctx context.Context, tx *sqlx.Tx, teamID int64, status, rank string, excludeID int64) (string, error)
+	SetRankTx(ctx context.Context, tx *sqlx.Tx, taskID int64, rank string) error
+	ListWIPLimits(ctx context.Context, teamID int64) (map[string]int, error)
+	DeleteWIPLimitsTx(ctx context.Context, tx *sqlx.Tx, teamID int64) error
+	InsertWIPLimitTx(ctx context.Context, tx *sqlx.Tx, teamID int64, status string, limit int, userID int64) error
+	WIPLimitForUpdateTx(ctx context.Context, tx *sqlx.Tx, teamID int64, status string) (int, bool, error)
+	CountColumnTx(ctx context.Context, tx *sqlx.Tx, teamID int64, status string) (int64, error)
+}
+
+func WithBoard(board boardRepo) TaskServiceOption {
+	return func(s *TaskService) { s.board = board }
+}
+
+type BoardInput struct {
+	TeamID     int64
+	AssigneeID *int64
+	SprintID   *int64
+	Limit      int
+}
+
+type BoardColumn struct {
+	Status   string
+	Tasks    []repository.BoardTask
+	Total    int64
+	WIPLimit *int
+}
+
+// BoardMove places a task in Status between AfterID (the task above) and BeforeID (the
+// task below). With neither, the task goes to the bottom of the column.
+type BoardMove struct {
+	Status   string
+	AfterID  *int64
+	BeforeID *int64
+}
+
+func (s *TaskService) GetBoard(ctx context.Context, userID int64, in BoardInput) ([]BoardColumn, error) {
+	if s.board == nil {
+		return nil, ErrUnavailable
+	}
+	if err := s.requireTeamMember(ctx, in.TeamID, userID); err != nil {
+		return nil, err
+	}
+	limits, err := s.board.ListWIPLimits(ctx, in.TeamID)
+	if err != nil {
+		return nil, err
+	}
+	filter := repository.BoardFilter{TeamID: in.TeamID, AssigneeID: in.AssigneeID, SprintID: in.SprintID}
+	columns := make([]BoardColumn, 0, len(BoardStatuses))
+	for _, status := range BoardStatuses {
+		tasks, total, err := s.board.ListColumn(ctx, filter, status, in.Limit)
+		if err != nil {
+			return nil, err
+		}
+		col := BoardColumn{Status: status, Tasks: tasks, Total: total}
+		if limit, ok := limits[status]; ok {
+			col.WIPLimit = &limit
+		}
+		columns = append(columns, col)
+	}
+	return columns, nil
+}
+
+func (s *TaskService) GetWIPLimits(ctx context.Context, userID, teamID int64) (map[string]int, error) {
+	if s.board == nil {
+		return nil, ErrUnavailable
+	}
+	if err := s.requireTeamMember(ctx, teamID, userID); err != nil {
+		return nil, err
+	}
+	return s.board.ListWIPLimits(ctx, teamID)
+}
+
+// SetWIPLimits replaces the team's limits; columns left out become unlimited. Owners and
+// admins only. Limits are checked when a task enters a column, so a column already over
+// its new limit keeps its tasks.
+func (s *TaskService) SetWIPLimits(ctx context.Context, userID, teamID int64, limits map[string]int) error {
+	if s.board == nil || s.db == nil {
+		return ErrUnavailable
+	}
+	team, err := s.teams.GetByID(ctx, teamID)
+	if err != nil {
+		return err
+	}
+	if team == nil {
+		return ErrNotFound
+	}
+	role, ok, err := s.members.GetRole(ctx, teamID, userID)
+	if err != nil {
+		return err
+	}
+	if !ok || (role != RoleOwner && role != RoleAdmin) {
+		return ErrForbidden
+	}
+	payload := make(map[string]any, len(limits))
+	for status, limit := range limits {
+		if !isValidStatus(status) || limit < 1 || limit > maxWIPLimit {
+			return ErrBadRequest
+		}
+		payload[status] = limit
+	}
+
+	tx, err := s.db.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := s.board.DeleteWIPLimitsTx(ctx, tx, teamID); err != nil {
+		return err
+	}
+	for _, status := range BoardStatuses {
+		if limit, ok := limits[status]; ok {
+			if err := s.board.InsertWIPLimitTx(ctx, tx, teamID, status, limit, userID); err != nil {
+				return err
+			}
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, AuditEntry{
+		ActorID: userID, Action: AuditWIPLimitsChanged, TargetType: auditTargetTeam, TargetID: teamID, TeamID: teamID,
+		Payload: payload,
+	})
+	return nil
+}
+
+// MoveTask changes status and board position in one UpdateTask transaction, so the move
+// is subject to the same role, precondition and WIP limit checks as a status edit.
+func (s *TaskService) MoveTask(ctx context.Context, userID, taskID int64, move BoardMove, cond *Precondition) (int64, error) {
+	if s.board == nil || s.db == nil || s.history == nil {
+		return 0, ErrUnavailable
+	}
+	if !isValidStatus(move.Status) {
+		return 0, ErrBadRequest
+	}
+	if move.AfterID != nil && move.BeforeID != nil && *move.AfterID == *move.BeforeID {
+		return 0, ErrBadRequest
+	}
+	if (move.AfterID != nil && *move.AfterID == taskID) || (move.BeforeID != nil && *move.BeforeID == taskID) {
+		return 0, ErrBadRequest
+	}
+	return s.updateTask(ctx, userID, taskID, cond, func(task repository.Task) (map[string]json.RawMessage, error) {
+		if task.Status == move.Status {
+			return map[string]json.RawMessage{}, nil
+		}
+		return map[string]json.RawMessage{"status": *mustJSON(move.Status)}, nil
+	}, &move)
+}
+
+// placeOnBoard sets updates["board_rank"] for the task's new spot. Unranked tasks in the
+// target column are ranked first, in their current display order, so neighbours always
+// have a rank to compare against.
+func (s *TaskService) placeOnBoard(ctx context.Context, tx *sqlx.Tx, task repository.Task, move *BoardMove, updates map[string]any) error {
+	unranked, err := s.board.ListUnrankedIDsForUpdateTx(ctx, tx, task.TeamID, move.Status, task.ID)
+	if err != nil {
+		return err
+	}
+	if len(unranked) > 0 {
+		last, err := s.board.MaxRankTx(ctx, tx, task.TeamID, move.Status, task.ID)
+		if err != nil {
+			return err
+		}
+		keys, err := ranksAfter(last, len(unranked))
+		if err != nil {
+			return err
+		}
+		for i, id := range unranked {
+			if err := s.board.SetRankTx(ctx, tx, id, keys[i]); err != nil {
+				return err
+			}
+		}
+	}
+
+	neighbour := func(id int64) (string, error) {
+		n, err := s.board.GetForUpdateTx(ctx, tx, id)
+		if err != nil {
+			return "", err
+		}
+		if n == nil || n.TeamID != task.TeamID || n.Status != move.Status || !n.BoardRank.Valid {
+			return "", ErrBadRequest
+		}
+		return n.BoardRank.String, nil
+	}
+	var lower, upper string
+	switch {
+	case move.AfterID != nil && move.BeforeID != nil:
+		if lower, err = neighbour(*move.AfterID); err != nil {
+			return err
+		}
+		if upper, err = neighbour(*move.BeforeID); err != nil {
+			return err
+		}
+	case move.AfterID != nil:
+		if lower, err = neighbour(*move.AfterID); err != nil {
+			return err
+		}
+		if upper, err = s.board.NextRankTx(ctx, tx, task.TeamID, move.Status, lower, task.ID); err != nil {
+			return err
+		}
+	case move.BeforeID != nil:
+		if upper, err = neighbour(*move.BeforeID); err != nil {
+			return err
+		}
+		if lower, err = s.board.PrevRankTx(ctx, tx, task.TeamID, move.Status, upper, task.ID); err != nil {
+			return err
+		}
+	default:
+		if lower, err = s.board.MaxRankTx(ctx, tx, task.TeamID, move.Status, task.ID); err != nil {
+			return err
+		}
+	}
+	rank, err := rankBetween(lower, upper)
+	if err != nil {
+		return ErrBadRequest
+	}
+	if len(updates) == 0 {
+		// A reorder inside a column is not an edit; keep updated_at for lists and done stats.
+		updates["updated_at"] = task.UpdatedAt
+	}
+	updates["board_rank"] = rank
+	return nil
+}
+
+// checkBoardColumn enforces the WIP limit of the column a task is entering. A status
+// change without a board position sends the task to the unranked tail.
+func (s *TaskService) checkBoardColumn(ctx context.Context, tx *sqlx.Tx, task repository.Task, updates map[string]any) error {
+	if s.board == nil {
+		return nil
+	}
+	status, ok := updates["status"].(string)
+	if !ok || status == task.Status {
+		return nil
+	}
+	if err := s.checkColumnLimit(ctx, tx, task.TeamID, status, 1); err != nil {
+		return err
+	}
+	if _, ranked := updates["board_rank"]; !ranked {
+		updates["board_rank"] = nil
+	}
+	return nil
+}
+
+// checkColumnLimit fails with ErrConflict when adding more tasks would push the column
+// over its WIP limit. The limit row is locked so concurrent writers into the same column
+// are counted one after another.
+func (s *TaskService) checkColumnLimit(ctx context.Context, tx *sqlx.Tx, teamID int64, status string, adding int) error {
+	if s.board == nil {
+		return nil
+	}
+	limit, ok, err := s.board.WIPLimitForUpdateTx(ctx, tx, teamID, status)
+	if err != nil || !ok {
+		return err
+	}
+	n, err := s.board.CountColumnTx(ctx, tx, teamID, status)
+	if err != nil {
+		return err
+	}
+	if n+int64(adding) > int64(limit) {
+		return ErrConflict
+	}
+	return nil
+}
+
+func (s *TaskService) requireTeamMember(ctx context.Context, teamID, userID int64) error {
+	team, err := s.teams.GetByID(ctx, teamID)
+	if err != nil {
+		return err
+	}
+	if team == nil {
+		return ErrNotFound
+	}
+	ok, err := s.members.IsMember(ctx, teamID, userID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrForbidden
+	}
+	return nil
+}
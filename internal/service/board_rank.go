@@ -0,0 +1,110 @@
+package service
+
+import (
+	"errors"
+	"strings"
+)
+
+// Board ranks are base-36 strings compared bytewise: a move takes a key strictly between
+// its neighbours, so no other task in the column is renumbered. Keys never end in '0',
+// which keeps a key available between any two of them.
+const rankDigits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+const rankBase = len(rankDigits)
+
+var errRankOrder = errors.New("rank bounds out of order")
+
+// rankBetween returns a key with a < key < b; "" stands for the open end on either side.
+func rankBetween(a, b string) (string, error) {
+	if !validRank(a) || !validRank(b) {
+		return "", errRankOrder
+	}
+	if b != "" && a >= b {
+		return "", errRankOrder
+	}
+	return rankMidpoint(a, b), nil
+}
+
+func rankMidpoint(a, b string) string {
+	if b != "" {
+		n := 0
+		for n < len(b) && rankDigitAt(a, n) == b[n] {
+			n++
+		}
+		if n > 0 {
+			rest := ""
+			if n < len(a) {
+				rest = a[n:]
+			}
+			return b[:n] + rankMidpoint(rest, b[n:])
+		}
+	}
+	lo := 0
+	if a != "" {
+		lo = strings.IndexByte(rankDigits, a[0])
+	}
+	hi := rankBase
+	if b != "" {
+		hi = strings.IndexByte(rankDigits, b[0])
+	}
+	if hi-lo > 1 {
+		return string(rankDigits[(lo+hi)/2])
+	}
+	if b != "" && len(b) > 1 {
+		return b[:1]
+	}
+	rest := ""
+	if len(a) > 1 {
+		rest = a[1:]
+	}
+	return string(rankDigits[lo]) + rankMidpoint(rest, "")
+}
+
+func rankDigitAt(s string, i int) byte {
+	if i < len(s) {
+		return s[i]
+	}
+	return '0'
+}
+
+// ranksAfter returns n increasing keys after a, evenly spaced so later moves between
+// them stay short.
+func ranksAfter(a string, n int) ([]string, error) {
+	prefix, err := rankBetween(a, "")
+	if err != nil {
+		return nil, err
+	}
+	width, space := 4, rankBase*rankBase*rankBase*rankBase
+	for space <= 4*n {
+		width++
+		space *= rankBase
+	}
+	step := space / (n + 1)
+	out := make([]string, 0, n)
+	for i := 1; i <= n; i++ {
+		key := prefix + rankEncode(i*step, width)
+		if strings.HasSuffix(key, "0") {
+			key += string(rankDigits[rankBase/2])
+		}
+		out = append(out, key)
+	}
+	return out, nil
+}
+
+func rankEncode(v, width int) string {
+	buf := make([]byte, width)
+	for i := width - 1; i >= 0; i-- {
+		buf[i] = rankDigits[v%rankBase]
+		v /= rankBase
+	}
+	return string(buf)
+}
+
+func validRank(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if strings.IndexByte(rankDigits, s[i]) < 0 {
+			return false
+		}
+	}
+	return !strings.HasSuffix(s, "0")
+}
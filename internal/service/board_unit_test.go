@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"MKK-Luna/internal/repository"
+)
+
+func TestRankBetween(t *testing.T) {
+	cases := [][2]string{
+		{"", ""}, {"", "1"}, {"a", "b"}, {"a", "a1"}, {"az", "b"}, {"z", ""}, {"zz", ""}, {"", "01"}, {"1", "2"},
+	}
+	for _, c := range cases {
+		got, err := rankBetween(c[0], c[1])
+		if err != nil {
+			t.Fatalf("rankBetween(%q, %q): %v", c[0], c[1], err)
+		}
+		if got <= c[0] || (c[1] != "" && got >= c[1]) || !validRank(got) {
+			t.Fatalf("rankBetween(%q, %q) = %q", c[0], c[1], got)
+		}
+	}
+	if _, err := rankBetween("b", "a"); err == nil {
+		t.Fatalf("expected error for reversed bounds")
+	}
+	if _, err := rankBetween("a", "a"); err == nil {
+		t.Fatalf("expected error for equal bounds")
+	}
+}
+
+func TestRankBetween_RepeatedInsertsStayOrdered(t *testing.T) {
+	lo, hi := "a", "b"
+	for i := 0; i < 200; i++ {
+		mid, err := rankBetween(lo, hi)
+		if err != nil || mid <= lo || mid >= hi {
+			t.Fatalf("step %d: rankBetween(%q, %q) = %q, %v", i, lo, hi, mid, err)
+		}
+		if i%2 == 0 {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+}
+
+func TestRanksAfter(t *testing.T) {
+	keys, err := ranksAfter("k", 1000)
+	if err != nil || len(keys) != 1000 {
+		t.Fatalf("keys=%d err=%v", len(keys), err)
+	}
+	prev := "k"
+	for _, k := range keys {
+		if k <= prev || !validRank(k) || len(k) > 8 {
+			t.Fatalf("key %q after %q", k, prev)
+		}
+		prev = k
+	}
+}
+
+type fakeBoardRepo struct {
+	tasks   map[int64]*repository.BoardTask
+	limits  map[string]int
+	count   int64
+	ranked  map[int64]string
+	replace map[string]int
+}
+
+func (f *fakeBoardRepo) ListColumn(context.Context, repository.BoardFilter, string, int) ([]repository.BoardTask, int64, error) {
+	return nil, 0, nil
+}
+func (f *fakeBoardRepo) GetForUpdateTx(_ context.Context, _ *sqlx.Tx, id int64) (*repository.BoardTask, error) {
+	if t, ok := f.tasks[id]; ok {
+		cp := *t
+		return &cp, nil
+	}
+	return nil, nil
+}
+func (f *fakeBoardRepo) ListUnrankedIDsForUpdateTx(context.Context, *sqlx.Tx, int64, string, int64) ([]int64, error) {
+	return nil, nil
+}
+func (f *fakeBoardRepo) MaxRankTx(context.Context, *sqlx.Tx, int64, string, int64) (string, error) {
+	return "m", nil
+}
+func (f *fakeBoardRepo) NextRankTx(context.Context, *sqlx.Tx, int64, string, string, int64) (string, error) {
+	return "", nil
+}
+func (f *fakeBoardRepo) PrevRankTx(context.Context, *sqlx.Tx, int64, string, string, int64) (string, error) {
+	return "", nil
+}
+func (f *fakeBoardRepo) SetRankTx(_ context.Context, _ *sqlx.Tx, id int64, rank string) error {
+	f.ranked[id] = rank
+	return nil
+}
+func (f *fakeBoardRepo) ListWIPLimits(context.Context, int64) (map[string]int, error) {
+	return f.limits, nil
+}
+func (f *fakeBoardRepo) DeleteWIPLimitsTx(context.Context, *sqlx.Tx, int64) error {
+	f.replace = map[string]int{}
+	return nil
+}
+func (f *fakeBoardRepo) InsertWIPLimitTx(_ context.Context, _ *sqlx.Tx, _ int64, status string, limit int, _ int64) error {
+	f.replace[status] = limit
+	return nil
+}
+func (f *fakeBoardRepo) WIPLimitForUpdateTx(_ context.Context, _ *sqlx.Tx, _ int64, status string) (int, bool, error) {
+	limit, ok := f.limits[status]
+	return limit, ok, nil
+}
+func (f *fakeBoardRepo) CountColumnTx(context.Context, *sqlx.Tx, int64, string) (int64, error) {
+	return f.count, nil
+}
+
+func newBoardTestService(t *testing.T, role string, board *fakeBoardRepo, task *repository.Task, updates *map[string]any) *TaskService {
+	t.Helper()
+	db, mock := newMockDB(t)
+	mock.MatchExpectationsInOrder(false)
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	mock.ExpectRollback()
+	taskRepo := &fakeTaskRepo{
+		getByIDForUpdate: func(context.Context, *sqlx.Tx, int64) (*repository.Task, error) { return task, nil },
+		updateTx: func(_ context.Context, _ *sqlx.Tx, _ int64, fields map[string]any) error {
+			*updates = fields
+			return nil
+		},
+	}
+	members := &fakeMemberRepo{role: role, hasRole: true}
+	return NewTaskService(db, taskRepo, &fakeTeamRepo{}, members, &fakeCommentRepo{}, &fakeHistoryRepo{}, WithBoard(board))
+}
+
+func TestTaskService_MoveTask_MemberChangesColumn(t *testing.T) {
+	task := &repository.Task{ID: 1, TeamID: 10, Title: "t", Status: "todo", Priority: "medium"}
+	board := &fakeBoardRepo{tasks: map[int64]*repository.BoardTask{
+		2: {Task: repository.Task{ID: 2, TeamID: 10, Status: "in_progress"}, BoardRank: sql.NullString{String: "c", Valid: true}},
+		3: {Task: repository.Task{ID: 3, TeamID: 10, Status: "in_progress"}, BoardRank: sql.NullString{String: "f", Valid: true}},
+	}}
+	var updates map[string]any
+	svc := newBoardTestService(t, RoleMember, board, task, &updates)
+
+	after, before := int64(2), int64(3)
+	if _, err := svc.MoveTask(context.Background(), 5, 1, BoardMove{Status: "in_progress", AfterID: &after, BeforeID: &before}, nil); err != nil {
+		t.Fatalf("move: %v", err)
+	}
+	rank, _ := updates["board_rank"].(string)
+	if updates["status"] != "in_progress" || rank <= "c" || rank >= "f" {
+		t.Fatalf("unexpected updates: %+v", updates)
+	}
+}
+
+func TestTaskService_MoveTask_WIPLimitReached(t *testing.T) {
+	task := &repository.Task{ID: 1, TeamID: 10, Title: "t", Status: "todo", Priority: "medium"}
+	board := &fakeBoardRepo{limits: map[string]int{"in_progress": 2}, count: 2}
+	var updates map[string]any
+	svc := newBoardTestService(t, RoleMember, board, task, &updates)
+
+	if _, err := svc.MoveTask(context.Background(), 5, 1, BoardMove{Status: "in_progress"}, nil); err != ErrConflict {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+	if updates != nil {
+		t.Fatalf("expected no update, got %+v", updates)
+	}
+}
+
+func TestTaskService_MoveTask_ReorderKeepsUpdatedAt(t *testing.T) {
+	updatedAt := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	task := &repository.Task{ID: 1, TeamID: 10, Title: "t", Status: "done", Priority: "medium", UpdatedAt: updatedAt}
+	board := &fakeBoardRepo{limits: map[string]int{"done": 1}, count: 5}
+	var updates map[string]any
+	svc := newBoardTestService(t, RoleMember, board, task, &updates)
+
+	if _, err := svc.MoveTask(context.Background(), 5, 1, BoardMove{Status: "done"}, nil); err != nil {
+		t.Fatalf("move: %v", err)
+	}
+	if _, ok := updates["status"]; ok || updates["updated_at"] != updatedAt || updates["board_rank"] == nil {
+		t.Fatalf("unexpected updates: %+v", updates)
+	}
+}
+
+func TestTaskService_MoveTask_NeighbourInOtherColumn(t *testing.T) {
+	task := &repository.Task{ID: 1, TeamID: 10, Title: "t", Status: "todo", Priority: "medium"}
+	board := &fakeBoardRepo{tasks: map[int64]*repository.BoardTask{
+		2: {Task: repository.Task{ID: 2, TeamID: 10, Status: "done"}, BoardRank: sql.NullString{String: "c", Valid: true}},
+	}}
+	var updates map[string]any
+	svc := newBoardTestService(t, RoleOwner, board, task, &updates)
+
+	after := int64(2)
+	if _, err := svc.MoveTask(context.Background(), 5, 1, BoardMove{Status: "in_progress", AfterID: &after}, nil); err != ErrBadRequest {
+		t.Fatalf("expected ErrBadRequest, got %v", err)
+	}
+}
+
+func TestTaskService_SetWIPLimits(t *testing.T) {
+	board := &fakeBoardRepo{}
+	members := &fakeMemberRepo{role: RoleMember, hasRole: true}
+	teams := &fakeTeamRepo{getByID: func(context.Context, int64) (*repository.Team, error) { return &repository.Team{ID: 10}, nil }}
+	db, mock := newMockDB(t)
+	svc := NewTaskService(db, &fakeTaskRepo{}, teams, members, &fakeCommentRepo{}, &fakeHistoryRepo{}, WithBoard(board))
+
+	if err := svc.SetWIPLimits(context.Background(), 1, 10, map[string]int{"in_progress": 3}); err != ErrForbidden {
+		t.Fatalf("member: expected ErrForbidden, got %v", err)
+	}
+	members.role = RoleAdmin
+	if err := svc.SetWIPLimits(context.Background(), 1, 10, map[string]int{"blocked": 3}); err != ErrBadRequest {
+		t.Fatalf("unknown status: expected ErrBadRequest, got %v", err)
+	}
+	if err := svc.SetWIPLimits(context.Background(), 1, 10, map[string]int{"in_progress": 0}); err != ErrBadRequest {
+		t.Fatalf("zero limit: expected ErrBadRequest, got %v", err)
+	}
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	if err := svc.SetWIPLimits(context.Background(), 1, 10, map[string]int{"in_progress": 3}); err != nil {
+		t.Fatalf("admin: %v", err)
+	}
+	if board.replace["in_progress"] != 3 {
+		t.Fatalf("unexpected limits: %+v", board.replace)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+}
+
+func TestTaskService_CreateTask_WIPLimit(t *testing.T) {
+	db, mock := newMockDB(t)
+	board := &fakeBoardRepo{limits: map[string]int{"in_progress": 2}, count: 2}
+	created := 0
+	taskRepo := &fakeTaskRepo{create: func(context.Context, repository.Task) (int64, error) {
+		created++
+		return 7, nil
+	}}
+	teams := &fakeTeamRepo{getByID: func(context.Context, int64) (*repository.Team, error) { return &repository.Team{ID: 10}, nil }}
+	svc := NewTaskService(db, taskRepo, teams, &fakeMemberRepo{}, &fakeCommentRepo{}, &fakeHistoryRepo{}, WithBoard(board))
+	ctx := context.Background()
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	if _, err := svc.CreateTask(ctx, 5, CreateTaskInput{TeamID: 10, Title: "t", Status: "in_progress"}); err != ErrConflict {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	if id, err := svc.CreateTask(ctx, 5, CreateTaskInput{TeamID: 10, Title: "t"}); err != nil || id != 7 {
+		t.Fatalf("todo: id=%d err=%v", id, err)
+	}
+	if created != 1 {
+		t.Fatalf("expected 1 created task, got %d", created)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+}
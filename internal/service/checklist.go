@@ -0,0 +1,335 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jmoiron/sqlx"
+
+	"MKK-Luna/internal/repository"
+)
+
+const (
+	maxChecklistItems   = 100
+	maxChecklistItemLen = 500
+)
+
+type taskChecklistRepo interface {
+	ListByTask(ctx context.Context, taskID int64) ([]repository.ChecklistItem, error)
+	GetByID(ctx context.Context, id int64) (*repository.ChecklistItem, error)
+	GetByIDForUpdateTx(ctx context.Context, tx *sqlx.Tx, id int64) (*repository.ChecklistItem, error)
+	CreateTx(ctx context.Context, tx *sqlx.Tx, taskID int64, items []repository.ChecklistItem) ([]int64, error)
+	UpdateTx(ctx context.Context, tx *sqlx.Tx, id int64, fields map[string]any) error
+	SetDoneTx(ctx context.Context, tx *sqlx.Tx, id int64, done bool, userID int64) error
+	DeleteTx(ctx context.Context, tx *sqlx.Tx, id int64) error
+	ListIDsForUpdateTx(ctx context.Context, tx *sqlx.Tx, taskID int64) ([]int64, error)
+	ReorderTx(ctx context.Context, tx *sqlx.Tx, taskID int64, ids []int64) error
+	TouchTaskTx(ctx context.Context, tx *sqlx.Tx, taskID int64) error
+	Summaries(ctx context.Context, taskIDs []int64) ([]repository.ChecklistSummary, error)
+}
+
+func WithChecklists(checklists taskChecklistRepo) TaskServiceOption {
+	return func(s *TaskService) { s.checklists = checklists }
+}
+
+type ChecklistItemInput struct {
+	Body       string
+	AssigneeID *int64
+}
+
+// ChecklistItemPatch carries optional changes; ClearAssignee removes the assignee.
+type ChecklistItemPatch struct {
+	Body          *string
+	AssigneeID    *int64
+	ClearAssignee bool
+}
+
+type ChecklistSummary struct {
+	Total int64
+	Done  int64
+}
+
+// CompletionPercent rounds down so a list is only 100% when every item is done.
+func (c ChecklistSummary) CompletionPercent() int {
+	if c.Total == 0 {
+		return 0
+	}
+	return int(c.Done * 100 / c.Total)
+}
+
+func (s *TaskService) ListChecklist(ctx context.Context, userID, taskID int64) ([]repository.ChecklistItem, int64, error) {
+	if s.checklists == nil {
+		return nil, 0, ErrUnavailable
+	}
+	task, err := s.tasks.GetByID(ctx, taskID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if task == nil {
+		return nil, 0, ErrNotFound
+	}
+	if ok, err := s.members.IsMember(ctx, task.TeamID, userID); err != nil {
+		return nil, 0, err
+	} else if !ok {
+		return nil, 0, ErrForbidden
+	}
+	items, err := s.checklists.ListByTask(ctx, taskID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, task.TeamID, nil
+}
+
+// AddChecklistItems appends items to the end of the task's checklist. Editing the
+// checklist follows the description: owners and admins only.
+func (s *TaskService) AddChecklistItems(ctx context.Context, userID, taskID int64, in []ChecklistItemInput) ([]int64, int64, error) {
+	if len(in) == 0 {
+		return nil, 0, ErrBadRequest
+	}
+	var ids []int64
+	teamID, err := s.inChecklistTx(ctx, userID, taskID, true, func(tx *sqlx.Tx, task *repository.Task) error {
+		existing, err := s.checklists.ListIDsForUpdateTx(ctx, tx, taskID)
+		if err != nil {
+			return err
+		}
+		if len(existing)+len(in) > maxChecklistItems {
+			return ErrBadRequest
+		}
+		items := make([]repository.ChecklistItem, 0, len(in))
+		for _, item := range in {
+			body, err := normalizeChecklistBody(item.Body)
+			if err != nil {
+				return err
+			}
+			assignee, err := s.checklistAssignee(ctx, task.TeamID, item.AssigneeID)
+			if err != nil {
+				return err
+			}
+			items = append(items, repository.ChecklistItem{Body: body, AssigneeID: assignee})
+		}
+		ids, err = s.checklists.CreateTx(ctx, tx, taskID, items)
+		return err
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return ids, teamID, nil
+}
+
+func (s *TaskService) UpdateChecklistItem(ctx context.Context, userID, itemID int64, patch ChecklistItemPatch) (int64, error) {
+	item, err := s.getChecklistItem(ctx, itemID)
+	if err != nil {
+		return 0, err
+	}
+	return s.inChecklistTx(ctx, userID, item.TaskID, true, func(tx *sqlx.Tx, task *repository.Task) error {
+		locked, err := s.checklists.GetByIDForUpdateTx(ctx, tx, itemID)
+		if err != nil {
+			return err
+		}
+		if locked == nil || locked.TaskID != task.ID {
+			return ErrNotFound
+		}
+		fields := map[string]any{}
+		if patch.Body != nil {
+			body, err := normalizeChecklistBody(*patch.Body)
+			if err != nil {
+				return err
+			}
+			fields["body"] = body
+		}
+		if patch.ClearAssignee {
+			fields["assignee_id"] = nil
+		} else if patch.AssigneeID != nil {
+			assignee, err := s.checklistAssignee(ctx, task.TeamID, patch.AssigneeID)
+			if err != nil {
+				return err
+			}
+			fields["assignee_id"] = assignee.Int64
+		}
+		if len(fields) == 0 {
+			return ErrBadRequest
+		}
+		return s.checklists.UpdateTx(ctx, tx, itemID, fields)
+	})
+}
+
+func (s *TaskService) DeleteChecklistItem(ctx context.Context, userID, itemID int64) (int64, error) {
+	item, err := s.getChecklistItem(ctx, itemID)
+	if err != nil {
+		return 0, err
+	}
+	return s.inChecklistTx(ctx, userID, item.TaskID, true, func(tx *sqlx.Tx, task *repository.Task) error {
+		locked, err := s.checklists.GetByIDForUpdateTx(ctx, tx, itemID)
+		if err != nil {
+			return err
+		}
+		if locked == nil || locked.TaskID != task.ID {
+			return ErrNotFound
+		}
+		return s.checklists.DeleteTx(ctx, tx, itemID)
+	})
+}
+
+// ToggleChecklistItem is open to every team member, unlike the other checklist edits,
+// and records the change in task history as a "checklist_item" entry.
+func (s *TaskService) ToggleChecklistItem(ctx context.Context, userID, itemID int64, done bool) (int64, error) {
+	item, err := s.getChecklistItem(ctx, itemID)
+	if err != nil {
+		return 0, err
+	}
+	return s.inChecklistTx(ctx, userID, item.TaskID, false, func(tx *sqlx.Tx, task *repository.Task) error {
+		locked, err := s.checklists.GetByIDForUpdateTx(ctx, tx, itemID)
+		if err != nil {
+			return err
+		}
+		if locked == nil || locked.TaskID != task.ID {
+			return ErrNotFound
+		}
+		if locked.Done == done {
+			return errChecklistNoop
+		}
+		if err := s.checklists.SetDoneTx(ctx, tx, itemID, done, userID); err != nil {
+			return err
+		}
+		entry := taskHistoryEntry(task.ID, userID, "checklist_item",
+			map[string]any{"item_id": itemID, "body": locked.Body, "done": locked.Done},
+			map[string]any{"item_id": itemID, "body": locked.Body, "done": done},
+		)
+		return s.history.CreateBatchTx(ctx, tx, []repository.TaskHistoryCreate{entry})
+	})
+}
+
+// ReorderChecklist takes the full list of item ids in their new order.
+func (s *TaskService) ReorderChecklist(ctx context.Context, userID, taskID int64, itemIDs []int64) (int64, error) {
+	if len(itemIDs) == 0 {
+		return 0, ErrBadRequest
+	}
+	return s.inChecklistTx(ctx, userID, taskID, true, func(tx *sqlx.Tx, task *repository.Task) error {
+		current, err := s.checklists.ListIDsForUpdateTx(ctx, tx, taskID)
+		if err != nil {
+			return err
+		}
+		if len(current) != len(itemIDs) {
+			return ErrBadRequest
+		}
+		pending := make(map[int64]bool, len(current))
+		for _, id := range current {
+			pending[id] = true
+		}
+		for _, id := range itemIDs {
+			if !pending[id] {
+				return ErrBadRequest
+			}
+			delete(pending, id)
+		}
+		return s.checklists.ReorderTx(ctx, tx, taskID, itemIDs)
+	})
+}
+
+// ChecklistSummaries returns totals only for tasks that have checklist items.
+func (s *TaskService) ChecklistSummaries(ctx context.Context, taskIDs []int64) (map[int64]ChecklistSummary, error) {
+	out := make(map[int64]ChecklistSummary)
+	if s.checklists == nil || len(taskIDs) == 0 {
+		return out, nil
+	}
+	rows, err := s.checklists.Summaries(ctx, taskIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		out[row.TaskID] = ChecklistSummary{Total: row.Total, Done: row.Done}
+	}
+	return out, nil
+}
+
+// errChecklistNoop ends a checklist transaction without writing anything.
+var errChecklistNoop = errors.New("checklist unchanged")
+
+// inChecklistTx locks the task, checks the caller's role and runs fn. Every change bumps
+// the task version so ETags and cached task lists see the new checklist.
+func (s *TaskService) inChecklistTx(ctx context.Context, userID, taskID int64, managerOnly bool, fn func(tx *sqlx.Tx, task *repository.Task) error) (int64, error) {
+	if s.checklists == nil || s.db == nil || s.history == nil {
+		return 0, ErrUnavailable
+	}
+	tx, err := s.db.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return 0, err
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			_ = tx.Rollback()
+		}
+	}()
+
+	task, err := s.tasks.GetByIDForUpdateTx(ctx, tx, taskID)
+	if err != nil {
+		return 0, err
+	}
+	if task == nil {
+		return 0, ErrNotFound
+	}
+	role, ok, err := s.members.GetRole(ctx, task.TeamID, userID)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, ErrForbidden
+	}
+	if managerOnly && role != RoleOwner && role != RoleAdmin {
+		return 0, ErrForbidden
+	}
+	if err := fn(tx, task); err != nil {
+		if err == errChecklistNoop {
+			return task.TeamID, nil
+		}
+		return 0, err
+	}
+	if err := s.checklists.TouchTaskTx(ctx, tx, taskID); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	committed = true
+	return task.TeamID, nil
+}
+
+func (s *TaskService) getChecklistItem(ctx context.Context, itemID int64) (*repository.ChecklistItem, error) {
+	if s.checklists == nil {
+		return nil, ErrUnavailable
+	}
+	item, err := s.checklists.GetByID(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, ErrNotFound
+	}
+	return item, nil
+}
+
+func (s *TaskService) checklistAssignee(ctx context.Context, teamID int64, assigneeID *int64) (sql.NullInt64, error) {
+	if assigneeID == nil {
+		return sql.NullInt64{}, nil
+	}
+	ok, err := s.members.IsMember(ctx, teamID, *assigneeID)
+	if err != nil {
+		return sql.NullInt64{}, err
+	}
+	if !ok {
+		return sql.NullInt64{}, ErrBadRequest
+	}
+	return sql.NullInt64{Int64: *assigneeID, Valid: true}, nil
+}
+
+func normalizeChecklistBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" || utf8.RuneCountInString(body) > maxChecklistItemLen {
+		return "", ErrBadRequest
+	}
+	return body, nil
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+
+	"MKK-Luna/internal/repository"
+)
+
+type fakeChecklistRepo struct {
+	items   map[int64]*repository.ChecklistItem
+	created []repository.ChecklistItem
+	order   []int64
+	touched int
+}
+
+func (f *fakeChecklistRepo) ListByTask(_ context.Context, taskID int64) ([]repository.ChecklistItem, error) {
+	var out []repository.ChecklistItem
+	for _, item := range f.items {
+		if item.TaskID == taskID {
+			out = append(out, *item)
+		}
+	}
+	return out, nil
+}
+func (f *fakeChecklistRepo) GetByID(_ context.Context, id int64) (*repository.ChecklistItem, error) {
+	if item, ok := f.items[id]; ok {
+		cp := *item
+		return &cp, nil
+	}
+	return nil, nil
+}
+func (f *fakeChecklistRepo) GetByIDForUpdateTx(ctx context.Context, _ *sqlx.Tx, id int64) (*repository.ChecklistItem, error) {
+	return f.GetByID(ctx, id)
+}
+func (f *fakeChecklistRepo) CreateTx(_ context.Context, _ *sqlx.Tx, _ int64, items []repository.ChecklistItem) ([]int64, error) {
+	f.created = append(f.created, items...)
+	ids := make([]int64, len(items))
+	for i := range items {
+		ids[i] = int64(100 + i)
+	}
+	return ids, nil
+}
+func (f *fakeChecklistRepo) UpdateTx(context.Context, *sqlx.Tx, int64, map[string]any) error {
+	return nil
+}
+func (f *fakeChecklistRepo) SetDoneTx(_ context.Context, _ *sqlx.Tx, id int64, done bool, _ int64) error {
+	f.items[id].Done = done
+	return nil
+}
+func (f *fakeChecklistRepo) DeleteTx(context.Context, *sqlx.Tx, int64) error { return nil }
+func (f *fakeChecklistRepo) ListIDsForUpdateTx(_ context.Context, _ *sqlx.Tx, taskID int64) ([]int64, error) {
+	var ids []int64
+	for id, item := range f.items {
+		if item.TaskID == taskID {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+func (f *fakeChecklistRepo) ReorderTx(_ context.Context, _ *sqlx.Tx, _ int64, ids []int64) error {
+	f.order = ids
+	return nil
+}
+func (f *fakeChecklistRepo) TouchTaskTx(context.Context, *sqlx.Tx, int64) error {
+	f.touched++
+	return nil
+}
+func (f *fakeChecklistRepo) Summaries(context.Context, []int64) ([]repository.ChecklistSummary, error) {
+	return []repository.ChecklistSummary{{TaskID: 1, Total: 3, Done: 2}}, nil
+}
+
+func newChecklistTestService(t *testing.T, role string, checklists *fakeChecklistRepo, history *fakeHistoryRepo) *TaskService {
+	t.Helper()
+	db, mock := newMockDB(t)
+	mock.MatchExpectationsInOrder(false)
+	for i := 0; i < 4; i++ {
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+		mock.ExpectRollback()
+	}
+	task := &repository.Task{ID: 1, TeamID: 10, Title: "t", Status: "todo", Priority: "medium"}
+	taskRepo := &fakeTaskRepo{
+		getByIDForUpdate: func(context.Context, *sqlx.Tx, int64) (*repository.Task, error) { return task, nil },
+	}
+	members := &fakeMemberRepo{role: role, hasRole: true}
+	return NewTaskService(db, taskRepo, &fakeTeamRepo{}, members, &fakeCommentRepo{}, history, WithChecklists(checklists))
+}
+
+func TestTaskService_ToggleChecklistItem_MemberRecordsHistory(t *testing.T) {
+	checklists := &fakeChecklistRepo{items: map[int64]*repository.ChecklistItem{
+		5: {ID: 5, TaskID: 1, Body: "ship it"},
+	}}
+	var entries []repository.TaskHistoryCreate
+	history := &fakeHistoryRepo{
+		createBatchTx: func(_ context.Context, _ *sqlx.Tx, e []repository.TaskHistoryCreate) error {
+			entries = append(entries, e...)
+			return nil
+		},
+	}
+	svc := newChecklistTestService(t, RoleMember, checklists, history)
+
+	teamID, err := svc.ToggleChecklistItem(context.Background(), 2, 5, true)
+	if err != nil || teamID != 10 {
+		t.Fatalf("toggle teamID=%d err=%v", teamID, err)
+	}
+	if !checklists.items[5].Done || checklists.touched != 1 {
+		t.Fatalf("expected item done and task touched, got %+v touched=%d", checklists.items[5], checklists.touched)
+	}
+	if len(entries) != 1 || entries[0].FieldName != "checklist_item" {
+		t.Fatalf("unexpected history: %+v", entries)
+	}
+	var newValue map[string]any
+	if err := json.Unmarshal(*entries[0].NewValue, &newValue); err != nil || newValue["done"] != true {
+		t.Fatalf("unexpected new value: %v err=%v", newValue, err)
+	}
+
+	// Toggling to the current state writes nothing.
+	if _, err := svc.ToggleChecklistItem(context.Background(), 2, 5, true); err != nil {
+		t.Fatalf("repeat toggle: %v", err)
+	}
+	if len(entries) != 1 || checklists.touched != 1 {
+		t.Fatalf("expected no-op, entries=%d touched=%d", len(entries), checklists.touched)
+	}
+}
+
+func TestTaskService_ChecklistEdits_ManagerOnly(t *testing.T) {
+	checklists := &fakeChecklistRepo{items: map[int64]*repository.ChecklistItem{
+		5: {ID: 5, TaskID: 1, Body: "a"},
+	}}
+	svc := newChecklistTestService(t, RoleMember, checklists, &fakeHistoryRepo{})
+
+	if _, _, err := svc.AddChecklistItems(context.Background(), 2, 1, []ChecklistItemInput{{Body: "b"}}); err != ErrForbidden {
+		t.Fatalf("add: expected ErrForbidden, got %v", err)
+	}
+	if _, err := svc.DeleteChecklistItem(context.Background(), 2, 5); err != ErrForbidden {
+		t.Fatalf("delete: expected ErrForbidden, got %v", err)
+	}
+}
+
+func TestTaskService_ReorderChecklist(t *testing.T) {
+	checklists := &fakeChecklistRepo{items: map[int64]*repository.ChecklistItem{
+		5: {ID: 5, TaskID: 1, Body: "a"},
+		6: {ID: 6, TaskID: 1, Body: "b"},
+	}}
+	svc := newChecklistTestService(t, RoleAdmin, checklists, &fakeHistoryRepo{})
+
+	if _, err := svc.ReorderChecklist(context.Background(), 1, 1, []int64{6}); err != ErrBadRequest {
+		t.Fatalf("partial order: expected ErrBadRequest, got %v", err)
+	}
+	if _, err := svc.ReorderChecklist(context.Background(), 1, 1, []int64{6, 6}); err != ErrBadRequest {
+		t.Fatalf("duplicate ids: expected ErrBadRequest, got %v", err)
+	}
+	if _, err := svc.ReorderChecklist(context.Background(), 1, 1, []int64{6, 5}); err != nil {
+		t.Fatalf("reorder: %v", err)
+	}
+	if len(checklists.order) != 2 || checklists.order[0] != 6 || checklists.touched != 1 {
+		t.Fatalf("unexpected order=%v touched=%d", checklists.order, checklists.touched)
+	}
+}
+
+func TestChecklistSummary_CompletionPercent(t *testing.T) {
+	cases := []struct {
+		sum  ChecklistSummary
+		want int
+	}{
+		{ChecklistSummary{}, 0},
+		{ChecklistSummary{Total: 3, Done: 2}, 66},
+		{ChecklistSummary{Total: 3, Done: 3}, 100},
+	}
+	for _, c := range cases {
+		if got := c.sum.CompletionPercent(); got != c.want {
+			t.Fatalf("%+v: got %d want %d", c.sum, got, c.want)
+		}
+	}
+}
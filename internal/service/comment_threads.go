@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/jmoiron/sqlx"
+
+	"MKK-Luna/internal/repository"
+)
+
+type commentThreadRepo interface {
+	CreateReplyTx(ctx context.Context, tx *sqlx.Tx, taskID, userID, parentID int64, body string) (int64, error)
+	UpdateBodyTx(ctx context.Context, tx *sqlx.Tx, commentID, version int64, body string) (bool, error)
+	InsertEditTx(ctx context.Context, tx *sqlx.Tx, commentID, editorID int64, prevBody string) error
+	SoftDelete(ctx context.Context, commentID, version int64) (bool, error)
+	ListEdits(ctx context.Context, commentID int64) ([]repository.TaskCommentEdit, error)
+}
+
+type commentReactionRepo interface {
+	Add(ctx context.Context, commentID, userID int64, emoji string) error
+	Remove(ctx context.Context, commentID, userID int64, emoji string) error
+	CountsByComments(ctx context.Context, viewerID int64, commentIDs []int64) ([]repository.CommentReactionCount, error)
+}
+
+func WithCommentThreads(threads commentThreadRepo, reactions commentReactionRepo) TaskServiceOption {
+	return func(s *TaskService) {
+		s.threads = threads
+		s.reactions = reactions
+	}
+}
+
+func (s *TaskService) ReplyToComment(ctx context.Context, userID, parentID int64, body string) (int64, error) {
+	if s.threads == nil {
+		return 0, ErrUnavailable
+	}
+	parent, task, err := s.commentForMember(ctx, userID, parentID)
+	if err != nil {
+		return 0, err
+	}
+	if parent.DeletedAt.Valid {
+		return 0, ErrNotFound
+	}
+	return s.createComment(ctx, userID, *task, &parent.ID, body)
+}
+
+func (s *TaskService) ListCommentEdits(ctx context.Context, userID, commentID int64) ([]repository.TaskCommentEdit, error) {
+	if s.threads == nil {
+		return nil, ErrUnavailable
+	}
+	comment, _, err := s.commentForMember(ctx, userID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.DeletedAt.Valid {
+		return nil, ErrNotFound
+	}
+	return s.threads.ListEdits(ctx, commentID)
+}
+
+func (s *TaskService) AddReaction(ctx context.Context, userID, commentID int64, emoji string) error {
+	return s.setReaction(ctx, userID, commentID, emoji, true)
+}
+
+func (s *TaskService) RemoveReaction(ctx context.Context, userID, commentID int64, emoji string) error {
+	return s.setReaction(ctx, userID, commentID, emoji, false)
+}
+
+func (s *TaskService) setReaction(ctx context.Context, userID, commentID int64, emoji string, add bool) error {
+	if s.reactions == nil {
+		return ErrUnavailable
+	}
+	if !isValidReaction(emoji) {
+		return ErrBadRequest
+	}
+	comment, _, err := s.commentForMember(ctx, userID, commentID)
+	if err != nil {
+		return err
+	}
+	if comment.DeletedAt.Valid {
+		return ErrNotFound
+	}
+	if add {
+		return s.reactions.Add(ctx, commentID, userID, emoji)
+	}
+	return s.reactions.Remove(ctx, commentID, userID, emoji)
+}
+
+func (s *TaskService) CommentReactions(ctx context.Context, viewerID int64, commentIDs []int64) (map[int64][]repository.CommentReactionCount, error) {
+	out := make(map[int64][]repository.CommentReactionCount)
+	if s.reactions == nil || len(commentIDs) == 0 {
+		return out, nil
+	}
+	items, err := s.reactions.CountsByComments(ctx, viewerID, commentIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range items {
+		out[r.CommentID] = append(out[r.CommentID], r)
+	}
+	return out, nil
+}
+
+func (s *TaskService) commentForMember(ctx context.Context, userID, commentID int64) (*repository.TaskComment, *repository.Task, error) {
+	comment, err := s.comments.GetByID(ctx, commentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if comment == nil {
+		return nil, nil, ErrNotFound
+	}
+	task, err := s.GetTask(ctx, userID, comment.TaskID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return comment, task, nil
+}
+
+func isValidReaction(emoji string) bool {
+	if emoji == "" || len(emoji) > 32 || !utf8.ValidString(emoji) {
+		return false
+	}
+	if utf8.RuneCountInString(emoji) > 8 {
+		return false
+	}
+	return !strings.ContainsFunc(emoji, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsControl(r)
+	})
+}
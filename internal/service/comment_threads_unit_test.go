@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"MKK-Luna/internal/repository"
+)
+
+type fakeThreadRepo struct {
+	replyParent int64
+	prevBody    string
+	softDeleted int64
+	stale       bool
+}
+
+func (f *fakeThreadRepo) CreateReplyTx(_ context.Context, _ *sqlx.Tx, _, _, parentID int64, _ string) (int64, error) {
+	f.replyParent = parentID
+	return 42, nil
+}
+func (f *fakeThreadRepo) UpdateBodyTx(context.Context, *sqlx.Tx, int64, int64, string) (bool, error) {
+	return !f.stale, nil
+}
+func (f *fakeThreadRepo) InsertEditTx(_ context.Context, _ *sqlx.Tx, _, _ int64, prevBody string) error {
+	f.prevBody = prevBody
+	return nil
+}
+func (f *fakeThreadRepo) SoftDelete(_ context.Context, commentID, _ int64) (bool, error) {
+	if f.stale {
+		return false, nil
+	}
+	f.softDeleted = commentID
+	return true, nil
+}
+func (f *fakeThreadRepo) ListEdits(context.Context, int64) ([]repository.TaskCommentEdit, error) {
+	return []repository.TaskCommentEdit{{ID: 1, Body: "v1"}}, nil
+}
+
+type fakeReactionRepo struct {
+	added []string
+}
+
+func (f *fakeReactionRepo) Add(_ context.Context, _, _ int64, emoji string) error {
+	f.added = append(f.added, emoji)
+	return nil
+}
+func (f *fakeReactionRepo) Remove(context.Context, int64, int64, string) error { return nil }
+func (f *fakeReactionRepo) CountsByComments(context.Context, int64, []int64) ([]repository.CommentReactionCount, error) {
+	return []repository.CommentReactionCount{{CommentID: 1, Emoji: "👍", Count: 2}}, nil
+}
+
+func newThreadTestService(db *sqlx.DB, comment *repository.TaskComment, threads *fakeThreadRepo, reactions *fakeReactionRepo) *TaskService {
+	taskRepo := &fakeTaskRepo{
+		getByID: func(context.Context, int64) (*repository.Task, error) {
+			return &repository.Task{ID: 1, TeamID: 10}, nil
+		},
+	}
+	comments := &commentRepoFns{
+		getFn: func(context.Context, int64) (*repository.TaskComment, error) { return comment, nil },
+	}
+	return NewTaskService(db, taskRepo, &fakeTeamRepo{}, &fakeMemberRepo{role: RoleMember, hasRole: true}, comments, &fakeHistoryRepo{},
+		WithCommentThreads(threads, reactions))
+}
+
+func TestTaskService_ReplyToComment(t *testing.T) {
+	db, mock := newMockDB(t)
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	threads := &fakeThreadRepo{}
+	comment := &repository.TaskComment{ID: 7, TaskID: 1, UserID: sqlNullInt64(5)}
+	svc := newThreadTestService(db, comment, threads, &fakeReactionRepo{})
+
+	id, err := svc.ReplyToComment(context.Background(), 5, 7, "reply")
+	if err != nil || id != 42 || threads.replyParent != 7 {
+		t.Fatalf("reply id=%d err=%v parent=%d", id, err, threads.replyParent)
+	}
+
+	comment.DeletedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	if _, err := svc.ReplyToComment(context.Background(), 5, 7, "reply"); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound replying to deleted comment, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+}
+
+func TestTaskService_CommentEditAndSoftDelete(t *testing.T) {
+	db, mock := newMockDB(t)
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	threads := &fakeThreadRepo{}
+	comment := &repository.TaskComment{ID: 7, TaskID: 1, UserID: sqlNullInt64(5), Body: "v1"}
+	svc := newThreadTestService(db, comment, threads, &fakeReactionRepo{})
+
+	if err := svc.UpdateComment(context.Background(), 5, 7, "v2"); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if threads.prevBody != "v1" {
+		t.Fatalf("prior version must be recorded, got %q", threads.prevBody)
+	}
+	if err := svc.DeleteComment(context.Background(), 5, 7); err != nil || threads.softDeleted != 7 {
+		t.Fatalf("soft delete err=%v deleted=%d", err, threads.softDeleted)
+	}
+
+	comment.DeletedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	if err := svc.UpdateComment(context.Background(), 5, 7, "v3"); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound editing deleted comment, got %v", err)
+	}
+	if _, err := svc.ListCommentEdits(context.Background(), 5, 7); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound for edits of deleted comment, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+}
+
+func TestTaskService_CommentPreconditions(t *testing.T) {
+	db, mock := newMockDB(t)
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	threads := &fakeThreadRepo{}
+	comment := &repository.TaskComment{ID: 7, TaskID: 1, UserID: sqlNullInt64(5), Body: "v1", Version: 3}
+	svc := newThreadTestService(db, comment, threads, &fakeReactionRepo{})
+
+	if err := svc.UpdateCommentIfMatch(context.Background(), 5, 7, "v2", &Precondition{Versions: []int64{2}}); err != ErrPreconditionFailed {
+		t.Fatalf("expected ErrPreconditionFailed for stale If-Match, got %v", err)
+	}
+	if threads.prevBody != "" {
+		t.Fatal("stale edit must not reach the repository")
+	}
+
+	threads.stale = true
+	if err := svc.UpdateCommentIfMatch(context.Background(), 5, 7, "v2", &Precondition{Versions: []int64{3}}); err != ErrPreconditionFailed {
+		t.Fatalf("expected ErrPreconditionFailed when the row changed concurrently, got %v", err)
+	}
+	if err := svc.DeleteComment(context.Background(), 5, 7); err != ErrConflict {
+		t.Fatalf("expected ErrConflict for a lost race without If-Match, got %v", err)
+	}
+
+	threads.stale = false
+	if err := svc.DeleteCommentIfMatch(context.Background(), 5, 7, &Precondition{Any: true}); err != nil || threads.softDeleted != 7 {
+		t.Fatalf("If-Match * delete err=%v deleted=%d", err, threads.softDeleted)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+}
+
+func TestTaskService_Reactions(t *testing.T) {
+	reactions := &fakeReactionRepo{}
+	comment := &repository.TaskComment{ID: 1, TaskID: 1}
+	svc := newThreadTestService(nil, comment, &fakeThreadRepo{}, reactions)
+
+	for _, bad := range []string{"", "thumbs up", "waytoolongreaction"} {
+		if err := svc.AddReaction(context.Background(), 5, 1, bad); err != ErrBadRequest {
+			t.Fatalf("expected ErrBadRequest for %q, got %v", bad, err)
+		}
+	}
+	if err := svc.AddReaction(context.Background(), 5, 1, "👍"); err != nil || len(reactions.added) != 1 {
+		t.Fatalf("add reaction err=%v added=%v", err, reactions.added)
+	}
+	counts, err := svc.CommentReactions(context.Background(), 5, []int64{1})
+	if err != nil || len(counts[1]) != 1 || counts[1][0].Count != 2 {
+		t.Fatalf("counts err=%v items=%v", err, counts)
+	}
+}
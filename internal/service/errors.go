@@ -8,4 +8,7 @@ var (
 	ErrConflict    = errors.New("conflict")
 	ErrBadRequest  = errors.New("bad request")
 	ErrUnavailable = errors.New("unavailable")
+
+	ErrPreconditionFailed = errors.New("precondition failed")
+	ErrExportTooLarge     = errors.New("export too large")
 )
@@ -0,0 +1,625 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"MKK-Luna/internal/config"
+	dstorage "MKK-Luna/internal/domain/storage"
+	"MKK-Luna/internal/repository"
+)
+
+const (
+	ExportKindTasks    = "tasks"
+	ExportKindHistory  = "history"
+	ExportKindComments = "comments"
+
+	ExportFormatCSV    = "csv"
+	ExportFormatNDJSON = "ndjson"
+
+	ExportJobPending = "pending"
+	ExportJobRunning = "running"
+	ExportJobDone    = "done"
+	ExportJobFailed  = "failed"
+)
+
+const (
+	exportCSVFlushEvery = 100
+	exportPurgeBatch    = 100
+)
+
+type exportStore interface {
+	CountTasks(ctx context.Context, f repository.TaskListFilter) (int64, error)
+	StreamTasks(ctx context.Context, f repository.TaskListFilter, fn func(repository.Task) error) error
+	CountHistory(ctx context.Context, teamID int64, from, to time.Time) (int64, error)
+	StreamHistory(ctx context.Context, teamID int64, from, to time.Time, fn func(repository.TaskHistory) error) error
+	CountComments(ctx context.Context, teamID int64, from, to time.Time) (int64, error)
+	StreamComments(ctx context.Context, teamID int64, from, to time.Time, fn func(repository.TaskComment) error) error
+	CreateJob(ctx context.Context, j repository.ExportJob) (int64, error)
+	GetJob(ctx context.Context, id int64) (*repository.ExportJob, error)
+	ClaimJob(ctx context.Context, staleBefore time.Time) (*repository.ExportJob, error)
+	FinishJob(ctx context.Context, id int64, storageKey string, rowCount, sizeBytes int64, expiresAt time.Time) error
+	FailJob(ctx context.Context, id int64, reason string, expiresAt time.Time) error
+	ListExpiredJobs(ctx context.Context, before time.Time, limit int) ([]repository.ExportJob, error)
+	DeleteJob(ctx context.Context, id int64) error
+}
+
+type ExportService struct {
+	exports    exportStore
+	teams      teamRepo
+	members    teamMemberRepo
+	blobs      dstorage.BlobStorage
+	cfg        config.ExportConfig
+	signingKey []byte
+	audit      *AuditService
+	logger     *slog.Logger
+}
+
+type ExportServiceOption func(*ExportService)
+
+func NewExportService(
+	exports exportStore,
+	teams teamRepo,
+	members teamMemberRepo,
+	blobs dstorage.BlobStorage,
+	cfg config.ExportConfig,
+	signingKey string,
+	logger *slog.Logger,
+	opts ...ExportServiceOption,
+) *ExportService {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	s := &ExportService{
+		exports: exports, teams: teams, members: members, blobs: blobs,
+		cfg: cfg, signingKey: []byte(signingKey), logger: logger,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// ExportRequest selects what to export. Task exports take the GET /tasks filters; history
+// and comment exports take a required [From, To) range on creation time instead.
+type ExportRequest struct {
+	TeamID     int64      `json:"team_id"`
+	Kind       string     `json:"kind"`
+	Format     string     `json:"format"`
+	Status     *string    `json:"status,omitempty"`
+	AssigneeID *int64     `json:"assignee_id,omitempty"`
+	SprintID   *int64     `json:"sprint_id,omitempty"`
+	Sort       string     `json:"sort,omitempty"`
+	From       *time.Time `json:"from,omitempty"`
+	To         *time.Time `json:"to,omitempty"`
+}
+
+func ExportContentType(format string) string {
+	if format == ExportFormatNDJSON {
+		return "application/x-ndjson"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Export streams the export to w. start runs once the request has been checked and before
+// the first byte is written, so callers can still report earlier errors normally. Exports
+// above the configured row limit fail with ErrExportTooLarge and should run as a job.
+func (s *ExportService) Export(ctx context.Context, userID int64, req ExportRequest, w io.Writer, start func()) (int64, error) {
+	if err := validateExportRequest(req); err != nil {
+		return 0, err
+	}
+	if err := s.requireMember(ctx, req.TeamID, userID); err != nil {
+		return 0, err
+	}
+	if s.cfg.SyncMaxRows > 0 {
+		n, err := s.count(ctx, req)
+		if err != nil {
+			return 0, err
+		}
+		if n > s.cfg.SyncMaxRows {
+			return 0, ErrExportTooLarge
+		}
+	}
+
+	start()
+	rows, err := s.write(ctx, req, w)
+	s.recordExport(ctx, userID, req, rows, err == nil, 0)
+	return rows, err
+}
+
+func (s *ExportService) CreateJob(ctx context.Context, userID int64, req ExportRequest) (*repository.ExportJob, error) {
+	if err := validateExportRequest(req); err != nil {
+		return nil, err
+	}
+	if err := s.requireMember(ctx, req.TeamID, userID); err != nil {
+		return nil, err
+	}
+	params, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	id, err := s.exports.CreateJob(ctx, repository.ExportJob{
+		TeamID: req.TeamID, RequestedBy: userID, Kind: req.Kind, Format: req.Format, Params: params,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.exports.GetJob(ctx, id)
+}
+
+// GetJob only shows jobs to the user who requested them.
+func (s *ExportService) GetJob(ctx context.Context, userID, jobID int64) (*repository.ExportJob, error) {
+	job, err := s.exports.GetJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil || job.RequestedBy != userID {
+		return nil, ErrNotFound
+	}
+	return job, nil
+}
+
+// RunPendingJobs runs queued jobs one after another until none is left and returns how many ran.
+func (s *ExportService) RunPendingJobs(ctx context.Context) (int, error) {
+	ran := 0
+	for ctx.Err() == nil {
+		job, err := s.exports.ClaimJob(ctx, time.Now().UTC().Add(-s.cfg.StaleAfter))
+		if err != nil {
+			return ran, err
+		}
+		if job == nil {
+			break
+		}
+		s.runJob(ctx, job)
+		ran++
+	}
+	return ran, nil
+}
+
+func (s *ExportService) runJob(ctx context.Context, job *repository.ExportJob) {
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.StaleAfter)
+	defer cancel()
+	expiresAt := time.Now().UTC().Add(s.cfg.Retention)
+
+	fail := func(reason string, err error) {
+		if err != nil {
+			s.logger.Error("export job failed", slog.Int64("job_id", job.ID), slog.String("error", err.Error()))
+		}
+		if err := s.exports.FailJob(context.WithoutCancel(ctx), job.ID, reason, expiresAt); err != nil {
+			s.logger.Error("export job status update failed", slog.Int64("job_id", job.ID), slog.String("error", err.Error()))
+		}
+	}
+
+	var req ExportRequest
+	if err := json.Unmarshal(job.Params, &req); err != nil {
+		fail("invalid export parameters", err)
+		return
+	}
+	// Membership is checked again: the requester may have left the team while the job waited.
+	if ok, err := s.members.IsMember(ctx, req.TeamID, job.RequestedBy); err != nil {
+		fail("export failed", err)
+		return
+	} else if !ok {
+		fail("requester is no longer a team member", nil)
+		return
+	}
+
+	f, err := os.CreateTemp("", "export-*")
+	if err != nil {
+		fail("export failed", err)
+		return
+	}
+	defer func() {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+	}()
+	bw := bufio.NewWriter(f)
+	rows, err := s.write(ctx, req, bw)
+	if err == nil {
+		err = bw.Flush()
+	}
+	var size int64
+	if err == nil {
+		size, err = f.Seek(0, io.SeekCurrent)
+	}
+	if err == nil {
+		_, err = f.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		s.recordExport(ctx, job.RequestedBy, req, rows, false, job.ID)
+		fail("export failed", err)
+		return
+	}
+
+	key := "exports/" + strconv.FormatInt(req.TeamID, 10) + "/" + uuid.NewString() + "." + req.Format
+	if err := s.blobs.Put(ctx, key, f, size, ExportContentType(req.Format)); err != nil {
+		fail("export failed", err)
+		return
+	}
+	if err := s.exports.FinishJob(ctx, job.ID, key, rows, size, expiresAt); err != nil {
+		_ = s.blobs.Delete(context.WithoutCancel(ctx), key)
+		fail("export failed", err)
+		return
+	}
+	s.recordExport(ctx, job.RequestedBy, req, rows, true, job.ID)
+}
+
+// PurgeExpiredJobs deletes finished jobs past their retention along with their files.
+func (s *ExportService) PurgeExpiredJobs(ctx context.Context, now time.Time) (int, error) {
+	jobs, err := s.exports.ListExpiredJobs(ctx, now, exportPurgeBatch)
+	if err != nil {
+		return 0, err
+	}
+	purged := 0
+	for _, job := range jobs {
+		if job.StorageKey.Valid {
+			if err := s.blobs.Delete(ctx, job.StorageKey.String); err != nil && err != dstorage.ErrBlobNotFound {
+				return purged, err
+			}
+		}
+		if err := s.exports.DeleteJob(ctx, job.ID); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// SignDownload returns query values for the public download route of a finished job.
+func (s *ExportService) SignDownload(jobID int64) (expires int64, signature string) {
+	expires = time.Now().Add(s.cfg.URLTTL).Unix()
+	return expires, s.sign(jobID, expires)
+}
+
+func (s *ExportService) OpenSigned(ctx context.Context, jobID, expires int64, signature string) (*repository.ExportJob, io.ReadCloser, error) {
+	if expires < time.Now().Unix() {
+		return nil, nil, ErrInvalidToken
+	}
+	if !hmac.Equal([]byte(signature), []byte(s.sign(jobID, expires))) {
+		return nil, nil, ErrInvalidToken
+	}
+	job, err := s.exports.GetJob(ctx, jobID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if job == nil || job.Status != ExportJobDone || !job.StorageKey.Valid || (job.ExpiresAt.Valid && job.ExpiresAt.Time.Before(time.Now())) {
+		return nil, nil, ErrNotFound
+	}
+	body, err := s.blobs.Get(ctx, job.StorageKey.String)
+	if err != nil {
+		if err == dstorage.ErrBlobNotFound {
+			return nil, nil, ErrNotFound
+		}
+		return nil, nil, err
+	}
+	return job, body, nil
+}
+
+// Download links are signed with exports.signing_key, which no other signer shares.
+func (s *ExportService) sign(jobID, expires int64) string {
+	mac := hmac.New(sha256.New, s.signingKey)
+	mac.Write([]byte("export:" + strconv.FormatInt(jobID, 10) + ":" + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *ExportService) requireMember(ctx context.Context, teamID, userID int64) error {
+	team, err := s.teams.GetByID(ctx, teamID)
+	if err != nil {
+		return err
+	}
+	if team == nil {
+		return ErrNotFound
+	}
+	if ok, err := s.members.IsMember(ctx, teamID, userID); err != nil {
+		return err
+	} else if !ok {
+		return ErrForbidden
+	}
+	return nil
+}
+
+func (s *ExportService) recordExport(ctx context.Context, userID int64, req ExportRequest, rows int64, complete bool, jobID int64) {
+	payload := map[string]any{"kind": req.Kind, "format": req.Format, "rows": rows, "complete": complete}
+	if jobID > 0 {
+		payload["job_id"] = jobID
+	}
+	s.audit.Record(ctx, AuditEntry{
+		ActorID:    userID,
+		Action:     AuditTeamExported,
+		TargetType: auditTargetTeam,
+		TargetID:   req.TeamID,
+		TeamID:     req.TeamID,
+		Payload:    payload,
+	})
+}
+
+func (s *ExportService) count(ctx context.Context, req ExportRequest) (int64, error) {
+	switch req.Kind {
+	case ExportKindHistory:
+		return s.exports.CountHistory(ctx, req.TeamID, *req.From, *req.To)
+	case ExportKindComments:
+		return s.exports.CountComments(ctx, req.TeamID, *req.From, *req.To)
+	default:
+		return s.exports.CountTasks(ctx, exportTaskFilter(req))
+	}
+}
+
+func (s *ExportService) write(ctx context.Context, req ExportRequest, w io.Writer) (int64, error) {
+	var enc *exportEncoder
+	var err error
+	switch req.Kind {
+	case ExportKindHistory:
+		enc, err = newExportEncoder(req.Format, w, exportHistoryHeader)
+		if err == nil {
+			err = s.exports.StreamHistory(ctx, req.TeamID, *req.From, *req.To, func(h repository.TaskHistory) error {
+				return enc.write(newExportHistory(h))
+			})
+		}
+	case ExportKindComments:
+		enc, err = newExportEncoder(req.Format, w, exportCommentHeader)
+		if err == nil {
+			err = s.exports.StreamComments(ctx, req.TeamID, *req.From, *req.To, func(c repository.TaskComment) error {
+				return enc.write(newExportComment(c))
+			})
+		}
+	default:
+		enc, err = newExportEncoder(req.Format, w, exportTaskHeader)
+		if err == nil {
+			err = s.exports.StreamTasks(ctx, exportTaskFilter(req), func(t repository.Task) error {
+				return enc.write(newExportTask(t))
+			})
+		}
+	}
+	if enc == nil {
+		return 0, err
+	}
+	if flushErr := enc.flush(); err == nil {
+		err = flushErr
+	}
+	return enc.rows, err
+}
+
+func validateExportRequest(req ExportRequest) error {
+	if req.TeamID <= 0 || (req.Format != ExportFormatCSV && req.Format != ExportFormatNDJSON) {
+		return ErrBadRequest
+	}
+	switch req.Kind {
+	case ExportKindTasks:
+		if req.From != nil || req.To != nil {
+			return ErrBadRequest
+		}
+		if req.Status != nil && !isValidStatus(*req.Status) {
+			return ErrBadRequest
+		}
+		if (req.AssigneeID != nil && *req.AssigneeID <= 0) || (req.SprintID != nil && *req.SprintID <= 0) {
+			return ErrBadRequest
+		}
+		if !repository.IsTaskSort(req.Sort) {
+			return ErrBadRequest
+		}
+	case ExportKindHistory, ExportKindComments:
+		if req.Status != nil || req.AssigneeID != nil || req.SprintID != nil || req.Sort != "" {
+			return ErrBadRequest
+		}
+		if req.From == nil || req.To == nil || !req.From.Before(*req.To) {
+			return ErrBadRequest
+		}
+	default:
+		return ErrBadRequest
+	}
+	return nil
+}
+
+func exportTaskFilter(req ExportRequest) repository.TaskListFilter {
+	return repository.TaskListFilter{
+		TeamID: req.TeamID, Status: req.Status, AssigneeID: req.AssigneeID, SprintID: req.SprintID, Sort: req.Sort,
+	}
+}
+
+// exportRow is one exported record: NDJSON encodes the value itself, CSV uses csvRecord.
+type exportRow interface {
+	csvRecord() []string
+}
+
+type exportEncoder struct {
+	csv  *csv.Writer
+	json *json.Encoder
+	rows int64
+}
+
+func newExportEncoder(format string, w io.Writer, header []string) (*exportEncoder, error) {
+	if format == ExportFormatNDJSON {
+		return &exportEncoder{json: json.NewEncoder(w)}, nil
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return nil, err
+	}
+	return &exportEncoder{csv: cw}, nil
+}
+
+func (e *exportEncoder) write(row exportRow) error {
+	if e.json != nil {
+		if err := e.json.Encode(row); err != nil {
+			return err
+		}
+		e.rows++
+		return nil
+	}
+	if err := e.csv.Write(row.csvRecord()); err != nil {
+		return err
+	}
+	if e.rows++; e.rows%exportCSVFlushEvery == 0 {
+		e.csv.Flush()
+	}
+	return e.csv.Error()
+}
+
+func (e *exportEncoder) flush() error {
+	if e.csv == nil {
+		return nil
+	}
+	e.csv.Flush()
+	return e.csv.Error()
+}
+
+var (
+	exportTaskHeader = []string{"id", "team_id", "title", "description", "status", "priority", "assignee_id", "created_by",
+		"due_date", "sprint_id", "original_estimate_minutes", "remaining_estimate_minutes", "created_at", "updated_at"}
+	exportHistoryHeader = []string{"id", "task_id", "changed_by", "field_name", "old_value", "new_value", "created_at"}
+	exportCommentHeader = []string{"id", "task_id", "parent_id", "user_id", "body", "edited_at", "created_at"}
+)
+
+type exportTask struct {
+	ID                int64   `json:"id"`
+	TeamID            int64   `json:"team_id"`
+	Title             string  `json:"title"`
+	Description       *string `json:"description"`
+	Status            string  `json:"status"`
+	Priority          string  `json:"priority"`
+	AssigneeID        *int64  `json:"assignee_id"`
+	CreatedBy         *int64  `json:"created_by"`
+	DueDate           *string `json:"due_date"`
+	SprintID          *int64  `json:"sprint_id"`
+	OriginalEstimate  *int64  `json:"original_estimate_minutes"`
+	RemainingEstimate *int64  `json:"remaining_estimate_minutes"`
+	CreatedAt         string  `json:"created_at"`
+	UpdatedAt         string  `json:"updated_at"`
+}
+
+func newExportTask(t repository.Task) exportTask {
+	row := exportTask{
+		ID: t.ID, TeamID: t.TeamID, Title: t.Title, Status: t.Status, Priority: t.Priority,
+		AssigneeID: exportInt64(t.AssigneeID.Int64, t.AssigneeID.Valid), CreatedBy: exportInt64(t.CreatedBy.Int64, t.CreatedBy.Valid),
+		SprintID:          exportInt64(t.SprintID.Int64, t.SprintID.Valid),
+		OriginalEstimate:  exportInt64(t.OriginalEstimate.Int64, t.OriginalEstimate.Valid),
+		RemainingEstimate: exportInt64(t.RemainingEstimate.Int64, t.RemainingEstimate.Valid),
+		CreatedAt:         t.CreatedAt.UTC().Format(time.RFC3339Nano),
+		UpdatedAt:         t.UpdatedAt.UTC().Format(time.RFC3339Nano),
+	}
+	if t.Description.Valid {
+		row.Description = &t.Description.String
+	}
+	if t.DueDate.Valid {
+		due := t.DueDate.Time.Format("2006-01-02")
+		row.DueDate = &due
+	}
+	return row
+}
+
+func (t exportTask) csvRecord() []string {
+	return []string{
+		strconv.FormatInt(t.ID, 10), strconv.FormatInt(t.TeamID, 10), csvText(t.Title), csvText(exportString(t.Description)),
+		t.Status, t.Priority, exportID(t.AssigneeID), exportID(t.CreatedBy), exportString(t.DueDate), exportID(t.SprintID),
+		exportID(t.OriginalEstimate), exportID(t.RemainingEstimate), t.CreatedAt, t.UpdatedAt,
+	}
+}
+
+type exportHistory struct {
+	ID        int64           `json:"id"`
+	TaskID    int64           `json:"task_id"`
+	ChangedBy *int64          `json:"changed_by"`
+	FieldName string          `json:"field_name"`
+	OldValue  json.RawMessage `json:"old_value"`
+	NewValue  json.RawMessage `json:"new_value"`
+	CreatedAt string          `json:"created_at"`
+}
+
+func newExportHistory(h repository.TaskHistory) exportHistory {
+	return exportHistory{
+		ID: h.ID, TaskID: h.TaskID, ChangedBy: exportInt64(h.ChangedBy.Int64, h.ChangedBy.Valid), FieldName: h.FieldName,
+		OldValue: exportJSON(h.OldValue), NewValue: exportJSON(h.NewValue), CreatedAt: h.CreatedAt.UTC().Format(time.RFC3339Nano),
+	}
+}
+
+func (h exportHistory) csvRecord() []string {
+	return []string{
+		strconv.FormatInt(h.ID, 10), strconv.FormatInt(h.TaskID, 10), exportID(h.ChangedBy), h.FieldName,
+		csvText(exportJSONText(h.OldValue)), csvText(exportJSONText(h.NewValue)), h.CreatedAt,
+	}
+}
+
+type exportComment struct {
+	ID        int64   `json:"id"`
+	TaskID    int64   `json:"task_id"`
+	ParentID  *int64  `json:"parent_id"`
+	UserID    *int64  `json:"user_id"`
+	Body      string  `json:"body"`
+	EditedAt  *string `json:"edited_at"`
+	CreatedAt string  `json:"created_at"`
+}
+
+func newExportComment(c repository.TaskComment) exportComment {
+	row := exportComment{
+		ID: c.ID, TaskID: c.TaskID, ParentID: exportInt64(c.ParentID.Int64, c.ParentID.Valid),
+		UserID: exportInt64(c.UserID.Int64, c.UserID.Valid), Body: c.Body, CreatedAt: c.CreatedAt.UTC().Format(time.RFC3339Nano),
+	}
+	if c.EditedAt.Valid {
+		edited := c.EditedAt.Time.UTC().Format(time.RFC3339Nano)
+		row.EditedAt = &edited
+	}
+	return row
+}
+
+func (c exportComment) csvRecord() []string {
+	return []string{
+		strconv.FormatInt(c.ID, 10), strconv.FormatInt(c.TaskID, 10), exportID(c.ParentID), exportID(c.UserID),
+		csvText(c.Body), exportString(c.EditedAt), c.CreatedAt,
+	}
+}
+
+// csvText stops spreadsheets from evaluating user text as a formula.
+func csvText(v string) string {
+	if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+		return "'" + v
+	}
+	return v
+}
+
+func exportInt64(v int64, ok bool) *int64 {
+	if !ok {
+		return nil
+	}
+	return &v
+}
+
+func exportID(v *int64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatInt(*v, 10)
+}
+
+func exportString(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
+
+func exportJSON(v json.RawMessage) json.RawMessage {
+	if len(v) == 0 {
+		return json.RawMessage("null")
+	}
+	return v
+}
+
+func exportJSONText(v json.RawMessage) string {
+	if string(v) == "null" {
+		return ""
+	}
+	return string(v)
+}
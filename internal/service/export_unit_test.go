@@ -0,0 +1,188 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"MKK-Luna/internal/config"
+	"MKK-Luna/internal/repository"
+)
+
+type fakeExportStore struct {
+	tasks    []repository.Task
+	jobs     map[int64]*repository.ExportJob
+	finished map[int64]int64
+}
+
+func (f *fakeExportStore) CountTasks(context.Context, repository.TaskListFilter) (int64, error) {
+	return int64(len(f.tasks)), nil
+}
+func (f *fakeExportStore) StreamTasks(_ context.Context, _ repository.TaskListFilter, fn func(repository.Task) error) error {
+	for _, t := range f.tasks {
+		if err := fn(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+func (f *fakeExportStore) CountHistory(context.Context, int64, time.Time, time.Time) (int64, error) {
+	return 0, nil
+}
+func (f *fakeExportStore) StreamHistory(context.Context, int64, time.Time, time.Time, func(repository.TaskHistory) error) error {
+	return nil
+}
+func (f *fakeExportStore) CountComments(context.Context, int64, time.Time, time.Time) (int64, error) {
+	return 0, nil
+}
+func (f *fakeExportStore) StreamComments(context.Context, int64, time.Time, time.Time, func(repository.TaskComment) error) error {
+	return nil
+}
+func (f *fakeExportStore) CreateJob(_ context.Context, j repository.ExportJob) (int64, error) {
+	j.ID = int64(len(f.jobs) + 1)
+	j.Status = ExportJobPending
+	f.jobs[j.ID] = &j
+	return j.ID, nil
+}
+func (f *fakeExportStore) GetJob(_ context.Context, id int64) (*repository.ExportJob, error) {
+	if j, ok := f.jobs[id]; ok {
+		cp := *j
+		return &cp, nil
+	}
+	return nil, nil
+}
+func (f *fakeExportStore) ClaimJob(context.Context, time.Time) (*repository.ExportJob, error) {
+	for _, j := range f.jobs {
+		if j.Status == ExportJobPending {
+			j.Status = ExportJobRunning
+			cp := *j
+			return &cp, nil
+		}
+	}
+	return nil, nil
+}
+func (f *fakeExportStore) FinishJob(_ context.Context, id int64, key string, rows, size int64, expiresAt time.Time) error {
+	j := f.jobs[id]
+	j.Status = ExportJobDone
+	j.StorageKey = sql.NullString{String: key, Valid: true}
+	j.SizeBytes = sql.NullInt64{Int64: size, Valid: true}
+	j.ExpiresAt = sql.NullTime{Time: expiresAt, Valid: true}
+	f.finished[id] = rows
+	return nil
+}
+func (f *fakeExportStore) FailJob(_ context.Context, id int64, reason string, _ time.Time) error {
+	f.jobs[id].Status = ExportJobFailed
+	f.jobs[id].Error = sql.NullString{String: reason, Valid: true}
+	return nil
+}
+func (f *fakeExportStore) ListExpiredJobs(context.Context, time.Time, int) ([]repository.ExportJob, error) {
+	return nil, nil
+}
+func (f *fakeExportStore) DeleteJob(context.Context, int64) error { return nil }
+
+func newExportTestService(store *fakeExportStore, blobs *fakeBlobStorage, members *fakeMemberRepo, maxRows int64) *ExportService {
+	teams := &fakeTeamRepo{getByID: func(_ context.Context, id int64) (*repository.Team, error) {
+		return &repository.Team{ID: id}, nil
+	}}
+	cfg := config.ExportConfig{SyncMaxRows: maxRows, StaleAfter: time.Minute, Retention: time.Hour, URLTTL: time.Minute}
+	return NewExportService(store, teams, members, blobs, cfg, "secret", nil)
+}
+
+func TestExportService_ExportTasksCSV(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	store := &fakeExportStore{tasks: []repository.Task{
+		{ID: 1, TeamID: 10, Title: "=SUM(A1)", Status: "todo", Priority: "high", CreatedAt: now, UpdatedAt: now,
+			AssigneeID: sql.NullInt64{Int64: 3, Valid: true}},
+		{ID: 2, TeamID: 10, Title: "Plain, quoted", Status: "done", Priority: "low", CreatedAt: now, UpdatedAt: now},
+	}}
+	svc := newExportTestService(store, &fakeBlobStorage{}, &fakeMemberRepo{}, 10)
+
+	var buf bytes.Buffer
+	started := false
+	rows, err := svc.Export(context.Background(), 1, ExportRequest{TeamID: 10, Kind: ExportKindTasks, Format: ExportFormatCSV}, &buf, func() { started = true })
+	if err != nil || rows != 2 || !started {
+		t.Fatalf("export rows=%d started=%v err=%v", rows, started, err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "id,team_id,title") {
+		t.Fatalf("unexpected csv:\n%s", buf.String())
+	}
+	if !strings.HasPrefix(lines[1], "1,10,'=SUM(A1),,todo,high,3,") || !strings.HasPrefix(lines[2], `2,10,"Plain, quoted"`) {
+		t.Fatalf("unexpected rows:\n%s", buf.String())
+	}
+
+	svc = newExportTestService(store, &fakeBlobStorage{}, &fakeMemberRepo{}, 1)
+	started = false
+	if _, err := svc.Export(context.Background(), 1, ExportRequest{TeamID: 10, Kind: ExportKindTasks, Format: ExportFormatNDJSON}, io.Discard, func() { started = true }); err != ErrExportTooLarge || started {
+		t.Fatalf("expected ErrExportTooLarge before start, got %v started=%v", err, started)
+	}
+}
+
+func TestExportService_Validation(t *testing.T) {
+	svc := newExportTestService(&fakeExportStore{}, &fakeBlobStorage{}, &fakeMemberRepo{}, 0)
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	sort := "title"
+	for _, req := range []ExportRequest{
+		{TeamID: 10, Kind: "users", Format: ExportFormatCSV},
+		{TeamID: 10, Kind: ExportKindTasks, Format: "xlsx"},
+		{TeamID: 10, Kind: ExportKindTasks, Format: ExportFormatCSV, Sort: sort},
+		{TeamID: 10, Kind: ExportKindTasks, Format: ExportFormatCSV, From: &from, To: &to},
+		{TeamID: 10, Kind: ExportKindHistory, Format: ExportFormatCSV},
+		{TeamID: 10, Kind: ExportKindComments, Format: ExportFormatCSV, From: &to, To: &from},
+	} {
+		if _, err := svc.Export(context.Background(), 1, req, io.Discard, func() {}); err != ErrBadRequest {
+			t.Fatalf("expected ErrBadRequest for %+v, got %v", req, err)
+		}
+	}
+
+	members := &fakeMemberRepo{isMember: func(context.Context, int64, int64) (bool, error) { return false, nil }}
+	svc = newExportTestService(&fakeExportStore{}, &fakeBlobStorage{}, members, 0)
+	if _, err := svc.Export(context.Background(), 1, ExportRequest{TeamID: 10, Kind: ExportKindHistory, Format: ExportFormatCSV, From: &from, To: &to}, io.Discard, func() {}); err != ErrForbidden {
+		t.Fatalf("expected ErrForbidden, got %v", err)
+	}
+}
+
+func TestExportService_Jobs(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	store := &fakeExportStore{
+		tasks:    []repository.Task{{ID: 1, TeamID: 10, Title: "A", Status: "todo", Priority: "low", CreatedAt: now, UpdatedAt: now}},
+		jobs:     map[int64]*repository.ExportJob{},
+		finished: map[int64]int64{},
+	}
+	blobs := &fakeBlobStorage{}
+	svc := newExportTestService(store, blobs, &fakeMemberRepo{}, 0)
+
+	job, err := svc.CreateJob(context.Background(), 1, ExportRequest{TeamID: 10, Kind: ExportKindTasks, Format: ExportFormatNDJSON})
+	if err != nil || job.Status != ExportJobPending {
+		t.Fatalf("create job=%+v err=%v", job, err)
+	}
+	if _, err := svc.GetJob(context.Background(), 2, job.ID); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound for another user, got %v", err)
+	}
+
+	if ran, err := svc.RunPendingJobs(context.Background()); err != nil || ran != 1 {
+		t.Fatalf("run ran=%d err=%v", ran, err)
+	}
+	if store.jobs[job.ID].Status != ExportJobDone || store.finished[job.ID] != 1 {
+		t.Fatalf("job not finished: %+v", store.jobs[job.ID])
+	}
+
+	expires, sig := svc.SignDownload(job.ID)
+	done, body, err := svc.OpenSigned(context.Background(), job.ID, expires, sig)
+	if err != nil {
+		t.Fatalf("open signed: %v", err)
+	}
+	data, _ := io.ReadAll(body)
+	_ = body.Close()
+	if done.Format != ExportFormatNDJSON || !strings.HasPrefix(string(data), `{"id":1,"team_id":10,"title":"A"`) {
+		t.Fatalf("unexpected file %q", data)
+	}
+	if _, _, err := svc.OpenSigned(context.Background(), job.ID, expires, "bad"); err != ErrInvalidToken {
+		t.Fatalf("expected ErrInvalidToken, got %v", err)
+	}
+}
@@ -0,0 +1,464 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"MKK-Luna/internal/repository"
+)
+
+const (
+	IntegrityTriggerSchedule = "schedule"
+	IntegrityTriggerManual   = "manual"
+
+	IntegrityRepairNone    = "none"
+	IntegrityRepairDryRun  = "dry_run"
+	IntegrityRepairApplied = "applied"
+
+	// maxIntegrityFindings caps one run of one check; a later run picks up the rest.
+	maxIntegrityFindings = 1000
+	maxIntegrityRunList  = 200
+	maxIntegrityErrorLen = 255
+
+	integrityCheckAssignees = "task_assignee_not_member"
+)
+
+// IntegrityCheck is one consistency rule. Find reports the offending rows; Repair fixes
+// them inside tx and returns how many it changed. Repairs must re-check their condition,
+// and must not touch anything outside tx: dry runs execute them and roll back. Checks that
+// are not Repairable only report, even when a repair is requested.
+type IntegrityCheck interface {
+	Name() string
+	Description() string
+	Find(ctx context.Context, limit int) ([]repository.IntegrityFinding, error)
+	Repairable() bool
+	Repair(ctx context.Context, tx *sqlx.Tx, findings []repository.IntegrityFinding) (int, error)
+	// TouchesTasks tells callers to drop cached task lists of the repaired teams.
+	TouchesTasks() bool
+}
+
+type integrityCheck struct {
+	name         string
+	description  string
+	touchesTasks bool
+	find         func(ctx context.Context, limit int) ([]repository.IntegrityFinding, error)
+	repair       func(ctx context.Context, tx *sqlx.Tx, findings []repository.IntegrityFinding) (int, error)
+}
+
+func (c integrityCheck) Name() string        { return c.name }
+func (c integrityCheck) Description() string { return c.description }
+func (c integrityCheck) TouchesTasks() bool  { return c.touchesTasks }
+func (c integrityCheck) Repairable() bool    { return c.repair != nil }
+func (c integrityCheck) Find(ctx context.Context, limit int) ([]repository.IntegrityFinding, error) {
+	return c.find(ctx, limit)
+}
+func (c integrityCheck) Repair(ctx context.Context, tx *sqlx.Tx, findings []repository.IntegrityFinding) (int, error) {
+	return c.repair(ctx, tx, findings)
+}
+
+type integrityCheckRepo interface {
+	FindAssigneeNotMember(ctx context.Context, limit int) ([]repository.IntegrityFinding, error)
+	FindTeamsWithoutOwner(ctx context.Context, limit int) ([]repository.IntegrityFinding, error)
+	PromoteOwnerTx(ctx context.Context, tx *sqlx.Tx, teamID int64) (bool, error)
+	FindOrphanedHistory(ctx context.Context, limit int) ([]repository.IntegrityFinding, error)
+	DeleteOrphanedHistoryTx(ctx context.Context, tx *sqlx.Tx, ids []int64) (int64, error)
+	FindOrphanedSessions(ctx context.Context, limit int) ([]repository.IntegrityFinding, error)
+	DeleteOrphanedSessionsTx(ctx context.Context, tx *sqlx.Tx, ids []int64) (int64, error)
+	FindCreatorNotMember(ctx context.Context, limit int) ([]repository.IntegrityFinding, error)
+	FindUpdatedBeforeCreated(ctx context.Context, limit int) ([]repository.IntegrityFinding, error)
+	FixUpdatedBeforeCreatedTx(ctx context.Context, tx *sqlx.Tx, ids []int64) (int64, error)
+}
+
+type assigneeIntegrityRepo interface {
+	UnassignNonMemberTx(ctx context.Context, tx *sqlx.Tx, issue repository.TaskIntegrityIssue) (bool, error)
+}
+
+// DefaultIntegrityChecks returns the built-in checks in the order they run.
+func DefaultIntegrityChecks(repo integrityCheckRepo, assignees assigneeIntegrityRepo, history taskHistoryRepo) []IntegrityCheck {
+	return []IntegrityCheck{
+		integrityCheck{
+			name:         integrityCheckAssignees,
+			description:  "Tasks assigned to a user who is not a member of the task's team. Repair unassigns them.",
+			touchesTasks: true,
+			find:         repo.FindAssigneeNotMember,
+			repair: func(ctx context.Context, tx *sqlx.Tx, findings []repository.IntegrityFinding) (int, error) {
+				repaired := 0
+				for _, f := range findings {
+					ok, err := assignees.UnassignNonMemberTx(ctx, tx, repository.TaskIntegrityIssue{
+						TaskID: f.EntityID, TeamID: f.TeamID.Int64, AssigneeID: f.RefID.Int64,
+					})
+					if err != nil {
+						return repaired, err
+					}
+					if !ok {
+						continue
+					}
+					entry := repository.TaskHistoryCreate{
+						TaskID: f.EntityID, FieldName: "assignee_id", OldValue: mustJSON(f.RefID.Int64), NewValue: mustJSON(nil),
+					}
+					if err := history.CreateBatchTx(ctx, tx, []repository.TaskHistoryCreate{entry}); err != nil {
+						return repaired, err
+					}
+					repaired++
+				}
+				return repaired, nil
+			},
+		},
+		integrityCheck{
+			name:        "team_without_owner",
+			description: "Teams with no owner. Repair promotes the longest-standing admin, else the longest-standing member.",
+			find:        repo.FindTeamsWithoutOwner,
+			repair: func(ctx context.Context, tx *sqlx.Tx, findings []repository.IntegrityFinding) (int, error) {
+				repaired := 0
+				for _, f := range findings {
+					ok, err := repo.PromoteOwnerTx(ctx, tx, f.EntityID)
+					if err != nil {
+						return repaired, err
+					}
+					if ok {
+						repaired++
+					}
+				}
+				return repaired, nil
+			},
+		},
+		integrityCheck{
+			name:        "task_history_orphaned",
+			description: "History entries whose task no longer exists. Repair deletes them.",
+			find:        repo.FindOrphanedHistory,
+			repair:      repairByIDs(repo.DeleteOrphanedHistoryTx),
+		},
+		integrityCheck{
+			name:        "session_user_missing",
+			description: "Sessions of users that no longer exist. Repair deletes them.",
+			find:        repo.FindOrphanedSessions,
+			repair:      repairByIDs(repo.DeleteOrphanedSessionsTx),
+		},
+		integrityCheck{
+			// Leaving a team does not unmake the tasks someone created there, so this is report-only.
+			name:        "task_creator_not_member",
+			description: "Tasks whose creator is not a member of the task's team. Report only.",
+			find:        repo.FindCreatorNotMember,
+		},
+		integrityCheck{
+			name:         "task_updated_before_created",
+			description:  "Tasks with updated_at earlier than created_at. Repair sets updated_at to created_at.",
+			touchesTasks: true,
+			find:         repo.FindUpdatedBeforeCreated,
+			repair:       repairByIDs(repo.FixUpdatedBeforeCreatedTx),
+		},
+	}
+}
+
+func repairByIDs(fn func(ctx context.Context, tx *sqlx.Tx, ids []int64) (int64, error)) func(context.Context, *sqlx.Tx, []repository.IntegrityFinding) (int, error) {
+	return func(ctx context.Context, tx *sqlx.Tx, findings []repository.IntegrityFinding) (int, error) {
+		ids := make([]int64, 0, len(findings))
+		for _, f := range findings {
+			ids = append(ids, f.EntityID)
+		}
+		n, err := fn(ctx, tx, ids)
+		return int(n), err
+	}
+}
+
+type integrityRunStore interface {
+	CreateRun(ctx context.Context, run repository.IntegrityRun) (int64, error)
+	ListRuns(ctx context.Context, check string, limit int) ([]repository.IntegrityRun, error)
+	LatestRuns(ctx context.Context) ([]repository.IntegrityRun, error)
+	DeleteRunsBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+type IntegrityMetrics interface {
+	SetIntegrityRun(check string, findings int, ok bool, finishedAt time.Time)
+}
+
+type IntegrityService struct {
+	db         *sqlx.DB
+	runs       integrityRunStore
+	checks     []IntegrityCheck
+	byName     map[string]IntegrityCheck
+	sampleSize int
+	adminUsers map[int64]struct{}
+	logger     *slog.Logger
+	metrics    IntegrityMetrics
+	audit      *AuditService
+}
+
+type IntegrityServiceOption func(*IntegrityService)
+
+func WithIntegrityMetrics(m IntegrityMetrics) IntegrityServiceOption {
+	return func(s *IntegrityService) { s.metrics = m }
+}
+
+func WithIntegrityAudit(audit *AuditService) IntegrityServiceOption {
+	return func(s *IntegrityService) { s.audit = audit }
+}
+
+func NewIntegrityService(db *sqlx.DB, runs integrityRunStore, checks []IntegrityCheck, sampleSize int, adminUserIDs []int64, logger *slog.Logger, opts ...IntegrityServiceOption) *IntegrityService {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	admins := make(map[int64]struct{}, len(adminUserIDs))
+	for _, id := range adminUserIDs {
+		if id > 0 {
+			admins[id] = struct{}{}
+		}
+	}
+	s := &IntegrityService{
+		db:         db,
+		runs:       runs,
+		checks:     checks,
+		byName:     make(map[string]IntegrityCheck, len(checks)),
+		sampleSize: sampleSize,
+		adminUsers: admins,
+		logger:     logger,
+	}
+	for _, c := range checks {
+		s.byName[c.Name()] = c
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// IntegrityRunRequest selects checks by name; none means all. DryRun implies Repair.
+type IntegrityRunRequest struct {
+	Checks      []string
+	Repair      bool
+	DryRun      bool
+	Trigger     string
+	TriggeredBy int64
+}
+
+type IntegrityRunResult struct {
+	Run repository.IntegrityRun
+	// TeamIDs are the teams whose tasks an applied repair may have changed.
+	TeamIDs []int64
+}
+
+type IntegrityCheckInfo struct {
+	Name        string
+	Description string
+	LastRun     *repository.IntegrityRun
+}
+
+// Run executes the checks one after another. A failing check is recorded in its run and
+// does not stop the others.
+func (s *IntegrityService) Run(ctx context.Context, req IntegrityRunRequest) ([]IntegrityRunResult, error) {
+	checks := s.checks
+	if len(req.Checks) > 0 {
+		checks = make([]IntegrityCheck, 0, len(req.Checks))
+		for _, name := range req.Checks {
+			c, ok := s.byName[name]
+			if !ok {
+				return nil, ErrBadRequest
+			}
+			checks = append(checks, c)
+		}
+	}
+	if req.Trigger == "" {
+		req.Trigger = IntegrityTriggerManual
+	}
+	out := make([]IntegrityRunResult, 0, len(checks))
+	for _, c := range checks {
+		if err := ctx.Err(); err != nil {
+			return out, err
+		}
+		out = append(out, s.runCheck(ctx, c, req))
+	}
+	return out, nil
+}
+
+func (s *IntegrityService) runCheck(ctx context.Context, c IntegrityCheck, req IntegrityRunRequest) IntegrityRunResult {
+	run := repository.IntegrityRun{
+		CheckName:     c.Name(),
+		TriggerSource: req.Trigger,
+		TriggeredBy:   sql.NullInt64{Int64: req.TriggeredBy, Valid: req.TriggeredBy > 0},
+		Repair:        IntegrityRepairNone,
+		StartedAt:     time.Now().UTC(),
+	}
+	res := IntegrityRunResult{}
+
+	findings, err := c.Find(ctx, maxIntegrityFindings)
+	if err == nil && (req.Repair || req.DryRun) && c.Repairable() && len(findings) > 0 {
+		run.Repair = IntegrityRepairApplied
+		if req.DryRun {
+			run.Repair = IntegrityRepairDryRun
+		}
+		run.Repaired, err = s.repair(ctx, c, findings, req.DryRun)
+		if err == nil && !req.DryRun && run.Repaired > 0 && c.TouchesTasks() {
+			res.TeamIDs = findingTeams(findings)
+		}
+	}
+	run.Found = len(findings)
+	if len(findings) > 0 {
+		run.Sample, _ = json.Marshal(integritySample(findings[:min(len(findings), s.sampleSize)]))
+	}
+	if err != nil {
+		msg := err.Error()
+		if len(msg) > maxIntegrityErrorLen {
+			msg = msg[:maxIntegrityErrorLen]
+		}
+		run.Error = sql.NullString{String: msg, Valid: true}
+		s.logger.Error("integrity check failed", slog.String("check", c.Name()), slog.String("error", msg))
+	}
+	run.FinishedAt = time.Now().UTC()
+
+	// The run is stored even when the request was cancelled so the failure stays visible.
+	storeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+	if id, serr := s.runs.CreateRun(storeCtx, run); serr != nil {
+		s.logger.Error("integrity run not stored", slog.String("check", c.Name()), slog.String("error", serr.Error()))
+	} else {
+		run.ID = id
+	}
+
+	outstanding := run.Found
+	if run.Repair == IntegrityRepairApplied {
+		outstanding -= run.Repaired
+	}
+	if s.metrics != nil {
+		s.metrics.SetIntegrityRun(c.Name(), outstanding, err == nil, run.FinishedAt)
+	}
+	res.Run = run
+	return res
+}
+
+func (s *IntegrityService) repair(ctx context.Context, c IntegrityCheck, findings []repository.IntegrityFinding, dryRun bool) (int, error) {
+	if s.db == nil {
+		return 0, ErrUnavailable
+	}
+	tx, err := s.db.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	n, err := c.Repair(ctx, tx, findings)
+	if err != nil {
+		return 0, err
+	}
+	if dryRun {
+		return n, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// RunScheduled runs every check for the background job.
+func (s *IntegrityService) RunScheduled(ctx context.Context, repair bool) ([]IntegrityRunResult, error) {
+	return s.Run(ctx, IntegrityRunRequest{Repair: repair, Trigger: IntegrityTriggerSchedule})
+}
+
+// RunChecks is the admin-triggered run.
+func (s *IntegrityService) RunChecks(ctx context.Context, userID int64, checks []string, repair, dryRun bool) ([]IntegrityRunResult, error) {
+	if !s.isAdmin(userID) {
+		return nil, ErrForbidden
+	}
+	results, err := s.Run(ctx, IntegrityRunRequest{Checks: checks, Repair: repair, DryRun: dryRun, TriggeredBy: userID})
+	if err != nil {
+		return nil, err
+	}
+	if repair && !dryRun {
+		repaired := map[string]int{}
+		for _, r := range results {
+			if r.Run.Repaired > 0 {
+				repaired[r.Run.CheckName] = r.Run.Repaired
+			}
+		}
+		s.audit.Record(ctx, AuditEntry{
+			ActorID: userID, Action: AuditIntegrityRepaired, TargetType: auditTargetSystem,
+			Payload: map[string]any{"repaired": repaired},
+		})
+	}
+	return results, nil
+}
+
+func (s *IntegrityService) ListChecks(ctx context.Context, userID int64) ([]IntegrityCheckInfo, error) {
+	if !s.isAdmin(userID) {
+		return nil, ErrForbidden
+	}
+	latest, err := s.runs.LatestRuns(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byCheck := make(map[string]*repository.IntegrityRun, len(latest))
+	for i := range latest {
+		byCheck[latest[i].CheckName] = &latest[i]
+	}
+	out := make([]IntegrityCheckInfo, 0, len(s.checks))
+	for _, c := range s.checks {
+		out = append(out, IntegrityCheckInfo{Name: c.Name(), Description: c.Description(), LastRun: byCheck[c.Name()]})
+	}
+	return out, nil
+}
+
+func (s *IntegrityService) ListRuns(ctx context.Context, userID int64, check string, limit int) ([]repository.IntegrityRun, error) {
+	if !s.isAdmin(userID) {
+		return nil, ErrForbidden
+	}
+	if check != "" {
+		if _, ok := s.byName[check]; !ok {
+			return nil, ErrBadRequest
+		}
+	}
+	if limit <= 0 || limit > maxIntegrityRunList {
+		limit = 50
+	}
+	return s.runs.ListRuns(ctx, check, limit)
+}
+
+func (s *IntegrityService) PurgeRuns(ctx context.Context, before time.Time) (int64, error) {
+	return s.runs.DeleteRunsBefore(ctx, before)
+}
+
+// Checks lists the registered checks in run order.
+func (s *IntegrityService) Checks() []IntegrityCheck {
+	return s.checks
+}
+
+func (s *IntegrityService) isAdmin(userID int64) bool {
+	_, ok := s.adminUsers[userID]
+	return ok
+}
+
+type integritySampleItem struct {
+	EntityID int64  `json:"entity_id"`
+	TeamID   *int64 `json:"team_id,omitempty"`
+	RefID    *int64 `json:"ref_id,omitempty"`
+}
+
+func integritySample(findings []repository.IntegrityFinding) []integritySampleItem {
+	out := make([]integritySampleItem, 0, len(findings))
+	for _, f := range findings {
+		item := integritySampleItem{EntityID: f.EntityID}
+		if f.TeamID.Valid {
+			item.TeamID = &f.TeamID.Int64
+		}
+		if f.RefID.Valid {
+			item.RefID = &f.RefID.Int64
+		}
+		out = append(out, item)
+	}
+	return out
+}
+
+func findingTeams(findings []repository.IntegrityFinding) []int64 {
+	seen := map[int64]bool{}
+	var out []int64
+	for _, f := range findings {
+		if f.TeamID.Valid && !seen[f.TeamID.Int64] {
+			seen[f.TeamID.Int64] = true
+			out = append(out, f.TeamID.Int64)
+		}
+	}
+	return out
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"MKK-Luna/internal/repository"
+)
+
+type fakeIntegrityRuns struct {
+	runs   []repository.IntegrityRun
+	purged time.Time
+}
+
+func (f *fakeIntegrityRuns) CreateRun(_ context.Context, run repository.IntegrityRun) (int64, error) {
+	f.runs = append(f.runs, run)
+	return int64(len(f.runs)), nil
+}
+func (f *fakeIntegrityRuns) ListRuns(_ context.Context, check string, limit int) ([]repository.IntegrityRun, error) {
+	var out []repository.IntegrityRun
+	for _, r := range f.runs {
+		if check == "" || r.CheckName == check {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+func (f *fakeIntegrityRuns) LatestRuns(context.Context) ([]repository.IntegrityRun, error) {
+	if len(f.runs) == 0 {
+		return nil, nil
+	}
+	return f.runs[len(f.runs)-1:], nil
+}
+func (f *fakeIntegrityRuns) DeleteRunsBefore(_ context.Context, before time.Time) (int64, error) {
+	f.purged = before
+	return 0, nil
+}
+
+type fakeAssigneeRepair struct {
+	stale map[int64]bool
+}
+
+func (f *fakeAssigneeRepair) UnassignNonMemberTx(_ context.Context, _ *sqlx.Tx, issue repository.TaskIntegrityIssue) (bool, error) {
+	return !f.stale[issue.TaskID], nil
+}
+
+type fakeIntegrityMetrics struct {
+	findings map[string]int
+	ok       map[string]bool
+}
+
+func (f *fakeIntegrityMetrics) SetIntegrityRun(check string, findings int, ok bool, _ time.Time) {
+	f.findings[check] = findings
+	f.ok[check] = ok
+}
+
+func findingsOf(ids ...int64) []repository.IntegrityFinding {
+	out := make([]repository.IntegrityFinding, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, repository.IntegrityFinding{EntityID: id, TeamID: sql.NullInt64{Int64: 10, Valid: true}})
+	}
+	return out
+}
+
+func newIntegrityTestService(db *sqlx.DB, runs *fakeIntegrityRuns, metrics *fakeIntegrityMetrics) *IntegrityService {
+	checks := []IntegrityCheck{
+		integrityCheck{
+			name:         "tasks_bad",
+			touchesTasks: true,
+			find: func(context.Context, int) ([]repository.IntegrityFinding, error) {
+				return findingsOf(1, 2, 3), nil
+			},
+			repair: func(context.Context, *sqlx.Tx, []repository.IntegrityFinding) (int, error) { return 2, nil },
+		},
+		integrityCheck{
+			name: "broken",
+			find: func(context.Context, int) ([]repository.IntegrityFinding, error) {
+				return nil, errors.New("boom")
+			},
+		},
+	}
+	return NewIntegrityService(db, runs, checks, 2, []int64{1}, nil, WithIntegrityMetrics(metrics))
+}
+
+func TestIntegrityService_Run(t *testing.T) {
+	db, mock := newMockDB(t)
+	runs := &fakeIntegrityRuns{}
+	metrics := &fakeIntegrityMetrics{findings: map[string]int{}, ok: map[string]bool{}}
+	svc := newIntegrityTestService(db, runs, metrics)
+	ctx := context.Background()
+
+	results, err := svc.RunScheduled(ctx, false)
+	if err != nil || len(results) != 2 {
+		t.Fatalf("run results=%+v err=%v", results, err)
+	}
+	first := results[0].Run
+	if first.Found != 3 || first.Repaired != 0 || first.Repair != IntegrityRepairNone || first.TriggerSource != IntegrityTriggerSchedule {
+		t.Fatalf("unexpected run %+v", first)
+	}
+	if string(first.Sample) != `[{"entity_id":1,"team_id":10},{"entity_id":2,"team_id":10}]` {
+		t.Fatalf("unexpected sample %s", first.Sample)
+	}
+	if !results[1].Run.Error.Valid || metrics.ok["broken"] || !metrics.ok["tasks_bad"] || metrics.findings["tasks_bad"] != 3 {
+		t.Fatalf("unexpected failure handling run=%+v metrics=%+v", results[1].Run, metrics)
+	}
+	if len(runs.runs) != 2 {
+		t.Fatalf("expected 2 stored runs, got %d", len(runs.runs))
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	results, err = svc.RunChecks(ctx, 1, []string{"tasks_bad"}, false, true)
+	if err != nil || results[0].Run.Repair != IntegrityRepairDryRun || results[0].Run.Repaired != 2 || len(results[0].TeamIDs) != 0 {
+		t.Fatalf("dry run results=%+v err=%v", results, err)
+	}
+	if metrics.findings["tasks_bad"] != 3 {
+		t.Fatalf("dry run must not lower the gauge, got %d", metrics.findings["tasks_bad"])
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	results, err = svc.RunChecks(ctx, 1, []string{"tasks_bad"}, true, false)
+	if err != nil || results[0].Run.Repair != IntegrityRepairApplied || len(results[0].TeamIDs) != 1 || results[0].Run.TriggeredBy.Int64 != 1 {
+		t.Fatalf("repair results=%+v err=%v", results, err)
+	}
+	if metrics.findings["tasks_bad"] != 1 {
+		t.Fatalf("expected 1 outstanding finding, got %d", metrics.findings["tasks_bad"])
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+}
+
+func TestIntegrityService_AccessAndValidation(t *testing.T) {
+	svc := newIntegrityTestService(nil, &fakeIntegrityRuns{}, &fakeIntegrityMetrics{findings: map[string]int{}, ok: map[string]bool{}})
+	ctx := context.Background()
+
+	if _, err := svc.RunChecks(ctx, 2, nil, false, false); err != ErrForbidden {
+		t.Fatalf("expected ErrForbidden, got %v", err)
+	}
+	if _, err := svc.ListChecks(ctx, 2); err != ErrForbidden {
+		t.Fatalf("expected ErrForbidden, got %v", err)
+	}
+	if _, err := svc.RunChecks(ctx, 1, []string{"nope"}, false, false); err != ErrBadRequest {
+		t.Fatalf("expected ErrBadRequest, got %v", err)
+	}
+	if _, err := svc.ListRuns(ctx, 1, "nope", 10); err != ErrBadRequest {
+		t.Fatalf("expected ErrBadRequest, got %v", err)
+	}
+	results, err := svc.RunChecks(ctx, 1, []string{"tasks_bad"}, true, false)
+	if err != nil || !results[0].Run.Error.Valid || len(results[0].TeamIDs) != 0 {
+		t.Fatalf("repair without db results=%+v err=%v", results, err)
+	}
+
+	checks, err := svc.ListChecks(ctx, 1)
+	if err != nil || len(checks) != 2 || checks[0].Name != "tasks_bad" || checks[0].LastRun == nil {
+		t.Fatalf("checks=%+v err=%v", checks, err)
+	}
+}
+
+func TestIntegrityService_ReportOnlyCheckIsNotRepaired(t *testing.T) {
+	db, mock := newMockDB(t)
+	check := integrityCheck{
+		name: "report_only",
+		find: func(context.Context, int) ([]repository.IntegrityFinding, error) { return findingsOf(1), nil },
+	}
+	metrics := &fakeIntegrityMetrics{findings: map[string]int{}, ok: map[string]bool{}}
+	svc := NewIntegrityService(db, &fakeIntegrityRuns{}, []IntegrityCheck{check}, 2, []int64{1}, nil, WithIntegrityMetrics(metrics))
+
+	results, err := svc.RunScheduled(context.Background(), true)
+	if err != nil || results[0].Run.Repair != IntegrityRepairNone || results[0].Run.Found != 1 || metrics.findings["report_only"] != 1 {
+		t.Fatalf("results=%+v err=%v", results, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+}
+
+func TestDefaultIntegrityChecks_AssigneeRepairWritesHistory(t *testing.T) {
+	assignees := &fakeAssigneeRepair{stale: map[int64]bool{2: true}}
+	var entries []repository.TaskHistoryCreate
+	history := &fakeHistoryRepo{createBatchTx: func(_ context.Context, _ *sqlx.Tx, e []repository.TaskHistoryCreate) error {
+		entries = append(entries, e...)
+		return nil
+	}}
+	check := DefaultIntegrityChecks(repository.NewIntegrityRepository(nil), assignees, history)[0]
+
+	findings := findingsOf(1, 2)
+	findings[0].RefID = sql.NullInt64{Int64: 5, Valid: true}
+	findings[1].RefID = sql.NullInt64{Int64: 6, Valid: true}
+	n, err := check.Repair(context.Background(), nil, findings)
+	if err != nil || n != 1 {
+		t.Fatalf("repair n=%d err=%v", n, err)
+	}
+	if len(entries) != 1 || entries[0].TaskID != 1 || entries[0].FieldName != "assignee_id" || entries[0].ChangedBy != nil {
+		t.Fatalf("unexpected history %+v", entries)
+	}
+}
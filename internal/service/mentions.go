@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	devents "MKK-Luna/internal/domain/events"
+	"MKK-Luna/internal/repository"
+)
+
+const TaskEventMentioned = "task.mentioned"
+
+const maxMentionsPerComment = 20
+
+var mentionRegex = regexp.MustCompile(`(?:^|[^a-zA-Z0-9_@.])@([a-zA-Z0-9_]{3,100})`)
+
+type commentMentionRepo interface {
+	AddTx(ctx context.Context, tx *sqlx.Tx, commentID int64, userIDs []int64) error
+	RemoveTx(ctx context.Context, tx *sqlx.Tx, commentID int64, userIDs []int64) error
+	ListUserIDsByCommentTx(ctx context.Context, tx *sqlx.Tx, commentID int64) ([]int64, error)
+	ListByComments(ctx context.Context, commentIDs []int64) ([]repository.CommentMention, error)
+}
+
+type mentionDirectory interface {
+	ListByUsernames(ctx context.Context, teamID int64, usernames []string) ([]repository.UserSummary, error)
+}
+
+func WithMentions(mentions commentMentionRepo, directory mentionDirectory) TaskServiceOption {
+	return func(s *TaskService) {
+		s.mentions = mentions
+		s.mentionDirectory = directory
+	}
+}
+
+func parseMentions(body string) []string {
+	matches := mentionRegex.FindAllStringSubmatch(body, -1)
+	seen := make(map[string]bool, len(matches))
+	out := make([]string, 0, len(matches))
+	for _, m := range matches {
+		key := strings.ToLower(m[1])
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, m[1])
+		if len(out) == maxMentionsPerComment {
+			break
+		}
+	}
+	return out
+}
+
+// Mentions of users outside the team stay plain text.
+func (s *TaskService) resolveMentions(ctx context.Context, teamID int64, body string) ([]int64, error) {
+	if s.mentions == nil || s.mentionDirectory == nil {
+		return nil, nil
+	}
+	usernames := parseMentions(body)
+	if len(usernames) == 0 {
+		return nil, nil
+	}
+	users, err := s.mentionDirectory.ListByUsernames(ctx, teamID, usernames)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int64, 0, len(users))
+	for _, u := range users {
+		ids = append(ids, u.ID)
+	}
+	return ids, nil
+}
+
+func (s *TaskService) CommentMentions(ctx context.Context, commentIDs []int64) (map[int64][]repository.CommentMention, error) {
+	out := make(map[int64][]repository.CommentMention)
+	if s.mentions == nil || len(commentIDs) == 0 {
+		return out, nil
+	}
+	items, err := s.mentions.ListByComments(ctx, commentIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range items {
+		out[m.CommentID] = append(out[m.CommentID], m)
+	}
+	return out, nil
+}
+
+func (s *TaskService) notifyMentioned(ctx context.Context, task repository.Task, actorID, commentID int64, userIDs []int64) {
+	if s.events == nil {
+		return
+	}
+	recipients := make([]int64, 0, len(userIDs))
+	for _, id := range userIDs {
+		if id != actorID {
+			recipients = append(recipients, id)
+		}
+	}
+	if len(recipients) == 0 {
+		return
+	}
+	_ = s.events.PublishTaskEvent(ctx, devents.TaskEvent{
+		Type:       TaskEventMentioned,
+		TaskID:     task.ID,
+		TeamID:     task.TeamID,
+		ActorID:    actorID,
+		CommentID:  commentID,
+		Recipients: recipients,
+		OccurredAt: time.Now().UTC(),
+	})
+}
+
+func diffMentions(before, after []int64) (added, removed []int64) {
+	had := make(map[int64]bool, len(before))
+	for _, id := range before {
+		had[id] = true
+	}
+	has := make(map[int64]bool, len(after))
+	for _, id := range after {
+		has[id] = true
+		if !had[id] {
+			added = append(added, id)
+		}
+	}
+	for _, id := range before {
+		if !has[id] {
+			removed = append(removed, id)
+		}
+	}
+	return added, removed
+}
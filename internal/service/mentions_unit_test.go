@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+
+	"MKK-Luna/internal/repository"
+)
+
+type fakeMentionRepo struct {
+	stored  map[int64][]int64
+	added   []int64
+	removed []int64
+	addErr  error
+}
+
+func (f *fakeMentionRepo) AddTx(_ context.Context, _ *sqlx.Tx, commentID int64, userIDs []int64) error {
+	if f.addErr != nil {
+		return f.addErr
+	}
+	f.added = append(f.added, userIDs...)
+	if f.stored == nil {
+		f.stored = make(map[int64][]int64)
+	}
+	f.stored[commentID] = append(f.stored[commentID], userIDs...)
+	return nil
+}
+func (f *fakeMentionRepo) RemoveTx(_ context.Context, _ *sqlx.Tx, _ int64, userIDs []int64) error {
+	f.removed = append(f.removed, userIDs...)
+	return nil
+}
+func (f *fakeMentionRepo) ListUserIDsByCommentTx(_ context.Context, _ *sqlx.Tx, commentID int64) ([]int64, error) {
+	return f.stored[commentID], nil
+}
+func (f *fakeMentionRepo) ListByComments(_ context.Context, commentIDs []int64) ([]repository.CommentMention, error) {
+	var out []repository.CommentMention
+	for _, cid := range commentIDs {
+		for _, uid := range f.stored[cid] {
+			out = append(out, repository.CommentMention{CommentID: cid, UserID: uid})
+		}
+	}
+	return out, nil
+}
+
+type fakeMentionDirectory struct {
+	members map[string]int64
+}
+
+func (f *fakeMentionDirectory) ListByUsernames(_ context.Context, _ int64, usernames []string) ([]repository.UserSummary, error) {
+	var out []repository.UserSummary
+	for _, name := range usernames {
+		if id, ok := f.members[name]; ok {
+			out = append(out, repository.UserSummary{ID: id, Username: name})
+		}
+	}
+	return out, nil
+}
+
+func TestParseMentions(t *testing.T) {
+	tests := []struct {
+		body string
+		want []string
+	}{
+		{body: "no mentions here", want: []string{}},
+		{body: "@alice please check", want: []string{"alice"}},
+		{body: "cc @alice, @bob_2 and @Alice again", want: []string{"alice", "bob_2"}},
+		{body: "mail me at jane@example.com", want: []string{}},
+		{body: "(@carol) @ab is too short", want: []string{"carol"}},
+	}
+	for _, tt := range tests {
+		if got := parseMentions(tt.body); !reflect.DeepEqual(got, tt.want) {
+			t.Fatalf("parseMentions(%q) = %v, want %v", tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestTaskService_CommentMentions_CreateAndUpdate(t *testing.T) {
+	taskRepo := &fakeTaskRepo{
+		getByID: func(context.Context, int64) (*repository.Task, error) {
+			return &repository.Task{ID: 1, TeamID: 10}, nil
+		},
+	}
+	comments := &commentRepoFns{
+		createFn: func(context.Context, int64, int64, string) (int64, error) { return 100, nil },
+		getFn: func(context.Context, int64) (*repository.TaskComment, error) {
+			return &repository.TaskComment{ID: 100, TaskID: 1, UserID: sqlNullInt64(5)}, nil
+		},
+	}
+	mentions := &fakeMentionRepo{}
+	directory := &fakeMentionDirectory{members: map[string]int64{"alice": 2, "bob": 3, "carol": 4, "me": 5}}
+	events := &fakeEventPublisher{}
+	db, mock := newMockDB(t)
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	svc := NewTaskService(db, taskRepo, &fakeTeamRepo{}, &fakeMemberRepo{role: RoleMember, hasRole: true}, comments, &fakeHistoryRepo{},
+		WithMentions(mentions, directory), WithTaskEvents(events))
+
+	if _, err := svc.CreateComment(context.Background(), 5, 1, "@alice @bob @stranger"); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if !reflect.DeepEqual(mentions.stored[100], []int64{2, 3}) {
+		t.Fatalf("expected only team members stored, got %v", mentions.stored[100])
+	}
+	if len(events.events) != 1 || !reflect.DeepEqual(events.events[0].Recipients, []int64{2, 3}) {
+		t.Fatalf("unexpected mention events: %+v", events.events)
+	}
+
+	events.events = nil
+	if err := svc.UpdateComment(context.Background(), 5, 100, "@bob @carol"); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if !reflect.DeepEqual(mentions.removed, []int64{2}) {
+		t.Fatalf("expected alice removed, got %v", mentions.removed)
+	}
+	if len(events.events) != 1 || !reflect.DeepEqual(events.events[0].Recipients, []int64{4}) {
+		t.Fatalf("only the newly mentioned user must be notified, got %+v", events.events)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+}
+
+func TestTaskService_CreateComment_MentionFailureRollsBack(t *testing.T) {
+	db, mock := newMockDB(t)
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	taskRepo := &fakeTaskRepo{
+		getByID: func(context.Context, int64) (*repository.Task, error) {
+			return &repository.Task{ID: 1, TeamID: 10}, nil
+		},
+	}
+	comments := &commentRepoFns{
+		createFn: func(context.Context, int64, int64, string) (int64, error) { return 100, nil },
+	}
+	mentions := &fakeMentionRepo{addErr: errMock("mention")}
+	directory := &fakeMentionDirectory{members: map[string]int64{"alice": 2}}
+	events := &fakeEventPublisher{}
+	svc := NewTaskService(db, taskRepo, &fakeTeamRepo{}, &fakeMemberRepo{}, comments, &fakeHistoryRepo{},
+		WithMentions(mentions, directory), WithTaskEvents(events))
+
+	if _, err := svc.CreateComment(context.Background(), 5, 1, "@alice"); err == nil || err.Error() != "mention" {
+		t.Fatalf("expected mention error, got %v", err)
+	}
+	if len(events.events) != 0 {
+		t.Fatalf("nothing must be published for a rolled back comment, got %+v", events.events)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"MKK-Luna/internal/repository"
+)
+
+type myTaskRepo interface {
+	ListForUser(ctx context.Context, f repository.UserTaskFilter) ([]repository.Task, int64, error)
+	SummaryForUser(ctx context.Context, f repository.UserTaskFilter, today, weekEnd time.Time) (repository.UserTaskSummary, error)
+}
+
+func WithMyTasks(mine myTaskRepo) TaskServiceOption {
+	return func(s *TaskService) { s.mine = mine }
+}
+
+// MyTasksInput mirrors TaskListInput without the required team. Relation is "assigned",
+// "created" or "" (both).
+type MyTasksInput struct {
+	Relation   string
+	TeamID     *int64
+	Status     *string
+	AssigneeID *int64
+	SprintID   *int64
+	Sort       string
+	Limit      int
+	Offset     int
+}
+
+// ListMyTasks returns the caller's tasks across every team they belong to, plus a summary
+// over the same filters except status. Weeks run Monday to Sunday, UTC.
+func (s *TaskService) ListMyTasks(ctx context.Context, userID int64, in MyTasksInput) ([]repository.Task, int64, *repository.UserTaskSummary, error) {
+	if s.mine == nil {
+		return nil, 0, nil, ErrUnavailable
+	}
+	switch in.Relation {
+	case "", "assigned", "created":
+	default:
+		return nil, 0, nil, ErrBadRequest
+	}
+	if in.Status != nil && !isValidStatus(*in.Status) {
+		return nil, 0, nil, ErrBadRequest
+	}
+	if !repository.IsTaskSort(in.Sort) {
+		return nil, 0, nil, ErrBadRequest
+	}
+	if in.TeamID != nil {
+		if ok, err := s.members.IsMember(ctx, *in.TeamID, userID); err != nil {
+			return nil, 0, nil, err
+		} else if !ok {
+			return nil, 0, nil, ErrForbidden
+		}
+	}
+
+	f := repository.UserTaskFilter{
+		UserID:     userID,
+		Relation:   in.Relation,
+		TeamID:     in.TeamID,
+		Status:     in.Status,
+		AssigneeID: in.AssigneeID,
+		SprintID:   in.SprintID,
+		Sort:       in.Sort,
+		Limit:      in.Limit,
+		Offset:     in.Offset,
+	}
+	items, total, err := s.mine.ListForUser(ctx, f)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	today, weekEnd := currentWeek(time.Now())
+	summary, err := s.mine.SummaryForUser(ctx, f, today, weekEnd)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	return items, total, &summary, nil
+}
+
+// currentWeek returns today (UTC midnight) and the following Monday.
+func currentWeek(now time.Time) (time.Time, time.Time) {
+	now = now.UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	daysLeft := 7 - (int(today.Weekday())+6)%7
+	return today, today.AddDate(0, 0, daysLeft)
+}
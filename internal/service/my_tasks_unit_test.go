@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"MKK-Luna/internal/repository"
+)
+
+type fakeMyTaskRepo struct {
+	filter  repository.UserTaskFilter
+	today   time.Time
+	weekEnd time.Time
+}
+
+func (f *fakeMyTaskRepo) ListForUser(_ context.Context, filter repository.UserTaskFilter) ([]repository.Task, int64, error) {
+	f.filter = filter
+	return []repository.Task{{ID: 1, TeamID: 10}}, 1, nil
+}
+
+func (f *fakeMyTaskRepo) SummaryForUser(_ context.Context, _ repository.UserTaskFilter, today, weekEnd time.Time) (repository.UserTaskSummary, error) {
+	f.today, f.weekEnd = today, weekEnd
+	return repository.UserTaskSummary{Total: 3, Todo: 3}, nil
+}
+
+func TestTaskService_ListMyTasks(t *testing.T) {
+	mine := &fakeMyTaskRepo{}
+	members := &fakeMemberRepo{isMember: func(_ context.Context, teamID, _ int64) (bool, error) { return teamID == 10, nil }}
+	svc := NewTaskService(nil, &fakeTaskRepo{}, &fakeTeamRepo{}, members, &fakeCommentRepo{}, &fakeHistoryRepo{}, WithMyTasks(mine))
+
+	if _, _, _, err := svc.ListMyTasks(context.Background(), 7, MyTasksInput{Relation: "watching"}); err != ErrBadRequest {
+		t.Fatalf("relation: expected ErrBadRequest, got %v", err)
+	}
+	if _, _, _, err := svc.ListMyTasks(context.Background(), 7, MyTasksInput{Sort: "title"}); err != ErrBadRequest {
+		t.Fatalf("sort: expected ErrBadRequest, got %v", err)
+	}
+	other := int64(11)
+	if _, _, _, err := svc.ListMyTasks(context.Background(), 7, MyTasksInput{TeamID: &other}); err != ErrForbidden {
+		t.Fatalf("foreign team: expected ErrForbidden, got %v", err)
+	}
+
+	team := int64(10)
+	items, total, summary, err := svc.ListMyTasks(context.Background(), 7, MyTasksInput{Relation: "created", TeamID: &team, Sort: "-priority", Limit: 20})
+	if err != nil || total != 1 || len(items) != 1 || summary.Todo != 3 {
+		t.Fatalf("items=%+v total=%d summary=%+v err=%v", items, total, summary, err)
+	}
+	if mine.filter.UserID != 7 || mine.filter.Relation != "created" || mine.filter.Sort != "-priority" || mine.filter.Limit != 20 {
+		t.Fatalf("unexpected filter: %+v", mine.filter)
+	}
+	if mine.weekEnd.Weekday() != time.Monday || !mine.weekEnd.After(mine.today) {
+		t.Fatalf("unexpected week: %v - %v", mine.today, mine.weekEnd)
+	}
+}
+
+func TestCurrentWeek(t *testing.T) {
+	cases := []struct {
+		now      time.Time
+		wantDays int
+	}{
+		{time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC), 7}, // Monday
+		{time.Date(2026, 3, 4, 15, 0, 0, 0, time.UTC), 5}, // Wednesday
+		{time.Date(2026, 3, 8, 23, 0, 0, 0, time.UTC), 1}, // Sunday
+	}
+	for _, c := range cases {
+		today, weekEnd := currentWeek(c.now)
+		if today.Hour() != 0 || weekEnd.Sub(today) != time.Duration(c.wantDays)*24*time.Hour {
+			t.Fatalf("%v: today=%v weekEnd=%v", c.now, today, weekEnd)
+		}
+	}
+}
@@ -0,0 +1,302 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"golang.org/x/crypto/bcrypt"
+
+	"MKK-Luna/internal/repository"
+)
+
+type opsUserStore interface {
+	Create(ctx context.Context, email, username, passwordHash string) (int64, error)
+	GetByID(ctx context.Context, userID int64) (*repository.User, error)
+	GetByEmail(ctx context.Context, email string) (*repository.User, error)
+	GetByUsername(ctx context.Context, username string) (*repository.User, error)
+	UpdatePasswordHash(ctx context.Context, userID int64, passwordHash string) error
+}
+
+type opsSessionStore interface {
+	GetActiveSessionsByUser(ctx context.Context, userID int64) ([]repository.Session, error)
+	RevokeAllByUser(ctx context.Context, userID int64, revokedAt time.Time) error
+}
+
+type opsMemberStore interface {
+	Add(ctx context.Context, teamID, userID int64, role string) error
+	RemoveTx(ctx context.Context, tx *sqlx.Tx, teamID, userID int64) (bool, error)
+	GetRoleForUpdateTx(ctx context.Context, tx *sqlx.Tx, teamID, userID int64) (string, bool, error)
+	ListOwnerIDsForUpdateTx(ctx context.Context, tx *sqlx.Tx, teamID int64) ([]int64, error)
+}
+
+// OpsService backs the operator CLI. It runs outside any request, so there is no acting
+// user: audit events carry a null actor and the operator name in the payload.
+type OpsService struct {
+	db         *sqlx.DB
+	users      opsUserStore
+	sessions   opsSessionStore
+	teams      teamRepo
+	members    opsMemberStore
+	integrity  *IntegrityService
+	bcryptCost int
+	operator   string
+	audit      *AuditService
+}
+
+type OpsServiceOption func(*OpsService)
+
+func WithOpsAudit(audit *AuditService, operator string) OpsServiceOption {
+	return func(s *OpsService) {
+		s.audit = audit
+		s.operator = operator
+	}
+}
+
+func NewOpsService(db *sqlx.DB, users opsUserStore, sessions opsSessionStore, teams teamRepo, members opsMemberStore, integrity *IntegrityService, bcryptCost int, opts ...OpsServiceOption) *OpsService {
+	s := &OpsService{
+		db:         db,
+		users:      users,
+		sessions:   sessions,
+		teams:      teams,
+		members:    members,
+		integrity:  integrity,
+		bcryptCost: bcryptCost,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// FindUser resolves a user by numeric ID, email or username.
+func (s *OpsService) FindUser(ctx context.Context, ref string) (*repository.User, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return nil, ErrBadRequest
+	}
+	var (
+		user *repository.User
+		err  error
+	)
+	switch {
+	case strings.Contains(ref, "@"):
+		user, err = s.users.GetByEmail(ctx, strings.ToLower(ref))
+	default:
+		if id, perr := strconv.ParseInt(ref, 10, 64); perr == nil && id > 0 {
+			user, err = s.users.GetByID(ctx, id)
+		} else {
+			user, err = s.users.GetByUsername(ctx, ref)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrNotFound
+	}
+	return user, nil
+}
+
+func (s *OpsService) CreateUser(ctx context.Context, email, username, password string) (*repository.User, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	username = strings.TrimSpace(username)
+	if err := validateEmail(email); err != nil {
+		return nil, err
+	}
+	if err := validateUsername(username); err != nil {
+		return nil, err
+	}
+	if err := validatePassword(password); err != nil {
+		return nil, err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), s.bcryptCost)
+	if err != nil {
+		return nil, err
+	}
+	id, err := s.users.Create(ctx, email, username, string(hash))
+	if err != nil {
+		if isDuplicate(err) {
+			return nil, ErrConflict
+		}
+		return nil, err
+	}
+	s.record(ctx, AuditOpsUserCreated, auditTargetUser, id, 0, nil)
+	return &repository.User{ID: id, Email: email, Username: username}, nil
+}
+
+// ResetPassword sets a new password and revokes every session of the user, so refresh
+// tokens issued under the old password stop working.
+func (s *OpsService) ResetPassword(ctx context.Context, ref, password string) (*repository.User, int, error) {
+	if err := validatePassword(password); err != nil {
+		return nil, 0, err
+	}
+	user, err := s.FindUser(ctx, ref)
+	if err != nil {
+		return nil, 0, err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), s.bcryptCost)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := s.users.UpdatePasswordHash(ctx, user.ID, string(hash)); err != nil {
+		return nil, 0, err
+	}
+	revoked, err := s.revokeSessions(ctx, user.ID)
+	if err != nil {
+		return nil, 0, err
+	}
+	s.record(ctx, AuditOpsPasswordReset, auditTargetUser, user.ID, 0, map[string]any{"sessions_revoked": revoked})
+	return user, revoked, nil
+}
+
+// RevokeSessions revokes all refresh sessions of the user. Access tokens already issued
+// stay valid until they expire.
+func (s *OpsService) RevokeSessions(ctx context.Context, ref string) (*repository.User, int, error) {
+	user, err := s.FindUser(ctx, ref)
+	if err != nil {
+		return nil, 0, err
+	}
+	revoked, err := s.revokeSessions(ctx, user.ID)
+	if err != nil {
+		return nil, 0, err
+	}
+	s.record(ctx, AuditSessionsRevoked, auditTargetUser, user.ID, 0, map[string]any{"sessions_revoked": revoked})
+	return user, revoked, nil
+}
+
+func (s *OpsService) AddMember(ctx context.Context, teamID int64, ref, role string) (*repository.User, error) {
+	if role != RoleOwner && role != RoleAdmin && role != RoleMember {
+		return nil, ErrBadRequest
+	}
+	if err := s.requireTeam(ctx, teamID); err != nil {
+		return nil, err
+	}
+	user, err := s.FindUser(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.members.Add(ctx, teamID, user.ID, role); err != nil {
+		if isDuplicate(err) {
+			return nil, ErrConflict
+		}
+		return nil, err
+	}
+	s.record(ctx, AuditOpsMemberAdded, auditTargetUser, user.ID, teamID, map[string]any{"role": role})
+	return user, nil
+}
+
+// RemoveMember drops a user from a team, with their watches, running timers and personal
+// saved views there. The last owner cannot be removed; promote someone else first.
+func (s *OpsService) RemoveMember(ctx context.Context, teamID int64, ref string) (*repository.User, error) {
+	if s.db == nil {
+		return nil, ErrUnavailable
+	}
+	if err := s.requireTeam(ctx, teamID); err != nil {
+		return nil, err
+	}
+	user, err := s.FindUser(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// Owner rows are locked before the member's own row, in the same order for every removal.
+	owners, err := s.members.ListOwnerIDsForUpdateTx(ctx, tx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	role, ok, err := s.members.GetRoleForUpdateTx(ctx, tx, teamID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if role == RoleOwner && len(owners) <= 1 {
+		return nil, ErrConflict
+	}
+	removed, err := s.members.RemoveTx(ctx, tx, teamID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !removed {
+		return nil, ErrNotFound
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	s.record(ctx, AuditOpsMemberRemoved, auditTargetUser, user.ID, teamID, map[string]any{"role": role})
+	return user, nil
+}
+
+// CheckAssignees runs the task_assignee_not_member integrity check; with fix the tasks are
+// unassigned with a history entry. The run is stored with the other integrity runs, and a
+// failed check comes back in Run.Error.
+func (s *OpsService) CheckAssignees(ctx context.Context, fix bool) (*IntegrityRunResult, error) {
+	if s.integrity == nil {
+		return nil, ErrUnavailable
+	}
+	results, err := s.integrity.Run(ctx, IntegrityRunRequest{Checks: []string{integrityCheckAssignees}, Repair: fix})
+	if err != nil {
+		return nil, err
+	}
+	res := results[0]
+	if fix && !res.Run.Error.Valid {
+		s.record(ctx, AuditOpsAssigneesFixed, auditTargetSystem, 0, 0, map[string]any{"found": res.Run.Found, "fixed": res.Run.Repaired})
+	}
+	return &res, nil
+}
+
+func (s *OpsService) revokeSessions(ctx context.Context, userID int64) (int, error) {
+	active, err := s.sessions.GetActiveSessionsByUser(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	if len(active) == 0 {
+		return 0, nil
+	}
+	if err := s.sessions.RevokeAllByUser(ctx, userID, time.Now().UTC()); err != nil {
+		return 0, err
+	}
+	return len(active), nil
+}
+
+func (s *OpsService) requireTeam(ctx context.Context, teamID int64) error {
+	if teamID <= 0 {
+		return ErrBadRequest
+	}
+	team, err := s.teams.GetByID(ctx, teamID)
+	if err != nil {
+		return err
+	}
+	if team == nil {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *OpsService) record(ctx context.Context, action, targetType string, targetID, teamID int64, payload map[string]any) {
+	if payload == nil {
+		payload = map[string]any{}
+	}
+	payload["via"] = "cli"
+	if s.operator != "" {
+		payload["operator"] = s.operator
+	}
+	s.audit.Record(ctx, AuditEntry{
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		TeamID:     teamID,
+		Payload:    payload,
+	})
+}
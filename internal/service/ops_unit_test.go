@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+	"golang.org/x/crypto/bcrypt"
+
+	"MKK-Luna/internal/repository"
+)
+
+type fakeOpsUsers struct {
+	users map[int64]*repository.User
+	hash  string
+}
+
+func (f *fakeOpsUsers) Create(_ context.Context, email, username, passwordHash string) (int64, error) {
+	for _, u := range f.users {
+		if u.Email == email {
+			return 0, &mysql.MySQLError{Number: 1062}
+		}
+	}
+	id := int64(len(f.users) + 1)
+	f.users[id] = &repository.User{ID: id, Email: email, Username: username, PasswordHash: passwordHash}
+	return id, nil
+}
+func (f *fakeOpsUsers) GetByID(_ context.Context, id int64) (*repository.User, error) {
+	return f.users[id], nil
+}
+func (f *fakeOpsUsers) GetByEmail(_ context.Context, email string) (*repository.User, error) {
+	for _, u := range f.users {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+	return nil, nil
+}
+func (f *fakeOpsUsers) GetByUsername(_ context.Context, username string) (*repository.User, error) {
+	for _, u := range f.users {
+		if u.Username == username {
+			return u, nil
+		}
+	}
+	return nil, nil
+}
+func (f *fakeOpsUsers) UpdatePasswordHash(_ context.Context, _ int64, hash string) error {
+	f.hash = hash
+	return nil
+}
+
+type fakeOpsSessions struct {
+	active  int
+	revoked int64
+}
+
+func (f *fakeOpsSessions) GetActiveSessionsByUser(context.Context, int64) ([]repository.Session, error) {
+	return make([]repository.Session, f.active), nil
+}
+func (f *fakeOpsSessions) RevokeAllByUser(_ context.Context, userID int64, _ time.Time) error {
+	f.revoked = userID
+	return nil
+}
+
+type fakeOpsMembers struct {
+	roles map[int64]string
+}
+
+func (f *fakeOpsMembers) Add(_ context.Context, _, userID int64, role string) error {
+	if _, ok := f.roles[userID]; ok {
+		return &mysql.MySQLError{Number: 1062}
+	}
+	f.roles[userID] = role
+	return nil
+}
+func (f *fakeOpsMembers) RemoveTx(_ context.Context, _ *sqlx.Tx, _, userID int64) (bool, error) {
+	_, ok := f.roles[userID]
+	delete(f.roles, userID)
+	return ok, nil
+}
+func (f *fakeOpsMembers) GetRoleForUpdateTx(_ context.Context, _ *sqlx.Tx, _, userID int64) (string, bool, error) {
+	role, ok := f.roles[userID]
+	return role, ok, nil
+}
+func (f *fakeOpsMembers) ListOwnerIDsForUpdateTx(context.Context, *sqlx.Tx, int64) ([]int64, error) {
+	var out []int64
+	for id, role := range f.roles {
+		if role == RoleOwner {
+			out = append(out, id)
+		}
+	}
+	return out, nil
+}
+
+func newOpsTestService(db *sqlx.DB, users *fakeOpsUsers, sessions *fakeOpsSessions, members *fakeOpsMembers, integrity *IntegrityService) *OpsService {
+	teams := &fakeTeamRepo{getByID: func(_ context.Context, id int64) (*repository.Team, error) {
+		if id != 10 {
+			return nil, nil
+		}
+		return &repository.Team{ID: id}, nil
+	}}
+	return NewOpsService(db, users, sessions, teams, members, integrity, bcrypt.MinCost)
+}
+
+func TestOpsService_Users(t *testing.T) {
+	users := &fakeOpsUsers{users: map[int64]*repository.User{}}
+	sessions := &fakeOpsSessions{active: 2}
+	svc := newOpsTestService(nil, users, sessions, &fakeOpsMembers{}, nil)
+	ctx := context.Background()
+
+	user, err := svc.CreateUser(ctx, " Ops@Example.com ", "ops_user", "password123")
+	if err != nil || user.ID != 1 || user.Email != "ops@example.com" {
+		t.Fatalf("create user=%+v err=%v", user, err)
+	}
+	if _, err := svc.CreateUser(ctx, "ops@example.com", "other", "password123"); err != ErrConflict {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+	if _, err := svc.CreateUser(ctx, "x@example.com", "x_user", "short"); err == nil {
+		t.Fatalf("expected weak password to be rejected")
+	}
+
+	for _, ref := range []string{"1", "ops@example.com", "ops_user"} {
+		if u, err := svc.FindUser(ctx, ref); err != nil || u.ID != 1 {
+			t.Fatalf("find %q user=%+v err=%v", ref, u, err)
+		}
+	}
+	if _, err := svc.FindUser(ctx, "nobody"); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	_, revoked, err := svc.ResetPassword(ctx, "ops_user", "newpassword456")
+	if err != nil || revoked != 2 || sessions.revoked != 1 {
+		t.Fatalf("reset revoked=%d err=%v", revoked, err)
+	}
+	if bcrypt.CompareHashAndPassword([]byte(users.hash), []byte("newpassword456")) != nil {
+		t.Fatalf("password hash not updated")
+	}
+}
+
+func TestOpsService_Members(t *testing.T) {
+	users := &fakeOpsUsers{users: map[int64]*repository.User{
+		1: {ID: 1, Email: "owner@example.com", Username: "owner"},
+		2: {ID: 2, Email: "dev@example.com", Username: "dev"},
+	}}
+	members := &fakeOpsMembers{roles: map[int64]string{1: RoleOwner}}
+	db, mock := newMockDB(t)
+	svc := newOpsTestService(db, users, &fakeOpsSessions{}, members, nil)
+	ctx := context.Background()
+
+	if _, err := svc.AddMember(ctx, 10, "dev", "superuser"); err != ErrBadRequest {
+		t.Fatalf("expected ErrBadRequest for role, got %v", err)
+	}
+	if _, err := svc.AddMember(ctx, 11, "dev", RoleMember); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound for team, got %v", err)
+	}
+	if _, err := svc.AddMember(ctx, 10, "dev", RoleMember); err != nil || members.roles[2] != RoleMember {
+		t.Fatalf("add member err=%v roles=%v", err, members.roles)
+	}
+	if _, err := svc.AddMember(ctx, 10, "dev", RoleMember); err != ErrConflict {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	if _, err := svc.RemoveMember(ctx, 10, "owner"); err != ErrConflict {
+		t.Fatalf("expected ErrConflict for last owner, got %v", err)
+	}
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	if _, err := svc.RemoveMember(ctx, 10, "dev"); err != nil {
+		t.Fatalf("remove member: %v", err)
+	}
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	if _, err := svc.RemoveMember(ctx, 10, "dev"); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+}
+
+func TestOpsService_CheckAssignees(t *testing.T) {
+	db, mock := newMockDB(t)
+	check := integrityCheck{
+		name:         integrityCheckAssignees,
+		touchesTasks: true,
+		find: func(context.Context, int) ([]repository.IntegrityFinding, error) {
+			return findingsOf(1, 2), nil
+		},
+		repair: func(context.Context, *sqlx.Tx, []repository.IntegrityFinding) (int, error) { return 1, nil },
+	}
+	runs := &fakeIntegrityRuns{}
+	integrity := NewIntegrityService(db, runs, []IntegrityCheck{check}, 5, nil, nil)
+	svc := newOpsTestService(db, &fakeOpsUsers{}, &fakeOpsSessions{}, &fakeOpsMembers{}, integrity)
+
+	found, err := svc.CheckAssignees(context.Background(), false)
+	if err != nil || found.Run.Found != 2 || found.Run.Repair != IntegrityRepairNone || len(found.TeamIDs) != 0 {
+		t.Fatalf("check found=%+v err=%v", found, err)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	fixed, err := svc.CheckAssignees(context.Background(), true)
+	if err != nil || fixed.Run.Repaired != 1 || fixed.Run.TriggerSource != IntegrityTriggerManual || len(fixed.TeamIDs) != 1 {
+		t.Fatalf("fix result=%+v err=%v", fixed, err)
+	}
+	if len(runs.runs) != 2 {
+		t.Fatalf("expected 2 stored runs, got %d", len(runs.runs))
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+}
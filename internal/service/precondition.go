@@ -0,0 +1,29 @@
+package service
+
+// Precondition is a parsed If-Match header. Any stands for "*"; otherwise Versions lists
+// the row versions the client holds, and an empty list never matches.
+type Precondition struct {
+	Any      bool
+	Versions []int64
+}
+
+func (p *Precondition) check(version int64) error {
+	if p == nil || p.Any {
+		return nil
+	}
+	for _, v := range p.Versions {
+		if v == version {
+			return nil
+		}
+	}
+	return ErrPreconditionFailed
+}
+
+// staleWrite picks the error for a guarded write that lost a race: the client asked for a
+// specific version, so it gets 412; otherwise the concurrent change is a plain conflict.
+func (p *Precondition) staleWrite() error {
+	if p != nil && !p.Any {
+		return ErrPreconditionFailed
+	}
+	return ErrConflict
+}
@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"unicode/utf8"
+
+	"MKK-Luna/internal/repository"
+)
+
+const (
+	maxSavedViewNameLen = 100
+	maxSavedViewColumns = 30
+)
+
+// savedViewColumnNames are the taskResponse fields a view may list as visible columns.
+var savedViewColumnNames = map[string]bool{
+	"id": true, "title": true, "description": true, "status": true, "priority": true,
+	"assignee": true, "creator": true, "due_date": true, "created_at": true, "updated_at": true,
+	"original_estimate_minutes": true, "remaining_estimate_minutes": true, "time_tracking": true,
+	"sprint_id": true, "checklist": true,
+}
+
+type savedViewRepo interface {
+	Create(ctx context.Context, v repository.SavedView) (int64, error)
+	GetByID(ctx context.Context, id int64) (*repository.SavedView, error)
+	ListVisible(ctx context.Context, teamID, userID int64) ([]repository.SavedView, error)
+	Update(ctx context.Context, id int64, fields map[string]any) error
+	Delete(ctx context.Context, id int64) error
+}
+
+type savedViewLookup interface {
+	GetByID(ctx context.Context, id int64) (*repository.SavedView, error)
+}
+
+type SavedViewService struct {
+	views   savedViewRepo
+	teams   teamRepo
+	members teamMemberRepo
+}
+
+func NewSavedViewService(views savedViewRepo, teams teamRepo, members teamMemberRepo) *SavedViewService {
+	return &SavedViewService{views: views, teams: teams, members: members}
+}
+
+func WithSavedViews(views savedViewLookup) TaskServiceOption {
+	return func(s *TaskService) { s.views = views }
+}
+
+// SavedViewFilters are the GET /tasks filters a view stores.
+type SavedViewFilters struct {
+	Status     *string `json:"status,omitempty"`
+	AssigneeID *int64  `json:"assignee_id,omitempty"`
+	SprintID   *int64  `json:"sprint_id,omitempty"`
+}
+
+// SavedViewInput is the whole view; updates replace every field. Shared views belong to
+// the team and are managed by owners and admins; personal views only by their owner.
+type SavedViewInput struct {
+	TeamID  int64
+	Name    string
+	Shared  bool
+	Filters SavedViewFilters
+	Sort    string
+	Columns []string
+}
+
+func (s *SavedViewService) CreateView(ctx context.Context, userID int64, in SavedViewInput) (int64, error) {
+	team, err := s.teams.GetByID(ctx, in.TeamID)
+	if err != nil {
+		return 0, err
+	}
+	if team == nil {
+		return 0, ErrNotFound
+	}
+	if ok, err := s.members.IsMember(ctx, in.TeamID, userID); err != nil {
+		return 0, err
+	} else if !ok {
+		return 0, ErrForbidden
+	}
+	if in.Shared {
+		if err := s.requireManager(ctx, in.TeamID, userID); err != nil {
+			return 0, err
+		}
+	}
+	view, err := buildSavedView(userID, in)
+	if err != nil {
+		return 0, err
+	}
+	view.TeamID = in.TeamID
+	view.CreatedBy = sql.NullInt64{Int64: userID, Valid: true}
+	id, err := s.views.Create(ctx, view)
+	if err != nil {
+		if isDuplicate(err) {
+			return 0, ErrConflict
+		}
+		return 0, err
+	}
+	return id, nil
+}
+
+func (s *SavedViewService) ListViews(ctx context.Context, userID, teamID int64) ([]repository.SavedView, error) {
+	team, err := s.teams.GetByID(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	if team == nil {
+		return nil, ErrNotFound
+	}
+	if ok, err := s.members.IsMember(ctx, teamID, userID); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, ErrForbidden
+	}
+	return s.views.ListVisible(ctx, teamID, userID)
+}
+
+func (s *SavedViewService) GetView(ctx context.Context, userID, viewID int64) (*repository.SavedView, error) {
+	return visibleSavedView(ctx, s.views, s.members, userID, viewID)
+}
+
+func (s *SavedViewService) UpdateView(ctx context.Context, userID, viewID int64, in SavedViewInput) error {
+	current, err := s.GetView(ctx, userID, viewID)
+	if err != nil {
+		return err
+	}
+	// Sharing or unsharing moves the view between the user and the team, so both sides
+	// need a manager once a shared view is involved.
+	if !current.OwnerID.Valid || in.Shared {
+		if err := s.requireManager(ctx, current.TeamID, userID); err != nil {
+			return err
+		}
+	}
+	view, err := buildSavedView(userID, in)
+	if err != nil {
+		return err
+	}
+	err = s.views.Update(ctx, viewID, map[string]any{
+		"owner_id":        nullableTemplateInt64(view.OwnerID),
+		"name":            view.Name,
+		"filters":         nullableTemplateJSON(view.Filters),
+		"sort":            view.Sort,
+		"visible_columns": nullableTemplateJSON(view.Columns),
+	})
+	if isDuplicate(err) {
+		return ErrConflict
+	}
+	return err
+}
+
+func (s *SavedViewService) DeleteView(ctx context.Context, userID, viewID int64) error {
+	view, err := s.GetView(ctx, userID, viewID)
+	if err != nil {
+		return err
+	}
+	if !view.OwnerID.Valid {
+		if err := s.requireManager(ctx, view.TeamID, userID); err != nil {
+			return err
+		}
+	}
+	return s.views.Delete(ctx, viewID)
+}
+
+func (s *SavedViewService) requireManager(ctx context.Context, teamID, userID int64) error {
+	role, ok, err := s.members.GetRole(ctx, teamID, userID)
+	if err != nil {
+		return err
+	}
+	if !ok || (role != RoleOwner && role != RoleAdmin) {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// TaskListView returns a view the caller may apply to GET /tasks.
+func (s *TaskService) TaskListView(ctx context.Context, userID, viewID int64) (*repository.SavedView, error) {
+	if s.views == nil {
+		return nil, ErrUnavailable
+	}
+	return visibleSavedView(ctx, s.views, s.members, userID, viewID)
+}
+
+// visibleSavedView hides other users' personal views as not found.
+func visibleSavedView(ctx context.Context, views savedViewLookup, members teamMemberRepo, userID, viewID int64) (*repository.SavedView, error) {
+	view, err := views.GetByID(ctx, viewID)
+	if err != nil {
+		return nil, err
+	}
+	if view == nil || (view.OwnerID.Valid && view.OwnerID.Int64 != userID) {
+		return nil, ErrNotFound
+	}
+	if ok, err := members.IsMember(ctx, view.TeamID, userID); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, ErrForbidden
+	}
+	return view, nil
+}
+
+func buildSavedView(userID int64, in SavedViewInput) (repository.SavedView, error) {
+	name := strings.TrimSpace(in.Name)
+	if name == "" || utf8.RuneCountInString(name) > maxSavedViewNameLen {
+		return repository.SavedView{}, ErrBadRequest
+	}
+	f := in.Filters
+	if f.Status != nil && !isValidStatus(*f.Status) {
+		return repository.SavedView{}, ErrBadRequest
+	}
+	if (f.AssigneeID != nil && *f.AssigneeID <= 0) || (f.SprintID != nil && *f.SprintID <= 0) {
+		return repository.SavedView{}, ErrBadRequest
+	}
+	if !repository.IsTaskSort(in.Sort) {
+		return repository.SavedView{}, ErrBadRequest
+	}
+	if len(in.Columns) > maxSavedViewColumns {
+		return repository.SavedView{}, ErrBadRequest
+	}
+	seen := make(map[string]bool, len(in.Columns))
+	for _, col := range in.Columns {
+		if !savedViewColumnNames[col] || seen[col] {
+			return repository.SavedView{}, ErrBadRequest
+		}
+		seen[col] = true
+	}
+
+	view := repository.SavedView{Name: name, Sort: in.Sort}
+	if !in.Shared {
+		view.OwnerID = sql.NullInt64{Int64: userID, Valid: true}
+	}
+	if f != (SavedViewFilters{}) {
+		view.Filters, _ = json.Marshal(f)
+	}
+	if len(in.Columns) > 0 {
+		view.Columns, _ = json.Marshal(in.Columns)
+	}
+	return view, nil
+}
+
+// SavedViewFiltersOf decodes a view's stored filters.
+func SavedViewFiltersOf(v repository.SavedView) SavedViewFilters {
+	var f SavedViewFilters
+	if len(v.Filters) > 0 {
+		_ = json.Unmarshal(v.Filters, &f)
+	}
+	return f
+}
+
+// SavedViewColumnsOf decodes a view's visible columns.
+func SavedViewColumnsOf(v repository.SavedView) []string {
+	out := make([]string, 0)
+	if len(v.Columns) > 0 {
+		_ = json.Unmarshal(v.Columns, &out)
+	}
+	return out
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"MKK-Luna/internal/repository"
+)
+
+type fakeSavedViewRepo struct {
+	created []repository.SavedView
+	byID    map[int64]*repository.SavedView
+	deleted []int64
+}
+
+func (f *fakeSavedViewRepo) Create(_ context.Context, v repository.SavedView) (int64, error) {
+	f.created = append(f.created, v)
+	return int64(len(f.created)), nil
+}
+func (f *fakeSavedViewRepo) GetByID(_ context.Context, id int64) (*repository.SavedView, error) {
+	return f.byID[id], nil
+}
+func (f *fakeSavedViewRepo) ListVisible(context.Context, int64, int64) ([]repository.SavedView, error) {
+	return nil, nil
+}
+func (f *fakeSavedViewRepo) Update(context.Context, int64, map[string]any) error { return nil }
+func (f *fakeSavedViewRepo) Delete(_ context.Context, id int64) error {
+	f.deleted = append(f.deleted, id)
+	return nil
+}
+
+func TestSavedViewService_CreateView(t *testing.T) {
+	views := &fakeSavedViewRepo{}
+	teams := &fakeTeamRepo{getByID: func(_ context.Context, id int64) (*repository.Team, error) {
+		return &repository.Team{ID: id}, nil
+	}}
+	members := &fakeMemberRepo{role: RoleMember, hasRole: true}
+	svc := NewSavedViewService(views, teams, members)
+
+	status := "todo"
+	in := SavedViewInput{
+		TeamID: 10, Name: " Mine ", Filters: SavedViewFilters{Status: &status},
+		Sort: "-priority", Columns: []string{"title", "status"},
+	}
+	if _, err := svc.CreateView(context.Background(), 1, in); err != nil {
+		t.Fatalf("create personal: %v", err)
+	}
+	got := views.created[0]
+	if got.Name != "Mine" || !got.OwnerID.Valid || got.OwnerID.Int64 != 1 || string(got.Filters) != `{"status":"todo"}` || string(got.Columns) != `["title","status"]` {
+		t.Fatalf("unexpected view: %+v filters=%s columns=%s", got, got.Filters, got.Columns)
+	}
+
+	in.Shared = true
+	if _, err := svc.CreateView(context.Background(), 1, in); err != ErrForbidden {
+		t.Fatalf("expected ErrForbidden for shared view by member, got %v", err)
+	}
+	members.role = RoleAdmin
+	if _, err := svc.CreateView(context.Background(), 1, in); err != nil {
+		t.Fatalf("create shared: %v", err)
+	}
+	if views.created[1].OwnerID.Valid {
+		t.Fatalf("shared view must have no owner: %+v", views.created[1])
+	}
+
+	for _, bad := range []SavedViewInput{
+		{TeamID: 10, Name: " "},
+		{TeamID: 10, Name: "x", Sort: "title"},
+		{TeamID: 10, Name: "x", Columns: []string{"secret"}},
+		{TeamID: 10, Name: "x", Columns: []string{"title", "title"}},
+	} {
+		if _, err := svc.CreateView(context.Background(), 1, bad); err != ErrBadRequest {
+			t.Fatalf("expected ErrBadRequest for %+v, got %v", bad, err)
+		}
+	}
+}
+
+func TestSavedViewService_Visibility(t *testing.T) {
+	views := &fakeSavedViewRepo{byID: map[int64]*repository.SavedView{
+		1: {ID: 1, TeamID: 10, OwnerID: sql.NullInt64{Int64: 2, Valid: true}, Name: "theirs"},
+		2: {ID: 2, TeamID: 10, Name: "shared"},
+	}}
+	members := &fakeMemberRepo{role: RoleMember, hasRole: true}
+	svc := NewSavedViewService(views, &fakeTeamRepo{}, members)
+
+	if _, err := svc.GetView(context.Background(), 1, 1); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound for another user's view, got %v", err)
+	}
+	if v, err := svc.GetView(context.Background(), 2, 1); err != nil || v.Name != "theirs" {
+		t.Fatalf("owner get: %v %+v", err, v)
+	}
+	if err := svc.DeleteView(context.Background(), 1, 2); err != ErrForbidden {
+		t.Fatalf("expected ErrForbidden deleting shared view as member, got %v", err)
+	}
+
+	tasks := NewTaskService(nil, &fakeTaskRepo{}, &fakeTeamRepo{}, members, &fakeCommentRepo{}, &fakeHistoryRepo{}, WithSavedViews(views))
+	if v, err := tasks.TaskListView(context.Background(), 1, 2); err != nil || v.ID != 2 {
+		t.Fatalf("task list view: %v %+v", err, v)
+	}
+	members.isMember = func(context.Context, int64, int64) (bool, error) { return false, nil }
+	if _, err := tasks.TaskListView(context.Background(), 1, 2); err != ErrForbidden {
+		t.Fatalf("expected ErrForbidden for non-member, got %v", err)
+	}
+}
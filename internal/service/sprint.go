@@ -0,0 +1,355 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jmoiron/sqlx"
+
+	"MKK-Luna/internal/repository"
+)
+
+const (
+	SprintStatusPlanned = "planned"
+	SprintStatusActive  = "active"
+	SprintStatusClosed  = "closed"
+
+	maxSprintNameLen = 100
+	maxSprintGoalLen = 2000
+	maxSprintLength  = 365 * 24 * time.Hour
+)
+
+type SprintService struct {
+	db      *sqlx.DB
+	sprints sprintRepo
+	teams   teamRepo
+	members teamMemberRepo
+	history taskHistoryRepo
+}
+
+type sprintRepo interface {
+	Create(ctx context.Context, s repository.Sprint) (int64, error)
+	GetByID(ctx context.Context, id int64) (*repository.Sprint, error)
+	GetByIDForUpdateTx(ctx context.Context, tx *sqlx.Tx, id int64) (*repository.Sprint, error)
+	ListByTeam(ctx context.Context, teamID int64, includeClosed bool) ([]repository.Sprint, error)
+	TaskCounts(ctx context.Context, ids []int64) ([]repository.SprintTaskCounts, error)
+	Update(ctx context.Context, id int64, fields map[string]any) error
+	Delete(ctx context.Context, id int64) error
+	CloseTx(ctx context.Context, tx *sqlx.Tx, id int64, closedAt time.Time) error
+	ListOpenTaskIDsTx(ctx context.Context, tx *sqlx.Tx, sprintID int64) ([]int64, error)
+	MoveTasksTx(ctx context.Context, tx *sqlx.Tx, taskIDs []int64, target *int64) error
+}
+
+func NewSprintService(db *sqlx.DB, sprints sprintRepo, teams teamRepo, members teamMemberRepo, history taskHistoryRepo) *SprintService {
+	return &SprintService{db: db, sprints: sprints, teams: teams, members: members, history: history}
+}
+
+type SprintInput struct {
+	TeamID   int64
+	Name     string
+	Goal     string
+	StartsOn time.Time
+	EndsOn   time.Time
+}
+
+// SprintUpdateInput leaves nil fields untouched; an empty Goal clears it.
+type SprintUpdateInput struct {
+	Name     *string
+	Goal     *string
+	StartsOn *time.Time
+	EndsOn   *time.Time
+}
+
+type SprintCloseResult struct {
+	MovedTaskIDs []int64
+	RolloverTo   *int64
+}
+
+func SprintStatus(s repository.Sprint, now time.Time) string {
+	if s.ClosedAt.Valid {
+		return SprintStatusClosed
+	}
+	if now.Before(s.StartsOn) {
+		return SprintStatusPlanned
+	}
+	return SprintStatusActive
+}
+
+func (s *SprintService) CreateSprint(ctx context.Context, userID int64, in SprintInput) (int64, error) {
+	team, err := s.teams.GetByID(ctx, in.TeamID)
+	if err != nil {
+		return 0, err
+	}
+	if team == nil {
+		return 0, ErrNotFound
+	}
+	if err := s.requireManager(ctx, in.TeamID, userID); err != nil {
+		return 0, err
+	}
+
+	name := strings.TrimSpace(in.Name)
+	if name == "" || utf8.RuneCountInString(name) > maxSprintNameLen {
+		return 0, ErrBadRequest
+	}
+	goal, err := sprintGoal(in.Goal)
+	if err != nil {
+		return 0, err
+	}
+	if err := validateSprintWindow(in.StartsOn, in.EndsOn); err != nil {
+		return 0, err
+	}
+
+	return s.sprints.Create(ctx, repository.Sprint{
+		TeamID:    in.TeamID,
+		Name:      name,
+		Goal:      goal,
+		StartsOn:  in.StartsOn,
+		EndsOn:    in.EndsOn,
+		CreatedBy: sql.NullInt64{Int64: userID, Valid: true},
+	})
+}
+
+func (s *SprintService) ListSprints(ctx context.Context, userID, teamID int64, includeClosed bool) ([]repository.Sprint, error) {
+	team, err := s.teams.GetByID(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	if team == nil {
+		return nil, ErrNotFound
+	}
+	if ok, err := s.members.IsMember(ctx, teamID, userID); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, ErrForbidden
+	}
+	return s.sprints.ListByTeam(ctx, teamID, includeClosed)
+}
+
+func (s *SprintService) GetSprint(ctx context.Context, userID, sprintID int64) (*repository.Sprint, error) {
+	sprint, err := s.sprints.GetByID(ctx, sprintID)
+	if err != nil {
+		return nil, err
+	}
+	if sprint == nil {
+		return nil, ErrNotFound
+	}
+	if ok, err := s.members.IsMember(ctx, sprint.TeamID, userID); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, ErrForbidden
+	}
+	return sprint, nil
+}
+
+func (s *SprintService) SprintTaskCounts(ctx context.Context, ids []int64) (map[int64]repository.SprintTaskCounts, error) {
+	rows, err := s.sprints.TaskCounts(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[int64]repository.SprintTaskCounts, len(rows))
+	for _, r := range rows {
+		out[r.SprintID] = r
+	}
+	return out, nil
+}
+
+func (s *SprintService) UpdateSprint(ctx context.Context, userID, sprintID int64, in SprintUpdateInput) error {
+	sprint, err := s.GetSprint(ctx, userID, sprintID)
+	if err != nil {
+		return err
+	}
+	if err := s.requireManager(ctx, sprint.TeamID, userID); err != nil {
+		return err
+	}
+	if sprint.ClosedAt.Valid {
+		return ErrConflict
+	}
+
+	fields := make(map[string]any)
+	if in.Name != nil {
+		name := strings.TrimSpace(*in.Name)
+		if name == "" || utf8.RuneCountInString(name) > maxSprintNameLen {
+			return ErrBadRequest
+		}
+		fields["name"] = name
+	}
+	if in.Goal != nil {
+		goal, err := sprintGoal(*in.Goal)
+		if err != nil {
+			return err
+		}
+		fields["goal"] = nullableGoal(goal)
+	}
+	startsOn, endsOn := sprint.StartsOn, sprint.EndsOn
+	if in.StartsOn != nil {
+		startsOn = *in.StartsOn
+		fields["starts_on"] = startsOn
+	}
+	if in.EndsOn != nil {
+		endsOn = *in.EndsOn
+		fields["ends_on"] = endsOn
+	}
+	if err := validateSprintWindow(startsOn, endsOn); err != nil {
+		return err
+	}
+	if len(fields) == 0 {
+		return ErrBadRequest
+	}
+	return s.sprints.Update(ctx, sprintID, fields)
+}
+
+// DeleteSprint drops the sprint; tasks fall back to the backlog through the FK's ON DELETE SET NULL.
+func (s *SprintService) DeleteSprint(ctx context.Context, userID, sprintID int64) (int64, error) {
+	sprint, err := s.GetSprint(ctx, userID, sprintID)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.requireManager(ctx, sprint.TeamID, userID); err != nil {
+		return 0, err
+	}
+	if sprint.ClosedAt.Valid {
+		return 0, ErrConflict
+	}
+	if err := s.sprints.Delete(ctx, sprintID); err != nil {
+		return 0, err
+	}
+	return sprint.TeamID, nil
+}
+
+// CloseSprint marks the sprint closed and moves every task that is not done to rolloverTo,
+// or back to the backlog when rolloverTo is nil. Each move is recorded in task_history.
+func (s *SprintService) CloseSprint(ctx context.Context, userID, sprintID int64, rolloverTo *int64) (int64, *SprintCloseResult, error) {
+	if rolloverTo != nil && *rolloverTo == sprintID {
+		return 0, nil, ErrBadRequest
+	}
+
+	tx, err := s.db.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return 0, nil, err
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			_ = tx.Rollback()
+		}
+	}()
+
+	sprint, err := s.sprints.GetByIDForUpdateTx(ctx, tx, sprintID)
+	if err != nil {
+		return 0, nil, err
+	}
+	if sprint == nil {
+		return 0, nil, ErrNotFound
+	}
+	role, ok, err := s.members.GetRole(ctx, sprint.TeamID, userID)
+	if err != nil {
+		return 0, nil, err
+	}
+	if !ok || (role != RoleOwner && role != RoleAdmin) {
+		return 0, nil, ErrForbidden
+	}
+	if sprint.ClosedAt.Valid {
+		return 0, nil, ErrConflict
+	}
+	if rolloverTo != nil {
+		target, err := s.sprints.GetByIDForUpdateTx(ctx, tx, *rolloverTo)
+		if err != nil {
+			return 0, nil, err
+		}
+		if target == nil || target.TeamID != sprint.TeamID || target.ClosedAt.Valid {
+			return 0, nil, ErrBadRequest
+		}
+	}
+
+	ids, err := s.sprints.ListOpenTaskIDsTx(ctx, tx, sprintID)
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := s.sprints.MoveTasksTx(ctx, tx, ids, rolloverTo); err != nil {
+		return 0, nil, err
+	}
+	var newValue any
+	if rolloverTo != nil {
+		newValue = *rolloverTo
+	}
+	entries := make([]repository.TaskHistoryCreate, 0, len(ids))
+	for _, id := range ids {
+		entries = append(entries, taskHistoryEntry(id, userID, "sprint_id", sprintID, newValue))
+	}
+	if err := s.history.CreateBatchTx(ctx, tx, entries); err != nil {
+		return 0, nil, err
+	}
+	if err := s.sprints.CloseTx(ctx, tx, sprintID, time.Now().UTC()); err != nil {
+		return 0, nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, nil, err
+	}
+	committed = true
+	return sprint.TeamID, &SprintCloseResult{MovedTaskIDs: ids, RolloverTo: rolloverTo}, nil
+}
+
+type sprintLookup interface {
+	GetByID(ctx context.Context, id int64) (*repository.Sprint, error)
+}
+
+func WithSprints(sprints sprintLookup) TaskServiceOption {
+	return func(s *TaskService) { s.sprints = sprints }
+}
+
+// checkTaskSprint rejects sprints from other teams and closed sprints as task targets.
+func (s *TaskService) checkTaskSprint(ctx context.Context, teamID, sprintID int64) error {
+	if s.sprints == nil {
+		return ErrUnavailable
+	}
+	sprint, err := s.sprints.GetByID(ctx, sprintID)
+	if err != nil {
+		return err
+	}
+	if sprint == nil || sprint.TeamID != teamID || sprint.ClosedAt.Valid {
+		return ErrBadRequest
+	}
+	return nil
+}
+
+func (s *SprintService) requireManager(ctx context.Context, teamID, userID int64) error {
+	role, ok, err := s.members.GetRole(ctx, teamID, userID)
+	if err != nil {
+		return err
+	}
+	if !ok || (role != RoleOwner && role != RoleAdmin) {
+		return ErrForbidden
+	}
+	return nil
+}
+
+func validateSprintWindow(startsOn, endsOn time.Time) error {
+	if startsOn.IsZero() || endsOn.IsZero() || endsOn.Before(startsOn) {
+		return ErrBadRequest
+	}
+	if endsOn.Sub(startsOn) > maxSprintLength {
+		return ErrBadRequest
+	}
+	return nil
+}
+
+func sprintGoal(v string) (sql.NullString, error) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return sql.NullString{}, nil
+	}
+	if utf8.RuneCountInString(v) > maxSprintGoalLen {
+		return sql.NullString{}, ErrBadRequest
+	}
+	return sql.NullString{String: v, Valid: true}, nil
+}
+
+func nullableGoal(goal sql.NullString) any {
+	if goal.Valid {
+		return goal.String
+	}
+	return nil
+}
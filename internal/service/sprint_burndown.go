@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"MKK-Luna/internal/repository"
+)
+
+type StatsServiceOption func(*StatsService)
+
+func WithSprintLookup(sprints sprintLookup, members teamMemberRepo) StatsServiceOption {
+	return func(s *StatsService) {
+		s.sprints = sprints
+		s.members = members
+	}
+}
+
+type BurndownPoint struct {
+	Date           time.Time
+	Scope          int64
+	Completed      int64
+	Remaining      int64
+	IdealRemaining float64
+}
+
+// GetSprintBurndown is visible to every team member, unlike the owner/admin team stats.
+func (s *StatsService) GetSprintBurndown(ctx context.Context, userID, sprintID int64) (*repository.Sprint, []BurndownPoint, error) {
+	if s.sprints == nil || s.members == nil {
+		return nil, nil, ErrUnavailable
+	}
+	sprint, err := s.sprints.GetByID(ctx, sprintID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if sprint == nil {
+		return nil, nil, ErrNotFound
+	}
+	if ok, err := s.members.IsMember(ctx, sprint.TeamID, userID); err != nil {
+		return nil, nil, err
+	} else if !ok {
+		return nil, nil, ErrForbidden
+	}
+
+	tasks, err := s.repo.GetSprintTaskStates(ctx, sprintID)
+	if err != nil {
+		return nil, nil, err
+	}
+	ids := make([]int64, 0, len(tasks))
+	for _, t := range tasks {
+		ids = append(ids, t.TaskID)
+	}
+	history, err := s.repo.ListHistoryForFields(ctx, ids, []string{"status", "sprint_id"})
+	if err != nil {
+		return nil, nil, err
+	}
+	return sprint, buildBurndown(*sprint, tasks, history, time.Now().UTC()), nil
+}
+
+type fieldChange struct {
+	at       time.Time
+	oldValue json.RawMessage
+	newValue json.RawMessage
+}
+
+// buildBurndown replays status and sprint_id history to evaluate each task at the end of every sprint day.
+// A field with no history keeps its current value; otherwise the value before the first change is that change's old value.
+func buildBurndown(sprint repository.Sprint, tasks []repository.SprintTaskState, history []repository.TaskHistory, now time.Time) []BurndownPoint {
+	changes := make(map[int64]map[string][]fieldChange, len(tasks))
+	for _, h := range history {
+		if changes[h.TaskID] == nil {
+			changes[h.TaskID] = make(map[string][]fieldChange, 2)
+		}
+		changes[h.TaskID][h.FieldName] = append(changes[h.TaskID][h.FieldName], fieldChange{at: h.CreatedAt, oldValue: h.OldValue, newValue: h.NewValue})
+	}
+
+	start := truncateToDate(sprint.StartsOn)
+	end := truncateToDate(sprint.EndsOn)
+	stop := now
+	if sprint.ClosedAt.Valid && sprint.ClosedAt.Time.Before(stop) {
+		stop = sprint.ClosedAt.Time
+	}
+	days := int(end.Sub(start)/(24*time.Hour)) + 1
+
+	var points []BurndownPoint
+	var initialScope int64
+	for i := 0; i < days; i++ {
+		day := start.AddDate(0, 0, i)
+		if day.After(stop) {
+			break
+		}
+		at := day.AddDate(0, 0, 1)
+		if at.After(stop) {
+			at = stop
+		}
+
+		var scope, completed int64
+		for _, t := range tasks {
+			if t.CreatedAt.After(at) {
+				continue
+			}
+			sprintRaw := fieldValueAt(changes[t.TaskID]["sprint_id"], at)
+			inSprint := t.SprintID.Valid && t.SprintID.Int64 == sprint.ID
+			if sprintRaw != nil {
+				var v *int64
+				inSprint = json.Unmarshal(sprintRaw, &v) == nil && v != nil && *v == sprint.ID
+			}
+			if !inSprint {
+				continue
+			}
+			scope++
+
+			status := t.Status
+			if statusRaw := fieldValueAt(changes[t.TaskID]["status"], at); statusRaw != nil {
+				status = ""
+				_ = json.Unmarshal(statusRaw, &status)
+			}
+			if status == "done" {
+				completed++
+			}
+		}
+		if i == 0 {
+			initialScope = scope
+		}
+
+		ideal := 0.0
+		if days > 1 {
+			ideal = float64(initialScope) * (1 - float64(i)/float64(days-1))
+		}
+		points = append(points, BurndownPoint{
+			Date:           day,
+			Scope:          scope,
+			Completed:      completed,
+			Remaining:      scope - completed,
+			IdealRemaining: ideal,
+		})
+	}
+	return points
+}
+
+// fieldValueAt returns nil when the field has no history, meaning the current column value applies.
+func fieldValueAt(changes []fieldChange, at time.Time) json.RawMessage {
+	if len(changes) == 0 {
+		return nil
+	}
+	value := changes[0].oldValue
+	for _, c := range changes {
+		if c.at.After(at) {
+			break
+		}
+		value = c.newValue
+	}
+	if value == nil {
+		return json.RawMessage("null")
+	}
+	return value
+}
@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"MKK-Luna/internal/repository"
+)
+
+type fakeSprintRepo struct {
+	sprints map[int64]repository.Sprint
+	open    []int64
+	moved   []int64
+	target  *int64
+	closed  bool
+}
+
+func (f *fakeSprintRepo) Create(_ context.Context, s repository.Sprint) (int64, error) {
+	if f.sprints == nil {
+		f.sprints = map[int64]repository.Sprint{}
+	}
+	s.ID = int64(len(f.sprints) + 1)
+	f.sprints[s.ID] = s
+	return s.ID, nil
+}
+
+func (f *fakeSprintRepo) GetByID(_ context.Context, id int64) (*repository.Sprint, error) {
+	s, ok := f.sprints[id]
+	if !ok {
+		return nil, nil
+	}
+	return &s, nil
+}
+
+func (f *fakeSprintRepo) GetByIDForUpdateTx(ctx context.Context, _ *sqlx.Tx, id int64) (*repository.Sprint, error) {
+	return f.GetByID(ctx, id)
+}
+
+func (f *fakeSprintRepo) ListByTeam(context.Context, int64, bool) ([]repository.Sprint, error) {
+	return nil, nil
+}
+
+func (f *fakeSprintRepo) TaskCounts(context.Context, []int64) ([]repository.SprintTaskCounts, error) {
+	return nil, nil
+}
+
+func (f *fakeSprintRepo) Update(context.Context, int64, map[string]any) error { return nil }
+func (f *fakeSprintRepo) Delete(context.Context, int64) error                 { return nil }
+
+func (f *fakeSprintRepo) CloseTx(context.Context, *sqlx.Tx, int64, time.Time) error {
+	f.closed = true
+	return nil
+}
+
+func (f *fakeSprintRepo) ListOpenTaskIDsTx(context.Context, *sqlx.Tx, int64) ([]int64, error) {
+	return f.open, nil
+}
+
+func (f *fakeSprintRepo) MoveTasksTx(_ context.Context, _ *sqlx.Tx, ids []int64, target *int64) error {
+	f.moved = ids
+	f.target = target
+	return nil
+}
+
+func sprintDate(s string) time.Time {
+	t, _ := time.Parse("2006-01-02", s)
+	return t
+}
+
+func TestSprintService_CreateSprint_Validation(t *testing.T) {
+	teams := &fakeTeamRepo{getByID: func(context.Context, int64) (*repository.Team, error) {
+		return &repository.Team{ID: 10}, nil
+	}}
+	tests := []struct {
+		name    string
+		role    string
+		in      SprintInput
+		wantErr error
+	}{
+		{name: "member forbidden", role: RoleMember, in: SprintInput{TeamID: 10, Name: "S1", StartsOn: sprintDate("2026-01-01"), EndsOn: sprintDate("2026-01-14")}, wantErr: ErrForbidden},
+		{name: "empty name", role: RoleAdmin, in: SprintInput{TeamID: 10, Name: "  ", StartsOn: sprintDate("2026-01-01"), EndsOn: sprintDate("2026-01-14")}, wantErr: ErrBadRequest},
+		{name: "ends before start", role: RoleAdmin, in: SprintInput{TeamID: 10, Name: "S1", StartsOn: sprintDate("2026-01-14"), EndsOn: sprintDate("2026-01-01")}, wantErr: ErrBadRequest},
+		{name: "too long", role: RoleOwner, in: SprintInput{TeamID: 10, Name: "S1", StartsOn: sprintDate("2026-01-01"), EndsOn: sprintDate("2027-06-01")}, wantErr: ErrBadRequest},
+		{name: "ok", role: RoleOwner, in: SprintInput{TeamID: 10, Name: "S1", StartsOn: sprintDate("2026-01-01"), EndsOn: sprintDate("2026-01-14")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewSprintService(nil, &fakeSprintRepo{}, teams, &fakeMemberRepo{role: tt.role, hasRole: true}, &fakeHistoryRepo{})
+			_, err := svc.CreateSprint(context.Background(), 1, tt.in)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestSprintService_CloseSprint_RollsOverOpenTasks(t *testing.T) {
+	db, mock := newMockDB(t)
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	repo := &fakeSprintRepo{
+		sprints: map[int64]repository.Sprint{
+			1: {ID: 1, TeamID: 10},
+			2: {ID: 2, TeamID: 10},
+		},
+		open: []int64{5, 6},
+	}
+	var entries []repository.TaskHistoryCreate
+	history := &fakeHistoryRepo{createBatchTx: func(_ context.Context, _ *sqlx.Tx, e []repository.TaskHistoryCreate) error {
+		entries = e
+		return nil
+	}}
+	svc := NewSprintService(db, repo, &fakeTeamRepo{}, &fakeMemberRepo{role: RoleAdmin, hasRole: true}, history)
+
+	target := int64(2)
+	teamID, res, err := svc.CloseSprint(context.Background(), 7, 1, &target)
+	if err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if teamID != 10 || len(res.MovedTaskIDs) != 2 || !repo.closed {
+		t.Fatalf("unexpected result: team=%d res=%+v closed=%v", teamID, res, repo.closed)
+	}
+	if repo.target == nil || *repo.target != 2 {
+		t.Fatalf("expected tasks moved to sprint 2, got %v", repo.target)
+	}
+	if len(entries) != 2 || entries[0].FieldName != "sprint_id" || entries[0].NewValue == nil || string(*entries[0].NewValue) != "2" {
+		t.Fatalf("unexpected history entries: %+v", entries)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+}
+
+func TestSprintService_CloseSprint_Errors(t *testing.T) {
+	closed := repository.Sprint{ID: 3, TeamID: 10, ClosedAt: sql.NullTime{Time: time.Now(), Valid: true}}
+	tests := []struct {
+		name    string
+		role    string
+		target  int64
+		wantErr error
+	}{
+		{name: "member forbidden", role: RoleMember, target: 2, wantErr: ErrForbidden},
+		{name: "target in other team", role: RoleOwner, target: 4, wantErr: ErrBadRequest},
+		{name: "target closed", role: RoleOwner, target: 3, wantErr: ErrBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := newMockDB(t)
+			mock.ExpectBegin()
+			mock.ExpectRollback()
+
+			repo := &fakeSprintRepo{sprints: map[int64]repository.Sprint{
+				1: {ID: 1, TeamID: 10},
+				2: {ID: 2, TeamID: 10},
+				3: closed,
+				4: {ID: 4, TeamID: 11},
+			}}
+			svc := NewSprintService(db, repo, &fakeTeamRepo{}, &fakeMemberRepo{role: tt.role, hasRole: true}, &fakeHistoryRepo{})
+			_, _, err := svc.CloseSprint(context.Background(), 7, 1, &tt.target)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if repo.closed {
+				t.Fatalf("sprint must stay open")
+			}
+		})
+	}
+}
+
+func TestBuildBurndown_ReplaysHistory(t *testing.T) {
+	sprint := repository.Sprint{ID: 1, StartsOn: sprintDate("2026-03-02"), EndsOn: sprintDate("2026-03-05")}
+	created := sprintDate("2026-03-01")
+	tasks := []repository.SprintTaskState{
+		{TaskID: 1, Status: "done", SprintID: sql.NullInt64{Int64: 1, Valid: true}, CreatedAt: created},
+		{TaskID: 2, Status: "todo", SprintID: sql.NullInt64{Int64: 1, Valid: true}, CreatedAt: created},
+		// added to the sprint on day 2
+		{TaskID: 3, Status: "todo", SprintID: sql.NullInt64{Int64: 1, Valid: true}, CreatedAt: created},
+	}
+	raw := func(v any) json.RawMessage {
+		b, _ := json.Marshal(v)
+		return b
+	}
+	history := []repository.TaskHistory{
+		{TaskID: 1, FieldName: "status", OldValue: raw("in_progress"), NewValue: raw("done"), CreatedAt: sprintDate("2026-03-04").Add(10 * time.Hour)},
+		{TaskID: 3, FieldName: "sprint_id", OldValue: raw(nil), NewValue: raw(1), CreatedAt: sprintDate("2026-03-03").Add(9 * time.Hour)},
+	}
+
+	points := buildBurndown(sprint, tasks, history, sprintDate("2026-03-10"))
+	if len(points) != 4 {
+		t.Fatalf("expected 4 points, got %d", len(points))
+	}
+	want := []struct{ scope, completed int64 }{{2, 0}, {3, 0}, {3, 1}, {3, 1}}
+	for i, w := range want {
+		if points[i].Scope != w.scope || points[i].Completed != w.completed {
+			t.Fatalf("day %d: expected %+v, got %+v", i, w, points[i])
+		}
+	}
+	if points[0].IdealRemaining != 2 || points[3].IdealRemaining != 0 {
+		t.Fatalf("unexpected ideal line: %v .. %v", points[0].IdealRemaining, points[3].IdealRemaining)
+	}
+
+	partial := buildBurndown(sprint, tasks, history, sprintDate("2026-03-03").Add(12*time.Hour))
+	if len(partial) != 2 {
+		t.Fatalf("expected series to stop at now, got %d points", len(partial))
+	}
+}
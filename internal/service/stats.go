@@ -16,7 +16,16 @@ const (
 type analyticsStore interface {
 	GetTeamDoneStats(ctx context.Context, userID int64, from, to time.Time) ([]repository.TeamDoneStat, error)
 	GetTopCreatorsByTeam(ctx context.Context, userID int64, from, to time.Time, limit int) ([]repository.TeamTopCreator, error)
+	GetLoggedTime(ctx context.Context, userID int64, from, to time.Time, f repository.LoggedTimeFilter) ([]repository.LoggedTimeStat, error)
+	GetSprintTaskStates(ctx context.Context, sprintID int64) ([]repository.SprintTaskState, error)
+	ListHistoryForFields(ctx context.Context, taskIDs []int64, fields []string) ([]repository.TaskHistory, error)
 	FindTasksWithAssigneeNotMember(ctx context.Context) ([]repository.TaskIntegrityIssue, error)
+	ListStatsTeams(ctx context.Context, userID int64) ([]repository.StatsTeam, error)
+	GetFlowCompletions(ctx context.Context, userID int64, from, to time.Time) ([]repository.FlowCompletion, error)
+	GetWIPTasks(ctx context.Context, userID int64) ([]repository.WIPTask, error)
+	GetOverdueStats(ctx context.Context, userID int64, from, to, today time.Time) ([]repository.OverdueStat, error)
+	GetMemberWorkload(ctx context.Context, userID int64, from, to time.Time) ([]repository.MemberWorkloadStat, error)
+	GetDoneSlots(ctx context.Context, userID int64, from, to time.Time) ([]repository.DoneSlot, error)
 }
 
 type StatsService struct {
@@ -24,9 +33,12 @@ type StatsService struct {
 	cache      dcache.StatsCache
 	adminUsers map[int64]struct{}
 	logger     *slog.Logger
+	sprints    sprintLookup
+	members    teamMemberRepo
+	audit      *AuditService
 }
 
-func NewStatsService(repo analyticsStore, statsCache dcache.StatsCache, adminUserIDs []int64, logger *slog.Logger) *StatsService {
+func NewStatsService(repo analyticsStore, statsCache dcache.StatsCache, adminUserIDs []int64, logger *slog.Logger, opts ...StatsServiceOption) *StatsService {
 	admins := make(map[int64]struct{}, len(adminUserIDs))
 	for _, id := range adminUserIDs {
 		if id > 0 {
@@ -36,7 +48,11 @@ func NewStatsService(repo analyticsStore, statsCache dcache.StatsCache, adminUse
 	if logger != nil && len(admins) == 0 {
 		logger.Warn("admin allowlist is empty")
 	}
-	return &StatsService{repo: repo, cache: statsCache, adminUsers: admins, logger: logger}
+	s := &StatsService{repo: repo, cache: statsCache, adminUsers: admins, logger: logger}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s *StatsService) GetTeamDoneStats(ctx context.Context, userID int64, from, to time.Time) ([]repository.TeamDoneStat, error) {
@@ -82,6 +98,51 @@ func (s *StatsService) GetTopCreatorsByTeam(ctx context.Context, userID int64, f
 	return rows, nil
 }
 
+// GetMemberWorkload lists the members of the caller's owner/admin teams; teamID 0 means all of them.
+func (s *StatsService) GetMemberWorkload(ctx context.Context, userID int64, from, to time.Time, teamID int64) ([]repository.MemberWorkloadStat, error) {
+	if err := validateStatsRange(from, to); err != nil {
+		return nil, err
+	}
+	var rows []repository.MemberWorkloadStat
+	cached := false
+	if s.cache != nil {
+		if items, ok, err := s.cache.GetWorkload(ctx, userID, from, to); err == nil && ok {
+			rows, cached = items, true
+		}
+	}
+	if !cached {
+		var err error
+		if rows, err = s.repo.GetMemberWorkload(ctx, userID, from, to); err != nil {
+			return nil, err
+		}
+		if s.cache != nil {
+			_ = s.cache.SetWorkload(ctx, userID, from, to, rows)
+		}
+	}
+	if teamID == 0 {
+		return rows, nil
+	}
+	out := make([]repository.MemberWorkloadStat, 0)
+	for _, row := range rows {
+		if row.TeamID == teamID {
+			out = append(out, row)
+		}
+	}
+	// The caller is a member of every team they may see, so an empty result means no access.
+	if len(out) == 0 {
+		return nil, ErrForbidden
+	}
+	return out, nil
+}
+
+// GetLoggedTime is uncached: billing exports must reflect work logs as soon as they are written.
+func (s *StatsService) GetLoggedTime(ctx context.Context, userID int64, from, to time.Time, f repository.LoggedTimeFilter) ([]repository.LoggedTimeStat, error) {
+	if err := validateStatsRange(from, to); err != nil {
+		return nil, err
+	}
+	return s.repo.GetLoggedTime(ctx, userID, from, to, f)
+}
+
 func (s *StatsService) FindTasksWithAssigneeNotMember(ctx context.Context, userID int64) ([]repository.TaskIntegrityIssue, error) {
 	if !s.isAdmin(userID) {
 		if s.logger != nil {
@@ -89,7 +150,15 @@ func (s *StatsService) FindTasksWithAssigneeNotMember(ctx context.Context, userI
 		}
 		return nil, ErrForbidden
 	}
-	return s.repo.FindTasksWithAssigneeNotMember(ctx)
+	rows, err := s.repo.FindTasksWithAssigneeNotMember(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, AuditEntry{
+		ActorID: userID, Action: AuditIntegrityQueried, TargetType: auditTargetSystem,
+		Payload: map[string]any{"check": "task_assignee_not_member", "issues": len(rows)},
+	})
+	return rows, nil
 }
 
 func (s *StatsService) isAdmin(userID int64) bool {
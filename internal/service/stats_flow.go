@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"MKK-Luna/internal/repository"
+)
+
+// wipAgeBuckets are upper bounds on the time since a task last entered in_progress; the last one is open-ended.
+var wipAgeBuckets = []struct {
+	label string
+	upTo  time.Duration
+}{
+	{"<1d", 24 * time.Hour},
+	{"1-3d", 3 * 24 * time.Hour},
+	{"3-7d", 7 * 24 * time.Hour},
+	{"7-14d", 14 * 24 * time.Hour},
+	{">=14d", 0},
+}
+
+// GetTeamFlowStats reports flow metrics for the caller's owner/admin teams; teamID 0 means all of them.
+// Cycle and lead time, throughput and the overdue rate cover [from, to); WIP is the current state.
+func (s *StatsService) GetTeamFlowStats(ctx context.Context, userID int64, from, to time.Time, teamID int64) ([]repository.TeamFlowStat, error) {
+	if err := validateStatsRange(from, to); err != nil {
+		return nil, err
+	}
+	var rows []repository.TeamFlowStat
+	cached := false
+	if s.cache != nil {
+		if items, ok, err := s.cache.GetFlow(ctx, userID, from, to); err == nil && ok {
+			rows, cached = items, true
+		}
+	}
+	if !cached {
+		var err error
+		if rows, err = s.loadFlowStats(ctx, userID, from, to); err != nil {
+			return nil, err
+		}
+		if s.cache != nil {
+			_ = s.cache.SetFlow(ctx, userID, from, to, rows)
+		}
+	}
+	if teamID == 0 {
+		return rows, nil
+	}
+	for _, row := range rows {
+		if row.TeamID == teamID {
+			return []repository.TeamFlowStat{row}, nil
+		}
+	}
+	return nil, ErrForbidden
+}
+
+func (s *StatsService) loadFlowStats(ctx context.Context, userID int64, from, to time.Time) ([]repository.TeamFlowStat, error) {
+	teams, err := s.repo.ListStatsTeams(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(teams) == 0 {
+		return []repository.TeamFlowStat{}, nil
+	}
+	completions, err := s.repo.GetFlowCompletions(ctx, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	wip, err := s.repo.GetWIPTasks(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	overdue, err := s.repo.GetOverdueStats(ctx, userID, from, to, truncateToDate(now))
+	if err != nil {
+		return nil, err
+	}
+	return buildFlowStats(teams, completions, wip, overdue, from, to, now), nil
+}
+
+func buildFlowStats(teams []repository.StatsTeam, completions []repository.FlowCompletion, wip []repository.WIPTask, overdue []repository.OverdueStat, from, to, now time.Time) []repository.TeamFlowStat {
+	firstWeek := weekStart(from)
+	weeks := int(weekStart(to.Add(-time.Nanosecond)).Sub(firstWeek)/(7*24*time.Hour)) + 1
+
+	out := make([]repository.TeamFlowStat, 0, len(teams))
+	index := make(map[int64]int, len(teams))
+	cycle := make(map[int64][]float64, len(teams))
+	lead := make(map[int64][]float64, len(teams))
+	for _, t := range teams {
+		index[t.TeamID] = len(out)
+		stat := repository.TeamFlowStat{
+			TeamID:     t.TeamID,
+			TeamName:   t.TeamName,
+			Throughput: make([]repository.ThroughputPoint, weeks),
+			WIPAge:     make([]repository.WIPAgeBucket, len(wipAgeBuckets)),
+		}
+		for i := range stat.Throughput {
+			stat.Throughput[i].WeekStart = firstWeek.AddDate(0, 0, 7*i)
+		}
+		for i, b := range wipAgeBuckets {
+			stat.WIPAge[i].Label = b.label
+		}
+		out = append(out, stat)
+	}
+
+	for _, c := range completions {
+		i, ok := index[c.TeamID]
+		if !ok {
+			continue
+		}
+		lead[c.TeamID] = append(lead[c.TeamID], c.DoneAt.Sub(c.CreatedAt).Hours())
+		if c.StartedAt.Valid && !c.StartedAt.Time.After(c.DoneAt) {
+			cycle[c.TeamID] = append(cycle[c.TeamID], c.DoneAt.Sub(c.StartedAt.Time).Hours())
+		}
+		if w := int(weekStart(c.DoneAt).Sub(firstWeek) / (7 * 24 * time.Hour)); w >= 0 && w < weeks {
+			out[i].Throughput[w].Done++
+		}
+	}
+
+	for _, t := range wip {
+		i, ok := index[t.TeamID]
+		if !ok {
+			continue
+		}
+		out[i].WIP++
+		age := now.Sub(t.Since)
+		for b, bucket := range wipAgeBuckets {
+			if bucket.upTo == 0 || age < bucket.upTo {
+				out[i].WIPAge[b].Count++
+				break
+			}
+		}
+	}
+
+	for _, o := range overdue {
+		i, ok := index[o.TeamID]
+		if !ok {
+			continue
+		}
+		out[i].DueCount = o.DueCount
+		out[i].OverdueCount = o.OverdueCount
+		if o.DueCount > 0 {
+			out[i].OverdueRate = roundTo(float64(o.OverdueCount)/float64(o.DueCount), 1000)
+		}
+	}
+
+	for i := range out {
+		out[i].CycleTime = durationPercentiles(cycle[out[i].TeamID])
+		out[i].LeadTime = durationPercentiles(lead[out[i].TeamID])
+	}
+	return out
+}
+
+// durationPercentiles uses nearest-rank percentiles, rounded to a tenth of an hour.
+func durationPercentiles(hours []float64) repository.DurationPercentiles {
+	res := repository.DurationPercentiles{Count: len(hours)}
+	if len(hours) == 0 {
+		return res
+	}
+	sort.Float64s(hours)
+	rank := func(p float64) float64 {
+		i := int(math.Ceil(p*float64(len(hours)))) - 1
+		return roundTo(hours[max(i, 0)], 10)
+	}
+	res.P50, res.P85, res.P95 = rank(0.50), rank(0.85), rank(0.95)
+	return res
+}
+
+// weekStart returns midnight UTC of the Monday starting tm's week.
+func weekStart(tm time.Time) time.Time {
+	day := truncateToDate(tm.UTC())
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
+
+func roundTo(v, scale float64) float64 {
+	return math.Round(v*scale) / scale
+}
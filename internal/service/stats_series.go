@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"MKK-Luna/internal/repository"
+)
+
+const (
+	StatsBucketDay   = "day"
+	StatsBucketWeek  = "week"
+	StatsBucketMonth = "month"
+
+	doneSlotMinutes = 15
+)
+
+// GetTeamDoneSeries splits GetTeamDoneStats into day, week (from Monday) or month buckets that start
+// at local midnight in tz. Every bucket between from and to is present, zero-filled; the first and
+// last ones only count the part inside [from, to).
+func (s *StatsService) GetTeamDoneSeries(ctx context.Context, userID int64, from, to time.Time, bucket, tz string, teamID int64) ([]repository.TeamDoneSeries, error) {
+	if err := validateStatsRange(from, to); err != nil {
+		return nil, err
+	}
+	switch bucket {
+	case StatsBucketDay, StatsBucketWeek, StatsBucketMonth:
+	default:
+		return nil, ErrBadRequest
+	}
+	if tz == "" {
+		tz = "UTC"
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, ErrBadRequest
+	}
+
+	var rows []repository.TeamDoneSeries
+	cached := false
+	if s.cache != nil {
+		if items, ok, err := s.cache.GetDoneSeries(ctx, userID, from, to, bucket, loc.String()); err == nil && ok {
+			rows, cached = items, true
+		}
+	}
+	if !cached {
+		teams, err := s.repo.ListStatsTeams(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		slots, err := s.repo.GetDoneSlots(ctx, userID, from, to)
+		if err != nil {
+			return nil, err
+		}
+		rows = buildDoneSeries(teams, slots, from, to, bucket, loc)
+		if s.cache != nil {
+			_ = s.cache.SetDoneSeries(ctx, userID, from, to, bucket, loc.String(), rows)
+		}
+	}
+	if teamID == 0 {
+		return rows, nil
+	}
+	for _, row := range rows {
+		if row.TeamID == teamID {
+			return []repository.TeamDoneSeries{row}, nil
+		}
+	}
+	return nil, ErrForbidden
+}
+
+func buildDoneSeries(teams []repository.StatsTeam, slots []repository.DoneSlot, from, to time.Time, bucket string, loc *time.Location) []repository.TeamDoneSeries {
+	var starts []time.Time
+	for start := bucketStart(from, bucket, loc); start.Before(to); start = nextBucket(start, bucket) {
+		starts = append(starts, start)
+	}
+
+	out := make([]repository.TeamDoneSeries, 0, len(teams))
+	index := make(map[int64]int, len(teams))
+	for _, t := range teams {
+		index[t.TeamID] = len(out)
+		points := make([]repository.SeriesPoint, len(starts))
+		for i, start := range starts {
+			points[i].Start = start
+		}
+		out = append(out, repository.TeamDoneSeries{TeamID: t.TeamID, TeamName: t.TeamName, Points: points})
+	}
+
+	for _, slot := range slots {
+		i, ok := index[slot.TeamID]
+		if !ok {
+			continue
+		}
+		at := time.Unix(slot.Slot*doneSlotMinutes*60, 0)
+		b := sort.Search(len(starts), func(j int) bool { return starts[j].After(at) }) - 1
+		if b >= 0 {
+			out[i].Points[b].Count += slot.DoneCount
+		}
+	}
+	return out
+}
+
+// bucketStart returns local midnight of the day, Monday or first of the month containing tm.
+func bucketStart(tm time.Time, bucket string, loc *time.Location) time.Time {
+	local := tm.In(loc)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	switch bucket {
+	case StatsBucketWeek:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case StatsBucketMonth:
+		return day.AddDate(0, 0, 1-day.Day())
+	}
+	return day
+}
+
+// nextBucket steps in calendar units, so buckets stay at local midnight across DST changes.
+func nextBucket(start time.Time, bucket string) time.Time {
+	switch bucket {
+	case StatsBucketWeek:
+		return start.AddDate(0, 0, 7)
+	case StatsBucketMonth:
+		return start.AddDate(0, 1, 0)
+	}
+	return start.AddDate(0, 0, 1)
+}
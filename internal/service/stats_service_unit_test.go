@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"testing"
 	"time"
 
@@ -12,6 +14,13 @@ type fakeAnalyticsRepo struct {
 	done      []repository.TeamDoneStat
 	top       []repository.TeamTopCreator
 	integrity []repository.TaskIntegrityIssue
+	logged    []repository.LoggedTimeStat
+	teams     []repository.StatsTeam
+	completed []repository.FlowCompletion
+	wip       []repository.WIPTask
+	overdue   []repository.OverdueStat
+	workload  []repository.MemberWorkloadStat
+	slots     []repository.DoneSlot
 	err       error
 }
 
@@ -20,6 +29,10 @@ type fakeStatsCache struct {
 	topItems  []repository.TeamTopCreator
 	doneHit   bool
 	topHit    bool
+	flowItems []repository.TeamFlowStat
+	flowSets  int
+	workItems []repository.MemberWorkloadStat
+	seriesKey string
 }
 
 func (f *fakeStatsCache) GetDone(ctx context.Context, userID int64, from, to time.Time) ([]repository.TeamDoneStat, bool, error) {
@@ -46,6 +59,34 @@ func (f *fakeStatsCache) SetTop(ctx context.Context, userID int64, from, to time
 	return nil
 }
 
+func (f *fakeStatsCache) GetFlow(ctx context.Context, userID int64, from, to time.Time) ([]repository.TeamFlowStat, bool, error) {
+	return f.flowItems, f.flowItems != nil, nil
+}
+
+func (f *fakeStatsCache) SetFlow(ctx context.Context, userID int64, from, to time.Time, items []repository.TeamFlowStat) error {
+	f.flowItems = items
+	f.flowSets++
+	return nil
+}
+
+func (f *fakeStatsCache) GetWorkload(ctx context.Context, userID int64, from, to time.Time) ([]repository.MemberWorkloadStat, bool, error) {
+	return f.workItems, f.workItems != nil, nil
+}
+
+func (f *fakeStatsCache) SetWorkload(ctx context.Context, userID int64, from, to time.Time, items []repository.MemberWorkloadStat) error {
+	f.workItems = items
+	return nil
+}
+
+func (f *fakeStatsCache) GetDoneSeries(ctx context.Context, userID int64, from, to time.Time, bucket, tz string) ([]repository.TeamDoneSeries, bool, error) {
+	return nil, false, nil
+}
+
+func (f *fakeStatsCache) SetDoneSeries(ctx context.Context, userID int64, from, to time.Time, bucket, tz string, items []repository.TeamDoneSeries) error {
+	f.seriesKey = bucket + "|" + tz
+	return nil
+}
+
 func (f *fakeAnalyticsRepo) GetTeamDoneStats(ctx context.Context, userID int64, from, to time.Time) ([]repository.TeamDoneStat, error) {
 	if f.err != nil {
 		return nil, f.err
@@ -60,6 +101,21 @@ func (f *fakeAnalyticsRepo) GetTopCreatorsByTeam(ctx context.Context, userID int
 	return f.top, nil
 }
 
+func (f *fakeAnalyticsRepo) GetLoggedTime(ctx context.Context, userID int64, from, to time.Time, filter repository.LoggedTimeFilter) ([]repository.LoggedTimeStat, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.logged, nil
+}
+
+func (f *fakeAnalyticsRepo) GetSprintTaskStates(context.Context, int64) ([]repository.SprintTaskState, error) {
+	return nil, f.err
+}
+
+func (f *fakeAnalyticsRepo) ListHistoryForFields(context.Context, []int64, []string) ([]repository.TaskHistory, error) {
+	return nil, f.err
+}
+
 func (f *fakeAnalyticsRepo) FindTasksWithAssigneeNotMember(ctx context.Context) ([]repository.TaskIntegrityIssue, error) {
 	if f.err != nil {
 		return nil, f.err
@@ -67,6 +123,30 @@ func (f *fakeAnalyticsRepo) FindTasksWithAssigneeNotMember(ctx context.Context)
 	return f.integrity, nil
 }
 
+func (f *fakeAnalyticsRepo) ListStatsTeams(context.Context, int64) ([]repository.StatsTeam, error) {
+	return f.teams, f.err
+}
+
+func (f *fakeAnalyticsRepo) GetFlowCompletions(context.Context, int64, time.Time, time.Time) ([]repository.FlowCompletion, error) {
+	return f.completed, f.err
+}
+
+func (f *fakeAnalyticsRepo) GetWIPTasks(context.Context, int64) ([]repository.WIPTask, error) {
+	return f.wip, f.err
+}
+
+func (f *fakeAnalyticsRepo) GetOverdueStats(context.Context, int64, time.Time, time.Time, time.Time) ([]repository.OverdueStat, error) {
+	return f.overdue, f.err
+}
+
+func (f *fakeAnalyticsRepo) GetMemberWorkload(context.Context, int64, time.Time, time.Time) ([]repository.MemberWorkloadStat, error) {
+	return f.workload, f.err
+}
+
+func (f *fakeAnalyticsRepo) GetDoneSlots(context.Context, int64, time.Time, time.Time) ([]repository.DoneSlot, error) {
+	return f.slots, f.err
+}
+
 func TestStatsServiceRangeValidation(t *testing.T) {
 	svc := NewStatsService(&fakeAnalyticsRepo{}, nil, nil, nil)
 
@@ -137,3 +217,144 @@ func TestStatsServiceUsesCache(t *testing.T) {
 		t.Fatalf("expected cached rows, got %+v", rows)
 	}
 }
+
+func TestBuildFlowStats(t *testing.T) {
+	from := time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC) // Wednesday
+	to := time.Date(2026, 3, 18, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2026, 3, 20, 12, 0, 0, 0, time.UTC)
+	created := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	started := sql.NullTime{Time: created.Add(24 * time.Hour), Valid: true}
+
+	teams := []repository.StatsTeam{{TeamID: 1, TeamName: "core"}, {TeamID: 2, TeamName: "idle"}}
+	completions := []repository.FlowCompletion{
+		{TeamID: 1, TaskID: 1, CreatedAt: created, StartedAt: started, DoneAt: created.Add(48 * time.Hour)},
+		{TeamID: 1, TaskID: 2, CreatedAt: created, StartedAt: started, DoneAt: created.Add(96 * time.Hour)},
+		{TeamID: 1, TaskID: 3, CreatedAt: created, DoneAt: time.Date(2026, 3, 16, 9, 0, 0, 0, time.UTC)},
+	}
+	wip := []repository.WIPTask{
+		{TeamID: 1, TaskID: 4, Since: now.Add(-2 * time.Hour)},
+		{TeamID: 1, TaskID: 5, Since: now.Add(-20 * 24 * time.Hour)},
+	}
+	overdue := []repository.OverdueStat{{TeamID: 1, DueCount: 3, OverdueCount: 1}}
+
+	rows := buildFlowStats(teams, completions, wip, overdue, from, to, now)
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 teams, got %d", len(rows))
+	}
+	core := rows[0]
+	if core.LeadTime.Count != 3 || core.CycleTime.Count != 2 || core.CycleTime.P50 != 24 || core.CycleTime.P95 != 72 {
+		t.Fatalf("unexpected durations lead=%+v cycle=%+v", core.LeadTime, core.CycleTime)
+	}
+	if len(core.Throughput) != 3 || !core.Throughput[0].WeekStart.Equal(time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected weeks %+v", core.Throughput)
+	}
+	if core.Throughput[0].Done != 2 || core.Throughput[1].Done != 0 || core.Throughput[2].Done != 1 {
+		t.Fatalf("unexpected throughput %+v", core.Throughput)
+	}
+	if core.WIP != 2 || core.WIPAge[0].Count != 1 || core.WIPAge[len(core.WIPAge)-1].Count != 1 {
+		t.Fatalf("unexpected wip %d %+v", core.WIP, core.WIPAge)
+	}
+	if core.OverdueRate != 0.333 {
+		t.Fatalf("unexpected overdue rate %v", core.OverdueRate)
+	}
+	if idle := rows[1]; idle.LeadTime.Count != 0 || len(idle.Throughput) != 3 || idle.WIP != 0 {
+		t.Fatalf("unexpected idle team %+v", idle)
+	}
+}
+
+func TestStatsServiceFlowCacheAndTeamFilter(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	later := now.Add(7 * 24 * time.Hour)
+	repo := &fakeAnalyticsRepo{teams: []repository.StatsTeam{{TeamID: 1}, {TeamID: 2}}}
+	cache := &fakeStatsCache{}
+	svc := NewStatsService(repo, cache, nil, nil)
+
+	rows, err := svc.GetTeamFlowStats(context.Background(), 1, now, later, 2)
+	if err != nil || len(rows) != 1 || rows[0].TeamID != 2 || cache.flowSets != 1 {
+		t.Fatalf("rows=%+v err=%v sets=%d", rows, err, cache.flowSets)
+	}
+	repo.err = errors.New("db down")
+	if rows, err := svc.GetTeamFlowStats(context.Background(), 1, now, later, 0); err != nil || len(rows) != 2 {
+		t.Fatalf("expected cached rows, got %+v err=%v", rows, err)
+	}
+	if _, err := svc.GetTeamFlowStats(context.Background(), 1, now, later, 3); err != ErrForbidden {
+		t.Fatalf("expected ErrForbidden, got %v", err)
+	}
+	if _, err := svc.GetTeamFlowStats(context.Background(), 1, later, now, 0); err != ErrBadRequest {
+		t.Fatalf("expected ErrBadRequest, got %v", err)
+	}
+}
+
+func TestStatsServiceMemberWorkload(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	later := now.Add(7 * 24 * time.Hour)
+	repo := &fakeAnalyticsRepo{workload: []repository.MemberWorkloadStat{
+		{TeamID: 1, UserID: 1, AssignedCount: 3},
+		{TeamID: 1, UserID: 2, OpenHigh: 2},
+		{TeamID: 2, UserID: 1},
+	}}
+	cache := &fakeStatsCache{}
+	svc := NewStatsService(repo, cache, nil, nil)
+
+	rows, err := svc.GetMemberWorkload(context.Background(), 1, now, later, 1)
+	if err != nil || len(rows) != 2 || rows[1].OpenHigh != 2 || len(cache.workItems) != 3 {
+		t.Fatalf("rows=%+v err=%v cached=%d", rows, err, len(cache.workItems))
+	}
+	if _, err := svc.GetMemberWorkload(context.Background(), 1, now, later, 9); err != ErrForbidden {
+		t.Fatalf("expected ErrForbidden, got %v", err)
+	}
+	if _, err := svc.GetMemberWorkload(context.Background(), 1, now, now.Add(366*24*time.Hour), 0); err != ErrBadRequest {
+		t.Fatalf("expected ErrBadRequest, got %v", err)
+	}
+}
+
+func TestBuildDoneSeriesTimeZone(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	// 2026-03-28 00:00 Berlin (UTC+1) to 2026-03-31 00:00 Berlin (UTC+2, DST starts on the 29th).
+	from := time.Date(2026, 3, 27, 23, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 30, 22, 0, 0, 0, time.UTC)
+	slot := func(tm time.Time) int64 { return tm.Unix() / 60 / doneSlotMinutes }
+	slots := []repository.DoneSlot{
+		{TeamID: 1, Slot: slot(time.Date(2026, 3, 27, 23, 30, 0, 0, time.UTC)), DoneCount: 2}, // 00:30 on the 28th in Berlin
+		{TeamID: 1, Slot: slot(time.Date(2026, 3, 29, 22, 15, 0, 0, time.UTC)), DoneCount: 1}, // 00:15 on the 30th in Berlin
+	}
+
+	rows := buildDoneSeries([]repository.StatsTeam{{TeamID: 1}, {TeamID: 2}}, slots, from, to, StatsBucketDay, berlin)
+	if len(rows) != 2 || len(rows[0].Points) != 3 || len(rows[1].Points) != 3 {
+		t.Fatalf("unexpected series %+v", rows)
+	}
+	got := []int64{rows[0].Points[0].Count, rows[0].Points[1].Count, rows[0].Points[2].Count}
+	if got[0] != 2 || got[1] != 0 || got[2] != 1 {
+		t.Fatalf("unexpected counts %v", got)
+	}
+	if start := rows[0].Points[2].Start; !start.Equal(time.Date(2026, 3, 29, 22, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected local midnight after DST, got %v", start)
+	}
+
+	months := buildDoneSeries([]repository.StatsTeam{{TeamID: 1}}, slots, from, to.AddDate(0, 1, 0), StatsBucketMonth, berlin)
+	if len(months[0].Points) != 2 || months[0].Points[0].Count != 3 || months[0].Points[0].Start.Day() != 1 {
+		t.Fatalf("unexpected months %+v", months[0].Points)
+	}
+}
+
+func TestStatsServiceDoneSeriesValidation(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	later := now.Add(7 * 24 * time.Hour)
+	cache := &fakeStatsCache{}
+	svc := NewStatsService(&fakeAnalyticsRepo{teams: []repository.StatsTeam{{TeamID: 1}}}, cache, nil, nil)
+	ctx := context.Background()
+
+	if _, err := svc.GetTeamDoneSeries(ctx, 1, now, later, "hour", "", 0); err != ErrBadRequest {
+		t.Fatalf("expected ErrBadRequest for bucket, got %v", err)
+	}
+	if _, err := svc.GetTeamDoneSeries(ctx, 1, now, later, StatsBucketDay, "Mars/Olympus", 0); err != ErrBadRequest {
+		t.Fatalf("expected ErrBadRequest for tz, got %v", err)
+	}
+	rows, err := svc.GetTeamDoneSeries(ctx, 1, now, later, StatsBucketWeek, "", 1)
+	if err != nil || len(rows) != 1 || len(rows[0].Points) != 2 || cache.seriesKey != "week|UTC" {
+		t.Fatalf("rows=%+v err=%v key=%q", rows, err, cache.seriesKey)
+	}
+}
@@ -5,11 +5,14 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"log/slog"
 	"strings"
 	"time"
 
 	"github.com/jmoiron/sqlx"
 
+	devents "MKK-Luna/internal/domain/events"
+	dstorage "MKK-Luna/internal/domain/storage"
 	"MKK-Luna/internal/repository"
 )
 
@@ -24,10 +27,45 @@ type TaskService struct {
 	members  teamMemberRepo
 	comments taskCommentRepo
 	history  taskHistoryRepo
+	users    userDirectory
+	watchers taskWatcherRepo
+	events   devents.TaskEventPublisher
+	tagCache taskTagCache
+
+	mentions         commentMentionRepo
+	mentionDirectory mentionDirectory
+	threads          commentThreadRepo
+	reactions        commentReactionRepo
+	attachmentKeys   attachmentKeyRepo
+	blobs            dstorage.BlobStorage
+	worklogs         workLogRepo
+	sprints          sprintLookup
+	replay           taskHistoryReplayRepo
+	restore          taskRestoreRepo
+	trash            taskTrashRepo
+	templates        taskTemplateLookup
+	checklists       taskChecklistRepo
+	board            boardRepo
+	mine             myTaskRepo
+	views            savedViewLookup
+	importer         *taskImporter
+	audit            *AuditService
+	logger           *slog.Logger
+}
+
+type TaskServiceOption func(*TaskService)
+
+func WithTaskLogger(logger *slog.Logger) TaskServiceOption {
+	return func(s *TaskService) { s.logger = logger }
+}
+
+func WithUserDirectory(users userDirectory) TaskServiceOption {
+	return func(s *TaskService) { s.users = users }
 }
 
 type taskRepo interface {
 	Create(ctx context.Context, t repository.Task) (int64, error)
+	CreateTx(ctx context.Context, tx *sqlx.Tx, t repository.Task) (int64, error)
 	GetByID(ctx context.Context, taskID int64) (*repository.Task, error)
 	GetByIDForUpdateTx(ctx context.Context, tx *sqlx.Tx, taskID int64) (*repository.Task, error)
 	List(ctx context.Context, f repository.TaskListFilter) ([]repository.Task, int64, error)
@@ -47,10 +85,10 @@ type teamMemberRepo interface {
 }
 
 type taskCommentRepo interface {
-	Create(ctx context.Context, taskID, userID int64, body string) (int64, error)
+	CreateTx(ctx context.Context, tx *sqlx.Tx, taskID, userID int64, body string) (int64, error)
 	ListByTask(ctx context.Context, taskID int64) ([]repository.TaskComment, error)
 	GetByID(ctx context.Context, commentID int64) (*repository.TaskComment, error)
-	Update(ctx context.Context, commentID int64, body string) error
+	UpdateTx(ctx context.Context, tx *sqlx.Tx, commentID int64, body string) error
 	Delete(ctx context.Context, commentID int64) error
 }
 
@@ -59,15 +97,24 @@ type taskHistoryRepo interface {
 	ListByTask(ctx context.Context, taskID int64, limit, offset int) ([]repository.TaskHistory, int64, error)
 }
 
-func NewTaskService(db *sqlx.DB, tasks taskRepo, teams teamRepo, members teamMemberRepo, comments taskCommentRepo, history taskHistoryRepo) *TaskService {
-	return &TaskService{
+type userDirectory interface {
+	ListSummariesByIDs(ctx context.Context, ids []int64) ([]repository.UserSummary, error)
+}
+
+func NewTaskService(db *sqlx.DB, tasks taskRepo, teams teamRepo, members teamMemberRepo, comments taskCommentRepo, history taskHistoryRepo, opts ...TaskServiceOption) *TaskService {
+	s := &TaskService{
 		db:       db,
 		tasks:    tasks,
 		teams:    teams,
 		members:  members,
 		comments: comments,
 		history:  history,
+		logger:   slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
 type CreateTaskInput struct {
@@ -78,9 +125,17 @@ type CreateTaskInput struct {
 	Priority    string
 	AssigneeID  *int64
 	DueDate     *time.Time
+
+	OriginalEstimate *int64
+	SprintID         *int64
 }
 
 func (s *TaskService) CreateTask(ctx context.Context, userID int64, in CreateTaskInput) (int64, error) {
+	return s.createTask(ctx, userID, in, nil)
+}
+
+// createTask validates and inserts the task together with its checklist items, if any.
+func (s *TaskService) createTask(ctx context.Context, userID int64, in CreateTaskInput, checklist []repository.ChecklistItem) (int64, error) {
 	team, err := s.teams.GetByID(ctx, in.TeamID)
 	if err != nil {
 		return 0, err
@@ -132,6 +187,20 @@ func (s *TaskService) CreateTask(ctx context.Context, userID int64, in CreateTas
 	if in.DueDate != nil {
 		due = sql.NullTime{Time: *in.DueDate, Valid: true}
 	}
+	var estimate sql.NullInt64
+	if in.OriginalEstimate != nil {
+		if !isValidEstimate(*in.OriginalEstimate) {
+			return 0, ErrBadRequest
+		}
+		estimate = sql.NullInt64{Int64: *in.OriginalEstimate, Valid: true}
+	}
+	var sprintID sql.NullInt64
+	if in.SprintID != nil {
+		if err := s.checkTaskSprint(ctx, in.TeamID, *in.SprintID); err != nil {
+			return 0, err
+		}
+		sprintID = sql.NullInt64{Int64: *in.SprintID, Valid: true}
+	}
 
 	task := repository.Task{
 		TeamID:      in.TeamID,
@@ -142,8 +211,54 @@ func (s *TaskService) CreateTask(ctx context.Context, userID int64, in CreateTas
 		AssigneeID:  assignee,
 		CreatedBy:   sql.NullInt64{Int64: userID, Valid: true},
 		DueDate:     due,
+
+		OriginalEstimate:  estimate,
+		RemainingEstimate: estimate,
+		SprintID:          sprintID,
 	}
-	return s.tasks.Create(ctx, task)
+	id, err := s.insertTask(ctx, task, checklist)
+	if err != nil {
+		return 0, err
+	}
+	var assigneeID int64
+	if in.AssigneeID != nil {
+		assigneeID = *in.AssigneeID
+	}
+	s.autoWatch(ctx, id, userID, assigneeID)
+	return id, nil
+}
+
+// insertTask creates the task and its checklist items inside one transaction, which also
+// holds the column's WIP limit when the board is enabled.
+func (s *TaskService) insertTask(ctx context.Context, task repository.Task, checklist []repository.ChecklistItem) (int64, error) {
+	if len(checklist) == 0 && (s.board == nil || s.db == nil) {
+		return s.tasks.Create(ctx, task)
+	}
+	if s.db == nil {
+		return 0, ErrUnavailable
+	}
+	tx, err := s.db.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := s.checkColumnLimit(ctx, tx, task.TeamID, task.Status, 1); err != nil {
+		return 0, err
+	}
+	id, err := s.tasks.CreateTx(ctx, tx, task)
+	if err != nil {
+		return 0, err
+	}
+	if len(checklist) > 0 {
+		if _, err := s.checklists.CreateTx(ctx, tx, id, checklist); err != nil {
+			return 0, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return id, nil
 }
 
 func (s *TaskService) GetTask(ctx context.Context, userID, taskID int64) (*repository.Task, error) {
@@ -166,6 +281,8 @@ type TaskListInput struct {
 	TeamID     int64
 	Status     *string
 	AssigneeID *int64
+	SprintID   *int64
+	Sort       string
 	Limit      int
 	Offset     int
 }
@@ -184,18 +301,45 @@ func (s *TaskService) ListTasks(ctx context.Context, userID int64, in TaskListIn
 		return nil, 0, ErrForbidden
 	}
 
+	if !repository.IsTaskSort(in.Sort) {
+		return nil, 0, ErrBadRequest
+	}
+
 	return s.tasks.List(ctx, repository.TaskListFilter{
 		TeamID:     in.TeamID,
 		Status:     in.Status,
 		AssigneeID: in.AssigneeID,
+		SprintID:   in.SprintID,
+		Sort:       in.Sort,
 		Limit:      in.Limit,
 		Offset:     in.Offset,
 	})
 }
 
 func (s *TaskService) UpdateTask(ctx context.Context, userID, taskID int64, raw map[string]json.RawMessage) (int64, error) {
+	return s.UpdateTaskIfMatch(ctx, userID, taskID, raw, nil)
+}
+
+// UpdateTaskIfMatch checks cond against the version locked inside the transaction, so a
+// client holding a stale ETag gets ErrPreconditionFailed instead of overwriting newer edits.
+func (s *TaskService) UpdateTaskIfMatch(ctx context.Context, userID, taskID int64, raw map[string]json.RawMessage, cond *Precondition) (int64, error) {
+	return s.updateTask(ctx, userID, taskID, cond, func(repository.Task) (map[string]json.RawMessage, error) {
+		if len(raw) == 0 {
+			return nil, ErrBadRequest
+		}
+		return raw, nil
+	}, nil)
+}
+
+// taskPatchFunc turns the task as read (locked, when running in a transaction) into the
+// fields to change. An empty result is a no-op.
+type taskPatchFunc func(task repository.Task) (map[string]json.RawMessage, error)
+
+// updateTask applies build's fields; a non-nil move also places the task on the board in
+// the same transaction.
+func (s *TaskService) updateTask(ctx context.Context, userID, taskID int64, cond *Precondition, build taskPatchFunc, move *BoardMove) (int64, error) {
 	if s.db == nil || s.history == nil {
-		return s.updateTaskNoTx(ctx, userID, taskID, raw)
+		return s.updateTaskNoTx(ctx, userID, taskID, cond, build)
 	}
 
 	tx, err := s.db.BeginTxx(ctx, &sql.TxOptions{})
@@ -224,11 +368,23 @@ func (s *TaskService) UpdateTask(ctx context.Context, userID, taskID int64, raw
 	if !ok {
 		return 0, ErrForbidden
 	}
-
-	parsed, err := s.parseTaskPatch(ctx, task.TeamID, raw)
+	if err := cond.check(task.Version); err != nil {
+		return 0, err
+	}
+	raw, err := build(*task)
 	if err != nil {
 		return 0, err
 	}
+	if len(raw) == 0 && move == nil {
+		return task.TeamID, nil
+	}
+
+	parsed := map[string]any{}
+	if len(raw) > 0 {
+		if parsed, err = s.parseTaskPatch(ctx, task.TeamID, raw); err != nil {
+			return 0, err
+		}
+	}
 
 	allowed := allowedTaskFields(role)
 	for key := range parsed {
@@ -238,9 +394,18 @@ func (s *TaskService) UpdateTask(ctx context.Context, userID, taskID int64, raw
 	}
 
 	updates, entries := buildTaskDiffEntries(*task, userID, parsed)
+	fields := changedFields(updates)
+	if move != nil {
+		if err := s.placeOnBoard(ctx, tx, *task, move, updates); err != nil {
+			return 0, err
+		}
+	}
 	if len(updates) == 0 {
 		return task.TeamID, nil
 	}
+	if err := s.checkBoardColumn(ctx, tx, *task, updates); err != nil {
+		return 0, err
+	}
 
 	if err := s.tasks.UpdateTx(ctx, tx, taskID, updates); err != nil {
 		return 0, err
@@ -248,14 +413,24 @@ func (s *TaskService) UpdateTask(ctx context.Context, userID, taskID int64, raw
 	if err := s.history.CreateBatchTx(ctx, tx, entries); err != nil {
 		return 0, err
 	}
+	if assigneeID, ok := updates["assignee_id"].(int64); ok && s.watchers != nil {
+		if err := s.watchers.AddTx(ctx, tx, taskID, assigneeID); err != nil {
+			return 0, err
+		}
+	}
 	if err := tx.Commit(); err != nil {
 		return 0, err
 	}
 	committed = true
+	if len(fields) > 0 {
+		s.notifyWatchers(ctx, devents.TaskEvent{
+			Type: TaskEventUpdated, TaskID: taskID, TeamID: task.TeamID, ActorID: userID, Fields: fields,
+		})
+	}
 	return task.TeamID, nil
 }
 
-func (s *TaskService) updateTaskNoTx(ctx context.Context, userID, taskID int64, raw map[string]json.RawMessage) (int64, error) {
+func (s *TaskService) updateTaskNoTx(ctx context.Context, userID, taskID int64, cond *Precondition, build taskPatchFunc) (int64, error) {
 	task, err := s.tasks.GetByID(ctx, taskID)
 	if err != nil {
 		return 0, err
@@ -270,6 +445,16 @@ func (s *TaskService) updateTaskNoTx(ctx context.Context, userID, taskID int64,
 	if !ok {
 		return 0, ErrForbidden
 	}
+	if err := cond.check(task.Version); err != nil {
+		return 0, err
+	}
+	raw, err := build(*task)
+	if err != nil {
+		return 0, err
+	}
+	if len(raw) == 0 {
+		return task.TeamID, nil
+	}
 
 	parsed, err := s.parseTaskPatch(ctx, task.TeamID, raw)
 	if err != nil {
@@ -289,12 +474,22 @@ func (s *TaskService) updateTaskNoTx(ctx context.Context, userID, taskID int64,
 	if err := s.tasks.Update(ctx, taskID, updates); err != nil {
 		return 0, err
 	}
+	if assigneeID, ok := updates["assignee_id"].(int64); ok {
+		s.autoWatch(ctx, taskID, assigneeID)
+	}
+	s.notifyWatchers(ctx, devents.TaskEvent{
+		Type: TaskEventUpdated, TaskID: taskID, TeamID: task.TeamID, ActorID: userID, Fields: changedFields(updates),
+	})
 	return task.TeamID, nil
 }
 
 func (s *TaskService) DeleteTask(ctx context.Context, userID, taskID int64) (int64, error) {
+	return s.DeleteTaskIfMatch(ctx, userID, taskID, nil)
+}
+
+func (s *TaskService) DeleteTaskIfMatch(ctx context.Context, userID, taskID int64, cond *Precondition) (int64, error) {
 	if s.db == nil || s.history == nil {
-		return s.deleteTaskNoTx(ctx, userID, taskID)
+		return s.deleteTaskNoTx(ctx, userID, taskID, cond)
 	}
 
 	tx, err := s.db.BeginTxx(ctx, &sql.TxOptions{})
@@ -326,6 +521,9 @@ func (s *TaskService) DeleteTask(ctx context.Context, userID, taskID int64) (int
 	if role != RoleOwner && role != RoleAdmin {
 		return 0, ErrForbidden
 	}
+	if err := cond.check(task.Version); err != nil {
+		return 0, err
+	}
 
 	snapshot, err := taskDeleteSnapshot(*task)
 	if err != nil {
@@ -334,7 +532,7 @@ func (s *TaskService) DeleteTask(ctx context.Context, userID, taskID int64) (int
 	entry := repository.TaskHistoryCreate{
 		TaskID:    task.ID,
 		ChangedBy: &userID,
-		FieldName: "task_deleted",
+		FieldName: historyFieldTaskDeleted,
 		OldValue:  snapshot,
 		NewValue:  nil,
 	}
@@ -351,7 +549,7 @@ func (s *TaskService) DeleteTask(ctx context.Context, userID, taskID int64) (int
 	return task.TeamID, nil
 }
 
-func (s *TaskService) deleteTaskNoTx(ctx context.Context, userID, taskID int64) (int64, error) {
+func (s *TaskService) deleteTaskNoTx(ctx context.Context, userID, taskID int64, cond *Precondition) (int64, error) {
 	task, err := s.tasks.GetByID(ctx, taskID)
 	if err != nil {
 		return 0, err
@@ -369,6 +567,9 @@ func (s *TaskService) deleteTaskNoTx(ctx context.Context, userID, taskID int64)
 	if role != RoleOwner && role != RoleAdmin {
 		return 0, ErrForbidden
 	}
+	if err := cond.check(task.Version); err != nil {
+		return 0, err
+	}
 	if err := s.tasks.Delete(ctx, taskID); err != nil {
 		return 0, err
 	}
@@ -388,7 +589,50 @@ func (s *TaskService) CreateComment(ctx context.Context, userID, taskID int64, b
 	} else if !ok {
 		return 0, ErrForbidden
 	}
-	return s.comments.Create(ctx, taskID, userID, body)
+	return s.createComment(ctx, userID, *task, nil, body)
+}
+
+// createComment stores the comment and its mentions in one transaction, so a failed
+// mention insert leaves nothing behind for a retry to duplicate.
+func (s *TaskService) createComment(ctx context.Context, userID int64, task repository.Task, parentID *int64, body string) (int64, error) {
+	if s.db == nil {
+		return 0, ErrUnavailable
+	}
+	mentioned, err := s.resolveMentions(ctx, task.TeamID, body)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := s.db.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var id int64
+	if parentID != nil {
+		id, err = s.threads.CreateReplyTx(ctx, tx, task.ID, userID, *parentID, body)
+	} else {
+		id, err = s.comments.CreateTx(ctx, tx, task.ID, userID, body)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if len(mentioned) > 0 {
+		if err := s.mentions.AddTx(ctx, tx, id, mentioned); err != nil {
+			return 0, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	s.autoWatch(ctx, task.ID, userID)
+	s.notifyWatchers(ctx, devents.TaskEvent{
+		Type: TaskEventCommentCreated, TaskID: task.ID, TeamID: task.TeamID, ActorID: userID, CommentID: id,
+	})
+	s.notifyMentioned(ctx, task, userID, id, mentioned)
+	return id, nil
 }
 
 func (s *TaskService) ListComments(ctx context.Context, userID, taskID int64) ([]repository.TaskComment, error) {
@@ -407,12 +651,69 @@ func (s *TaskService) ListComments(ctx context.Context, userID, taskID int64) ([
 	return s.comments.ListByTask(ctx, taskID)
 }
 
+// editCommentTx writes the new body, keeps the previous one as an edit and brings the
+// mentions in line in one transaction. It returns the newly mentioned users.
+func (s *TaskService) editCommentTx(ctx context.Context, userID int64, comment *repository.TaskComment, body string, mentioned []int64, cond *Precondition) ([]int64, error) {
+	if s.db == nil {
+		return nil, ErrUnavailable
+	}
+	tx, err := s.db.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if s.threads != nil {
+		applied, err := s.threads.UpdateBodyTx(ctx, tx, comment.ID, comment.Version, body)
+		if err != nil {
+			return nil, err
+		}
+		if !applied {
+			return nil, cond.staleWrite()
+		}
+		if err := s.threads.InsertEditTx(ctx, tx, comment.ID, userID, comment.Body); err != nil {
+			return nil, err
+		}
+	} else if err := s.comments.UpdateTx(ctx, tx, comment.ID, body); err != nil {
+		return nil, err
+	}
+
+	// The body update holds the comment row lock, so concurrent edits diff one after another.
+	var added []int64
+	if s.mentions != nil {
+		before, err := s.mentions.ListUserIDsByCommentTx(ctx, tx, comment.ID)
+		if err != nil {
+			return nil, err
+		}
+		var removed []int64
+		added, removed = diffMentions(before, mentioned)
+		if len(added) > 0 {
+			if err := s.mentions.AddTx(ctx, tx, comment.ID, added); err != nil {
+				return nil, err
+			}
+		}
+		if len(removed) > 0 {
+			if err := s.mentions.RemoveTx(ctx, tx, comment.ID, removed); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return added, nil
+}
+
 func (s *TaskService) UpdateComment(ctx context.Context, userID, commentID int64, body string) error {
+	return s.UpdateCommentIfMatch(ctx, userID, commentID, body, nil)
+}
+
+func (s *TaskService) UpdateCommentIfMatch(ctx context.Context, userID, commentID int64, body string, cond *Precondition) error {
 	comment, err := s.comments.GetByID(ctx, commentID)
 	if err != nil {
 		return err
 	}
-	if comment == nil {
+	if comment == nil || comment.DeletedAt.Valid {
 		return ErrNotFound
 	}
 	task, err := s.tasks.GetByID(ctx, comment.TaskID)
@@ -429,18 +730,42 @@ func (s *TaskService) UpdateComment(ctx context.Context, userID, commentID int64
 	if !ok {
 		return ErrForbidden
 	}
-	if comment.UserID != userID && role != RoleOwner && role != RoleAdmin {
+	if !isCommentAuthor(*comment, userID) && role != RoleOwner && role != RoleAdmin {
 		return ErrForbidden
 	}
-	return s.comments.Update(ctx, commentID, body)
+	if err := cond.check(comment.Version); err != nil {
+		return err
+	}
+
+	if s.threads != nil && comment.Body == body {
+		return nil
+	}
+	mentioned, err := s.resolveMentions(ctx, task.TeamID, body)
+	if err != nil {
+		return err
+	}
+	added, err := s.editCommentTx(ctx, userID, comment, body, mentioned, cond)
+	if err != nil {
+		return err
+	}
+	s.audit.Record(ctx, AuditEntry{
+		ActorID: userID, Action: AuditCommentEdited, TargetType: auditTargetComment, TargetID: commentID, TeamID: task.TeamID,
+		Payload: map[string]any{"task_id": task.ID, "by_author": isCommentAuthor(*comment, userID)},
+	})
+	s.notifyMentioned(ctx, *task, userID, commentID, added)
+	return nil
 }
 
 func (s *TaskService) DeleteComment(ctx context.Context, userID, commentID int64) error {
+	return s.DeleteCommentIfMatch(ctx, userID, commentID, nil)
+}
+
+func (s *TaskService) DeleteCommentIfMatch(ctx context.Context, userID, commentID int64, cond *Precondition) error {
 	comment, err := s.comments.GetByID(ctx, commentID)
 	if err != nil {
 		return err
 	}
-	if comment == nil {
+	if comment == nil || comment.DeletedAt.Valid {
 		return ErrNotFound
 	}
 	task, err := s.tasks.GetByID(ctx, comment.TaskID)
@@ -457,10 +782,28 @@ func (s *TaskService) DeleteComment(ctx context.Context, userID, commentID int64
 	if !ok {
 		return ErrForbidden
 	}
-	if comment.UserID != userID && role != RoleOwner && role != RoleAdmin {
+	if !isCommentAuthor(*comment, userID) && role != RoleOwner && role != RoleAdmin {
 		return ErrForbidden
 	}
-	return s.comments.Delete(ctx, commentID)
+	if err := cond.check(comment.Version); err != nil {
+		return err
+	}
+	if s.threads != nil {
+		applied, err := s.threads.SoftDelete(ctx, commentID, comment.Version)
+		if err != nil {
+			return err
+		}
+		if !applied {
+			return cond.staleWrite()
+		}
+	} else if err := s.comments.Delete(ctx, commentID); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, AuditEntry{
+		ActorID: userID, Action: AuditCommentDeleted, TargetType: auditTargetComment, TargetID: commentID, TeamID: task.TeamID,
+		Payload: map[string]any{"task_id": task.ID, "by_author": isCommentAuthor(*comment, userID)},
+	})
+	return nil
 }
 
 func (s *TaskService) GetTaskHistory(ctx context.Context, userID, taskID int64, limit, offset int) ([]repository.TaskHistory, int64, error) {
@@ -482,6 +825,33 @@ func (s *TaskService) GetTaskHistory(ctx context.Context, userID, taskID int64,
 	return s.history.ListByTask(ctx, taskID, limit, offset)
 }
 
+func (s *TaskService) UserSummaries(ctx context.Context, ids []int64) (map[int64]repository.UserSummary, error) {
+	out := make(map[int64]repository.UserSummary)
+	if s.users == nil {
+		return out, nil
+	}
+	seen := make(map[int64]bool, len(ids))
+	unique := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if id <= 0 || seen[id] {
+			continue
+		}
+		seen[id] = true
+		unique = append(unique, id)
+	}
+	if len(unique) == 0 {
+		return out, nil
+	}
+	items, err := s.users.ListSummariesByIDs(ctx, unique)
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range items {
+		out[u.ID] = u
+	}
+	return out, nil
+}
+
 func (s *TaskService) parseTaskPatch(ctx context.Context, teamID int64, raw map[string]json.RawMessage) (map[string]any, error) {
 	parsed := make(map[string]any, len(raw))
 	for key, val := range raw {
@@ -543,6 +913,26 @@ func (s *TaskService) parseTaskPatch(ctx context.Context, teamID int64, raw map[
 				return nil, ErrBadRequest
 			}
 			parsed[key] = &tm
+		case "original_estimate_minutes", "remaining_estimate_minutes":
+			var v *int64
+			if err := json.Unmarshal(val, &v); err != nil {
+				return nil, ErrBadRequest
+			}
+			if v != nil && !isValidEstimate(*v) {
+				return nil, ErrBadRequest
+			}
+			parsed[key] = v
+		case "sprint_id":
+			var v *int64
+			if err := json.Unmarshal(val, &v); err != nil {
+				return nil, ErrBadRequest
+			}
+			if v != nil {
+				if err := s.checkTaskSprint(ctx, teamID, *v); err != nil {
+					return nil, err
+				}
+			}
+			parsed[key] = v
 		}
 	}
 	if len(parsed) == 0 {
@@ -636,6 +1026,26 @@ func buildTaskDiffEntries(task repository.Task, userID int64, parsed map[string]
 			}
 			updates[key] = *newPtr
 			entries = append(entries, taskHistoryEntry(task.ID, userID, key, oldValue, newDate))
+		case "original_estimate_minutes", "remaining_estimate_minutes", "sprint_id":
+			current := taskNullInt64Field(task, key)
+			var oldValue any
+			if current.Valid {
+				oldValue = current.Int64
+			}
+			newPtr := val.(*int64)
+			if newPtr == nil {
+				if !current.Valid {
+					continue
+				}
+				updates[key] = nil
+				entries = append(entries, taskHistoryEntry(task.ID, userID, key, oldValue, nil))
+				continue
+			}
+			if current.Valid && current.Int64 == *newPtr {
+				continue
+			}
+			updates[key] = *newPtr
+			entries = append(entries, taskHistoryEntry(task.ID, userID, key, oldValue, *newPtr))
 		}
 	}
 
@@ -657,12 +1067,19 @@ func taskHistoryEntry(taskID, userID int64, field string, oldValue, newValue any
 func taskDeleteSnapshot(task repository.Task) (*json.RawMessage, error) {
 	payload := map[string]any{
 		"id":          task.ID,
+		"team_id":     task.TeamID,
 		"title":       task.Title,
 		"description": nil,
 		"status":      task.Status,
 		"assignee_id": nil,
 		"priority":    task.Priority,
 		"due_date":    nil,
+		"created_by":  nil,
+		"created_at":  task.CreatedAt.UTC().Format(time.RFC3339Nano),
+
+		"original_estimate_minutes":  nil,
+		"remaining_estimate_minutes": nil,
+		"sprint_id":                  nil,
 	}
 	if task.Description.Valid {
 		payload["description"] = task.Description.String
@@ -673,6 +1090,18 @@ func taskDeleteSnapshot(task repository.Task) (*json.RawMessage, error) {
 	if task.DueDate.Valid {
 		payload["due_date"] = task.DueDate.Time.Format("2006-01-02")
 	}
+	if task.CreatedBy.Valid {
+		payload["created_by"] = task.CreatedBy.Int64
+	}
+	if task.OriginalEstimate.Valid {
+		payload["original_estimate_minutes"] = task.OriginalEstimate.Int64
+	}
+	if task.RemainingEstimate.Valid {
+		payload["remaining_estimate_minutes"] = task.RemainingEstimate.Int64
+	}
+	if task.SprintID.Valid {
+		payload["sprint_id"] = task.SprintID.Int64
+	}
 
 	b, err := json.Marshal(payload)
 	if err != nil {
@@ -682,6 +1111,23 @@ func taskDeleteSnapshot(task repository.Task) (*json.RawMessage, error) {
 	return &raw, nil
 }
 
+func taskNullInt64Field(task repository.Task, key string) sql.NullInt64 {
+	switch key {
+	case "original_estimate_minutes":
+		return task.OriginalEstimate
+	case "remaining_estimate_minutes":
+		return task.RemainingEstimate
+	case "sprint_id":
+		return task.SprintID
+	default:
+		return sql.NullInt64{}
+	}
+}
+
+func isCommentAuthor(c repository.TaskComment, userID int64) bool {
+	return c.UserID.Valid && c.UserID.Int64 == userID
+}
+
 func mustJSON(v any) *json.RawMessage {
 	b, _ := json.Marshal(v)
 	raw := json.RawMessage(b)
@@ -690,7 +1136,8 @@ func mustJSON(v any) *json.RawMessage {
 
 func isKnownTaskField(field string) bool {
 	switch field {
-	case "title", "description", "status", "assignee_id", "priority", "due_date":
+	case "title", "description", "status", "assignee_id", "priority", "due_date",
+		"original_estimate_minutes", "remaining_estimate_minutes", "sprint_id":
 		return true
 	default:
 		return false
@@ -702,10 +1149,11 @@ func allowedTaskFields(role string) map[string]bool {
 	case RoleOwner, RoleAdmin:
 		return map[string]bool{
 			"title": true, "description": true, "status": true, "assignee_id": true, "priority": true, "due_date": true,
+			"original_estimate_minutes": true, "remaining_estimate_minutes": true, "sprint_id": true,
 		}
 	case RoleMember:
 		return map[string]bool{
-			"status": true, "assignee_id": true,
+			"status": true, "assignee_id": true, "remaining_estimate_minutes": true,
 		}
 	default:
 		return map[string]bool{}
@@ -719,3 +1167,7 @@ func isValidStatus(v string) bool {
 func isValidPriority(v string) bool {
 	return v == "low" || v == "medium" || v == "high"
 }
+
+func isValidEstimate(minutes int64) bool {
+	return minutes >= 0 && minutes <= maxEstimateMinutes
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	devents "MKK-Luna/internal/domain/events"
+	"MKK-Luna/internal/repository"
+)
+
+const (
+	TaskEventUpdated        = "task.updated"
+	TaskEventCommentCreated = "task.comment_created"
+)
+
+type taskWatcherRepo interface {
+	Add(ctx context.Context, taskID, userID int64) error
+	AddTx(ctx context.Context, tx *sqlx.Tx, taskID, userID int64) error
+	Remove(ctx context.Context, taskID, userID int64) error
+	ListByTask(ctx context.Context, taskID int64) ([]repository.TaskWatcher, error)
+	ListMemberIDsByTask(ctx context.Context, taskID int64) ([]int64, error)
+}
+
+func WithWatchers(watchers taskWatcherRepo) TaskServiceOption {
+	return func(s *TaskService) { s.watchers = watchers }
+}
+
+// taskTagCache drops cached task ETags after changes that leave the task row alone.
+type taskTagCache interface {
+	InvalidateTask(ctx context.Context, taskID int64) error
+}
+
+func WithTaskTagCache(cache taskTagCache) TaskServiceOption {
+	return func(s *TaskService) { s.tagCache = cache }
+}
+
+func WithTaskEvents(events devents.TaskEventPublisher) TaskServiceOption {
+	return func(s *TaskService) { s.events = events }
+}
+
+func (s *TaskService) WatchTask(ctx context.Context, userID, taskID int64) error {
+	if s.watchers == nil {
+		return ErrUnavailable
+	}
+	if _, err := s.GetTask(ctx, userID, taskID); err != nil {
+		return err
+	}
+	if err := s.watchers.Add(ctx, taskID, userID); err != nil {
+		return err
+	}
+	s.invalidateTaskTag(ctx, taskID)
+	return nil
+}
+
+func (s *TaskService) UnwatchTask(ctx context.Context, userID, taskID int64) error {
+	if s.watchers == nil {
+		return ErrUnavailable
+	}
+	if _, err := s.GetTask(ctx, userID, taskID); err != nil {
+		return err
+	}
+	if err := s.watchers.Remove(ctx, taskID, userID); err != nil {
+		return err
+	}
+	s.invalidateTaskTag(ctx, taskID)
+	return nil
+}
+
+func (s *TaskService) ListWatchers(ctx context.Context, userID, taskID int64) ([]repository.TaskWatcher, error) {
+	if _, err := s.GetTask(ctx, userID, taskID); err != nil {
+		return nil, err
+	}
+	if s.watchers == nil {
+		return nil, nil
+	}
+	return s.watchers.ListByTask(ctx, taskID)
+}
+
+// Auto-watching outside a transaction is best effort: the primary write has already succeeded.
+func (s *TaskService) autoWatch(ctx context.Context, taskID int64, userIDs ...int64) {
+	if s.watchers == nil {
+		return
+	}
+	for _, id := range userIDs {
+		if id > 0 {
+			_ = s.watchers.Add(ctx, taskID, id)
+		}
+	}
+	s.invalidateTaskTag(ctx, taskID)
+}
+
+// invalidateTaskTag is best effort: a stale tag only lives until the cache TTL.
+func (s *TaskService) invalidateTaskTag(ctx context.Context, taskID int64) {
+	if s.tagCache == nil {
+		return
+	}
+	if err := s.tagCache.InvalidateTask(ctx, taskID); err != nil {
+		s.logger.Warn("task etag not invalidated", "err", err, "task_id", taskID)
+	}
+}
+
+// notifyWatchers runs after the write has committed, so failures are only logged. Watchers
+// who have left the team are not notified.
+func (s *TaskService) notifyWatchers(ctx context.Context, ev devents.TaskEvent) {
+	if s.events == nil || s.watchers == nil {
+		return
+	}
+	watchers, err := s.watchers.ListMemberIDsByTask(ctx, ev.TaskID)
+	if err != nil {
+		s.logger.Warn("task watchers not loaded", "err", err, "task_id", ev.TaskID)
+		return
+	}
+	for _, id := range watchers {
+		if id != ev.ActorID {
+			ev.Recipients = append(ev.Recipients, id)
+		}
+	}
+	if len(ev.Recipients) == 0 {
+		return
+	}
+	ev.OccurredAt = time.Now().UTC()
+	if err := s.events.PublishTaskEvent(ctx, ev); err != nil {
+		s.logger.Warn("task event not published", "err", err, "type", ev.Type, "task_id", ev.TaskID)
+	}
+}
+
+func changedFields(updates map[string]any) []string {
+	fields := make([]string, 0, len(updates))
+	for key := range updates {
+		fields = append(fields, key)
+	}
+	sort.Strings(fields)
+	return fields
+}
@@ -0,0 +1,487 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jmoiron/sqlx"
+
+	"MKK-Luna/internal/repository"
+)
+
+const (
+	ImportSourceCSV  = "csv"
+	ImportSourceJSON = "json"
+
+	ImportActionCreate = "create"
+	ImportActionSkip   = "skip"
+	ImportActionError  = "error"
+
+	maxImportRows          = 5000
+	maxImportExternalIDLen = 255
+)
+
+// importFields are the task fields an import row can carry; CSV mappings use these names.
+var importFields = map[string]bool{
+	"external_id": true, "title": true, "description": true, "status": true, "priority": true,
+	"assignee_email": true, "due_date": true, "estimate_minutes": true,
+}
+
+// importJSONAliases lists, per field, the keys common tracker exports use for it.
+var importJSONAliases = map[string][]string{
+	"external_id":      {"external_id", "key", "id", "gid", "number"},
+	"title":            {"title", "summary", "name"},
+	"description":      {"description", "body", "notes"},
+	"status":           {"status", "state"},
+	"priority":         {"priority"},
+	"assignee_email":   {"assignee_email", "assignee"},
+	"due_date":         {"due_date", "duedate", "due_on", "due"},
+	"estimate_minutes": {"estimate_minutes"},
+}
+
+var importStatuses = map[string]string{
+	"": "todo", "todo": "todo", "to_do": "todo", "open": "todo", "new": "todo", "backlog": "todo", "reopened": "todo",
+	"in_progress": "in_progress", "doing": "in_progress", "started": "in_progress", "in_review": "in_progress", "review": "in_progress",
+	"done": "done", "closed": "done", "resolved": "done", "complete": "done", "completed": "done",
+}
+
+var importPriorities = map[string]string{
+	"": "medium", "medium": "medium", "normal": "medium", "p2": "medium",
+	"high": "high", "highest": "high", "urgent": "high", "critical": "high", "blocker": "high", "p0": "high", "p1": "high",
+	"low": "low", "lowest": "low", "minor": "low", "trivial": "low", "p3": "low", "p4": "low",
+}
+
+type taskImportRepo interface {
+	TaskIDs(ctx context.Context, teamID int64, externalIDs []string) (map[string]int64, error)
+	CreateTx(ctx context.Context, tx *sqlx.Tx, teamID int64, externalID string, taskID int64, source string, importedBy int64) error
+}
+
+type taskTxCreator interface {
+	CreateTx(ctx context.Context, tx *sqlx.Tx, t repository.Task) (int64, error)
+}
+
+type taskImporter struct {
+	imports taskImportRepo
+	tasks   taskTxCreator
+	users   UserStore
+}
+
+// WithImports enables bulk task import; assignees are matched to users by email.
+func WithImports(imports taskImportRepo, tasks taskTxCreator, users UserStore) TaskServiceOption {
+	return func(s *TaskService) { s.importer = &taskImporter{imports: imports, tasks: tasks, users: users} }
+}
+
+// ImportTask is one parsed input row. Values stay raw text until ImportTasks checks them,
+// so every problem can be reported against its row.
+type ImportTask struct {
+	Row             int
+	ExternalID      string
+	Title           string
+	Description     string
+	Status          string
+	Priority        string
+	AssigneeEmail   string
+	DueDate         string
+	EstimateMinutes string
+}
+
+type ImportRowResult struct {
+	Row        int
+	ExternalID string
+	Action     string
+	TaskID     *int64
+	Errors     []string
+	Warnings   []string
+}
+
+// ImportReport describes what an import did, or for a dry run what it would do. An import
+// with any failed row writes nothing.
+type ImportReport struct {
+	DryRun  bool
+	Total   int
+	Created int
+	Skipped int
+	Failed  int
+	Rows    []ImportRowResult
+}
+
+// ParseImportCSV reads a CSV file with a header line. mapping maps import field names to
+// header names; without one, headers must be the field names themselves.
+func ParseImportCSV(r io.Reader, mapping map[string]string) ([]ImportTask, error) {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err != nil {
+		return nil, ErrBadRequest
+	}
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if len(mapping) == 0 {
+		mapping = make(map[string]string, len(importFields))
+		for field := range importFields {
+			if _, ok := columns[field]; ok {
+				mapping[field] = field
+			}
+		}
+	}
+	index := make(map[string]int, len(mapping))
+	for field, name := range mapping {
+		i, ok := columns[strings.ToLower(strings.TrimSpace(name))]
+		if !importFields[field] || !ok {
+			return nil, ErrBadRequest
+		}
+		index[field] = i
+	}
+	if _, ok := index["title"]; !ok {
+		return nil, ErrBadRequest
+	}
+
+	var rows []ImportTask
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, ErrBadRequest
+		}
+		if len(rows) == maxImportRows {
+			return nil, ErrBadRequest
+		}
+		line, _ := cr.FieldPos(0)
+		values := make(map[string]string, len(index))
+		for field, i := range index {
+			values[field] = record[i]
+		}
+		rows = append(rows, importTaskFromValues(line, values))
+	}
+	return rows, nil
+}
+
+// ParseImportJSON reads a JSON array of tasks, or an object holding one under "tasks" or
+// "issues". Keys follow importJSONAliases, a nested "fields" object is read as well, and
+// object values such as {"name": "Done"} or {"emailAddress": "..."} are unwrapped.
+func ParseImportJSON(r io.Reader) ([]ImportTask, error) {
+	var raw json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, ErrBadRequest
+	}
+	var items []map[string]json.RawMessage
+	if trimmed := bytes.TrimSpace(raw); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return nil, ErrBadRequest
+		}
+	} else {
+		var wrapper struct {
+			Tasks  []map[string]json.RawMessage `json:"tasks"`
+			Issues []map[string]json.RawMessage `json:"issues"`
+		}
+		if err := json.Unmarshal(raw, &wrapper); err != nil {
+			return nil, ErrBadRequest
+		}
+		items = append(wrapper.Tasks, wrapper.Issues...)
+	}
+	if len(items) > maxImportRows {
+		return nil, ErrBadRequest
+	}
+
+	rows := make([]ImportTask, 0, len(items))
+	for i, item := range items {
+		if nested, ok := item["fields"]; ok {
+			var fields map[string]json.RawMessage
+			if json.Unmarshal(nested, &fields) == nil {
+				for k, v := range fields {
+					if _, exists := item[k]; !exists {
+						item[k] = v
+					}
+				}
+			}
+		}
+		values := make(map[string]string, len(importJSONAliases))
+		for field, keys := range importJSONAliases {
+			for _, key := range keys {
+				if v, ok := item[key]; ok {
+					values[field] = importJSONText(v)
+					break
+				}
+			}
+		}
+		rows = append(rows, importTaskFromValues(i+1, values))
+	}
+	return rows, nil
+}
+
+// ImportTasks creates the rows as tasks of the team, owners and admins only. Rows whose
+// external ID was imported before are skipped; a row with errors fails the whole import.
+// Each created task gets an "imported" history entry naming its source and external ID.
+func (s *TaskService) ImportTasks(ctx context.Context, userID, teamID int64, source string, rows []ImportTask, dryRun bool) (*ImportReport, error) {
+	if s.importer == nil || s.db == nil || s.history == nil {
+		return nil, ErrUnavailable
+	}
+	if source != ImportSourceCSV && source != ImportSourceJSON {
+		return nil, ErrBadRequest
+	}
+	if len(rows) == 0 || len(rows) > maxImportRows {
+		return nil, ErrBadRequest
+	}
+	team, err := s.teams.GetByID(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	if team == nil {
+		return nil, ErrNotFound
+	}
+	role, ok, err := s.members.GetRole(ctx, teamID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok || (role != RoleOwner && role != RoleAdmin) {
+		return nil, ErrForbidden
+	}
+
+	externalIDs := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if id := strings.TrimSpace(row.ExternalID); id != "" {
+			externalIDs = append(externalIDs, id)
+		}
+	}
+	existing, err := s.importer.imports.TaskIDs(ctx, teamID, externalIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ImportReport{DryRun: dryRun, Total: len(rows), Rows: make([]ImportRowResult, len(rows))}
+	tasks := make([]*repository.Task, len(rows))
+	seen := make(map[string]int, len(rows))
+	assignees := map[string]*int64{}
+	for i, row := range rows {
+		res := ImportRowResult{Row: row.Row, ExternalID: strings.TrimSpace(row.ExternalID)}
+		switch {
+		case res.ExternalID == "":
+			res.Errors = append(res.Errors, "external_id is required")
+		case utf8.RuneCountInString(res.ExternalID) > maxImportExternalIDLen:
+			res.Errors = append(res.Errors, "external_id is too long")
+		case seen[res.ExternalID] > 0:
+			res.Errors = append(res.Errors, "external_id repeats row "+strconv.Itoa(seen[res.ExternalID]))
+		}
+		if res.ExternalID != "" && seen[res.ExternalID] == 0 {
+			seen[res.ExternalID] = row.Row
+		}
+		if taskID, ok := existing[res.ExternalID]; ok && len(res.Errors) == 0 {
+			res.Action = ImportActionSkip
+			res.TaskID = &taskID
+			report.Skipped++
+			report.Rows[i] = res
+			continue
+		}
+
+		task, err := s.importTaskRow(ctx, teamID, userID, row, assignees, &res)
+		if err != nil {
+			return nil, err
+		}
+		if len(res.Errors) > 0 {
+			res.Action = ImportActionError
+			report.Failed++
+		} else {
+			res.Action = ImportActionCreate
+			tasks[i] = task
+		}
+		report.Rows[i] = res
+	}
+	if dryRun || report.Failed > 0 {
+		return report, nil
+	}
+
+	if err := s.createImportedTasks(ctx, userID, teamID, source, tasks, report); err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, AuditEntry{
+		ActorID:    userID,
+		Action:     AuditTasksImported,
+		TargetType: auditTargetTeam,
+		TargetID:   teamID,
+		TeamID:     teamID,
+		Payload:    map[string]any{"source": source, "created": report.Created, "skipped": report.Skipped},
+	})
+	for _, task := range tasks {
+		if task != nil && task.AssigneeID.Valid {
+			s.autoWatch(ctx, task.ID, task.AssigneeID.Int64)
+		}
+	}
+	return report, nil
+}
+
+func (s *TaskService) importTaskRow(ctx context.Context, teamID, userID int64, row ImportTask, assignees map[string]*int64, res *ImportRowResult) (*repository.Task, error) {
+	task := &repository.Task{TeamID: teamID, CreatedBy: sql.NullInt64{Int64: userID, Valid: true}}
+
+	task.Title = strings.TrimSpace(row.Title)
+	if task.Title == "" {
+		res.Errors = append(res.Errors, "title is required")
+	} else if utf8.RuneCountInString(task.Title) > maxTaskTitleLen {
+		res.Errors = append(res.Errors, "title is too long")
+	}
+	if strings.TrimSpace(row.Description) != "" {
+		task.Description = sql.NullString{String: row.Description, Valid: true}
+	}
+
+	status, ok := importStatuses[importWord(row.Status)]
+	if !ok {
+		res.Errors = append(res.Errors, "unknown status "+strconv.Quote(row.Status))
+	}
+	task.Status = status
+	priority, ok := importPriorities[importWord(row.Priority)]
+	if !ok {
+		res.Errors = append(res.Errors, "unknown priority "+strconv.Quote(row.Priority))
+	}
+	task.Priority = priority
+
+	if v := strings.TrimSpace(row.DueDate); v != "" {
+		due, err := parseImportDate(v)
+		if err != nil {
+			res.Errors = append(res.Errors, "due_date must be YYYY-MM-DD or RFC3339")
+		} else {
+			task.DueDate = sql.NullTime{Time: due, Valid: true}
+		}
+	}
+	if v := strings.TrimSpace(row.EstimateMinutes); v != "" {
+		minutes, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || !isValidEstimate(minutes) {
+			res.Errors = append(res.Errors, "estimate_minutes is invalid")
+		} else {
+			task.OriginalEstimate = sql.NullInt64{Int64: minutes, Valid: true}
+			task.RemainingEstimate = task.OriginalEstimate
+		}
+	}
+
+	// Unknown assignees do not fail the row: the task is imported unassigned.
+	if email := strings.ToLower(strings.TrimSpace(row.AssigneeEmail)); email != "" {
+		assigneeID, cached := assignees[email]
+		if !cached {
+			user, err := s.importer.users.GetByEmail(ctx, email)
+			if err != nil {
+				return nil, err
+			}
+			if user != nil {
+				ok, err := s.members.IsMember(ctx, teamID, user.ID)
+				if err != nil {
+					return nil, err
+				}
+				if ok {
+					assigneeID = &user.ID
+				}
+			}
+			assignees[email] = assigneeID
+		}
+		if assigneeID != nil {
+			task.AssigneeID = sql.NullInt64{Int64: *assigneeID, Valid: true}
+		} else {
+			res.Warnings = append(res.Warnings, "assignee "+email+" is not a team member; task left unassigned")
+		}
+	}
+	return task, nil
+}
+
+func (s *TaskService) createImportedTasks(ctx context.Context, userID, teamID int64, source string, tasks []*repository.Task, report *ImportReport) error {
+	tx, err := s.db.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	adding := map[string]int{}
+	for _, task := range tasks {
+		if task != nil {
+			adding[task.Status]++
+		}
+	}
+	for _, status := range BoardStatuses {
+		if adding[status] == 0 {
+			continue
+		}
+		if err := s.checkColumnLimit(ctx, tx, teamID, status, adding[status]); err != nil {
+			return err
+		}
+	}
+
+	for i, task := range tasks {
+		if task == nil {
+			continue
+		}
+		res := &report.Rows[i]
+		id, err := s.importer.tasks.CreateTx(ctx, tx, *task)
+		if err != nil {
+			return err
+		}
+		// A concurrent import of the same rows wins the mapping; this one backs out.
+		if err := s.importer.imports.CreateTx(ctx, tx, teamID, res.ExternalID, id, source, userID); err != nil {
+			if isDuplicate(err) {
+				return ErrConflict
+			}
+			return err
+		}
+		entry := taskHistoryEntry(id, userID, "imported", nil, map[string]string{"source": source, "external_id": res.ExternalID})
+		if err := s.history.CreateBatchTx(ctx, tx, []repository.TaskHistoryCreate{entry}); err != nil {
+			return err
+		}
+		task.ID = id
+		res.TaskID = &task.ID
+		report.Created++
+	}
+	return tx.Commit()
+}
+
+func importTaskFromValues(row int, v map[string]string) ImportTask {
+	return ImportTask{
+		Row: row, ExternalID: v["external_id"], Title: v["title"], Description: v["description"], Status: v["status"],
+		Priority: v["priority"], AssigneeEmail: v["assignee_email"], DueDate: v["due_date"], EstimateMinutes: v["estimate_minutes"],
+	}
+}
+
+// importJSONText flattens a JSON value to text; objects yield their name or email field.
+func importJSONText(raw json.RawMessage) string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	var n json.Number
+	if json.Unmarshal(raw, &n) == nil {
+		return n.String()
+	}
+	var obj map[string]json.RawMessage
+	if json.Unmarshal(raw, &obj) == nil {
+		for _, key := range []string{"emailAddress", "email", "name", "value"} {
+			if v, ok := obj[key]; ok {
+				return importJSONText(v)
+			}
+		}
+	}
+	return ""
+}
+
+func importWord(v string) string {
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(strings.ToLower(strings.TrimSpace(v)))
+}
+
+func parseImportDate(v string) (time.Time, error) {
+	if tm, err := time.Parse("2006-01-02", v); err == nil {
+		return tm, nil
+	}
+	tm, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, errors.New("invalid date")
+	}
+	return time.Date(tm.Year(), tm.Month(), tm.Day(), 0, 0, 0, 0, time.UTC), nil
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+
+	"MKK-Luna/internal/repository"
+)
+
+type fakeTaskImportRepo struct {
+	existing map[string]int64
+	created  map[string]int64
+}
+
+func (f *fakeTaskImportRepo) TaskIDs(context.Context, int64, []string) (map[string]int64, error) {
+	return f.existing, nil
+}
+func (f *fakeTaskImportRepo) CreateTx(_ context.Context, _ *sqlx.Tx, _ int64, externalID string, taskID int64, _ string, _ int64) error {
+	f.created[externalID] = taskID
+	return nil
+}
+
+type fakeTaskTxCreator struct {
+	tasks []repository.Task
+}
+
+func (f *fakeTaskTxCreator) CreateTx(_ context.Context, _ *sqlx.Tx, t repository.Task) (int64, error) {
+	f.tasks = append(f.tasks, t)
+	return int64(100 + len(f.tasks)), nil
+}
+
+func TestParseImportCSV(t *testing.T) {
+	in := "\ufeffKey,Summary,State,Due\nA-1,First,In Progress,2026-03-01\nA-2,\"Second, quoted\",,\n"
+	rows, err := ParseImportCSV(strings.NewReader(in), map[string]string{
+		"external_id": "Key", "title": "Summary", "status": "State", "due_date": "Due",
+	})
+	if err != nil || len(rows) != 2 {
+		t.Fatalf("rows=%+v err=%v", rows, err)
+	}
+	if rows[0].Row != 2 || rows[0].ExternalID != "A-1" || rows[0].Status != "In Progress" || rows[0].DueDate != "2026-03-01" {
+		t.Fatalf("unexpected first row %+v", rows[0])
+	}
+	if rows[1].Row != 3 || rows[1].Title != "Second, quoted" {
+		t.Fatalf("unexpected second row %+v", rows[1])
+	}
+
+	if _, err := ParseImportCSV(strings.NewReader("a,b\n1,2\n"), map[string]string{"title": "missing"}); err != ErrBadRequest {
+		t.Fatalf("expected ErrBadRequest for unknown header, got %v", err)
+	}
+}
+
+func TestParseImportJSON(t *testing.T) {
+	in := `{"issues":[{"key":"PRJ-1","fields":{"summary":"Fix login","priority":{"name":"Highest"},"assignee":{"emailAddress":"a@x.io"}}},
+		{"id":7,"name":"Second","state":"closed"}]}`
+	rows, err := ParseImportJSON(strings.NewReader(in))
+	if err != nil || len(rows) != 2 {
+		t.Fatalf("rows=%+v err=%v", rows, err)
+	}
+	if rows[0].ExternalID != "PRJ-1" || rows[0].Title != "Fix login" || rows[0].Priority != "Highest" || rows[0].AssigneeEmail != "a@x.io" {
+		t.Fatalf("unexpected first row %+v", rows[0])
+	}
+	if rows[1].ExternalID != "7" || rows[1].Status != "closed" {
+		t.Fatalf("unexpected second row %+v", rows[1])
+	}
+	if _, err := ParseImportJSON(strings.NewReader(`"x"`)); err != ErrBadRequest {
+		t.Fatalf("expected ErrBadRequest, got %v", err)
+	}
+}
+
+func newImportTestService(t *testing.T, imports *fakeTaskImportRepo, creator *fakeTaskTxCreator, history *fakeHistoryRepo) (*TaskService, func() error) {
+	db, mock := newMockDB(t)
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	teams := &fakeTeamRepo{getByID: func(_ context.Context, id int64) (*repository.Team, error) {
+		return &repository.Team{ID: id}, nil
+	}}
+	members := &fakeMemberRepo{role: RoleAdmin, hasRole: true}
+	users := &fakeUsers{user: &repository.User{ID: 5, Email: "dev@x.io"}}
+	svc := NewTaskService(db, &fakeTaskRepo{}, teams, members, &fakeCommentRepo{}, history, WithImports(imports, creator, users))
+	return svc, mock.ExpectationsWereMet
+}
+
+func TestTaskService_ImportTasks_DryRun(t *testing.T) {
+	imports := &fakeTaskImportRepo{existing: map[string]int64{"A-1": 9}, created: map[string]int64{}}
+	creator := &fakeTaskTxCreator{}
+	svc, _ := newImportTestService(t, imports, creator, &fakeHistoryRepo{})
+
+	rows := []ImportTask{
+		{Row: 2, ExternalID: "A-1", Title: "Old"},
+		{Row: 3, ExternalID: "A-2", Title: "New", AssigneeEmail: "ghost@x.io"},
+		{Row: 4, ExternalID: "A-2", Title: "Again"},
+		{Row: 5, ExternalID: "A-3", Title: "", Status: "weird"},
+	}
+	report, err := svc.ImportTasks(context.Background(), 1, 10, ImportSourceCSV, rows, true)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if report.Skipped != 1 || report.Failed != 2 || report.Created != 0 || len(creator.tasks) != 0 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if r := report.Rows[0]; r.Action != ImportActionSkip || r.TaskID == nil || *r.TaskID != 9 {
+		t.Fatalf("expected skip, got %+v", r)
+	}
+	if r := report.Rows[1]; r.Action != ImportActionCreate || len(r.Warnings) != 1 {
+		t.Fatalf("expected create with warning, got %+v", r)
+	}
+	if r := report.Rows[2]; r.Action != ImportActionError || !strings.Contains(r.Errors[0], "repeats row 3") {
+		t.Fatalf("expected repeat error, got %+v", r)
+	}
+	if r := report.Rows[3]; r.Action != ImportActionError || len(r.Errors) != 2 {
+		t.Fatalf("expected two errors, got %+v", r)
+	}
+}
+
+func TestTaskService_ImportTasks_Create(t *testing.T) {
+	imports := &fakeTaskImportRepo{created: map[string]int64{}}
+	creator := &fakeTaskTxCreator{}
+	var entries []repository.TaskHistoryCreate
+	history := &fakeHistoryRepo{createBatchTx: func(_ context.Context, _ *sqlx.Tx, e []repository.TaskHistoryCreate) error {
+		entries = append(entries, e...)
+		return nil
+	}}
+	svc, met := newImportTestService(t, imports, creator, history)
+
+	rows := []ImportTask{
+		{Row: 2, ExternalID: "A-1", Title: "One", Status: "Done", Priority: "urgent", AssigneeEmail: "DEV@x.io", DueDate: "2026-04-01T15:00:00Z", EstimateMinutes: "90"},
+		{Row: 3, ExternalID: "A-2", Title: "Two"},
+	}
+	report, err := svc.ImportTasks(context.Background(), 1, 10, ImportSourceJSON, rows, false)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if report.Created != 2 || imports.created["A-1"] != 101 || imports.created["A-2"] != 102 || len(entries) != 2 {
+		t.Fatalf("unexpected report=%+v created=%v entries=%d", report, imports.created, len(entries))
+	}
+	first := creator.tasks[0]
+	if first.Status != "done" || first.Priority != "high" || first.AssigneeID.Int64 != 5 ||
+		first.DueDate.Time.Format("2006-01-02") != "2026-04-01" || first.OriginalEstimate.Int64 != 90 {
+		t.Fatalf("unexpected task %+v", first)
+	}
+	if second := creator.tasks[1]; second.Status != "todo" || second.Priority != "medium" || second.AssigneeID.Valid {
+		t.Fatalf("unexpected defaults %+v", second)
+	}
+	if entries[0].FieldName != "imported" {
+		t.Fatalf("unexpected history %+v", entries[0])
+	}
+	if err := met(); err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+}
+
+func TestTaskService_ImportTasks_Forbidden(t *testing.T) {
+	svc, _ := newImportTestService(t, &fakeTaskImportRepo{}, &fakeTaskTxCreator{}, &fakeHistoryRepo{})
+	svc.members = &fakeMemberRepo{role: RoleMember, hasRole: true}
+	if _, err := svc.ImportTasks(context.Background(), 1, 10, ImportSourceCSV, []ImportTask{{Row: 2, ExternalID: "x", Title: "t"}}, true); err != ErrForbidden {
+		t.Fatalf("expected ErrForbidden, got %v", err)
+	}
+}
+
+func TestTaskService_ImportTasks_WIPLimit(t *testing.T) {
+	creator := &fakeTaskTxCreator{}
+	svc, _ := newImportTestService(t, &fakeTaskImportRepo{created: map[string]int64{}}, creator, &fakeHistoryRepo{})
+	svc.board = &fakeBoardRepo{limits: map[string]int{"in_progress": 3}, count: 2}
+
+	rows := []ImportTask{
+		{Row: 2, ExternalID: "A-1", Title: "One", Status: "in_progress"},
+		{Row: 3, ExternalID: "A-2", Title: "Two", Status: "in_progress"},
+	}
+	if _, err := svc.ImportTasks(context.Background(), 1, 10, ImportSourceCSV, rows, false); err != ErrConflict {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+	if len(creator.tasks) != 0 {
+		t.Fatalf("expected no tasks, got %d", len(creator.tasks))
+	}
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"MKK-Luna/internal/repository"
+)
+
+// requiredTaskFields must be present in a full replacement; every other field of
+// revertibleTaskFields is cleared when omitted.
+var requiredTaskFields = []string{"title", "status", "priority"}
+
+// TaskPatchOp is one RFC 6902 operation. Only test, replace and remove are supported,
+// and paths address top-level task fields ("/status").
+type TaskPatchOp struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// ReplaceTask applies a PUT body as the new state of the task. Fields that already hold
+// the sent value are dropped before the role check, so members can send back a full
+// representation as long as they only change the fields they are allowed to.
+func (s *TaskService) ReplaceTask(ctx context.Context, userID, taskID int64, raw map[string]json.RawMessage, cond *Precondition) (int64, error) {
+	for key := range raw {
+		if !isKnownTaskField(key) {
+			return 0, ErrBadRequest
+		}
+	}
+	for _, field := range requiredTaskFields {
+		if _, ok := raw[field]; !ok {
+			return 0, ErrBadRequest
+		}
+	}
+	return s.updateTask(ctx, userID, taskID, cond, func(task repository.Task) (map[string]json.RawMessage, error) {
+		changed := make(map[string]json.RawMessage)
+		for _, field := range revertibleTaskFields {
+			val, ok := raw[field]
+			if !ok {
+				val = json.RawMessage("null")
+			}
+			if sameTaskFieldJSON(val, taskFieldJSON(task, field)) {
+				continue
+			}
+			changed[field] = val
+		}
+		return changed, nil
+	}, nil)
+}
+
+// ApplyTaskJSONPatch runs the operations in order against the task as locked by the
+// update, so a failing test op (ErrConflict) sees the same row the write would change.
+func (s *TaskService) ApplyTaskJSONPatch(ctx context.Context, userID, taskID int64, ops []TaskPatchOp, cond *Precondition) (int64, error) {
+	if len(ops) == 0 {
+		return 0, ErrBadRequest
+	}
+	for _, op := range ops {
+		if _, ok := taskPatchPath(op.Path); !ok {
+			return 0, ErrBadRequest
+		}
+		switch op.Op {
+		case "test", "replace":
+			if len(op.Value) == 0 {
+				return 0, ErrBadRequest
+			}
+		case "remove":
+		default:
+			return 0, ErrBadRequest
+		}
+	}
+	return s.updateTask(ctx, userID, taskID, cond, func(task repository.Task) (map[string]json.RawMessage, error) {
+		changed := make(map[string]json.RawMessage)
+		current := func(field string) json.RawMessage {
+			if v, ok := changed[field]; ok {
+				return v
+			}
+			return taskFieldJSON(task, field)
+		}
+		for _, op := range ops {
+			field, _ := taskPatchPath(op.Path)
+			switch op.Op {
+			case "test":
+				if !sameTaskFieldJSON(op.Value, current(field)) {
+					return nil, ErrConflict
+				}
+			case "replace":
+				changed[field] = op.Value
+			case "remove":
+				changed[field] = json.RawMessage("null")
+			}
+		}
+		return changed, nil
+	}, nil)
+}
+
+func taskPatchPath(path string) (string, bool) {
+	field, ok := strings.CutPrefix(path, "/")
+	if !ok || !isKnownTaskField(field) {
+		return "", false
+	}
+	return field, true
+}
+
+// sameTaskFieldJSON compares values rather than bytes, so spacing and number formatting
+// in the client's JSON do not matter.
+func sameTaskFieldJSON(a, b json.RawMessage) bool {
+	if bytes.Equal(a, b) {
+		return true
+	}
+	var av, bv any
+	if json.Unmarshal(a, &av) != nil || json.Unmarshal(b, &bv) != nil {
+		return false
+	}
+	return reflect.DeepEqual(av, bv)
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	"MKK-Luna/internal/repository"
+)
+
+func newPatchTestService(task *repository.Task, role string, updates *map[string]any) *TaskService {
+	taskRepo := &fakeTaskRepo{
+		getByID: func(context.Context, int64) (*repository.Task, error) { return task, nil },
+		update: func(_ context.Context, _ int64, fields map[string]any) error {
+			*updates = fields
+			return nil
+		},
+	}
+	members := &fakeMemberRepo{role: role, hasRole: true}
+	return NewTaskService(nil, taskRepo, &fakeTeamRepo{}, members, &fakeCommentRepo{}, &fakeHistoryRepo{})
+}
+
+func TestTaskService_ReplaceTask(t *testing.T) {
+	task := &repository.Task{
+		ID: 1, TeamID: 10, Title: "t", Status: "todo", Priority: "low",
+		Description: sql.NullString{String: "d", Valid: true},
+	}
+	var updates map[string]any
+	svc := newPatchTestService(task, RoleMember, &updates)
+
+	if _, err := svc.ReplaceTask(context.Background(), 1, 1, map[string]json.RawMessage{"status": json.RawMessage(`"done"`)}, nil); err != ErrBadRequest {
+		t.Fatalf("expected ErrBadRequest without required fields, got %v", err)
+	}
+
+	full := map[string]json.RawMessage{
+		"title": json.RawMessage(`"t"`), "status": json.RawMessage(`"done"`), "priority": json.RawMessage(`"low"`),
+		"description": json.RawMessage(`"d"`),
+	}
+	if _, err := svc.ReplaceTask(context.Background(), 1, 1, full, nil); err != nil {
+		t.Fatalf("member replacing only status: %v", err)
+	}
+	if len(updates) != 1 || updates["status"] != "done" {
+		t.Fatalf("expected only status to change, got %v", updates)
+	}
+
+	delete(full, "description")
+	if _, err := svc.ReplaceTask(context.Background(), 1, 1, full, nil); err != ErrForbidden {
+		t.Fatalf("omitted description clears it, which members may not do; got %v", err)
+	}
+}
+
+func TestTaskService_ApplyTaskJSONPatch(t *testing.T) {
+	task := &repository.Task{ID: 1, TeamID: 10, Title: "t", Status: "todo", Priority: "low"}
+	var updates map[string]any
+	svc := newPatchTestService(task, RoleOwner, &updates)
+
+	for _, ops := range [][]TaskPatchOp{
+		nil,
+		{{Op: "add", Path: "/title", Value: json.RawMessage(`"x"`)}},
+		{{Op: "replace", Path: "/unknown", Value: json.RawMessage(`1`)}},
+		{{Op: "replace", Path: "title", Value: json.RawMessage(`"x"`)}},
+		{{Op: "test", Path: "/title"}},
+	} {
+		if _, err := svc.ApplyTaskJSONPatch(context.Background(), 1, 1, ops, nil); err != ErrBadRequest {
+			t.Fatalf("expected ErrBadRequest for %+v, got %v", ops, err)
+		}
+	}
+
+	ops := []TaskPatchOp{
+		{Op: "test", Path: "/status", Value: json.RawMessage(`"in_progress"`)},
+		{Op: "replace", Path: "/status", Value: json.RawMessage(`"done"`)},
+	}
+	if _, err := svc.ApplyTaskJSONPatch(context.Background(), 1, 1, ops, nil); err != ErrConflict {
+		t.Fatalf("expected ErrConflict on failed test, got %v", err)
+	}
+	if updates != nil {
+		t.Fatalf("failed patch must not write, got %v", updates)
+	}
+
+	ops = []TaskPatchOp{
+		{Op: "test", Path: "/status", Value: json.RawMessage(`"todo"`)},
+		{Op: "replace", Path: "/status", Value: json.RawMessage(`"done"`)},
+		{Op: "test", Path: "/status", Value: json.RawMessage(` "done" `)},
+		{Op: "test", Path: "/assignee_id", Value: json.RawMessage(`null`)},
+		{Op: "remove", Path: "/description"},
+	}
+	if _, err := svc.ApplyTaskJSONPatch(context.Background(), 1, 1, ops, nil); err != nil {
+		t.Fatalf("patch: %v", err)
+	}
+	if len(updates) != 1 || updates["status"] != "done" {
+		t.Fatalf("expected status change only, got %v", updates)
+	}
+
+	if _, err := svc.ApplyTaskJSONPatch(context.Background(), 1, 1, []TaskPatchOp{{Op: "remove", Path: "/title"}}, nil); err != ErrBadRequest {
+		t.Fatalf("removing a required field must fail, got %v", err)
+	}
+}
@@ -0,0 +1,327 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"MKK-Luna/internal/repository"
+)
+
+const (
+	historyFieldTaskDeleted  = "task_deleted"
+	historyFieldTaskRestored = "task_restored"
+)
+
+// revertibleTaskFields lists the task_history fields that can be replayed onto a task.
+var revertibleTaskFields = []string{
+	"title", "description", "status", "priority", "assignee_id", "due_date",
+	"original_estimate_minutes", "remaining_estimate_minutes", "sprint_id",
+}
+
+type taskHistoryReplayRepo interface {
+	ListAllByTask(ctx context.Context, taskID int64) ([]repository.TaskHistory, error)
+	ListAllByTaskTx(ctx context.Context, tx *sqlx.Tx, taskID int64) ([]repository.TaskHistory, error)
+}
+
+type taskRestoreRepo interface {
+	GetDeletedForUpdateTx(ctx context.Context, tx *sqlx.Tx, taskID int64) (*repository.Task, error)
+	UndeleteTx(ctx context.Context, tx *sqlx.Tx, taskID int64, fields map[string]any) error
+}
+
+func WithTaskReplay(history taskHistoryReplayRepo, restore taskRestoreRepo) TaskServiceOption {
+	return func(s *TaskService) {
+		s.replay = history
+		s.restore = restore
+	}
+}
+
+// GetTaskAsOf rebuilds the task as it was at asOf by rolling later history entries back
+// from the current row. ErrNotFound is returned for moments before creation or while deleted.
+func (s *TaskService) GetTaskAsOf(ctx context.Context, userID, taskID int64, asOf time.Time) (*repository.Task, error) {
+	if s.replay == nil {
+		return nil, ErrUnavailable
+	}
+	task, err := s.GetTask(ctx, userID, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if asOf.Before(task.CreatedAt) {
+		return nil, ErrNotFound
+	}
+	history, err := s.replay.ListAllByTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	cut := len(history)
+	for cut > 0 && history[cut-1].CreatedAt.After(asOf) {
+		cut--
+	}
+	state, deleted, err := rollbackTaskHistory(*task, history[cut:])
+	if err != nil {
+		return nil, err
+	}
+	if deleted {
+		return nil, ErrNotFound
+	}
+	state.UpdatedAt = task.CreatedAt
+	if cut > 0 {
+		state.UpdatedAt = history[cut-1].CreatedAt
+	}
+	return &state, nil
+}
+
+// RevertTask restores the task to its state right after historyID was applied.
+// The difference is applied as a regular update, so role checks, validation and
+// history recording are the same as for PUT /tasks/{id}.
+func (s *TaskService) RevertTask(ctx context.Context, userID, taskID, historyID int64) (int64, error) {
+	if s.replay == nil {
+		return 0, ErrUnavailable
+	}
+	task, err := s.GetTask(ctx, userID, taskID)
+	if err != nil {
+		return 0, err
+	}
+	history, err := s.replay.ListAllByTask(ctx, taskID)
+	if err != nil {
+		return 0, err
+	}
+	idx := -1
+	for i, h := range history {
+		if h.ID == historyID {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return 0, ErrBadRequest
+	}
+
+	target, deleted, err := rollbackTaskHistory(*task, history[idx+1:])
+	if err != nil {
+		return 0, err
+	}
+	if deleted {
+		// The entry is the delete marker itself; there is no state to go back to.
+		return 0, ErrBadRequest
+	}
+	raw := make(map[string]json.RawMessage)
+	for _, field := range revertibleTaskFields {
+		want := taskFieldJSON(target, field)
+		if !bytes.Equal(want, taskFieldJSON(*task, field)) {
+			raw[field] = want
+		}
+	}
+	if len(raw) == 0 {
+		return task.TeamID, nil
+	}
+	return s.UpdateTask(ctx, userID, taskID, raw)
+}
+
+// UndeleteTask brings a task back from the trash. An assignee who left the team or a sprint that is gone or closed is dropped rather
+// than failing the restore.
+func (s *TaskService) UndeleteTask(ctx context.Context, userID, taskID int64) (int64, error) {
+	if s.db == nil || s.history == nil || s.restore == nil {
+		return 0, ErrUnavailable
+	}
+
+	tx, err := s.db.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return 0, err
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			_ = tx.Rollback()
+		}
+	}()
+
+	task, err := s.restore.GetDeletedForUpdateTx(ctx, tx, taskID)
+	if err != nil {
+		return 0, err
+	}
+	if task == nil {
+		return 0, ErrNotFound
+	}
+
+	role, ok, err := s.members.GetRole(ctx, task.TeamID, userID)
+	if err != nil {
+		return 0, err
+	}
+	if !ok || (role != RoleOwner && role != RoleAdmin) {
+		return 0, ErrForbidden
+	}
+
+	// Dropped fields get their own history entries ahead of the restore marker, so as_of reads
+	// and reverts from before the delete still see the old assignee and sprint.
+	cleared := make(map[string]any)
+	var entries []repository.TaskHistoryCreate
+	if task.AssigneeID.Valid {
+		ok, err := s.members.IsMember(ctx, task.TeamID, task.AssigneeID.Int64)
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			entries = append(entries, taskHistoryEntry(taskID, userID, "assignee_id", task.AssigneeID.Int64, nil))
+			task.AssigneeID = sql.NullInt64{}
+			cleared["assignee_id"] = nil
+		}
+	}
+	if task.SprintID.Valid {
+		if err := s.checkTaskSprint(ctx, task.TeamID, task.SprintID.Int64); err != nil {
+			if !errors.Is(err, ErrBadRequest) && !errors.Is(err, ErrUnavailable) {
+				return 0, err
+			}
+			entries = append(entries, taskHistoryEntry(taskID, userID, "sprint_id", task.SprintID.Int64, nil))
+			task.SprintID = sql.NullInt64{}
+			cleared["sprint_id"] = nil
+		}
+	}
+
+	// A trashed task is not counted in its column, so restoring it adds one.
+	if err := s.checkColumnLimit(ctx, tx, task.TeamID, task.Status, 1); err != nil {
+		return 0, err
+	}
+
+	if err := s.restore.UndeleteTx(ctx, tx, taskID, cleared); err != nil {
+		return 0, err
+	}
+	snapshot, err := taskDeleteSnapshot(*task)
+	if err != nil {
+		return 0, err
+	}
+	entries = append(entries, repository.TaskHistoryCreate{
+		TaskID:    taskID,
+		ChangedBy: &userID,
+		FieldName: historyFieldTaskRestored,
+		NewValue:  snapshot,
+	})
+	if err := s.history.CreateBatchTx(ctx, tx, entries); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	committed = true
+
+	var assigneeID int64
+	if task.AssigneeID.Valid {
+		assigneeID = task.AssigneeID.Int64
+	}
+	s.autoWatch(ctx, taskID, userID, assigneeID)
+	return task.TeamID, nil
+}
+
+// rollbackTaskHistory undoes entries (oldest first) starting from the current state.
+// deleted reports whether the task did not exist at the resulting point in time.
+func rollbackTaskHistory(task repository.Task, entries []repository.TaskHistory) (repository.Task, bool, error) {
+	deleted := false
+	for i := len(entries) - 1; i >= 0; i-- {
+		h := entries[i]
+		switch h.FieldName {
+		case historyFieldTaskRestored:
+			deleted = true
+		case historyFieldTaskDeleted:
+			deleted = false
+		default:
+			if err := applyTaskFieldValue(&task, h.FieldName, h.OldValue); err != nil {
+				return task, false, err
+			}
+		}
+	}
+	return task, deleted, nil
+}
+
+func applyTaskFieldValue(task *repository.Task, field string, raw json.RawMessage) error {
+	if len(raw) == 0 {
+		raw = json.RawMessage("null")
+	}
+	switch field {
+	case "title":
+		return json.Unmarshal(raw, &task.Title)
+	case "status":
+		return json.Unmarshal(raw, &task.Status)
+	case "priority":
+		return json.Unmarshal(raw, &task.Priority)
+	case "description":
+		var v *string
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return err
+		}
+		task.Description = sql.NullString{}
+		if v != nil {
+			task.Description = sql.NullString{String: *v, Valid: true}
+		}
+	case "due_date":
+		var v *string
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return err
+		}
+		task.DueDate = sql.NullTime{}
+		if v != nil {
+			tm, err := time.Parse("2006-01-02", *v)
+			if err != nil {
+				return err
+			}
+			task.DueDate = sql.NullTime{Time: tm, Valid: true}
+		}
+	case "assignee_id", "original_estimate_minutes", "remaining_estimate_minutes", "sprint_id":
+		var v *int64
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return err
+		}
+		value := sql.NullInt64{}
+		if v != nil {
+			value = sql.NullInt64{Int64: *v, Valid: true}
+		}
+		switch field {
+		case "assignee_id":
+			task.AssigneeID = value
+		case "original_estimate_minutes":
+			task.OriginalEstimate = value
+		case "remaining_estimate_minutes":
+			task.RemainingEstimate = value
+		case "sprint_id":
+			task.SprintID = value
+		}
+	default:
+		// Fields this version does not know about are left as they are.
+	}
+	return nil
+}
+
+// taskFieldJSON encodes a field the way parseTaskPatch expects it.
+func taskFieldJSON(task repository.Task, field string) json.RawMessage {
+	var v any
+	switch field {
+	case "title":
+		v = task.Title
+	case "status":
+		v = task.Status
+	case "priority":
+		v = task.Priority
+	case "description":
+		if task.Description.Valid {
+			v = task.Description.String
+		}
+	case "due_date":
+		if task.DueDate.Valid {
+			v = task.DueDate.Time.Format("2006-01-02")
+		}
+	case "assignee_id":
+		if task.AssigneeID.Valid {
+			v = task.AssigneeID.Int64
+		}
+	default:
+		if n := taskNullInt64Field(task, field); n.Valid {
+			v = n.Int64
+		}
+	}
+	return *mustJSON(v)
+}
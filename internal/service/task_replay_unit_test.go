@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"MKK-Luna/internal/repository"
+)
+
+type fakeReplayRepo struct {
+	items []repository.TaskHistory
+}
+
+func (f *fakeReplayRepo) ListAllByTask(context.Context, int64) ([]repository.TaskHistory, error) {
+	return f.items, nil
+}
+
+func (f *fakeReplayRepo) ListAllByTaskTx(ctx context.Context, _ *sqlx.Tx, taskID int64) ([]repository.TaskHistory, error) {
+	return f.ListAllByTask(ctx, taskID)
+}
+
+type fakeTaskRestore struct {
+	trashed *repository.Task
+	cleared map[string]any
+}
+
+func (f *fakeTaskRestore) GetDeletedForUpdateTx(context.Context, *sqlx.Tx, int64) (*repository.Task, error) {
+	return f.trashed, nil
+}
+
+func (f *fakeTaskRestore) UndeleteTx(_ context.Context, _ *sqlx.Tx, _ int64, fields map[string]any) error {
+	f.cleared = fields
+	return nil
+}
+
+func historyRow(id int64, field string, oldValue, newValue any, at time.Time) repository.TaskHistory {
+	return repository.TaskHistory{
+		ID: id, TaskID: 1, FieldName: field,
+		OldValue: *mustJSON(oldValue), NewValue: *mustJSON(newValue), CreatedAt: at,
+	}
+}
+
+func TestTaskService_GetTaskAsOf(t *testing.T) {
+	created := time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC)
+	current := &repository.Task{
+		ID: 1, TeamID: 10, Title: "renamed", Status: "done", Priority: "high",
+		AssigneeID: sql.NullInt64{Int64: 5, Valid: true}, CreatedAt: created,
+	}
+	replay := &fakeReplayRepo{items: []repository.TaskHistory{
+		historyRow(1, "status", "todo", "in_progress", created.Add(time.Hour)),
+		historyRow(2, "title", "first", "renamed", created.Add(2*time.Hour)),
+		historyRow(3, "assignee_id", nil, 5, created.Add(2*time.Hour)),
+		historyRow(4, "status", "in_progress", "done", created.Add(3*time.Hour)),
+	}}
+	svc := NewTaskService(nil, &fakeTaskRepo{getByID: func(context.Context, int64) (*repository.Task, error) {
+		task := *current
+		return &task, nil
+	}}, &fakeTeamRepo{}, &fakeMemberRepo{}, &fakeCommentRepo{}, &fakeHistoryRepo{},
+		WithTaskReplay(replay, nil))
+
+	got, err := svc.GetTaskAsOf(context.Background(), 1, 1, created.Add(90*time.Minute))
+	if err != nil {
+		t.Fatalf("as of: %v", err)
+	}
+	if got.Title != "first" || got.Status != "in_progress" || got.AssigneeID.Valid || got.Priority != "high" {
+		t.Fatalf("unexpected state: %+v", got)
+	}
+	if !got.UpdatedAt.Equal(created.Add(time.Hour)) {
+		t.Fatalf("expected updated_at of last applied entry, got %v", got.UpdatedAt)
+	}
+
+	if _, err := svc.GetTaskAsOf(context.Background(), 1, 1, created.Add(-time.Minute)); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected not found before creation, got %v", err)
+	}
+}
+
+func TestTaskService_GetTaskAsOf_WhileDeleted(t *testing.T) {
+	created := time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC)
+	replay := &fakeReplayRepo{items: []repository.TaskHistory{
+		historyRow(1, historyFieldTaskDeleted, map[string]any{"title": "t"}, nil, created.Add(time.Hour)),
+		historyRow(2, historyFieldTaskRestored, nil, map[string]any{"title": "t"}, created.Add(2*time.Hour)),
+	}}
+	svc := NewTaskService(nil, &fakeTaskRepo{getByID: func(context.Context, int64) (*repository.Task, error) {
+		return &repository.Task{ID: 1, TeamID: 10, Title: "t", CreatedAt: created}, nil
+	}}, &fakeTeamRepo{}, &fakeMemberRepo{}, &fakeCommentRepo{}, &fakeHistoryRepo{},
+		WithTaskReplay(replay, nil))
+
+	if _, err := svc.GetTaskAsOf(context.Background(), 1, 1, created.Add(90*time.Minute)); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected not found while deleted, got %v", err)
+	}
+	if _, err := svc.GetTaskAsOf(context.Background(), 1, 1, created.Add(30*time.Minute)); err != nil {
+		t.Fatalf("expected state before delete, got %v", err)
+	}
+}
+
+func TestTaskService_GetTaskAsOf_AcrossRestoreWithClearedAssignee(t *testing.T) {
+	created := time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC)
+	replay := &fakeReplayRepo{items: []repository.TaskHistory{
+		historyRow(1, historyFieldTaskDeleted, map[string]any{"title": "t"}, nil, created.Add(time.Hour)),
+		historyRow(2, "assignee_id", 5, nil, created.Add(2*time.Hour)),
+		historyRow(3, historyFieldTaskRestored, nil, map[string]any{"title": "t"}, created.Add(2*time.Hour)),
+	}}
+	svc := NewTaskService(nil, &fakeTaskRepo{getByID: func(context.Context, int64) (*repository.Task, error) {
+		return &repository.Task{ID: 1, TeamID: 10, Title: "t", CreatedAt: created}, nil
+	}}, &fakeTeamRepo{}, &fakeMemberRepo{}, &fakeCommentRepo{}, &fakeHistoryRepo{},
+		WithTaskReplay(replay, nil))
+
+	got, err := svc.GetTaskAsOf(context.Background(), 1, 1, created.Add(30*time.Minute))
+	if err != nil {
+		t.Fatalf("as of: %v", err)
+	}
+	if !got.AssigneeID.Valid || got.AssigneeID.Int64 != 5 {
+		t.Fatalf("expected assignee 5 before the delete, got %+v", got.AssigneeID)
+	}
+}
+
+func TestTaskService_RevertTask(t *testing.T) {
+	created := time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC)
+	replay := &fakeReplayRepo{items: []repository.TaskHistory{
+		historyRow(1, "status", "todo", "in_progress", created.Add(time.Hour)),
+		historyRow(2, "title", "first", "renamed", created.Add(2*time.Hour)),
+		historyRow(3, "due_date", nil, "2026-03-01", created.Add(2*time.Hour)),
+	}}
+	var updates map[string]any
+	svc := NewTaskService(nil, &fakeTaskRepo{
+		getByID: func(context.Context, int64) (*repository.Task, error) {
+			return &repository.Task{
+				ID: 1, TeamID: 10, Title: "renamed", Status: "in_progress", Priority: "low",
+				DueDate: sql.NullTime{Time: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), Valid: true}, CreatedAt: created,
+			}, nil
+		},
+		update: func(_ context.Context, _ int64, fields map[string]any) error {
+			updates = fields
+			return nil
+		},
+	}, &fakeTeamRepo{}, &fakeMemberRepo{role: RoleAdmin, hasRole: true}, &fakeCommentRepo{}, &fakeHistoryRepo{},
+		WithTaskReplay(replay, nil))
+
+	teamID, err := svc.RevertTask(context.Background(), 1, 1, 1)
+	if err != nil || teamID != 10 {
+		t.Fatalf("revert team=%d err=%v", teamID, err)
+	}
+	if len(updates) != 2 || updates["title"] != "first" {
+		t.Fatalf("unexpected updates: %+v", updates)
+	}
+	if v, ok := updates["due_date"]; !ok || v != nil {
+		t.Fatalf("expected due_date cleared, got %+v", updates)
+	}
+
+	if _, err := svc.RevertTask(context.Background(), 1, 1, 99); !errors.Is(err, ErrBadRequest) {
+		t.Fatalf("expected bad request for unknown entry, got %v", err)
+	}
+}
+
+func TestTaskService_UndeleteTask_FromTrash(t *testing.T) {
+	db, mock := newMockDB(t)
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	restore := &fakeTaskRestore{trashed: &repository.Task{
+		ID: 7, TeamID: 10, Title: "trashed", Status: "todo", Priority: "low",
+		AssigneeID: sql.NullInt64{Int64: 5, Valid: true},
+		DeletedAt:  sql.NullTime{Time: time.Now(), Valid: true},
+	}}
+	var entries []repository.TaskHistoryCreate
+	history := &fakeHistoryRepo{createBatchTx: func(_ context.Context, _ *sqlx.Tx, e []repository.TaskHistoryCreate) error {
+		entries = e
+		return nil
+	}}
+	members := &fakeMemberRepo{
+		role: RoleAdmin, hasRole: true,
+		isMember: func(_ context.Context, _, userID int64) (bool, error) { return userID != 5, nil },
+	}
+	svc := NewTaskService(db, &fakeTaskRepo{}, &fakeTeamRepo{}, members, &fakeCommentRepo{}, history,
+		WithTaskReplay(&fakeReplayRepo{}, restore))
+
+	teamID, err := svc.UndeleteTask(context.Background(), 1, 7)
+	if err != nil || teamID != 10 {
+		t.Fatalf("undelete team=%d err=%v", teamID, err)
+	}
+	if v, ok := restore.cleared["assignee_id"]; !ok || v != nil || len(restore.cleared) != 1 {
+		t.Fatalf("expected only the assignee cleared, got %+v", restore.cleared)
+	}
+	if len(entries) != 2 || entries[0].FieldName != "assignee_id" || entries[1].FieldName != historyFieldTaskRestored {
+		t.Fatalf("unexpected history entries: %+v", entries)
+	}
+	if string(*entries[0].OldValue) != "5" || string(*entries[0].NewValue) != "null" {
+		t.Fatalf("expected assignee 5 -> null recorded, got %s -> %s", *entries[0].OldValue, *entries[0].NewValue)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+}
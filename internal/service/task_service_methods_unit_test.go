@@ -6,6 +6,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/jmoiron/sqlx"
+
 	"MKK-Luna/internal/repository"
 )
 
@@ -23,6 +25,10 @@ func (f *taskRepoWithCreate) Create(ctx context.Context, t repository.Task) (int
 	return 0, nil
 }
 
+func (f *taskRepoWithCreate) CreateTx(ctx context.Context, _ *sqlx.Tx, t repository.Task) (int64, error) {
+	return f.Create(ctx, t)
+}
+
 func (f *taskRepoWithCreate) List(ctx context.Context, flt repository.TaskListFilter) ([]repository.Task, int64, error) {
 	if f.listFn != nil {
 		return f.listFn(ctx, flt)
@@ -45,7 +51,7 @@ type commentRepoFns struct {
 	deleteFn func(context.Context, int64) error
 }
 
-func (f *commentRepoFns) Create(ctx context.Context, taskID, userID int64, body string) (int64, error) {
+func (f *commentRepoFns) CreateTx(ctx context.Context, _ *sqlx.Tx, taskID, userID int64, body string) (int64, error) {
 	if f.createFn != nil {
 		return f.createFn(ctx, taskID, userID, body)
 	}
@@ -63,7 +69,7 @@ func (f *commentRepoFns) GetByID(ctx context.Context, commentID int64) (*reposit
 	}
 	return nil, nil
 }
-func (f *commentRepoFns) Update(ctx context.Context, commentID int64, body string) error {
+func (f *commentRepoFns) UpdateTx(ctx context.Context, _ *sqlx.Tx, commentID int64, body string) error {
 	if f.updateFn != nil {
 		return f.updateFn(ctx, commentID, body)
 	}
@@ -195,16 +201,21 @@ func TestTaskService_Comments(t *testing.T) {
 	comments := &commentRepoFns{
 		createFn: func(context.Context, int64, int64, string) (int64, error) { return 7, nil },
 		listFn: func(context.Context, int64) ([]repository.TaskComment, error) {
-			return []repository.TaskComment{{ID: 1, TaskID: 1, UserID: 2}}, nil
+			return []repository.TaskComment{{ID: 1, TaskID: 1, UserID: sql.NullInt64{Int64: 2, Valid: true}}}, nil
 		},
 		getFn: func(context.Context, int64) (*repository.TaskComment, error) {
-			return &repository.TaskComment{ID: 1, TaskID: 1, UserID: 2}, nil
+			return &repository.TaskComment{ID: 1, TaskID: 1, UserID: sql.NullInt64{Int64: 2, Valid: true}}, nil
 		},
 		updateFn: func(context.Context, int64, string) error { return nil },
 		deleteFn: func(context.Context, int64) error { return nil },
 	}
+	db, mock := newMockDB(t)
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectCommit()
 
-	svc := NewTaskService(nil,
+	svc := NewTaskService(db,
 		taskRepo,
 		&fakeTeamRepo{getByID: func(context.Context, int64) (*repository.Team, error) { return &repository.Team{ID: 10}, nil }},
 		&fakeMemberRepo{
@@ -230,6 +241,9 @@ func TestTaskService_Comments(t *testing.T) {
 	if err := svc.DeleteComment(context.Background(), 2, 1); err != nil {
 		t.Fatalf("delete own comment err=%v", err)
 	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
 }
 
 func TestTaskService_ListComments_Errors(t *testing.T) {
@@ -427,7 +441,7 @@ func TestTaskService_CommentForbiddenCases(t *testing.T) {
 	taskRepo := &taskRepoWithCreate{fakeTaskRepo: fakeTaskRepo{getByID: func(context.Context, int64) (*repository.Task, error) { return &repository.Task{ID: 1, TeamID: 1}, nil }}}
 	comments := &commentRepoFns{
 		getFn: func(context.Context, int64) (*repository.TaskComment, error) {
-			return &repository.TaskComment{ID: 1, TaskID: 1, UserID: 9}, nil
+			return &repository.TaskComment{ID: 1, TaskID: 1, UserID: sql.NullInt64{Int64: 9, Valid: true}}, nil
 		},
 	}
 	svc := NewTaskService(nil,
@@ -482,7 +496,7 @@ func TestTaskService_UpdateComment_ErrorBranches(t *testing.T) {
 			&fakeTeamRepo{},
 			&fakeMemberRepo{},
 			&commentRepoFns{getFn: func(context.Context, int64) (*repository.TaskComment, error) {
-				return &repository.TaskComment{ID: 1, TaskID: 1, UserID: 1}, nil
+				return &repository.TaskComment{ID: 1, TaskID: 1, UserID: sql.NullInt64{Int64: 1, Valid: true}}, nil
 			}},
 			&fakeHistoryRepo{},
 		)
@@ -499,7 +513,7 @@ func TestTaskService_UpdateComment_ErrorBranches(t *testing.T) {
 				getRole: func(context.Context, int64, int64) (string, bool, error) { return "", false, errMock("role") },
 			},
 			&commentRepoFns{getFn: func(context.Context, int64) (*repository.TaskComment, error) {
-				return &repository.TaskComment{ID: 1, TaskID: 1, UserID: 1}, nil
+				return &repository.TaskComment{ID: 1, TaskID: 1, UserID: sql.NullInt64{Int64: 1, Valid: true}}, nil
 			}},
 			&fakeHistoryRepo{},
 		)
@@ -516,7 +530,7 @@ func TestTaskService_UpdateComment_ErrorBranches(t *testing.T) {
 				getRole: func(context.Context, int64, int64) (string, bool, error) { return "", false, nil },
 			},
 			&commentRepoFns{getFn: func(context.Context, int64) (*repository.TaskComment, error) {
-				return &repository.TaskComment{ID: 1, TaskID: 1, UserID: 1}, nil
+				return &repository.TaskComment{ID: 1, TaskID: 1, UserID: sql.NullInt64{Int64: 1, Valid: true}}, nil
 			}},
 			&fakeHistoryRepo{},
 		)
@@ -526,7 +540,10 @@ func TestTaskService_UpdateComment_ErrorBranches(t *testing.T) {
 	})
 
 	t.Run("update repo error", func(t *testing.T) {
-		svc := NewTaskService(nil,
+		db, mock := newMockDB(t)
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+		svc := NewTaskService(db,
 			&taskRepoWithCreate{fakeTaskRepo: fakeTaskRepo{getByID: func(context.Context, int64) (*repository.Task, error) { return &repository.Task{ID: 1, TeamID: 1}, nil }}},
 			&fakeTeamRepo{},
 			&fakeMemberRepo{
@@ -534,7 +551,7 @@ func TestTaskService_UpdateComment_ErrorBranches(t *testing.T) {
 			},
 			&commentRepoFns{
 				getFn: func(context.Context, int64) (*repository.TaskComment, error) {
-					return &repository.TaskComment{ID: 1, TaskID: 1, UserID: 2}, nil
+					return &repository.TaskComment{ID: 1, TaskID: 1, UserID: sql.NullInt64{Int64: 2, Valid: true}}, nil
 				},
 				updateFn: func(context.Context, int64, string) error { return errMock("upd") },
 			},
@@ -543,6 +560,9 @@ func TestTaskService_UpdateComment_ErrorBranches(t *testing.T) {
 		if err := svc.UpdateComment(context.Background(), 1, 1, "x"); err == nil || err.Error() != "upd" {
 			t.Fatalf("expected upd error, got %v", err)
 		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("sqlmock: %v", err)
+		}
 	})
 }
 
@@ -569,7 +589,7 @@ func TestTaskService_DeleteComment_ErrorBranches(t *testing.T) {
 			},
 			&commentRepoFns{
 				getFn: func(context.Context, int64) (*repository.TaskComment, error) {
-					return &repository.TaskComment{ID: 1, TaskID: 1, UserID: 2}, nil
+					return &repository.TaskComment{ID: 1, TaskID: 1, UserID: sql.NullInt64{Int64: 2, Valid: true}}, nil
 				},
 				deleteFn: func(context.Context, int64) error { return errMock("del") },
 			},
@@ -587,7 +607,7 @@ func TestTaskService_DeleteComment_ErrorBranches(t *testing.T) {
 			&fakeMemberRepo{},
 			&commentRepoFns{
 				getFn: func(context.Context, int64) (*repository.TaskComment, error) {
-					return &repository.TaskComment{ID: 1, TaskID: 777, UserID: 2}, nil
+					return &repository.TaskComment{ID: 1, TaskID: 777, UserID: sql.NullInt64{Int64: 2, Valid: true}}, nil
 				},
 			},
 			&fakeHistoryRepo{},
@@ -606,7 +626,7 @@ func TestTaskService_DeleteComment_ErrorBranches(t *testing.T) {
 			},
 			&commentRepoFns{
 				getFn: func(context.Context, int64) (*repository.TaskComment, error) {
-					return &repository.TaskComment{ID: 1, TaskID: 1, UserID: 2}, nil
+					return &repository.TaskComment{ID: 1, TaskID: 1, UserID: sql.NullInt64{Int64: 2, Valid: true}}, nil
 				},
 			},
 			&fakeHistoryRepo{},
@@ -616,3 +636,33 @@ func TestTaskService_DeleteComment_ErrorBranches(t *testing.T) {
 		}
 	})
 }
+
+type fakeUserDirectory struct {
+	requested []int64
+}
+
+func (f *fakeUserDirectory) ListSummariesByIDs(_ context.Context, ids []int64) ([]repository.UserSummary, error) {
+	f.requested = ids
+	out := make([]repository.UserSummary, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, repository.UserSummary{ID: id, Username: "user"})
+	}
+	return out, nil
+}
+
+func TestTaskService_UserSummaries(t *testing.T) {
+	plain := NewTaskService(nil, &fakeTaskRepo{}, &fakeTeamRepo{}, &fakeMemberRepo{}, &fakeCommentRepo{}, &fakeHistoryRepo{})
+	if users, err := plain.UserSummaries(context.Background(), []int64{1}); err != nil || len(users) != 0 {
+		t.Fatalf("expected empty map without directory, got %v err=%v", users, err)
+	}
+
+	dir := &fakeUserDirectory{}
+	svc := NewTaskService(nil, &fakeTaskRepo{}, &fakeTeamRepo{}, &fakeMemberRepo{}, &fakeCommentRepo{}, &fakeHistoryRepo{}, WithUserDirectory(dir))
+	users, err := svc.UserSummaries(context.Background(), []int64{3, 1, 3, 0, 1})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if len(dir.requested) != 2 || len(users) != 2 || users[3].Username != "user" {
+		t.Fatalf("expected one batched lookup of unique ids, requested=%v users=%v", dir.requested, users)
+	}
+}
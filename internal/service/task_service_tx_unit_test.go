@@ -249,3 +249,28 @@ func TestTaskDeleteSnapshot(t *testing.T) {
 		t.Fatalf("expected snapshot json")
 	}
 }
+
+func TestTaskService_UpdateTask_Tx_PreconditionFailed(t *testing.T) {
+	db, mock := newMockDB(t)
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	task := &repository.Task{ID: 1, TeamID: 10, Title: "old", Status: "todo", Priority: "medium", Version: 4}
+	taskRepo := &fakeTaskRepo{
+		getByIDForUpdate: func(context.Context, *sqlx.Tx, int64) (*repository.Task, error) { return task, nil },
+		updateTx: func(context.Context, *sqlx.Tx, int64, map[string]any) error {
+			t.Fatal("stale update must not be written")
+			return nil
+		},
+	}
+	members := &fakeMemberRepo{role: RoleOwner, hasRole: true}
+
+	svc := NewTaskService(db, taskRepo, &fakeTeamRepo{}, members, &fakeCommentRepo{}, &fakeHistoryRepo{})
+	raw := map[string]json.RawMessage{"title": json.RawMessage(`"new"`)}
+	if _, err := svc.UpdateTaskIfMatch(context.Background(), 1, 1, raw, &Precondition{Versions: []int64{3}}); err != ErrPreconditionFailed {
+		t.Fatalf("expected ErrPreconditionFailed, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+}
@@ -11,6 +11,7 @@ import (
 )
 
 type fakeTaskRepo struct {
+	create           func(ctx context.Context, task repository.Task) (int64, error)
 	getByID          func(ctx context.Context, taskID int64) (*repository.Task, error)
 	getByIDForUpdate func(ctx context.Context, tx *sqlx.Tx, taskID int64) (*repository.Task, error)
 	update           func(ctx context.Context, taskID int64, fields map[string]any) error
@@ -19,7 +20,15 @@ type fakeTaskRepo struct {
 	deleteTx         func(ctx context.Context, tx *sqlx.Tx, taskID int64) error
 }
 
-func (f *fakeTaskRepo) Create(context.Context, repository.Task) (int64, error) { return 0, nil }
+func (f *fakeTaskRepo) Create(ctx context.Context, task repository.Task) (int64, error) {
+	if f.create != nil {
+		return f.create(ctx, task)
+	}
+	return 0, nil
+}
+func (f *fakeTaskRepo) CreateTx(ctx context.Context, _ *sqlx.Tx, task repository.Task) (int64, error) {
+	return f.Create(ctx, task)
+}
 func (f *fakeTaskRepo) GetByID(ctx context.Context, taskID int64) (*repository.Task, error) {
 	return f.getByID(ctx, taskID)
 }
@@ -99,7 +108,7 @@ func (f *fakeTeamRepo) GetByID(ctx context.Context, teamID int64) (*repository.T
 
 type fakeCommentRepo struct{}
 
-func (f *fakeCommentRepo) Create(context.Context, int64, int64, string) (int64, error) {
+func (f *fakeCommentRepo) CreateTx(context.Context, *sqlx.Tx, int64, int64, string) (int64, error) {
 	return 0, nil
 }
 func (f *fakeCommentRepo) ListByTask(context.Context, int64) ([]repository.TaskComment, error) {
@@ -108,8 +117,8 @@ func (f *fakeCommentRepo) ListByTask(context.Context, int64) ([]repository.TaskC
 func (f *fakeCommentRepo) GetByID(context.Context, int64) (*repository.TaskComment, error) {
 	return nil, nil
 }
-func (f *fakeCommentRepo) Update(context.Context, int64, string) error { return nil }
-func (f *fakeCommentRepo) Delete(context.Context, int64) error         { return nil }
+func (f *fakeCommentRepo) UpdateTx(context.Context, *sqlx.Tx, int64, string) error { return nil }
+func (f *fakeCommentRepo) Delete(context.Context, int64) error                     { return nil }
 
 type fakeHistoryRepo struct {
 	createBatchTx func(ctx context.Context, tx *sqlx.Tx, entries []repository.TaskHistoryCreate) error
@@ -0,0 +1,414 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"MKK-Luna/internal/repository"
+)
+
+const (
+	maxTemplateNameLen       = 100
+	maxTaskTitleLen          = 255
+	maxTemplateLabels        = 20
+	maxTemplateLabelLen      = 50
+	maxTemplateChecklist     = 50
+	maxTemplateChecklistItem = 500
+	maxTemplateVariables     = 50
+)
+
+// templateVarPattern matches {{name}} placeholders; names are lower snake case.
+var templateVarPattern = regexp.MustCompile(`\{\{\s*([a-z][a-z0-9_]*)\s*\}\}`)
+
+type taskTemplateRepo interface {
+	Create(ctx context.Context, t repository.TaskTemplate) (int64, error)
+	GetByID(ctx context.Context, id int64) (*repository.TaskTemplate, error)
+	ListByTeam(ctx context.Context, teamID int64) ([]repository.TaskTemplate, error)
+	Update(ctx context.Context, id int64, fields map[string]any) error
+	Delete(ctx context.Context, id int64) error
+}
+
+type taskTemplateLookup interface {
+	GetByID(ctx context.Context, id int64) (*repository.TaskTemplate, error)
+}
+
+type TaskTemplateService struct {
+	templates taskTemplateRepo
+	teams     teamRepo
+	members   teamMemberRepo
+}
+
+func NewTaskTemplateService(templates taskTemplateRepo, teams teamRepo, members teamMemberRepo) *TaskTemplateService {
+	return &TaskTemplateService{templates: templates, teams: teams, members: members}
+}
+
+func WithTaskTemplates(templates taskTemplateLookup) TaskServiceOption {
+	return func(s *TaskService) { s.templates = templates }
+}
+
+// TaskTemplateInput is the whole template; updates replace every field.
+type TaskTemplateInput struct {
+	TeamID            int64
+	Name              string
+	TitlePattern      string
+	Description       string
+	Priority          string
+	DefaultAssigneeID *int64
+	Labels            []string
+	Checklist         []string
+}
+
+func (s *TaskTemplateService) CreateTemplate(ctx context.Context, userID int64, in TaskTemplateInput) (int64, error) {
+	team, err := s.teams.GetByID(ctx, in.TeamID)
+	if err != nil {
+		return 0, err
+	}
+	if team == nil {
+		return 0, ErrNotFound
+	}
+	if err := s.requireManager(ctx, in.TeamID, userID); err != nil {
+		return 0, err
+	}
+	tpl, err := s.buildTemplate(ctx, in.TeamID, in)
+	if err != nil {
+		return 0, err
+	}
+	tpl.CreatedBy = sql.NullInt64{Int64: userID, Valid: true}
+	id, err := s.templates.Create(ctx, tpl)
+	if err != nil {
+		if isDuplicate(err) {
+			return 0, ErrConflict
+		}
+		return 0, err
+	}
+	return id, nil
+}
+
+func (s *TaskTemplateService) ListTemplates(ctx context.Context, userID, teamID int64) ([]repository.TaskTemplate, error) {
+	team, err := s.teams.GetByID(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	if team == nil {
+		return nil, ErrNotFound
+	}
+	if ok, err := s.members.IsMember(ctx, teamID, userID); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, ErrForbidden
+	}
+	return s.templates.ListByTeam(ctx, teamID)
+}
+
+func (s *TaskTemplateService) GetTemplate(ctx context.Context, userID, templateID int64) (*repository.TaskTemplate, error) {
+	tpl, err := s.templates.GetByID(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if tpl == nil {
+		return nil, ErrNotFound
+	}
+	if ok, err := s.members.IsMember(ctx, tpl.TeamID, userID); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, ErrForbidden
+	}
+	return tpl, nil
+}
+
+func (s *TaskTemplateService) UpdateTemplate(ctx context.Context, userID, templateID int64, in TaskTemplateInput) error {
+	current, err := s.GetTemplate(ctx, userID, templateID)
+	if err != nil {
+		return err
+	}
+	if err := s.requireManager(ctx, current.TeamID, userID); err != nil {
+		return err
+	}
+	tpl, err := s.buildTemplate(ctx, current.TeamID, in)
+	if err != nil {
+		return err
+	}
+	err = s.templates.Update(ctx, templateID, map[string]any{
+		"name":                tpl.Name,
+		"title_pattern":       tpl.TitlePattern,
+		"description":         nullableTemplateString(tpl.Description),
+		"priority":            nullableTemplateString(tpl.Priority),
+		"default_assignee_id": nullableTemplateInt64(tpl.DefaultAssigneeID),
+		"labels":              nullableTemplateJSON(tpl.Labels),
+		"checklist":           nullableTemplateJSON(tpl.Checklist),
+	})
+	if isDuplicate(err) {
+		return ErrConflict
+	}
+	return err
+}
+
+func (s *TaskTemplateService) DeleteTemplate(ctx context.Context, userID, templateID int64) error {
+	tpl, err := s.GetTemplate(ctx, userID, templateID)
+	if err != nil {
+		return err
+	}
+	if err := s.requireManager(ctx, tpl.TeamID, userID); err != nil {
+		return err
+	}
+	return s.templates.Delete(ctx, templateID)
+}
+
+func (s *TaskTemplateService) requireManager(ctx context.Context, teamID, userID int64) error {
+	role, ok, err := s.members.GetRole(ctx, teamID, userID)
+	if err != nil {
+		return err
+	}
+	if !ok || (role != RoleOwner && role != RoleAdmin) {
+		return ErrForbidden
+	}
+	return nil
+}
+
+func (s *TaskTemplateService) buildTemplate(ctx context.Context, teamID int64, in TaskTemplateInput) (repository.TaskTemplate, error) {
+	name := strings.TrimSpace(in.Name)
+	if name == "" || utf8.RuneCountInString(name) > maxTemplateNameLen {
+		return repository.TaskTemplate{}, ErrBadRequest
+	}
+	title := strings.TrimSpace(in.TitlePattern)
+	if title == "" || utf8.RuneCountInString(title) > maxTaskTitleLen || !validTemplateText(title) {
+		return repository.TaskTemplate{}, ErrBadRequest
+	}
+	if !validTemplateText(in.Description) {
+		return repository.TaskTemplate{}, ErrBadRequest
+	}
+	priority := strings.TrimSpace(in.Priority)
+	if priority != "" && !isValidPriority(priority) {
+		return repository.TaskTemplate{}, ErrBadRequest
+	}
+	if in.DefaultAssigneeID != nil {
+		ok, err := s.members.IsMember(ctx, teamID, *in.DefaultAssigneeID)
+		if err != nil {
+			return repository.TaskTemplate{}, err
+		}
+		if !ok {
+			return repository.TaskTemplate{}, ErrBadRequest
+		}
+	}
+	labels, err := normalizeTemplateList(in.Labels, maxTemplateLabels, maxTemplateLabelLen, true)
+	if err != nil {
+		return repository.TaskTemplate{}, err
+	}
+	checklist, err := normalizeTemplateList(in.Checklist, maxTemplateChecklist, maxTemplateChecklistItem, false)
+	if err != nil {
+		return repository.TaskTemplate{}, err
+	}
+	for _, item := range checklist {
+		if !validTemplateText(item) {
+			return repository.TaskTemplate{}, ErrBadRequest
+		}
+	}
+
+	tpl := repository.TaskTemplate{
+		TeamID:       teamID,
+		Name:         name,
+		TitlePattern: title,
+		Labels:       encodeTemplateList(labels),
+		Checklist:    encodeTemplateList(checklist),
+	}
+	if strings.TrimSpace(in.Description) != "" {
+		tpl.Description = sql.NullString{String: in.Description, Valid: true}
+	}
+	if priority != "" {
+		tpl.Priority = sql.NullString{String: priority, Valid: true}
+	}
+	if in.DefaultAssigneeID != nil {
+		tpl.DefaultAssigneeID = sql.NullInt64{Int64: *in.DefaultAssigneeID, Valid: true}
+	}
+	return tpl, nil
+}
+
+// TemplateList decodes the labels or checklist column of a template.
+func TemplateList(raw []byte) []string {
+	out := make([]string, 0)
+	if len(raw) > 0 {
+		_ = json.Unmarshal(raw, &out)
+	}
+	return out
+}
+
+type TaskFromTemplateInput struct {
+	Variables  map[string]string
+	Status     string
+	AssigneeID *int64
+	DueDate    *time.Time
+	SprintID   *int64
+}
+
+// TaskFromTemplateResult carries the rendered labels and checklist alongside the new task.
+type TaskFromTemplateResult struct {
+	TaskID    int64
+	TeamID    int64
+	Labels    []string
+	Checklist []string
+}
+
+// CreateTaskFromTemplate renders the template with the given variables plus {{date}}
+// (today, UTC) and creates the task with the same validation as POST /tasks; the task and
+// its checklist are written in one transaction. A default assignee who has left the team
+// is dropped; an explicit AssigneeID overrides it. Unknown placeholders are rejected.
+func (s *TaskService) CreateTaskFromTemplate(ctx context.Context, userID, templateID int64, in TaskFromTemplateInput) (*TaskFromTemplateResult, error) {
+	if s.templates == nil {
+		return nil, ErrUnavailable
+	}
+	tpl, err := s.templates.GetByID(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if tpl == nil {
+		return nil, ErrNotFound
+	}
+	if ok, err := s.members.IsMember(ctx, tpl.TeamID, userID); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, ErrForbidden
+	}
+	if len(in.Variables) > maxTemplateVariables {
+		return nil, ErrBadRequest
+	}
+
+	vars := map[string]string{"date": time.Now().UTC().Format("2006-01-02")}
+	for k, v := range in.Variables {
+		vars[k] = v
+	}
+	title, err := renderTemplateText(tpl.TitlePattern, vars)
+	if err != nil {
+		return nil, err
+	}
+	title = strings.TrimSpace(title)
+	if title == "" || utf8.RuneCountInString(title) > maxTaskTitleLen {
+		return nil, ErrBadRequest
+	}
+	var description string
+	if tpl.Description.Valid {
+		if description, err = renderTemplateText(tpl.Description.String, vars); err != nil {
+			return nil, err
+		}
+	}
+	checklist := TemplateList(tpl.Checklist)
+	var items []repository.ChecklistItem
+	for i, item := range checklist {
+		if checklist[i], err = renderTemplateText(item, vars); err != nil {
+			return nil, err
+		}
+		if s.checklists != nil {
+			body, err := normalizeChecklistBody(checklist[i])
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, repository.ChecklistItem{Body: body})
+		}
+	}
+
+	assigneeID := in.AssigneeID
+	if assigneeID == nil && tpl.DefaultAssigneeID.Valid {
+		ok, err := s.members.IsMember(ctx, tpl.TeamID, tpl.DefaultAssigneeID.Int64)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			assigneeID = &tpl.DefaultAssigneeID.Int64
+		}
+	}
+
+	// The creator may be a plain member, so the checklist skips the owner/admin check of AddChecklistItems.
+	id, err := s.createTask(ctx, userID, CreateTaskInput{
+		TeamID:      tpl.TeamID,
+		Title:       title,
+		Description: description,
+		Status:      in.Status,
+		Priority:    tpl.Priority.String,
+		AssigneeID:  assigneeID,
+		DueDate:     in.DueDate,
+		SprintID:    in.SprintID,
+	}, items)
+	if err != nil {
+		return nil, err
+	}
+	return &TaskFromTemplateResult{TaskID: id, TeamID: tpl.TeamID, Labels: TemplateList(tpl.Labels), Checklist: checklist}, nil
+}
+
+func renderTemplateText(text string, vars map[string]string) (string, error) {
+	var missing bool
+	out := templateVarPattern.ReplaceAllStringFunc(text, func(m string) string {
+		name := templateVarPattern.FindStringSubmatch(m)[1]
+		v, ok := vars[name]
+		if !ok {
+			missing = true
+		}
+		return v
+	})
+	if missing {
+		return "", ErrBadRequest
+	}
+	return out, nil
+}
+
+// validTemplateText rejects braces that are not part of a well-formed placeholder, so
+// typos like {{ Name } fail when the template is saved rather than when it is used.
+func validTemplateText(text string) bool {
+	rest := templateVarPattern.ReplaceAllString(text, "")
+	return !strings.Contains(rest, "{{") && !strings.Contains(rest, "}}")
+}
+
+func normalizeTemplateList(items []string, maxItems, maxLen int, dedupe bool) ([]string, error) {
+	if len(items) > maxItems {
+		return nil, ErrBadRequest
+	}
+	out := make([]string, 0, len(items))
+	seen := make(map[string]struct{}, len(items))
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if item == "" || utf8.RuneCountInString(item) > maxLen {
+			return nil, ErrBadRequest
+		}
+		if dedupe {
+			key := strings.ToLower(item)
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+		}
+		out = append(out, item)
+	}
+	return out, nil
+}
+
+func encodeTemplateList(items []string) []byte {
+	if len(items) == 0 {
+		return nil
+	}
+	b, _ := json.Marshal(items)
+	return b
+}
+
+func nullableTemplateString(v sql.NullString) any {
+	if v.Valid {
+		return v.String
+	}
+	return nil
+}
+
+func nullableTemplateInt64(v sql.NullInt64) any {
+	if v.Valid {
+		return v.Int64
+	}
+	return nil
+}
+
+func nullableTemplateJSON(v []byte) any {
+	if len(v) == 0 {
+		return nil
+	}
+	return v
+}
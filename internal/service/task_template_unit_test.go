@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"MKK-Luna/internal/repository"
+)
+
+type fakeTemplateRepo struct {
+	created []repository.TaskTemplate
+	byID    map[int64]*repository.TaskTemplate
+}
+
+func (f *fakeTemplateRepo) Create(_ context.Context, t repository.TaskTemplate) (int64, error) {
+	f.created = append(f.created, t)
+	return int64(len(f.created)), nil
+}
+func (f *fakeTemplateRepo) GetByID(_ context.Context, id int64) (*repository.TaskTemplate, error) {
+	return f.byID[id], nil
+}
+func (f *fakeTemplateRepo) ListByTeam(context.Context, int64) ([]repository.TaskTemplate, error) {
+	return nil, nil
+}
+func (f *fakeTemplateRepo) Update(context.Context, int64, map[string]any) error { return nil }
+func (f *fakeTemplateRepo) Delete(context.Context, int64) error                 { return nil }
+
+func TestTaskTemplateService_CreateTemplate(t *testing.T) {
+	templates := &fakeTemplateRepo{}
+	teams := &fakeTeamRepo{getByID: func(_ context.Context, id int64) (*repository.Team, error) {
+		return &repository.Team{ID: id}, nil
+	}}
+	members := &fakeMemberRepo{role: RoleMember, hasRole: true}
+	svc := NewTaskTemplateService(templates, teams, members)
+
+	in := TaskTemplateInput{
+		TeamID: 10, Name: " Release ", TitlePattern: "Release {{version}}", Priority: "high",
+		Labels: []string{"ops", " Ops ", "release"}, Checklist: []string{" Tag {{version}} "},
+	}
+	if _, err := svc.CreateTemplate(context.Background(), 1, in); err != ErrForbidden {
+		t.Fatalf("expected ErrForbidden for member, got %v", err)
+	}
+
+	members.role = RoleAdmin
+	if _, err := svc.CreateTemplate(context.Background(), 1, in); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	got := templates.created[0]
+	if got.Name != "Release" || got.Priority.String != "high" || string(got.Labels) != `["ops","release"]` || string(got.Checklist) != `["Tag {{version}}"]` {
+		t.Fatalf("unexpected template: %+v labels=%s checklist=%s", got, got.Labels, got.Checklist)
+	}
+
+	for _, bad := range []TaskTemplateInput{
+		{TeamID: 10, Name: "x", TitlePattern: "Release {{ Version }}"},
+		{TeamID: 10, Name: "x", TitlePattern: "Release {version}}"},
+		{TeamID: 10, Name: "x", TitlePattern: "x", Priority: "urgent"},
+		{TeamID: 10, Name: "x", TitlePattern: "x", Checklist: []string{" "}},
+	} {
+		if _, err := svc.CreateTemplate(context.Background(), 1, bad); err != ErrBadRequest {
+			t.Fatalf("expected ErrBadRequest for %+v, got %v", bad, err)
+		}
+	}
+}
+
+func TestTaskService_CreateTaskFromTemplate(t *testing.T) {
+	tpl := &repository.TaskTemplate{
+		ID: 3, TeamID: 10, TitlePattern: "Release {{version}} ({{date}})",
+		Description:       sql.NullString{String: "Ship {{version}}", Valid: true},
+		Priority:          sql.NullString{String: "high", Valid: true},
+		DefaultAssigneeID: sql.NullInt64{Int64: 7, Valid: true},
+		Labels:            []byte(`["ops"]`),
+		Checklist:         []byte(`["Tag {{version}}"]`),
+	}
+	var created repository.Task
+	taskRepo := &fakeTaskRepo{create: func(_ context.Context, task repository.Task) (int64, error) {
+		created = task
+		return 42, nil
+	}}
+	teams := &fakeTeamRepo{getByID: func(_ context.Context, id int64) (*repository.Team, error) {
+		return &repository.Team{ID: id}, nil
+	}}
+	leftTeam := false
+	members := &fakeMemberRepo{isMember: func(_ context.Context, _, userID int64) (bool, error) {
+		return !(leftTeam && userID == 7), nil
+	}}
+	svc := NewTaskService(nil, taskRepo, teams, members, &fakeCommentRepo{}, &fakeHistoryRepo{},
+		WithTaskTemplates(&fakeTemplateRepo{byID: map[int64]*repository.TaskTemplate{3: tpl}}))
+
+	if _, err := svc.CreateTaskFromTemplate(context.Background(), 1, 3, TaskFromTemplateInput{}); err != ErrBadRequest {
+		t.Fatalf("expected ErrBadRequest for a missing variable, got %v", err)
+	}
+	if _, err := svc.CreateTaskFromTemplate(context.Background(), 1, 4, TaskFromTemplateInput{}); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	res, err := svc.CreateTaskFromTemplate(context.Background(), 1, 3, TaskFromTemplateInput{Variables: map[string]string{"version": "1.2"}})
+	if err != nil {
+		t.Fatalf("from template: %v", err)
+	}
+	today := time.Now().UTC().Format("2006-01-02")
+	if res.TaskID != 42 || created.Title != "Release 1.2 ("+today+")" || created.Description.String != "Ship 1.2" || created.Priority != "high" {
+		t.Fatalf("unexpected task: %+v", created)
+	}
+	if created.AssigneeID.Int64 != 7 || len(res.Checklist) != 1 || res.Checklist[0] != "Tag 1.2" || res.Labels[0] != "ops" {
+		t.Fatalf("unexpected assignee %v or result %+v", created.AssigneeID, res)
+	}
+
+	leftTeam = true
+	if _, err := svc.CreateTaskFromTemplate(context.Background(), 1, 3, TaskFromTemplateInput{Variables: map[string]string{"version": "1.3"}}); err != nil {
+		t.Fatalf("from template after default assignee left: %v", err)
+	}
+	if created.AssigneeID.Valid {
+		t.Fatalf("default assignee who left must be dropped, got %v", created.AssigneeID)
+	}
+}
+
+func TestTaskService_CreateTaskFromTemplate_ChecklistInTaskTx(t *testing.T) {
+	db, mock := newMockDB(t)
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	tpl := &repository.TaskTemplate{ID: 3, TeamID: 10, TitlePattern: "Release", Checklist: []byte(`["Tag","Ship"]`)}
+	taskRepo := &fakeTaskRepo{create: func(context.Context, repository.Task) (int64, error) { return 42, nil }}
+	teams := &fakeTeamRepo{getByID: func(_ context.Context, id int64) (*repository.Team, error) {
+		return &repository.Team{ID: id}, nil
+	}}
+	members := &fakeMemberRepo{isMember: func(context.Context, int64, int64) (bool, error) { return true, nil }}
+	checklists := &fakeChecklistRepo{}
+	svc := NewTaskService(db, taskRepo, teams, members, &fakeCommentRepo{}, &fakeHistoryRepo{},
+		WithTaskTemplates(&fakeTemplateRepo{byID: map[int64]*repository.TaskTemplate{3: tpl}}),
+		WithChecklists(checklists))
+
+	res, err := svc.CreateTaskFromTemplate(context.Background(), 1, 3, TaskFromTemplateInput{})
+	if err != nil || res.TaskID != 42 {
+		t.Fatalf("from template res=%+v err=%v", res, err)
+	}
+	if len(checklists.created) != 2 || checklists.created[0].Body != "Tag" || checklists.touched != 0 {
+		t.Fatalf("unexpected checklist items %+v touched=%d", checklists.created, checklists.touched)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+
+	devents "MKK-Luna/internal/domain/events"
+	"MKK-Luna/internal/repository"
+)
+
+type fakeWatcherRepo struct {
+	watching map[int64]bool
+	left     map[int64]bool
+}
+
+func newFakeWatcherRepo(userIDs ...int64) *fakeWatcherRepo {
+	f := &fakeWatcherRepo{watching: make(map[int64]bool)}
+	for _, id := range userIDs {
+		f.watching[id] = true
+	}
+	return f
+}
+
+func (f *fakeWatcherRepo) Add(_ context.Context, _ int64, userID int64) error {
+	f.watching[userID] = true
+	return nil
+}
+func (f *fakeWatcherRepo) AddTx(_ context.Context, _ *sqlx.Tx, _ int64, userID int64) error {
+	f.watching[userID] = true
+	return nil
+}
+func (f *fakeWatcherRepo) Remove(_ context.Context, _ int64, userID int64) error {
+	delete(f.watching, userID)
+	return nil
+}
+func (f *fakeWatcherRepo) ListByTask(_ context.Context, taskID int64) ([]repository.TaskWatcher, error) {
+	items := make([]repository.TaskWatcher, 0, len(f.watching))
+	for id := range f.watching {
+		items = append(items, repository.TaskWatcher{TaskID: taskID, UserID: id})
+	}
+	return items, nil
+}
+
+func (f *fakeWatcherRepo) ListMemberIDsByTask(context.Context, int64) ([]int64, error) {
+	ids := make([]int64, 0, len(f.watching))
+	for id := range f.watching {
+		if !f.left[id] {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+type fakeEventPublisher struct {
+	events []devents.TaskEvent
+}
+
+func (f *fakeEventPublisher) PublishTaskEvent(_ context.Context, ev devents.TaskEvent) error {
+	f.events = append(f.events, ev)
+	return nil
+}
+
+func TestTaskService_UpdateTask_WatchersNotified(t *testing.T) {
+	db, mock := newMockDB(t)
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	task := &repository.Task{ID: 1, TeamID: 10, Title: "old", Status: "todo", Priority: "medium"}
+	taskRepo := &fakeTaskRepo{
+		getByIDForUpdate: func(context.Context, *sqlx.Tx, int64) (*repository.Task, error) { return task, nil },
+	}
+	watchers := newFakeWatcherRepo(1, 2)
+	events := &fakeEventPublisher{}
+	svc := NewTaskService(db, taskRepo, &fakeTeamRepo{}, &fakeMemberRepo{role: RoleOwner, hasRole: true}, &fakeCommentRepo{}, &fakeHistoryRepo{},
+		WithWatchers(watchers), WithTaskEvents(events))
+
+	_, err := svc.UpdateTask(context.Background(), 1, 1, map[string]json.RawMessage{"assignee_id": json.RawMessage(`3`)})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if !watchers.watching[3] {
+		t.Fatalf("new assignee must be auto-watched")
+	}
+	if len(events.events) != 1 {
+		t.Fatalf("expected one event, got %d", len(events.events))
+	}
+	ev := events.events[0]
+	if ev.Type != TaskEventUpdated || len(ev.Fields) != 1 || ev.Fields[0] != "assignee_id" {
+		t.Fatalf("unexpected event: %+v", ev)
+	}
+	if len(ev.Recipients) != 2 {
+		t.Fatalf("expected watchers 2 and 3 without the actor, got %v", ev.Recipients)
+	}
+	for _, id := range ev.Recipients {
+		if id == 1 {
+			t.Fatalf("actor must not be notified: %v", ev.Recipients)
+		}
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+}
+
+func TestTaskService_CreateComment_AutoWatch(t *testing.T) {
+	taskRepo := &fakeTaskRepo{
+		getByID: func(context.Context, int64) (*repository.Task, error) {
+			return &repository.Task{ID: 1, TeamID: 10}, nil
+		},
+	}
+	watchers := newFakeWatcherRepo(2)
+	events := &fakeEventPublisher{}
+	db, mock := newMockDB(t)
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	svc := NewTaskService(db, taskRepo, &fakeTeamRepo{}, &fakeMemberRepo{}, &fakeCommentRepo{}, &fakeHistoryRepo{},
+		WithWatchers(watchers), WithTaskEvents(events))
+
+	if _, err := svc.CreateComment(context.Background(), 5, 1, "hello"); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if !watchers.watching[5] {
+		t.Fatalf("commenter must be auto-watched")
+	}
+	if len(events.events) != 1 || events.events[0].Type != TaskEventCommentCreated {
+		t.Fatalf("unexpected events: %+v", events.events)
+	}
+	if got := events.events[0].Recipients; len(got) != 1 || got[0] != 2 {
+		t.Fatalf("expected only watcher 2 notified, got %v", got)
+	}
+}
+
+func TestTaskService_WatchUnwatch(t *testing.T) {
+	taskRepo := &fakeTaskRepo{
+		getByID: func(context.Context, int64) (*repository.Task, error) {
+			return &repository.Task{ID: 1, TeamID: 10}, nil
+		},
+	}
+	plain := NewTaskService(nil, taskRepo, &fakeTeamRepo{}, &fakeMemberRepo{}, &fakeCommentRepo{}, &fakeHistoryRepo{})
+	if err := plain.WatchTask(context.Background(), 1, 1); err != ErrUnavailable {
+		t.Fatalf("expected ErrUnavailable without watcher store, got %v", err)
+	}
+
+	watchers := newFakeWatcherRepo()
+	svc := NewTaskService(nil, taskRepo, &fakeTeamRepo{}, &fakeMemberRepo{}, &fakeCommentRepo{}, &fakeHistoryRepo{}, WithWatchers(watchers))
+	if err := svc.WatchTask(context.Background(), 7, 1); err != nil || !watchers.watching[7] {
+		t.Fatalf("watch failed: err=%v watching=%v", err, watchers.watching)
+	}
+	items, err := svc.ListWatchers(context.Background(), 7, 1)
+	if err != nil || len(items) != 1 {
+		t.Fatalf("list watchers: err=%v items=%v", err, items)
+	}
+	if err := svc.UnwatchTask(context.Background(), 7, 1); err != nil || watchers.watching[7] {
+		t.Fatalf("unwatch failed: err=%v watching=%v", err, watchers.watching)
+	}
+
+	denied := NewTaskService(nil, taskRepo, &fakeTeamRepo{}, &fakeMemberRepo{
+		isMember: func(context.Context, int64, int64) (bool, error) { return false, nil },
+	}, &fakeCommentRepo{}, &fakeHistoryRepo{}, WithWatchers(watchers))
+	if err := denied.WatchTask(context.Background(), 9, 1); err != ErrForbidden {
+		t.Fatalf("expected ErrForbidden for non-member, got %v", err)
+	}
+}
+
+func TestTaskService_CreateComment_SkipsWatchersWhoLeft(t *testing.T) {
+	taskRepo := &fakeTaskRepo{
+		getByID: func(context.Context, int64) (*repository.Task, error) {
+			return &repository.Task{ID: 1, TeamID: 10}, nil
+		},
+	}
+	watchers := newFakeWatcherRepo(2, 3)
+	watchers.left = map[int64]bool{3: true}
+	events := &fakeEventPublisher{}
+	db, mock := newMockDB(t)
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	svc := NewTaskService(db, taskRepo, &fakeTeamRepo{}, &fakeMemberRepo{}, &fakeCommentRepo{}, &fakeHistoryRepo{},
+		WithWatchers(watchers), WithTaskEvents(events))
+
+	if _, err := svc.CreateComment(context.Background(), 5, 1, "hello"); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if len(events.events) != 1 {
+		t.Fatalf("expected one event, got %+v", events.events)
+	}
+	if got := events.events[0].Recipients; len(got) != 1 || got[0] != 2 {
+		t.Fatalf("expected only member watcher 2 notified, got %v", got)
+	}
+}
+
+type fakeTaskTagCache struct {
+	invalidated []int64
+}
+
+func (f *fakeTaskTagCache) InvalidateTask(_ context.Context, taskID int64) error {
+	f.invalidated = append(f.invalidated, taskID)
+	return nil
+}
+
+func TestTaskService_WatchUnwatch_InvalidatesTaskTag(t *testing.T) {
+	taskRepo := &fakeTaskRepo{
+		getByID: func(context.Context, int64) (*repository.Task, error) {
+			return &repository.Task{ID: 1, TeamID: 10}, nil
+		},
+	}
+	tags := &fakeTaskTagCache{}
+	svc := NewTaskService(nil, taskRepo, &fakeTeamRepo{}, &fakeMemberRepo{}, &fakeCommentRepo{}, &fakeHistoryRepo{},
+		WithWatchers(newFakeWatcherRepo()), WithTaskTagCache(tags))
+
+	if err := svc.WatchTask(context.Background(), 7, 1); err != nil {
+		t.Fatalf("watch: %v", err)
+	}
+	if err := svc.UnwatchTask(context.Background(), 7, 1); err != nil {
+		t.Fatalf("unwatch: %v", err)
+	}
+	if len(tags.invalidated) != 2 || tags.invalidated[0] != 1 || tags.invalidated[1] != 1 {
+		t.Fatalf("expected task 1 tag dropped twice, got %v", tags.invalidated)
+	}
+}
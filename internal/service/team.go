@@ -30,6 +30,7 @@ type TeamService struct {
 	lockTTL time.Duration
 	logger  *slog.Logger
 	metrics TeamMetrics
+	audit   *AuditService
 }
 
 type teamStore interface {
@@ -43,6 +44,7 @@ type teamMemberStore interface {
 	Add(ctx context.Context, teamID, userID int64, role string) error
 	GetRole(ctx context.Context, teamID, userID int64) (string, bool, error)
 	IsMember(ctx context.Context, teamID, userID int64) (bool, error)
+	ListByTeam(ctx context.Context, teamID int64) ([]repository.TeamMemberDetail, error)
 }
 
 type userStore interface {
@@ -62,6 +64,8 @@ type TeamMetrics interface {
 	IncLockReleaseError()
 }
 
+type TeamServiceOption func(*TeamService)
+
 func NewTeamService(
 	db *sqlx.DB,
 	teams teamStore,
@@ -72,11 +76,16 @@ func NewTeamService(
 	lockTTL time.Duration,
 	logger *slog.Logger,
 	metrics TeamMetrics,
+	opts ...TeamServiceOption,
 ) *TeamService {
-	return &TeamService{
+	s := &TeamService{
 		db: db, teams: teams, members: members, users: users, email: emailSender,
 		locker: locker, lockTTL: lockTTL, logger: logger, metrics: metrics,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s *TeamService) CreateTeam(ctx context.Context, userID int64, name string) (int64, error) {
@@ -96,6 +105,10 @@ func (s *TeamService) CreateTeam(ctx context.Context, userID int64, name string)
 	if err := tx.Commit(); err != nil {
 		return 0, err
 	}
+	s.audit.Record(ctx, AuditEntry{
+		ActorID: userID, Action: AuditTeamCreated, TargetType: auditTargetTeam, TargetID: teamID, TeamID: teamID,
+		Payload: map[string]any{"name": name},
+	})
 	return teamID, nil
 }
 
@@ -121,6 +134,13 @@ func (s *TeamService) EnsureMemberRole(ctx context.Context, teamID, userID int64
 	return role, nil
 }
 
+func (s *TeamService) ListMembers(ctx context.Context, userID, teamID int64) ([]repository.TeamMemberDetail, error) {
+	if _, err := s.EnsureMemberRole(ctx, teamID, userID); err != nil {
+		return nil, err
+	}
+	return s.members.ListByTeam(ctx, teamID)
+}
+
 func (s *TeamService) InviteByEmail(ctx context.Context, inviterID, teamID int64, email, role string) error {
 	team, err := s.teams.GetByID(ctx, teamID)
 	if err != nil {
@@ -190,6 +210,10 @@ func (s *TeamService) InviteByEmail(ctx context.Context, inviterID, teamID int64
 		}
 		return err
 	}
+	s.audit.Record(ctx, AuditEntry{
+		ActorID: inviterID, Action: AuditMemberInvited, TargetType: auditTargetUser, TargetID: user.ID, TeamID: teamID,
+		Payload: map[string]any{"role": role},
+	})
 	return nil
 }
 
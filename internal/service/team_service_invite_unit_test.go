@@ -42,6 +42,7 @@ type fakeTeamMemberStore struct {
 	isMember func(ctx context.Context, teamID, userID int64) (bool, error)
 	add      func(ctx context.Context, teamID, userID int64, role string) error
 	addTx    func(ctx context.Context, tx *sqlx.Tx, teamID, userID int64, role string) error
+	list     func(ctx context.Context, teamID int64) ([]repository.TeamMemberDetail, error)
 }
 
 func (f *fakeTeamMemberStore) AddTx(ctx context.Context, tx *sqlx.Tx, teamID, userID int64, role string) error {
@@ -69,6 +70,13 @@ func (f *fakeTeamMemberStore) IsMember(ctx context.Context, teamID, userID int64
 	return false, nil
 }
 
+func (f *fakeTeamMemberStore) ListByTeam(ctx context.Context, teamID int64) ([]repository.TeamMemberDetail, error) {
+	if f.list != nil {
+		return f.list(ctx, teamID)
+	}
+	return nil, nil
+}
+
 type fakeUserStore struct {
 	getByEmail func(ctx context.Context, email string) (*repository.User, error)
 }
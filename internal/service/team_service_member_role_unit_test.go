@@ -74,3 +74,24 @@ func TestTeamService_EnsureMemberRole(t *testing.T) {
 		})
 	}
 }
+
+func TestTeamService_ListMembers(t *testing.T) {
+	teams := &fakeTeamStore{getByID: func(context.Context, int64) (*repository.Team, error) { return &repository.Team{ID: 1}, nil }}
+	members := &fakeTeamMemberStore{
+		getRole: func(_ context.Context, _ int64, userID int64) (string, bool, error) {
+			return RoleMember, userID == 1, nil
+		},
+		list: func(context.Context, int64) ([]repository.TeamMemberDetail, error) {
+			return []repository.TeamMemberDetail{{UserID: 1, Username: "alice", Role: RoleOwner}}, nil
+		},
+	}
+	svc := NewTeamService(nil, teams, members, &fakeUserStore{}, nil, nil, 0, nil, nil)
+
+	if _, err := svc.ListMembers(context.Background(), 2, 1); err != ErrForbidden {
+		t.Fatalf("expected ErrForbidden for non-member, got %v", err)
+	}
+	items, err := svc.ListMembers(context.Background(), 1, 1)
+	if err != nil || len(items) != 1 || items[0].Username != "alice" {
+		t.Fatalf("unexpected result: items=%v err=%v", items, err)
+	}
+}
@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jmoiron/sqlx"
+
+	"MKK-Luna/internal/repository"
+)
+
+const (
+	maxEstimateMinutes = 100000
+	maxWorkLogMinutes  = 24 * 60
+	maxWorkLogNote     = 1000
+	maxWorkLogAge      = 366 * 24 * time.Hour
+)
+
+type workLogRepo interface {
+	Create(ctx context.Context, l repository.TaskWorkLog) (int64, error)
+	CreateTx(ctx context.Context, tx *sqlx.Tx, l repository.TaskWorkLog) (int64, error)
+	GetByID(ctx context.Context, id int64) (*repository.TaskWorkLog, error)
+	ListByTask(ctx context.Context, taskID int64, limit, offset int) ([]repository.TaskWorkLog, int64, error)
+	TotalMinutesByTask(ctx context.Context, taskID int64) (int64, error)
+	Delete(ctx context.Context, id int64) error
+	StartTimer(ctx context.Context, taskID, userID int64) error
+	GetTimer(ctx context.Context, taskID, userID int64) (*repository.TaskTimer, error)
+	TakeTimerTx(ctx context.Context, tx *sqlx.Tx, taskID, userID int64) (*repository.TaskTimer, error)
+}
+
+func WithTimeTracking(worklogs workLogRepo) TaskServiceOption {
+	return func(s *TaskService) { s.worklogs = worklogs }
+}
+
+type WorkLogInput struct {
+	Minutes  int64
+	WorkDate time.Time
+	Note     string
+}
+
+type TaskTimeSummary struct {
+	LoggedMinutes  int64
+	TimerStartedAt *time.Time
+}
+
+func (s *TaskService) LogWork(ctx context.Context, userID, taskID int64, in WorkLogInput) (int64, error) {
+	if s.worklogs == nil {
+		return 0, ErrUnavailable
+	}
+	if in.Minutes <= 0 || in.Minutes > maxWorkLogMinutes {
+		return 0, ErrBadRequest
+	}
+	now := time.Now().UTC()
+	if in.WorkDate.IsZero() {
+		in.WorkDate = now
+	}
+	// One day of slack lets clients ahead of UTC log "today" in their own timezone.
+	if in.WorkDate.After(now.Add(24*time.Hour)) || in.WorkDate.Before(now.Add(-maxWorkLogAge)) {
+		return 0, ErrBadRequest
+	}
+	note, err := workLogNote(in.Note)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := s.GetTask(ctx, userID, taskID); err != nil {
+		return 0, err
+	}
+	id, err := s.worklogs.Create(ctx, repository.TaskWorkLog{
+		TaskID:   taskID,
+		UserID:   sql.NullInt64{Int64: userID, Valid: true},
+		Minutes:  in.Minutes,
+		WorkDate: truncateToDate(in.WorkDate),
+		Note:     note,
+	})
+	if err != nil {
+		return 0, err
+	}
+	s.invalidateTaskTag(ctx, taskID)
+	return id, nil
+}
+
+func (s *TaskService) ListWorkLogs(ctx context.Context, userID, taskID int64, limit, offset int) ([]repository.TaskWorkLog, int64, error) {
+	if s.worklogs == nil {
+		return nil, 0, ErrUnavailable
+	}
+	if _, err := s.GetTask(ctx, userID, taskID); err != nil {
+		return nil, 0, err
+	}
+	return s.worklogs.ListByTask(ctx, taskID, limit, offset)
+}
+
+func (s *TaskService) DeleteWorkLog(ctx context.Context, userID, logID int64) error {
+	if s.worklogs == nil {
+		return ErrUnavailable
+	}
+	entry, err := s.worklogs.GetByID(ctx, logID)
+	if err != nil {
+		return err
+	}
+	if entry == nil {
+		return ErrNotFound
+	}
+	task, err := s.GetTask(ctx, userID, entry.TaskID)
+	if err != nil {
+		return err
+	}
+	if !entry.UserID.Valid || entry.UserID.Int64 != userID {
+		role, _, err := s.members.GetRole(ctx, task.TeamID, userID)
+		if err != nil {
+			return err
+		}
+		if role != RoleOwner && role != RoleAdmin {
+			return ErrForbidden
+		}
+	}
+	if err := s.worklogs.Delete(ctx, logID); err != nil {
+		return err
+	}
+	s.invalidateTaskTag(ctx, entry.TaskID)
+	return nil
+}
+
+func (s *TaskService) StartTimer(ctx context.Context, userID, taskID int64) error {
+	if s.worklogs == nil {
+		return ErrUnavailable
+	}
+	if _, err := s.GetTask(ctx, userID, taskID); err != nil {
+		return err
+	}
+	if err := s.worklogs.StartTimer(ctx, taskID, userID); err != nil {
+		if isDuplicate(err) {
+			return ErrConflict
+		}
+		return err
+	}
+	s.invalidateTaskTag(ctx, taskID)
+	return nil
+}
+
+// StopTimer converts the caller's running timer into work logs, one per UTC day it ran,
+// each rounded up to whole minutes, so no entry exceeds what LogWork accepts. Days older
+// than maxWorkLogAge are dropped, as LogWork would reject them.
+func (s *TaskService) StopTimer(ctx context.Context, userID, taskID int64, note string) ([]repository.TaskWorkLog, error) {
+	if s.worklogs == nil || s.db == nil {
+		return nil, ErrUnavailable
+	}
+	noteValue, err := workLogNote(note)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.GetTask(ctx, userID, taskID); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			_ = tx.Rollback()
+		}
+	}()
+
+	timer, err := s.worklogs.TakeTimerTx(ctx, tx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if timer == nil {
+		return nil, ErrNotFound
+	}
+	now := time.Now().UTC()
+	start := timer.StartedAt.UTC()
+	if oldest := truncateToDate(now.Add(-maxWorkLogAge)); start.Before(oldest) {
+		start = oldest
+	}
+
+	var entries []repository.TaskWorkLog
+	for _, span := range timerDays(start, now) {
+		entry := repository.TaskWorkLog{
+			TaskID:    taskID,
+			UserID:    sql.NullInt64{Int64: userID, Valid: true},
+			Minutes:   span.minutes,
+			WorkDate:  span.day,
+			Note:      noteValue,
+			CreatedAt: now,
+		}
+		if entry.ID, err = s.worklogs.CreateTx(ctx, tx, entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	committed = true
+	s.invalidateTaskTag(ctx, taskID)
+	return entries, nil
+}
+
+type timerDay struct {
+	day     time.Time
+	minutes int64
+}
+
+// timerDays splits [start, end) at UTC midnights. A day holds at most 24h, so its rounded
+// minutes stay within maxWorkLogMinutes. A zero or negative span still logs one minute.
+func timerDays(start, end time.Time) []timerDay {
+	if !end.After(start) {
+		return []timerDay{{day: truncateToDate(start), minutes: 1}}
+	}
+	var out []timerDay
+	for from := start; from.Before(end); {
+		day := truncateToDate(from)
+		to := day.AddDate(0, 0, 1)
+		if to.After(end) {
+			to = end
+		}
+		minutes := int64((to.Sub(from) + time.Minute - 1) / time.Minute)
+		out = append(out, timerDay{day: day, minutes: min(max(minutes, 1), maxWorkLogMinutes)})
+		from = to
+	}
+	return out
+}
+
+// TaskTimeSummary assumes the caller already passed GetTask for taskID.
+func (s *TaskService) TaskTimeSummary(ctx context.Context, userID, taskID int64) (*TaskTimeSummary, error) {
+	if s.worklogs == nil {
+		return nil, nil
+	}
+	total, err := s.worklogs.TotalMinutesByTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	summary := &TaskTimeSummary{LoggedMinutes: total}
+	timer, err := s.worklogs.GetTimer(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if timer != nil {
+		summary.TimerStartedAt = &timer.StartedAt
+	}
+	return summary, nil
+}
+
+func workLogNote(v string) (sql.NullString, error) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return sql.NullString{}, nil
+	}
+	if utf8.RuneCountInString(v) > maxWorkLogNote {
+		return sql.NullString{}, ErrBadRequest
+	}
+	return sql.NullString{String: v, Valid: true}, nil
+}
+
+func truncateToDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
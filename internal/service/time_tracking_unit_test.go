@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+
+	"MKK-Luna/internal/repository"
+)
+
+type fakeWorkLogRepo struct {
+	logs     map[int64]repository.TaskWorkLog
+	timers   map[int64]time.Time
+	startErr error
+}
+
+func (f *fakeWorkLogRepo) Create(_ context.Context, l repository.TaskWorkLog) (int64, error) {
+	if f.logs == nil {
+		f.logs = map[int64]repository.TaskWorkLog{}
+	}
+	l.ID = int64(len(f.logs) + 1)
+	f.logs[l.ID] = l
+	return l.ID, nil
+}
+
+func (f *fakeWorkLogRepo) CreateTx(ctx context.Context, _ *sqlx.Tx, l repository.TaskWorkLog) (int64, error) {
+	return f.Create(ctx, l)
+}
+
+func (f *fakeWorkLogRepo) GetByID(_ context.Context, id int64) (*repository.TaskWorkLog, error) {
+	l, ok := f.logs[id]
+	if !ok {
+		return nil, nil
+	}
+	return &l, nil
+}
+
+func (f *fakeWorkLogRepo) ListByTask(context.Context, int64, int, int) ([]repository.TaskWorkLog, int64, error) {
+	return nil, 0, nil
+}
+
+func (f *fakeWorkLogRepo) TotalMinutesByTask(_ context.Context, taskID int64) (int64, error) {
+	var total int64
+	for _, l := range f.logs {
+		if l.TaskID == taskID {
+			total += l.Minutes
+		}
+	}
+	return total, nil
+}
+
+func (f *fakeWorkLogRepo) Delete(_ context.Context, id int64) error {
+	delete(f.logs, id)
+	return nil
+}
+
+func (f *fakeWorkLogRepo) StartTimer(_ context.Context, _, userID int64) error {
+	if f.startErr != nil {
+		return f.startErr
+	}
+	if f.timers == nil {
+		f.timers = map[int64]time.Time{}
+	}
+	f.timers[userID] = time.Now().UTC()
+	return nil
+}
+
+func (f *fakeWorkLogRepo) GetTimer(_ context.Context, taskID, userID int64) (*repository.TaskTimer, error) {
+	started, ok := f.timers[userID]
+	if !ok {
+		return nil, nil
+	}
+	return &repository.TaskTimer{TaskID: taskID, UserID: userID, StartedAt: started}, nil
+}
+
+func (f *fakeWorkLogRepo) TakeTimerTx(ctx context.Context, _ *sqlx.Tx, taskID, userID int64) (*repository.TaskTimer, error) {
+	timer, err := f.GetTimer(ctx, taskID, userID)
+	delete(f.timers, userID)
+	return timer, err
+}
+
+func newTimeTrackingService(t *testing.T, db *sqlx.DB, members *fakeMemberRepo, worklogs *fakeWorkLogRepo) *TaskService {
+	t.Helper()
+	tasks := &fakeTaskRepo{getByID: func(context.Context, int64) (*repository.Task, error) {
+		return &repository.Task{ID: 1, TeamID: 10}, nil
+	}}
+	return NewTaskService(db, tasks, &fakeTeamRepo{}, members, &fakeCommentRepo{}, &fakeHistoryRepo{}, WithTimeTracking(worklogs))
+}
+
+func TestTaskService_LogWork(t *testing.T) {
+	worklogs := &fakeWorkLogRepo{}
+	svc := newTimeTrackingService(t, nil, &fakeMemberRepo{role: RoleMember, hasRole: true}, worklogs)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		in      WorkLogInput
+		wantErr error
+	}{
+		{name: "zero minutes", in: WorkLogInput{Minutes: 0}, wantErr: ErrBadRequest},
+		{name: "more than a day", in: WorkLogInput{Minutes: 24*60 + 1}, wantErr: ErrBadRequest},
+		{name: "future date", in: WorkLogInput{Minutes: 30, WorkDate: time.Now().Add(72 * time.Hour)}, wantErr: ErrBadRequest},
+		{name: "too old", in: WorkLogInput{Minutes: 30, WorkDate: time.Now().AddDate(-2, 0, 0)}, wantErr: ErrBadRequest},
+		{name: "ok default date", in: WorkLogInput{Minutes: 30, Note: "  review  "}},
+		{name: "ok explicit date", in: WorkLogInput{Minutes: 90, WorkDate: time.Now().AddDate(0, 0, -3)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.LogWork(ctx, 5, 1, tt.in); err != tt.wantErr {
+				t.Fatalf("err=%v want=%v", err, tt.wantErr)
+			}
+		})
+	}
+
+	summary, err := svc.TaskTimeSummary(ctx, 5, 1)
+	if err != nil || summary.LoggedMinutes != 120 {
+		t.Fatalf("summary=%+v err=%v", summary, err)
+	}
+	if worklogs.logs[1].Note.String != "review" {
+		t.Fatalf("note not trimmed: %q", worklogs.logs[1].Note.String)
+	}
+}
+
+func TestTaskService_DeleteWorkLog(t *testing.T) {
+	worklogs := &fakeWorkLogRepo{}
+	members := &fakeMemberRepo{role: RoleMember, hasRole: true}
+	svc := newTimeTrackingService(t, nil, members, worklogs)
+	ctx := context.Background()
+
+	id, err := svc.LogWork(ctx, 5, 1, WorkLogInput{Minutes: 15})
+	if err != nil {
+		t.Fatalf("log: %v", err)
+	}
+	if err := svc.DeleteWorkLog(ctx, 6, id); err != ErrForbidden {
+		t.Fatalf("expected ErrForbidden, got %v", err)
+	}
+	members.role = RoleAdmin
+	if err := svc.DeleteWorkLog(ctx, 6, id); err != nil {
+		t.Fatalf("admin delete: %v", err)
+	}
+	if err := svc.DeleteWorkLog(ctx, 6, id); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestTimerDays(t *testing.T) {
+	start := time.Date(2026, 3, 1, 22, 30, 0, 0, time.UTC)
+	days := timerDays(start, time.Date(2026, 3, 3, 0, 10, 30, 0, time.UTC))
+	if len(days) != 3 || days[0].minutes != 90 || days[1].minutes != maxWorkLogMinutes || days[2].minutes != 11 {
+		t.Fatalf("unexpected days %+v", days)
+	}
+	if !days[1].day.Equal(time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected day %v", days[1].day)
+	}
+	if days := timerDays(start, start); len(days) != 1 || days[0].minutes != 1 {
+		t.Fatalf("empty span: %+v", days)
+	}
+}
+
+func TestTaskService_Timer(t *testing.T) {
+	db, mock := newMockDB(t)
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	worklogs := &fakeWorkLogRepo{}
+	svc := newTimeTrackingService(t, db, &fakeMemberRepo{role: RoleMember, hasRole: true}, worklogs)
+	ctx := context.Background()
+
+	if err := svc.StartTimer(ctx, 5, 1); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	worklogs.timers[5] = time.Now().UTC().Add(-90 * time.Second)
+
+	entries, err := svc.StopTimer(ctx, 5, 1, "")
+	if err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	if len(entries) == 0 || entries[len(entries)-1].ID == 0 {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+	var total int64
+	for _, e := range entries {
+		total += e.Minutes
+	}
+	if total < 2 || total > 3 {
+		t.Fatalf("unexpected minutes %d in %+v", total, entries)
+	}
+	if _, err := svc.StopTimer(ctx, 5, 1, ""); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound without running timer, got %v", err)
+	}
+
+	worklogs.startErr = &mysql.MySQLError{Number: 1062}
+	if err := svc.StartTimer(ctx, 5, 1); err != ErrConflict {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+}
+
+func TestTaskService_UpdateTask_Estimates(t *testing.T) {
+	tests := []struct {
+		name    string
+		role    string
+		raw     map[string]json.RawMessage
+		wantErr error
+	}{
+		{name: "negative", role: RoleOwner, raw: map[string]json.RawMessage{"original_estimate_minutes": json.RawMessage(`-1`)}, wantErr: ErrBadRequest},
+		{name: "too large", role: RoleOwner, raw: map[string]json.RawMessage{"original_estimate_minutes": json.RawMessage(`100001`)}, wantErr: ErrBadRequest},
+		{name: "member original", role: RoleMember, raw: map[string]json.RawMessage{"original_estimate_minutes": json.RawMessage(`60`)}, wantErr: ErrForbidden},
+		{name: "member remaining", role: RoleMember, raw: map[string]json.RawMessage{"remaining_estimate_minutes": json.RawMessage(`30`)}},
+		{name: "owner clears", role: RoleOwner, raw: map[string]json.RawMessage{"original_estimate_minutes": json.RawMessage(`null`)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := &repository.Task{ID: 1, TeamID: 10}
+			task.OriginalEstimate.Int64, task.OriginalEstimate.Valid = 120, true
+			svc := NewTaskService(nil,
+				&fakeTaskRepo{getByID: func(context.Context, int64) (*repository.Task, error) { return task, nil }},
+				&fakeTeamRepo{}, &fakeMemberRepo{role: tt.role, hasRole: true}, &fakeCommentRepo{}, &fakeHistoryRepo{},
+			)
+			if _, err := svc.UpdateTask(context.Background(), 1, 1, tt.raw); err != tt.wantErr {
+				t.Fatalf("err=%v want=%v", err, tt.wantErr)
+			}
+		})
+	}
+}
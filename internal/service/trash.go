@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"MKK-Luna/internal/repository"
+)
+
+type taskTrashRepo interface {
+	ListDeleted(ctx context.Context, teamID int64, limit, offset int) ([]repository.TrashedTask, int64, error)
+	ListPurgeable(ctx context.Context, deletedBefore time.Time, limit int) ([]int64, error)
+	PurgeTx(ctx context.Context, tx *sqlx.Tx, taskID int64, deletedBefore time.Time) (bool, error)
+}
+
+func WithTrash(trash taskTrashRepo) TaskServiceOption {
+	return func(s *TaskService) { s.trash = trash }
+}
+
+// ListTrash shows soft-deleted tasks to the people who can delete and restore them.
+func (s *TaskService) ListTrash(ctx context.Context, userID, teamID int64, limit, offset int) ([]repository.TrashedTask, int64, error) {
+	if s.trash == nil {
+		return nil, 0, ErrUnavailable
+	}
+	team, err := s.teams.GetByID(ctx, teamID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if team == nil {
+		return nil, 0, ErrNotFound
+	}
+	role, ok, err := s.members.GetRole(ctx, teamID, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !ok || (role != RoleOwner && role != RoleAdmin) {
+		return nil, 0, ErrForbidden
+	}
+	return s.trash.ListDeleted(ctx, teamID, limit, offset)
+}
+
+// PurgeDeletedTasks hard-deletes up to limit tasks that have been in the trash since before
+// deletedBefore, then removes their attachment blobs.
+func (s *TaskService) PurgeDeletedTasks(ctx context.Context, deletedBefore time.Time, limit int) (int, error) {
+	if s.db == nil || s.trash == nil {
+		return 0, ErrUnavailable
+	}
+	ids, err := s.trash.ListPurgeable(ctx, deletedBefore, limit)
+	if err != nil {
+		return 0, err
+	}
+	purged := 0
+	for _, id := range ids {
+		ok, err := s.purgeTask(ctx, id, deletedBefore)
+		if err != nil {
+			return purged, err
+		}
+		if ok {
+			purged++
+			s.audit.Record(ctx, AuditEntry{Action: AuditTaskPurged, TargetType: auditTargetTask, TargetID: id})
+		}
+	}
+	return purged, nil
+}
+
+func (s *TaskService) purgeTask(ctx context.Context, taskID int64, deletedBefore time.Time) (bool, error) {
+	tx, err := s.db.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return false, err
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			_ = tx.Rollback()
+		}
+	}()
+
+	blobKeys, err := s.attachmentKeysTx(ctx, tx, taskID)
+	if err != nil {
+		return false, err
+	}
+	// The task may have been restored since it was listed; PurgeTx re-checks deleted_at.
+	ok, err := s.trash.PurgeTx(ctx, tx, taskID, deletedBefore)
+	if err != nil {
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	committed = true
+	if ok {
+		s.removeBlobs(ctx, blobKeys)
+	}
+	return ok, nil
+}
@@ -0,0 +1,405 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jmoiron/sqlx"
+	"golang.org/x/crypto/bcrypt"
+
+	"MKK-Luna/internal/repository"
+)
+
+type UserService struct {
+	db           *sqlx.DB
+	users        accountUserStore
+	emailChanges emailChangeStore
+	sessions     accountSessionStore
+	teams        accountTeamStore
+	members      accountMemberStore
+	comments     accountCommentStore
+	verifier     VerificationSender
+	tokenTTL     time.Duration
+	logger       *slog.Logger
+	audit        *AuditService
+}
+
+type accountUserStore interface {
+	GetByID(ctx context.Context, userID int64) (*repository.User, error)
+	GetByEmail(ctx context.Context, email string) (*repository.User, error)
+	GetProfile(ctx context.Context, userID int64) (*repository.UserProfile, error)
+	UpdateProfile(ctx context.Context, userID int64, fields map[string]any) error
+	UpdateEmailTx(ctx context.Context, tx *sqlx.Tx, userID int64, email string) error
+	DeleteTx(ctx context.Context, tx *sqlx.Tx, userID int64) error
+	SearchSharingTeam(ctx context.Context, userID int64, q string, limit int) ([]repository.UserSummary, error)
+}
+
+type emailChangeStore interface {
+	Create(ctx context.Context, userID int64, newEmail, tokenHash string, expiresAt time.Time) (int64, error)
+	GetByTokenHashForUpdateTx(ctx context.Context, tx *sqlx.Tx, tokenHash string) (*repository.UserEmailChange, error)
+	ConfirmTx(ctx context.Context, tx *sqlx.Tx, id int64, confirmedAt time.Time) error
+	DeletePendingByUser(ctx context.Context, userID int64) error
+}
+
+type accountSessionStore interface {
+	RevokeAllByUserTx(ctx context.Context, tx *sqlx.Tx, userID int64, revokedAt time.Time) error
+}
+
+type accountTeamStore interface {
+	DeleteTx(ctx context.Context, tx *sqlx.Tx, teamID int64) error
+}
+
+type accountMemberStore interface {
+	GetRoleForUpdateTx(ctx context.Context, tx *sqlx.Tx, teamID, userID int64) (string, bool, error)
+	ListOwnershipByUserForUpdateTx(ctx context.Context, tx *sqlx.Tx, userID int64) ([]repository.TeamOwnership, error)
+	SetRoleTx(ctx context.Context, tx *sqlx.Tx, teamID, userID int64, role string) error
+}
+
+type accountCommentStore interface {
+	AnonymizeByUserTx(ctx context.Context, tx *sqlx.Tx, userID int64) error
+}
+
+type VerificationSender interface {
+	SendEmailVerification(ctx context.Context, toEmail, token string) error
+}
+
+type UserServiceOption func(*UserService)
+
+func NewUserService(
+	db *sqlx.DB,
+	users accountUserStore,
+	emailChanges emailChangeStore,
+	sessions accountSessionStore,
+	teams accountTeamStore,
+	members accountMemberStore,
+	comments accountCommentStore,
+	verifier VerificationSender,
+	tokenTTL time.Duration,
+	logger *slog.Logger,
+	opts ...UserServiceOption,
+) *UserService {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	s := &UserService{
+		db: db, users: users, emailChanges: emailChanges, sessions: sessions, teams: teams,
+		members: members, comments: comments, verifier: verifier, tokenTTL: tokenTTL, logger: logger,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *UserService) GetProfile(ctx context.Context, userID int64) (*repository.UserProfile, error) {
+	profile, err := s.users.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		return nil, ErrNotFound
+	}
+	return profile, nil
+}
+
+func (s *UserService) UpdateProfile(ctx context.Context, userID int64, raw map[string]json.RawMessage) error {
+	fields, err := parseProfilePatch(raw)
+	if err != nil {
+		return err
+	}
+	profile, err := s.users.GetProfile(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if profile == nil {
+		return ErrNotFound
+	}
+	return s.users.UpdateProfile(ctx, userID, fields)
+}
+
+func (s *UserService) SearchUsers(ctx context.Context, userID int64, q string, limit int) ([]repository.UserSummary, error) {
+	q = strings.TrimSpace(q)
+	if q == "" || utf8.RuneCountInString(q) > 100 {
+		return nil, ErrBadRequest
+	}
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+	return s.users.SearchSharingTeam(ctx, userID, q, limit)
+}
+
+func (s *UserService) RequestEmailChange(ctx context.Context, userID int64, newEmail, password string) error {
+	newEmail = strings.TrimSpace(newEmail)
+	if err := validateEmail(newEmail); err != nil {
+		return ErrBadRequest
+	}
+	user, err := s.verifyPassword(ctx, userID, password)
+	if err != nil {
+		return err
+	}
+	if strings.EqualFold(user.Email, newEmail) {
+		return ErrBadRequest
+	}
+	if existing, err := s.users.GetByEmail(ctx, newEmail); err != nil {
+		return err
+	} else if existing != nil {
+		return ErrConflict
+	}
+
+	token, err := newOpaqueToken()
+	if err != nil {
+		return err
+	}
+	if err := s.emailChanges.DeletePendingByUser(ctx, userID); err != nil {
+		return err
+	}
+	expiresAt := time.Now().UTC().Add(s.tokenTTL)
+	if _, err := s.emailChanges.Create(ctx, userID, newEmail, hashToken(token), expiresAt); err != nil {
+		return err
+	}
+
+	if s.verifier != nil {
+		if err := s.verifier.SendEmailVerification(ctx, newEmail, token); err != nil {
+			return ErrUnavailable
+		}
+	}
+	s.logger.Info("account_event", "event", "email_change_requested", "user_id", userID)
+	return nil
+}
+
+func (s *UserService) ConfirmEmailChange(ctx context.Context, token string) error {
+	if strings.TrimSpace(token) == "" {
+		return ErrInvalidToken
+	}
+
+	tx, err := s.db.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	change, err := s.emailChanges.GetByTokenHashForUpdateTx(ctx, tx, hashToken(token))
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	if change == nil || change.ConfirmedAt != nil || change.ExpiresAt.Before(now) {
+		return ErrInvalidToken
+	}
+	if err := s.users.UpdateEmailTx(ctx, tx, change.UserID, change.NewEmail); err != nil {
+		if isDuplicate(err) {
+			return ErrConflict
+		}
+		return err
+	}
+	if err := s.emailChanges.ConfirmTx(ctx, tx, change.ID, now); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	s.logger.Info("account_event", "event", "email_changed", "user_id", change.UserID)
+	s.audit.Record(ctx, AuditEntry{
+		ActorID: change.UserID, Action: AuditEmailChanged, TargetType: auditTargetUser, TargetID: change.UserID,
+	})
+	return nil
+}
+
+// Sole-owned teams with other members need a transfer target, otherwise ErrConflict. The
+// ownership check, session revocation and the deletion share one transaction.
+func (s *UserService) DeleteAccount(ctx context.Context, userID int64, password string, transfers map[int64]int64) error {
+	if _, err := s.verifyPassword(ctx, userID, password); err != nil {
+		return err
+	}
+	if s.db == nil {
+		return ErrUnavailable
+	}
+
+	tx, err := s.db.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	owned, err := s.members.ListOwnershipByUserForUpdateTx(ctx, tx, userID)
+	if err != nil {
+		return err
+	}
+	promote := make(map[int64]int64)
+	var drop []int64
+	for _, o := range owned {
+		switch {
+		case o.OwnersCount > 1:
+			continue
+		case o.MembersCount <= 1:
+			drop = append(drop, o.TeamID)
+		default:
+			target, ok := transfers[o.TeamID]
+			if !ok {
+				return ErrConflict
+			}
+			if target == userID {
+				return ErrBadRequest
+			}
+			if _, ok, err := s.members.GetRoleForUpdateTx(ctx, tx, o.TeamID, target); err != nil {
+				return err
+			} else if !ok {
+				return ErrBadRequest
+			}
+			promote[o.TeamID] = target
+		}
+	}
+
+	for teamID, target := range promote {
+		if err := s.members.SetRoleTx(ctx, tx, teamID, target, RoleOwner); err != nil {
+			return err
+		}
+	}
+	for _, teamID := range drop {
+		if err := s.teams.DeleteTx(ctx, tx, teamID); err != nil {
+			return err
+		}
+	}
+	if err := s.comments.AnonymizeByUserTx(ctx, tx, userID); err != nil {
+		return err
+	}
+	// Revoke before the delete: fk_sessions_user_id cascades, so afterwards there is nothing left to revoke.
+	if err := s.sessions.RevokeAllByUserTx(ctx, tx, userID, time.Now().UTC()); err != nil {
+		return err
+	}
+	if err := s.users.DeleteTx(ctx, tx, userID); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	s.audit.Record(ctx, AuditEntry{
+		ActorID: userID, Action: AuditSessionsRevoked, TargetType: auditTargetUser, TargetID: userID,
+		Payload: map[string]any{"reason": "account_deleted"},
+	})
+
+	s.logger.Info("account_event",
+		"event", "account_deleted",
+		"user_id", userID,
+		"teams_transferred", len(promote),
+		"teams_deleted", len(drop),
+	)
+	for teamID, target := range promote {
+		s.audit.Record(ctx, AuditEntry{
+			ActorID: userID, Action: AuditMemberRoleChanged, TargetType: auditTargetUser, TargetID: target, TeamID: teamID,
+			Payload: map[string]any{"role": RoleOwner, "reason": "ownership_transfer"},
+		})
+	}
+	for _, teamID := range drop {
+		s.audit.Record(ctx, AuditEntry{
+			ActorID: userID, Action: AuditTeamDeleted, TargetType: auditTargetTeam, TargetID: teamID, TeamID: teamID,
+			Payload: map[string]any{"reason": "account_deleted"},
+		})
+	}
+	s.audit.Record(ctx, AuditEntry{
+		ActorID: userID, Action: AuditAccountDeleted, TargetType: auditTargetUser, TargetID: userID,
+		Payload: map[string]any{"teams_transferred": len(promote), "teams_deleted": len(drop)},
+	})
+	return nil
+}
+
+func (s *UserService) verifyPassword(ctx context.Context, userID int64, password string) (*repository.User, error) {
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrNotFound
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) != nil {
+		return nil, ErrForbidden
+	}
+	return user, nil
+}
+
+var localeRegex = regexp.MustCompile(`^[a-z]{2}(-[A-Z]{2})?$`)
+
+func parseProfilePatch(raw map[string]json.RawMessage) (map[string]any, error) {
+	fields := make(map[string]any, len(raw))
+	for key, val := range raw {
+		switch key {
+		case "display_name":
+			var v *string
+			if err := json.Unmarshal(val, &v); err != nil {
+				return nil, ErrBadRequest
+			}
+			if v == nil || strings.TrimSpace(*v) == "" {
+				fields[key] = nil
+				continue
+			}
+			name := strings.TrimSpace(*v)
+			if utf8.RuneCountInString(name) > 100 {
+				return nil, ErrBadRequest
+			}
+			fields[key] = name
+		case "avatar_url":
+			var v *string
+			if err := json.Unmarshal(val, &v); err != nil {
+				return nil, ErrBadRequest
+			}
+			if v == nil || strings.TrimSpace(*v) == "" {
+				fields[key] = nil
+				continue
+			}
+			if !isValidAvatarURL(*v) {
+				return nil, ErrBadRequest
+			}
+			fields[key] = *v
+		case "timezone":
+			var v string
+			if err := json.Unmarshal(val, &v); err != nil || v == "" || len(v) > 64 {
+				return nil, ErrBadRequest
+			}
+			if _, err := time.LoadLocation(v); err != nil {
+				return nil, ErrBadRequest
+			}
+			fields[key] = v
+		case "locale":
+			var v string
+			if err := json.Unmarshal(val, &v); err != nil || !localeRegex.MatchString(v) {
+				return nil, ErrBadRequest
+			}
+			fields[key] = v
+		default:
+			return nil, ErrBadRequest
+		}
+	}
+	if len(fields) == 0 {
+		return nil, ErrBadRequest
+	}
+	return fields, nil
+}
+
+func isValidAvatarURL(v string) bool {
+	if len(v) > 512 {
+		return false
+	}
+	u, err := url.Parse(v)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	return u.Scheme == "https" || u.Scheme == "http"
+}
+
+func newOpaqueToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,278 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"golang.org/x/crypto/bcrypt"
+
+	"MKK-Luna/internal/repository"
+)
+
+type fakeAccountUsers struct {
+	user      *repository.User
+	byEmail   *repository.User
+	profile   *repository.UserProfile
+	updated   map[string]any
+	deletedID int64
+	searched  string
+	limit     int
+}
+
+func (f *fakeAccountUsers) GetByID(context.Context, int64) (*repository.User, error) {
+	return f.user, nil
+}
+func (f *fakeAccountUsers) GetByEmail(context.Context, string) (*repository.User, error) {
+	return f.byEmail, nil
+}
+func (f *fakeAccountUsers) GetProfile(context.Context, int64) (*repository.UserProfile, error) {
+	return f.profile, nil
+}
+func (f *fakeAccountUsers) UpdateProfile(_ context.Context, _ int64, fields map[string]any) error {
+	f.updated = fields
+	return nil
+}
+func (f *fakeAccountUsers) UpdateEmailTx(context.Context, *sqlx.Tx, int64, string) error {
+	return nil
+}
+func (f *fakeAccountUsers) DeleteTx(_ context.Context, _ *sqlx.Tx, userID int64) error {
+	f.deletedID = userID
+	return nil
+}
+
+func (f *fakeAccountUsers) SearchSharingTeam(_ context.Context, _ int64, q string, limit int) ([]repository.UserSummary, error) {
+	f.searched = q
+	f.limit = limit
+	return []repository.UserSummary{{ID: 2, Username: "jane"}}, nil
+}
+
+type fakeEmailChanges struct {
+	created   string
+	tokenHash string
+}
+
+func (f *fakeEmailChanges) Create(_ context.Context, _ int64, newEmail, tokenHash string, _ time.Time) (int64, error) {
+	f.created = newEmail
+	f.tokenHash = tokenHash
+	return 1, nil
+}
+func (f *fakeEmailChanges) GetByTokenHashForUpdateTx(context.Context, *sqlx.Tx, string) (*repository.UserEmailChange, error) {
+	return nil, nil
+}
+func (f *fakeEmailChanges) ConfirmTx(context.Context, *sqlx.Tx, int64, time.Time) error { return nil }
+func (f *fakeEmailChanges) DeletePendingByUser(context.Context, int64) error            { return nil }
+
+type fakeAccountSessions struct {
+	revoked int64
+}
+
+func (f *fakeAccountSessions) RevokeAllByUserTx(_ context.Context, _ *sqlx.Tx, userID int64, _ time.Time) error {
+	f.revoked = userID
+	return nil
+}
+
+type fakeAccountTeams struct {
+	deleted []int64
+}
+
+func (f *fakeAccountTeams) DeleteTx(_ context.Context, _ *sqlx.Tx, teamID int64) error {
+	f.deleted = append(f.deleted, teamID)
+	return nil
+}
+
+type fakeAccountMembers struct {
+	owned    []repository.TeamOwnership
+	members  map[int64]bool
+	promoted map[int64]int64
+}
+
+func (f *fakeAccountMembers) GetRoleForUpdateTx(_ context.Context, _ *sqlx.Tx, _ int64, userID int64) (string, bool, error) {
+	if f.members[userID] {
+		return RoleMember, true, nil
+	}
+	return "", false, nil
+}
+func (f *fakeAccountMembers) ListOwnershipByUserForUpdateTx(context.Context, *sqlx.Tx, int64) ([]repository.TeamOwnership, error) {
+	return f.owned, nil
+}
+func (f *fakeAccountMembers) SetRoleTx(_ context.Context, _ *sqlx.Tx, teamID, userID int64, _ string) error {
+	if f.promoted == nil {
+		f.promoted = make(map[int64]int64)
+	}
+	f.promoted[teamID] = userID
+	return nil
+}
+
+type fakeAccountComments struct {
+	anonymized int64
+}
+
+func (f *fakeAccountComments) AnonymizeByUserTx(_ context.Context, _ *sqlx.Tx, userID int64) error {
+	f.anonymized = userID
+	return nil
+}
+
+type fakeVerifier struct {
+	to    string
+	token string
+}
+
+func (f *fakeVerifier) SendEmailVerification(_ context.Context, toEmail, token string) error {
+	f.to = toEmail
+	f.token = token
+	return nil
+}
+
+func testUserWithPassword(t *testing.T, password string) *repository.User {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("bcrypt: %v", err)
+	}
+	return &repository.User{ID: 1, Email: "old@test.com", Username: "user", PasswordHash: string(hash)}
+}
+
+func TestParseProfilePatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     map[string]json.RawMessage
+		wantErr bool
+	}{
+		{name: "display name", raw: map[string]json.RawMessage{"display_name": json.RawMessage(`"Jane"`)}},
+		{name: "clear display name", raw: map[string]json.RawMessage{"display_name": json.RawMessage(`null`)}},
+		{name: "avatar https", raw: map[string]json.RawMessage{"avatar_url": json.RawMessage(`"https://cdn.test/a.png"`)}},
+		{name: "avatar bad scheme", raw: map[string]json.RawMessage{"avatar_url": json.RawMessage(`"ftp://cdn.test/a.png"`)}, wantErr: true},
+		{name: "timezone", raw: map[string]json.RawMessage{"timezone": json.RawMessage(`"Europe/Moscow"`)}},
+		{name: "bad timezone", raw: map[string]json.RawMessage{"timezone": json.RawMessage(`"Mars/Olympus"`)}, wantErr: true},
+		{name: "locale", raw: map[string]json.RawMessage{"locale": json.RawMessage(`"ru-RU"`)}},
+		{name: "bad locale", raw: map[string]json.RawMessage{"locale": json.RawMessage(`"russian"`)}, wantErr: true},
+		{name: "unknown field", raw: map[string]json.RawMessage{"email": json.RawMessage(`"x@test.com"`)}, wantErr: true},
+		{name: "empty", raw: map[string]json.RawMessage{}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseProfilePatch(tt.raw)
+			if tt.wantErr && err != ErrBadRequest {
+				t.Fatalf("expected ErrBadRequest, got %v", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+		})
+	}
+}
+
+func TestUserService_RequestEmailChange(t *testing.T) {
+	users := &fakeAccountUsers{user: testUserWithPassword(t, "password123")}
+	changes := &fakeEmailChanges{}
+	verifier := &fakeVerifier{}
+	svc := NewUserService(nil, users, changes, &fakeAccountSessions{}, &fakeAccountTeams{}, &fakeAccountMembers{}, &fakeAccountComments{}, verifier, time.Hour, nil)
+
+	if err := svc.RequestEmailChange(context.Background(), 1, "new@test.com", "wrong-password1"); err != ErrForbidden {
+		t.Fatalf("expected ErrForbidden for wrong password, got %v", err)
+	}
+	if err := svc.RequestEmailChange(context.Background(), 1, "OLD@test.com", "password123"); err != ErrBadRequest {
+		t.Fatalf("expected ErrBadRequest for same email, got %v", err)
+	}
+
+	users.byEmail = &repository.User{ID: 2}
+	if err := svc.RequestEmailChange(context.Background(), 1, "taken@test.com", "password123"); err != ErrConflict {
+		t.Fatalf("expected ErrConflict for taken email, got %v", err)
+	}
+
+	users.byEmail = nil
+	if err := svc.RequestEmailChange(context.Background(), 1, "new@test.com", "password123"); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if changes.created != "new@test.com" || verifier.to != "new@test.com" {
+		t.Fatalf("change not recorded: created=%q sent=%q", changes.created, verifier.to)
+	}
+	if changes.tokenHash != hashToken(verifier.token) {
+		t.Fatalf("stored hash must match the sent token")
+	}
+}
+
+func TestUserService_DeleteAccount_RequiresTransfer(t *testing.T) {
+	db, mock := newMockDB(t)
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	users := &fakeAccountUsers{user: testUserWithPassword(t, "password123")}
+	members := &fakeAccountMembers{
+		owned:   []repository.TeamOwnership{{TeamID: 7, OwnersCount: 1, MembersCount: 3}},
+		members: map[int64]bool{5: true},
+	}
+	sessions := &fakeAccountSessions{}
+	svc := NewUserService(db, users, &fakeEmailChanges{}, sessions, &fakeAccountTeams{}, members, &fakeAccountComments{}, nil, time.Hour, nil)
+
+	if err := svc.DeleteAccount(context.Background(), 1, "password123", nil); err != ErrConflict {
+		t.Fatalf("expected ErrConflict without transfer, got %v", err)
+	}
+	if err := svc.DeleteAccount(context.Background(), 1, "password123", map[int64]int64{7: 9}); err != ErrBadRequest {
+		t.Fatalf("expected ErrBadRequest for non-member target, got %v", err)
+	}
+	if sessions.revoked != 0 {
+		t.Fatalf("sessions must not be revoked when deletion is rejected")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+}
+
+func TestUserService_DeleteAccount_Success(t *testing.T) {
+	db, mock := newMockDB(t)
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	users := &fakeAccountUsers{user: testUserWithPassword(t, "password123")}
+	members := &fakeAccountMembers{
+		owned: []repository.TeamOwnership{
+			{TeamID: 7, OwnersCount: 1, MembersCount: 3},
+			{TeamID: 8, OwnersCount: 1, MembersCount: 1},
+			{TeamID: 9, OwnersCount: 2, MembersCount: 4},
+		},
+		members: map[int64]bool{5: true},
+	}
+	teams := &fakeAccountTeams{}
+	sessions := &fakeAccountSessions{}
+	comments := &fakeAccountComments{}
+	svc := NewUserService(db, users, &fakeEmailChanges{}, sessions, teams, members, comments, nil, time.Hour, nil)
+
+	if err := svc.DeleteAccount(context.Background(), 1, "password123", map[int64]int64{7: 5}); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if members.promoted[7] != 5 {
+		t.Fatalf("team 7 ownership not transferred: %v", members.promoted)
+	}
+	if len(teams.deleted) != 1 || teams.deleted[0] != 8 {
+		t.Fatalf("expected solo team 8 deleted, got %v", teams.deleted)
+	}
+	if sessions.revoked != 1 || comments.anonymized != 1 || users.deletedID != 1 {
+		t.Fatalf("cleanup incomplete: revoked=%d anonymized=%d deleted=%d", sessions.revoked, comments.anonymized, users.deletedID)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+}
+
+func TestUserService_SearchUsers(t *testing.T) {
+	users := &fakeAccountUsers{}
+	svc := NewUserService(nil, users, &fakeEmailChanges{}, &fakeAccountSessions{}, &fakeAccountTeams{}, &fakeAccountMembers{}, &fakeAccountComments{}, nil, time.Hour, nil)
+
+	if _, err := svc.SearchUsers(context.Background(), 1, "   ", 10); err != ErrBadRequest {
+		t.Fatalf("expected ErrBadRequest for blank query, got %v", err)
+	}
+	items, err := svc.SearchUsers(context.Background(), 1, " ja ", 500)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if len(items) != 1 || users.searched != "ja" || users.limit != 20 {
+		t.Fatalf("unexpected search: items=%v q=%q limit=%d", items, users.searched, users.limit)
+	}
+}
@@ -0,0 +1,15 @@
+DELETE FROM task_comments WHERE user_id IS NULL;
+ALTER TABLE task_comments DROP FOREIGN KEY fk_task_comments_user_id;
+ALTER TABLE task_comments MODIFY user_id BIGINT NOT NULL;
+ALTER TABLE task_comments
+  ADD CONSTRAINT fk_task_comments_user_id
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+DROP TABLE user_email_changes;
+
+ALTER TABLE users
+  DROP COLUMN updated_at,
+  DROP COLUMN locale,
+  DROP COLUMN timezone,
+  DROP COLUMN avatar_url,
+  DROP COLUMN display_name;
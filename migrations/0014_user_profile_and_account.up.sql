@@ -0,0 +1,26 @@
+ALTER TABLE users
+  ADD COLUMN display_name VARCHAR(100) NULL AFTER username,
+  ADD COLUMN avatar_url VARCHAR(512) NULL AFTER display_name,
+  ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT 'UTC' AFTER avatar_url,
+  ADD COLUMN locale VARCHAR(16) NOT NULL DEFAULT 'en' AFTER timezone,
+  ADD COLUMN updated_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3) AFTER created_at;
+
+CREATE TABLE user_email_changes (
+  id BIGINT AUTO_INCREMENT PRIMARY KEY,
+  user_id BIGINT NOT NULL,
+  new_email VARCHAR(255) NOT NULL,
+  token_hash CHAR(64) NOT NULL,
+  expires_at DATETIME(3) NOT NULL,
+  confirmed_at DATETIME(3) NULL,
+  created_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+  UNIQUE KEY uq_user_email_changes_token_hash (token_hash),
+  KEY idx_user_email_changes_user_id (user_id),
+  CONSTRAINT fk_user_email_changes_user_id FOREIGN KEY (user_id)
+    REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+ALTER TABLE task_comments DROP FOREIGN KEY fk_task_comments_user_id;
+ALTER TABLE task_comments MODIFY user_id BIGINT NULL;
+ALTER TABLE task_comments
+  ADD CONSTRAINT fk_task_comments_user_id
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL;
@@ -0,0 +1,17 @@
+CREATE TABLE task_watchers (
+  task_id BIGINT NOT NULL,
+  user_id BIGINT NOT NULL,
+  created_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+  PRIMARY KEY (task_id, user_id),
+  KEY idx_task_watchers_user_id (user_id),
+  CONSTRAINT fk_task_watchers_task_id FOREIGN KEY (task_id)
+    REFERENCES tasks(id) ON DELETE CASCADE,
+  CONSTRAINT fk_task_watchers_user_id FOREIGN KEY (user_id)
+    REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+INSERT IGNORE INTO task_watchers (task_id, user_id)
+SELECT id, assignee_id FROM tasks WHERE assignee_id IS NOT NULL;
+
+INSERT IGNORE INTO task_watchers (task_id, user_id)
+SELECT id, created_by FROM tasks WHERE created_by IS NOT NULL;
@@ -0,0 +1,11 @@
+CREATE TABLE comment_mentions (
+  comment_id BIGINT NOT NULL,
+  user_id BIGINT NOT NULL,
+  created_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+  PRIMARY KEY (comment_id, user_id),
+  KEY idx_comment_mentions_user_id (user_id),
+  CONSTRAINT fk_comment_mentions_comment_id FOREIGN KEY (comment_id)
+    REFERENCES task_comments(id) ON DELETE CASCADE,
+  CONSTRAINT fk_comment_mentions_user_id FOREIGN KEY (user_id)
+    REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS comment_reactions;
+DROP TABLE IF EXISTS task_comment_edits;
+
+DELETE FROM task_comments WHERE deleted_at IS NOT NULL;
+ALTER TABLE task_comments DROP FOREIGN KEY fk_task_comments_parent_id;
+ALTER TABLE task_comments
+  DROP KEY idx_task_comments_parent_id,
+  DROP COLUMN deleted_at,
+  DROP COLUMN edited_at,
+  DROP COLUMN parent_id;
@@ -0,0 +1,33 @@
+ALTER TABLE task_comments
+  ADD COLUMN parent_id BIGINT NULL AFTER task_id,
+  ADD COLUMN edited_at DATETIME(3) NULL AFTER body,
+  ADD COLUMN deleted_at DATETIME(3) NULL AFTER edited_at,
+  ADD KEY idx_task_comments_parent_id (parent_id),
+  ADD CONSTRAINT fk_task_comments_parent_id
+    FOREIGN KEY (parent_id) REFERENCES task_comments(id) ON DELETE CASCADE;
+
+CREATE TABLE task_comment_edits (
+  id BIGINT AUTO_INCREMENT PRIMARY KEY,
+  comment_id BIGINT NOT NULL,
+  edited_by BIGINT NULL,
+  body TEXT NOT NULL,
+  created_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+  KEY idx_task_comment_edits_comment_id (comment_id, created_at),
+  CONSTRAINT fk_task_comment_edits_comment_id FOREIGN KEY (comment_id)
+    REFERENCES task_comments(id) ON DELETE CASCADE,
+  CONSTRAINT fk_task_comment_edits_edited_by FOREIGN KEY (edited_by)
+    REFERENCES users(id) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+CREATE TABLE comment_reactions (
+  comment_id BIGINT NOT NULL,
+  user_id BIGINT NOT NULL,
+  emoji VARCHAR(32) NOT NULL,
+  created_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+  PRIMARY KEY (comment_id, user_id, emoji),
+  KEY idx_comment_reactions_user_id (user_id),
+  CONSTRAINT fk_comment_reactions_comment_id FOREIGN KEY (comment_id)
+    REFERENCES task_comments(id) ON DELETE CASCADE,
+  CONSTRAINT fk_comment_reactions_user_id FOREIGN KEY (user_id)
+    REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
@@ -0,0 +1,20 @@
+CREATE TABLE task_attachments (
+  id BIGINT AUTO_INCREMENT PRIMARY KEY,
+  task_id BIGINT NOT NULL,
+  comment_id BIGINT NULL,
+  uploaded_by BIGINT NULL,
+  filename VARCHAR(255) NOT NULL,
+  content_type VARCHAR(127) NOT NULL,
+  size_bytes BIGINT NOT NULL,
+  storage_key VARCHAR(255) NOT NULL,
+  created_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+  UNIQUE KEY uq_task_attachments_storage_key (storage_key),
+  KEY idx_task_attachments_task_id (task_id, created_at),
+  KEY idx_task_attachments_comment_id (comment_id),
+  CONSTRAINT fk_task_attachments_task_id FOREIGN KEY (task_id)
+    REFERENCES tasks(id) ON DELETE CASCADE,
+  CONSTRAINT fk_task_attachments_comment_id FOREIGN KEY (comment_id)
+    REFERENCES task_comments(id) ON DELETE SET NULL,
+  CONSTRAINT fk_task_attachments_uploaded_by FOREIGN KEY (uploaded_by)
+    REFERENCES users(id) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
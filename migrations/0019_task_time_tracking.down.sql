@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS task_timers;
+DROP TABLE IF EXISTS task_work_logs;
+
+ALTER TABLE tasks
+  DROP COLUMN remaining_estimate_minutes,
+  DROP COLUMN original_estimate_minutes;
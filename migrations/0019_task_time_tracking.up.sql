@@ -0,0 +1,31 @@
+ALTER TABLE tasks
+  ADD COLUMN original_estimate_minutes INT UNSIGNED NULL AFTER due_date,
+  ADD COLUMN remaining_estimate_minutes INT UNSIGNED NULL AFTER original_estimate_minutes;
+
+CREATE TABLE task_work_logs (
+  id BIGINT AUTO_INCREMENT PRIMARY KEY,
+  task_id BIGINT NOT NULL,
+  user_id BIGINT NULL,
+  minutes INT UNSIGNED NOT NULL,
+  work_date DATE NOT NULL,
+  note VARCHAR(1000) NULL,
+  created_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+  KEY idx_task_work_logs_task_id (task_id, work_date),
+  KEY idx_task_work_logs_user_date (user_id, work_date),
+  CONSTRAINT fk_task_work_logs_task_id FOREIGN KEY (task_id)
+    REFERENCES tasks(id) ON DELETE CASCADE,
+  CONSTRAINT fk_task_work_logs_user_id FOREIGN KEY (user_id)
+    REFERENCES users(id) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+CREATE TABLE task_timers (
+  task_id BIGINT NOT NULL,
+  user_id BIGINT NOT NULL,
+  started_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+  PRIMARY KEY (task_id, user_id),
+  KEY idx_task_timers_user_id (user_id),
+  CONSTRAINT fk_task_timers_task_id FOREIGN KEY (task_id)
+    REFERENCES tasks(id) ON DELETE CASCADE,
+  CONSTRAINT fk_task_timers_user_id FOREIGN KEY (user_id)
+    REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
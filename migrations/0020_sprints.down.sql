@@ -0,0 +1,6 @@
+ALTER TABLE tasks
+  DROP FOREIGN KEY fk_tasks_sprint_id,
+  DROP KEY idx_tasks_sprint_id,
+  DROP COLUMN sprint_id;
+
+DROP TABLE IF EXISTS sprints;
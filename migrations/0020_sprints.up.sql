@@ -0,0 +1,23 @@
+CREATE TABLE sprints (
+  id BIGINT AUTO_INCREMENT PRIMARY KEY,
+  team_id BIGINT NOT NULL,
+  name VARCHAR(100) NOT NULL,
+  goal TEXT NULL,
+  starts_on DATE NOT NULL,
+  ends_on DATE NOT NULL,
+  closed_at DATETIME(3) NULL,
+  created_by BIGINT NULL,
+  created_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+  updated_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),
+  KEY idx_sprints_team_id (team_id, starts_on),
+  CONSTRAINT fk_sprints_team_id FOREIGN KEY (team_id)
+    REFERENCES teams(id) ON DELETE CASCADE,
+  CONSTRAINT fk_sprints_created_by FOREIGN KEY (created_by)
+    REFERENCES users(id) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+ALTER TABLE tasks
+  ADD COLUMN sprint_id BIGINT NULL AFTER remaining_estimate_minutes,
+  ADD KEY idx_tasks_sprint_id (sprint_id, status),
+  ADD CONSTRAINT fk_tasks_sprint_id FOREIGN KEY (sprint_id)
+    REFERENCES sprints(id) ON DELETE SET NULL;
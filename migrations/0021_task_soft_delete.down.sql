@@ -0,0 +1,6 @@
+DELETE FROM tasks WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE tasks
+  DROP KEY idx_tasks_team_deleted_at,
+  DROP KEY idx_tasks_deleted_at,
+  DROP COLUMN deleted_at;
@@ -0,0 +1,4 @@
+ALTER TABLE tasks
+  ADD COLUMN deleted_at DATETIME(3) NULL,
+  ADD KEY idx_tasks_deleted_at (deleted_at),
+  ADD KEY idx_tasks_team_deleted_at (team_id, deleted_at);
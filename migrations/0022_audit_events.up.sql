@@ -0,0 +1,16 @@
+CREATE TABLE audit_events (
+  id BIGINT AUTO_INCREMENT PRIMARY KEY,
+  actor_id BIGINT NULL,
+  action VARCHAR(64) NOT NULL,
+  target_type VARCHAR(32) NOT NULL,
+  target_id BIGINT NULL,
+  team_id BIGINT NULL,
+  ip VARCHAR(45) NULL,
+  request_id VARCHAR(128) NULL,
+  payload JSON NULL,
+  created_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+  KEY idx_audit_events_team_id (team_id, id),
+  KEY idx_audit_events_actor_id (actor_id, id),
+  KEY idx_audit_events_action (action, id),
+  KEY idx_audit_events_created_at (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
@@ -0,0 +1,5 @@
+ALTER TABLE task_comments
+  DROP COLUMN version;
+
+ALTER TABLE tasks
+  DROP COLUMN version;
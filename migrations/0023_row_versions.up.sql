@@ -0,0 +1,5 @@
+ALTER TABLE tasks
+  ADD COLUMN version BIGINT NOT NULL DEFAULT 1 AFTER deleted_at;
+
+ALTER TABLE task_comments
+  ADD COLUMN version BIGINT NOT NULL DEFAULT 1 AFTER deleted_at;
@@ -0,0 +1,21 @@
+CREATE TABLE task_templates (
+  id BIGINT AUTO_INCREMENT PRIMARY KEY,
+  team_id BIGINT NOT NULL,
+  name VARCHAR(100) NOT NULL,
+  title_pattern VARCHAR(255) NOT NULL,
+  description TEXT NULL,
+  priority ENUM('low','medium','high') NULL,
+  default_assignee_id BIGINT NULL,
+  labels JSON NULL,
+  checklist JSON NULL,
+  created_by BIGINT NULL,
+  created_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+  updated_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),
+  UNIQUE KEY uq_task_templates_team_name (team_id, name),
+  CONSTRAINT fk_task_templates_team_id FOREIGN KEY (team_id)
+    REFERENCES teams(id) ON DELETE CASCADE,
+  CONSTRAINT fk_task_templates_default_assignee_id FOREIGN KEY (default_assignee_id)
+    REFERENCES users(id) ON DELETE SET NULL,
+  CONSTRAINT fk_task_templates_created_by FOREIGN KEY (created_by)
+    REFERENCES users(id) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
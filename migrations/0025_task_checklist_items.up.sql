@@ -0,0 +1,19 @@
+CREATE TABLE task_checklist_items (
+  id BIGINT AUTO_INCREMENT PRIMARY KEY,
+  task_id BIGINT NOT NULL,
+  body VARCHAR(500) NOT NULL,
+  done TINYINT(1) NOT NULL DEFAULT 0,
+  position INT NOT NULL,
+  assignee_id BIGINT NULL,
+  done_by BIGINT NULL,
+  done_at DATETIME(3) NULL,
+  created_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+  updated_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),
+  KEY idx_task_checklist_items_task_id (task_id, position),
+  CONSTRAINT fk_task_checklist_items_task_id FOREIGN KEY (task_id)
+    REFERENCES tasks(id) ON DELETE CASCADE,
+  CONSTRAINT fk_task_checklist_items_assignee_id FOREIGN KEY (assignee_id)
+    REFERENCES users(id) ON DELETE SET NULL,
+  CONSTRAINT fk_task_checklist_items_done_by FOREIGN KEY (done_by)
+    REFERENCES users(id) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS team_wip_limits;
+
+ALTER TABLE tasks
+  DROP KEY idx_tasks_board,
+  DROP COLUMN board_rank;
@@ -0,0 +1,16 @@
+ALTER TABLE tasks
+  ADD COLUMN board_rank VARCHAR(255) CHARACTER SET ascii COLLATE ascii_bin NULL,
+  ADD KEY idx_tasks_board (team_id, status, board_rank);
+
+CREATE TABLE team_wip_limits (
+  team_id BIGINT NOT NULL,
+  status ENUM('todo','in_progress','done') NOT NULL,
+  wip_limit INT NOT NULL,
+  updated_by BIGINT NULL,
+  updated_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),
+  PRIMARY KEY (team_id, status),
+  CONSTRAINT fk_team_wip_limits_team_id FOREIGN KEY (team_id)
+    REFERENCES teams(id) ON DELETE CASCADE,
+  CONSTRAINT fk_team_wip_limits_updated_by FOREIGN KEY (updated_by)
+    REFERENCES users(id) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
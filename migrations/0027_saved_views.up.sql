@@ -0,0 +1,21 @@
+CREATE TABLE saved_views (
+  id BIGINT AUTO_INCREMENT PRIMARY KEY,
+  team_id BIGINT NOT NULL,
+  owner_id BIGINT NULL,
+  owner_key BIGINT AS (COALESCE(owner_id, 0)) STORED,
+  name VARCHAR(100) NOT NULL,
+  filters JSON NULL,
+  sort VARCHAR(32) NOT NULL DEFAULT '',
+  visible_columns JSON NULL,
+  created_by BIGINT NULL,
+  created_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+  updated_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),
+  UNIQUE KEY uq_saved_views_team_owner_name (team_id, owner_key, name),
+  KEY idx_saved_views_owner_id (owner_id),
+  CONSTRAINT fk_saved_views_team_id FOREIGN KEY (team_id)
+    REFERENCES teams(id) ON DELETE CASCADE,
+  CONSTRAINT fk_saved_views_owner_id FOREIGN KEY (owner_id)
+    REFERENCES users(id) ON DELETE CASCADE,
+  CONSTRAINT fk_saved_views_created_by FOREIGN KEY (created_by)
+    REFERENCES users(id) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
@@ -0,0 +1,24 @@
+CREATE TABLE export_jobs (
+  id BIGINT AUTO_INCREMENT PRIMARY KEY,
+  team_id BIGINT NOT NULL,
+  requested_by BIGINT NOT NULL,
+  kind ENUM('tasks','history','comments') NOT NULL,
+  format ENUM('csv','ndjson') NOT NULL,
+  params JSON NOT NULL,
+  status ENUM('pending','running','done','failed') NOT NULL DEFAULT 'pending',
+  storage_key VARCHAR(255) NULL,
+  row_count BIGINT NULL,
+  size_bytes BIGINT NULL,
+  error VARCHAR(255) NULL,
+  created_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+  started_at DATETIME(3) NULL,
+  finished_at DATETIME(3) NULL,
+  expires_at DATETIME(3) NULL,
+  KEY idx_export_jobs_status (status, id),
+  KEY idx_export_jobs_expires_at (expires_at),
+  KEY idx_export_jobs_requested_by (requested_by),
+  CONSTRAINT fk_export_jobs_team_id FOREIGN KEY (team_id)
+    REFERENCES teams(id) ON DELETE CASCADE,
+  CONSTRAINT fk_export_jobs_requested_by FOREIGN KEY (requested_by)
+    REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
@@ -0,0 +1,16 @@
+CREATE TABLE task_imports (
+  team_id BIGINT NOT NULL,
+  external_id VARCHAR(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin NOT NULL,
+  task_id BIGINT NOT NULL,
+  source ENUM('csv','json') NOT NULL,
+  imported_by BIGINT NULL,
+  created_at DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+  PRIMARY KEY (team_id, external_id),
+  KEY idx_task_imports_task_id (task_id),
+  CONSTRAINT fk_task_imports_team_id FOREIGN KEY (team_id)
+    REFERENCES teams(id) ON DELETE CASCADE,
+  CONSTRAINT fk_task_imports_task_id FOREIGN KEY (task_id)
+    REFERENCES tasks(id) ON DELETE CASCADE,
+  CONSTRAINT fk_task_imports_imported_by FOREIGN KEY (imported_by)
+    REFERENCES users(id) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
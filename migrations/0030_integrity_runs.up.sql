@@ -0,0 +1,17 @@
+CREATE TABLE integrity_runs (
+  id BIGINT AUTO_INCREMENT PRIMARY KEY,
+  check_name VARCHAR(64) NOT NULL,
+  trigger_source ENUM('schedule','manual') NOT NULL,
+  triggered_by BIGINT NULL,
+  repair ENUM('none','dry_run','applied') NOT NULL DEFAULT 'none',
+  found INT NOT NULL DEFAULT 0,
+  repaired INT NOT NULL DEFAULT 0,
+  sample JSON NULL,
+  error VARCHAR(255) NULL,
+  started_at DATETIME(3) NOT NULL,
+  finished_at DATETIME(3) NOT NULL,
+  KEY idx_integrity_runs_check (check_name, id),
+  KEY idx_integrity_runs_started_at (started_at),
+  CONSTRAINT fk_integrity_runs_triggered_by FOREIGN KEY (triggered_by)
+    REFERENCES users(id) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
@@ -180,7 +180,7 @@ func createTask(t *testing.T, baseURL, token string, teamID int64, title string)
 
 func updateTask(t *testing.T, baseURL, token string, taskID int64, payload map[string]any, want int) {
 	t.Helper()
-	status, _ := doJSON(t, http.MethodPut, baseURL+"/api/v1/tasks/"+itoa(taskID), token, payload)
+	status, _ := doJSON(t, http.MethodPatch, baseURL+"/api/v1/tasks/"+itoa(taskID), token, payload)
 	if status != want {
 		t.Fatalf("update task status=%d want=%d", status, want)
 	}
@@ -293,6 +293,73 @@ func TestAnalyticsIntegrityQuery(t *testing.T) {
 	}
 }
 
+func TestAnalyticsLoggedTime(t *testing.T) {
+	if !integrationEnabled() {
+		t.Skip("set INTEGRATION=1 to run")
+	}
+
+	ctx := context.Background()
+	db := setupMySQLDB(t, ctx)
+	defer db.Close()
+
+	userRepo := repository.NewUserRepository(db)
+	owner, _ := userRepo.Create(ctx, "lt-owner@test.com", "ltowner", "hash")
+	member, _ := userRepo.Create(ctx, "lt-member@test.com", "ltmember", "hash")
+
+	teamID := insertTeam(t, ctx, db, "lt-team", owner)
+	memberRepo := repository.NewTeamMemberRepository(db)
+	_ = memberRepo.Add(ctx, teamID, owner, "owner")
+	_ = memberRepo.Add(ctx, teamID, member, "member")
+
+	at := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	task1 := insertTaskWithTimes(t, ctx, db, teamID, "billable-1", "todo", "medium", at, at, &owner)
+	task2 := insertTaskWithTimes(t, ctx, db, teamID, "billable-2", "todo", "medium", at, at, &owner)
+
+	worklogs := repository.NewTaskWorkLogRepository(db)
+	logWork := func(taskID, userID, minutes int64, day time.Time) {
+		t.Helper()
+		_, err := worklogs.Create(ctx, repository.TaskWorkLog{
+			TaskID: taskID, UserID: sql.NullInt64{Int64: userID, Valid: true}, Minutes: minutes, WorkDate: day,
+		})
+		if err != nil {
+			t.Fatalf("create work log: %v", err)
+		}
+	}
+	logWork(task1, member, 30, time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC))
+	logWork(task2, member, 45, time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC))
+	logWork(task1, owner, 60, time.Date(2026, 1, 6, 0, 0, 0, 0, time.UTC))
+	logWork(task1, member, 999, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC))
+
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	analytics := repository.NewAnalyticsRepository(db)
+
+	rows, err := analytics.GetLoggedTime(ctx, owner, from, to, repository.LoggedTimeFilter{})
+	if err != nil {
+		t.Fatalf("GetLoggedTime: %v", err)
+	}
+	got := make(map[int64]repository.LoggedTimeStat, len(rows))
+	for _, r := range rows {
+		got[r.UserID] = r
+	}
+	if r := got[member]; r.Minutes != 75 || r.EntriesCount != 2 || r.TasksCount != 2 {
+		t.Fatalf("member expected 75/2/2, got %+v", r)
+	}
+	if r := got[owner]; r.Minutes != 60 {
+		t.Fatalf("owner expected 60, got %+v", r)
+	}
+
+	rows, err = analytics.GetLoggedTime(ctx, owner, from, to, repository.LoggedTimeFilter{UserID: &owner})
+	if err != nil || len(rows) != 1 || rows[0].UserID != owner {
+		t.Fatalf("user filter rows=%+v err=%v", rows, err)
+	}
+
+	rows, err = analytics.GetLoggedTime(ctx, member, from, to, repository.LoggedTimeFilter{})
+	if err != nil || len(rows) != 0 {
+		t.Fatalf("plain member must not see team totals, rows=%+v err=%v", rows, err)
+	}
+}
+
 func TestAnalyticsExplainUsesIndexes(t *testing.T) {
 	if !integrationEnabled() {
 		t.Skip("set INTEGRATION=1 to run")
@@ -82,7 +82,7 @@ func TestAPIErrorBranches_400_403_404_409(t *testing.T) {
 	}
 
 	// 403: member patch forbidden field
-	status, _ = doJSONRequest(t, http.MethodPut, srv.URL+"/api/v1/tasks/"+itoa(taskID), memberToken, map[string]any{"title": "hack"})
+	status, _ = doJSONRequest(t, http.MethodPatch, srv.URL+"/api/v1/tasks/"+itoa(taskID), memberToken, map[string]any{"title": "hack"})
 	if status != http.StatusForbidden {
 		t.Fatalf("expected 403 member patch forbidden field, got %d", status)
 	}
@@ -101,11 +101,28 @@ func TestAPIErrorBranches_400_403_404_409(t *testing.T) {
 	}
 
 	// create one history entry
-	status, _ = doJSONRequest(t, http.MethodPut, srv.URL+"/api/v1/tasks/"+itoa(taskID), ownerToken, map[string]any{"status": "done"})
+	status, _ = doJSONRequest(t, http.MethodPatch, srv.URL+"/api/v1/tasks/"+itoa(taskID), ownerToken, map[string]any{"status": "done"})
 	if status != http.StatusOK {
 		t.Fatalf("expected 200 on patch for history, got %d", status)
 	}
 
+	// 400: PUT is a full replacement
+	status, _ = doJSONRequest(t, http.MethodPut, srv.URL+"/api/v1/tasks/"+itoa(taskID), ownerToken, map[string]any{"status": "todo"})
+	if status != http.StatusBadRequest {
+		t.Fatalf("expected 400 on partial PUT, got %d", status)
+	}
+
+	// 409: JSON Patch test op against a stale value
+	jsonPatch := map[string]string{"Content-Type": "application/json-patch+json"}
+	ops := []map[string]any{
+		{"op": "test", "path": "/status", "value": "todo"},
+		{"op": "replace", "path": "/status", "value": "in_progress"},
+	}
+	status, _ = doJSONRequestWithHeaders(t, http.MethodPatch, srv.URL+"/api/v1/tasks/"+itoa(taskID), ownerToken, jsonPatch, ops)
+	if status != http.StatusConflict {
+		t.Fatalf("expected 409 on failed JSON Patch test, got %d", status)
+	}
+
 	// 400: invalid limit
 	status, _ = doJSONRequest(t, http.MethodGet, srv.URL+"/api/v1/tasks/"+itoa(taskID)+"/history?limit=101", ownerToken, nil)
 	if status != http.StatusBadRequest {
@@ -0,0 +1,88 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	redisTC "github.com/testcontainers/testcontainers-go/modules/redis"
+
+	authinfra "MKK-Luna/internal/infra/auth"
+	cacheinfra "MKK-Luna/internal/infra/cache"
+	ideminfra "MKK-Luna/internal/infra/idempotency"
+)
+
+func TestRedisAdminOperations(t *testing.T) {
+	if os.Getenv("INTEGRATION") != "1" {
+		t.Skip("set INTEGRATION=1 to run")
+	}
+
+	ctx := context.Background()
+	redisC, err := redisTC.RunContainer(ctx)
+	if err != nil {
+		t.Fatalf("redis container: %v", err)
+	}
+	defer redisC.Terminate(ctx)
+
+	endpoint, err := redisC.Endpoint(ctx, "tcp")
+	if err != nil {
+		t.Fatalf("redis endpoint: %v", err)
+	}
+	client := redis.NewClient(&redis.Options{Addr: strings.TrimPrefix(endpoint, "tcp://")})
+
+	tasks := cacheinfra.NewTaskCache(client, time.Minute, true, nil, nil)
+	filters := map[string]string{"status": "todo"}
+	for _, teamID := range []int64{1, 2} {
+		if err := tasks.SetList(ctx, teamID, filters, []byte(`[]`)); err != nil {
+			t.Fatalf("set list: %v", err)
+		}
+	}
+	if n, err := tasks.InvalidateAll(ctx); err != nil || n != 2 {
+		t.Fatalf("invalidate all n=%d err=%v", n, err)
+	}
+	if _, ok, _ := tasks.GetList(ctx, 2, filters); ok {
+		t.Fatalf("expected miss after invalidate all")
+	}
+
+	stats := cacheinfra.NewStatsCache(client, time.Minute, true, nil, nil)
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	if err := stats.SetDone(ctx, 1, day, day, nil); err != nil {
+		t.Fatalf("set done: %v", err)
+	}
+	if n, err := stats.Flush(ctx); err != nil || n != 1 {
+		t.Fatalf("flush n=%d err=%v", n, err)
+	}
+
+	lockout := authinfra.NewLockout(client, 1, time.Minute, 128, nil, nil)
+	if locked, _, err := lockout.OnFailure(ctx, "alice"); err != nil || !locked {
+		t.Fatalf("expected lock, got %v err=%v", locked, err)
+	}
+	list, err := lockout.Locked(ctx, 10)
+	if err != nil || len(list) != 1 || list[0].Login != "alice" || list[0].TTL <= 0 {
+		t.Fatalf("locked list=%+v err=%v", list, err)
+	}
+	if ok, err := lockout.Unlock(ctx, "alice"); err != nil || !ok {
+		t.Fatalf("unlock ok=%v err=%v", ok, err)
+	}
+	if locked, _, _ := lockout.IsLocked(ctx, "alice"); locked {
+		t.Fatalf("expected unlocked")
+	}
+
+	store := ideminfra.NewStore(client)
+	key := ideminfra.ResponseKey(7, "abcd", "k1")
+	if err := store.Set(ctx, key, time.Minute, ideminfra.StoredResponse{Status: 201, RequestHash: "h"}); err != nil {
+		t.Fatalf("idem set: %v", err)
+	}
+	entries, err := store.ListByUser(ctx, 7, 10)
+	if err != nil || len(entries) != 1 || entries[0].Key != key || entries[0].Response.Status != 201 {
+		t.Fatalf("idem list=%+v err=%v", entries, err)
+	}
+	if ok, err := store.Delete(ctx, key); err != nil || !ok {
+		t.Fatalf("idem delete ok=%v err=%v", ok, err)
+	}
+}
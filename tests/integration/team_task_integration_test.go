@@ -161,7 +161,7 @@ func TestMemberTaskPatchRules(t *testing.T) {
 	outsiderID, _ := users.Create(ctx, "outsider@test.com", "outsider", "hash")
 
 	teamSvc := service.NewTeamService(db, teams, members, users, emailOKSender{}, nil, 0, nil, nil)
-	taskSvc := service.NewTaskService(db, tasks, teams, members, comments, history)
+	taskSvc := service.NewTaskService(db, tasks, teams, members, comments, history, service.WithTrash(tasks))
 
 	teamID, err := teamSvc.CreateTeam(ctx, ownerID, "team-b")
 	if err != nil {
@@ -350,21 +350,31 @@ func TestTaskAndCommentFlows(t *testing.T) {
 	if err := db.GetContext(ctx, &deletedRows, `SELECT COUNT(*) FROM task_history WHERE task_id = ? AND field_name = 'task_deleted'`, taskID); err != nil {
 		t.Fatalf("count deleted history rows: %v", err)
 	}
-	// task_history.task_id has ON DELETE CASCADE, so delete audit rows are removed with task.
-	if deletedRows != 0 {
-		t.Fatalf("expected task_deleted row to be cascaded, got %d", deletedRows)
+	// Deleted tasks stay in the trash, so their history survives until purge.
+	if deletedRows != 1 {
+		t.Fatalf("expected task_deleted row to be kept, got %d", deletedRows)
 	}
 
 	if _, err := taskSvc.GetTask(ctx, ownerID, taskID); err != service.ErrNotFound {
 		t.Fatalf("expected task not found after delete, got %v", err)
 	}
 
+	trash, total, err := taskSvc.ListTrash(ctx, ownerID, teamID, 10, 0)
+	if err != nil || total != 1 || len(trash) != 1 || trash[0].ID != taskID {
+		t.Fatalf("expected task in trash, got %+v total=%d err=%v", trash, total, err)
+	}
+
+	purged, err := taskSvc.PurgeDeletedTasks(ctx, time.Now().Add(time.Minute), 10)
+	if err != nil || purged != 1 {
+		t.Fatalf("purge trash: purged=%d err=%v", purged, err)
+	}
+
 	var cnt int
 	if err := db.GetContext(ctx, &cnt, `SELECT COUNT(*) FROM task_comments WHERE task_id = ?`, taskID); err != nil {
 		t.Fatalf("count comments: %v", err)
 	}
 	if cnt != 0 {
-		t.Fatalf("expected comments cascade delete, got %d", cnt)
+		t.Fatalf("expected comments cascade delete on purge, got %d", cnt)
 	}
 }
 
@@ -445,6 +455,96 @@ func TestTaskAndCommentErrorMatrix(t *testing.T) {
 	}
 }
 
+func TestTaskUndeleteAndRevert(t *testing.T) {
+	if !integrationEnabled() {
+		t.Skip("set INTEGRATION=1 to run")
+	}
+
+	ctx := context.Background()
+	db := setupMySQLDB(t, ctx)
+	defer db.Close()
+
+	users := repository.NewUserRepository(db)
+	teams := repository.NewTeamRepository(db)
+	members := repository.NewTeamMemberRepository(db)
+	tasks := repository.NewTaskRepository(db)
+	history := repository.NewTaskHistoryRepository(db)
+	teamSvc := service.NewTeamService(db, teams, members, users, emailOKSender{}, nil, 0, nil, nil)
+	taskSvc := service.NewTaskService(db, tasks, teams, members, repository.NewTaskCommentRepository(db), history,
+		service.WithTaskReplay(history, tasks),
+	)
+
+	ownerID, _ := users.Create(ctx, "owner-undelete@test.com", "ownerundelete", "hash")
+	teamID, err := teamSvc.CreateTeam(ctx, ownerID, "team-undelete")
+	if err != nil {
+		t.Fatalf("create team: %v", err)
+	}
+	taskID, err := taskSvc.CreateTask(ctx, ownerID, service.CreateTaskInput{TeamID: teamID, Title: "original", Description: "keep me"})
+	if err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	if _, err := taskSvc.UpdateTask(ctx, ownerID, taskID, map[string]json.RawMessage{"title": json.RawMessage(`"first edit"`)}); err != nil {
+		t.Fatalf("first update: %v", err)
+	}
+	firstEdit := time.Now().UTC()
+	time.Sleep(10 * time.Millisecond)
+	if _, err := taskSvc.UpdateTask(ctx, ownerID, taskID, map[string]json.RawMessage{
+		"title":  json.RawMessage(`"bulk edit"`),
+		"status": json.RawMessage(`"done"`),
+	}); err != nil {
+		t.Fatalf("second update: %v", err)
+	}
+
+	past, err := taskSvc.GetTaskAsOf(ctx, ownerID, taskID, firstEdit)
+	if err != nil {
+		t.Fatalf("as of: %v", err)
+	}
+	if past.Title != "first edit" || past.Status != "todo" {
+		t.Fatalf("unexpected past state: %+v", past)
+	}
+
+	if _, err := taskSvc.DeleteTask(ctx, ownerID, taskID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := taskSvc.UndeleteTask(ctx, ownerID, taskID); err != nil {
+		t.Fatalf("undelete: %v", err)
+	}
+	restored, err := taskSvc.GetTask(ctx, ownerID, taskID)
+	if err != nil {
+		t.Fatalf("get restored: %v", err)
+	}
+	if restored.Title != "bulk edit" || !restored.Description.Valid || restored.Description.String != "keep me" {
+		t.Fatalf("unexpected restored task: %+v", restored)
+	}
+	if _, err := taskSvc.UndeleteTask(ctx, ownerID, taskID); err != service.ErrNotFound {
+		t.Fatalf("second undelete must be not found, got %v", err)
+	}
+
+	rows, err := history.ListAllByTask(ctx, taskID)
+	if err != nil {
+		t.Fatalf("list history: %v", err)
+	}
+	var firstEditID int64
+	for _, h := range rows {
+		if h.FieldName == "title" && string(h.NewValue) == `"first edit"` {
+			firstEditID = h.ID
+		}
+	}
+	if firstEditID == 0 {
+		t.Fatalf("history was not restored: %+v", rows)
+	}
+	if _, err := taskSvc.RevertTask(ctx, ownerID, taskID, firstEditID); err != nil {
+		t.Fatalf("revert: %v", err)
+	}
+	reverted, err := taskSvc.GetTask(ctx, ownerID, taskID)
+	if err != nil {
+		t.Fatalf("get reverted: %v", err)
+	}
+	if reverted.Title != "first edit" || reverted.Status != "todo" {
+		t.Fatalf("unexpected reverted task: %+v", reverted)
+	}
+}
+
 func setupMySQLDB(t *testing.T, ctx context.Context) *sqlx.DB {
 	t.Helper()
 
@@ -512,3 +612,45 @@ func findMigrationsPathForTeamTask() (string, error) {
 	}
 	return "", os.ErrNotExist
 }
+
+func TestTeamAuditTrail(t *testing.T) {
+	if !integrationEnabled() {
+		t.Skip("set INTEGRATION=1 to run")
+	}
+
+	ctx := context.Background()
+	db := setupMySQLDB(t, ctx)
+	defer db.Close()
+
+	users := repository.NewUserRepository(db)
+	teams := repository.NewTeamRepository(db)
+	members := repository.NewTeamMemberRepository(db)
+	audit := service.NewAuditService(repository.NewAuditRepository(db), teams, members, nil, nil)
+	svc := service.NewTeamService(db, teams, members, users, emailOKSender{}, nil, 0, nil, nil, service.WithTeamAudit(audit))
+
+	ownerID, _ := users.Create(ctx, "audit-owner@test.com", "auditowner", "hash")
+	memberID, _ := users.Create(ctx, "audit-member@test.com", "auditmember", "hash")
+
+	reqCtx := service.WithRequestMeta(ctx, service.RequestMeta{IP: "192.0.2.1", RequestID: "req-audit"})
+	teamID, err := svc.CreateTeam(reqCtx, ownerID, "audited")
+	if err != nil {
+		t.Fatalf("create team: %v", err)
+	}
+	if err := svc.InviteByEmail(reqCtx, ownerID, teamID, "audit-member@test.com", service.RoleMember); err != nil {
+		t.Fatalf("invite: %v", err)
+	}
+
+	items, total, err := audit.ListTeamEvents(ctx, ownerID, teamID, repository.AuditFilter{}, 10, 0)
+	if err != nil || total != 2 {
+		t.Fatalf("list audit total=%d err=%v", total, err)
+	}
+	if items[0].Action != service.AuditMemberInvited || items[0].TargetID.Int64 != memberID || items[0].RequestID.String != "req-audit" {
+		t.Fatalf("unexpected newest event: %+v", items[0])
+	}
+	if items[1].Action != service.AuditTeamCreated || items[1].IP.String != "192.0.2.1" {
+		t.Fatalf("unexpected oldest event: %+v", items[1])
+	}
+	if _, _, err := audit.ListTeamEvents(ctx, memberID, teamID, repository.AuditFilter{}, 10, 0); err != service.ErrForbidden {
+		t.Fatalf("member must not read the audit log, got %v", err)
+	}
+}